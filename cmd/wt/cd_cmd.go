@@ -11,11 +11,15 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/raphi011/wt/internal/config"
+	"github.com/raphi011/wt/internal/forge"
 	"github.com/raphi011/wt/internal/git"
 	"github.com/raphi011/wt/internal/history"
+	"github.com/raphi011/wt/internal/hooks"
 	"github.com/raphi011/wt/internal/log"
 	"github.com/raphi011/wt/internal/output"
+	"github.com/raphi011/wt/internal/prcache"
 	"github.com/raphi011/wt/internal/registry"
+	"github.com/raphi011/wt/internal/ui/styles"
 	"github.com/raphi011/wt/internal/ui/wizard/flows"
 )
 
@@ -23,6 +27,7 @@ func newCdCmd() *cobra.Command {
 	var interactive bool
 	var copyToClipboard bool
 	var global bool
+	var hf hookFlags
 
 	cmd := &cobra.Command{
 		Use:     "cd [repo:]branch",
@@ -40,7 +45,12 @@ The argument can be:
 With no arguments, returns the most recently accessed worktree.
 
 Interactive mode (-i) is repo-aware: inside a repo it shows only that
-repo's worktrees. Use -g to show all repos.`,
+repo's worktrees. Use -g to show all repos.
+
+Hooks with on = ["cd"] run in the worktree after its path was printed,
+"before:cd" hooks before (a failure aborts). Their output goes to stderr,
+as stdout is read by the shell. They run in a child process, so they can't
+change the shell's environment.`,
 		Example: `  cd $(wt cd)              # cd to most recently accessed worktree
   cd $(wt cd feature-x)    # cd to feature-x worktree (error if ambiguous)
   cd $(wt cd wt:feature-x) # cd to feature-x worktree in wt repo
@@ -76,35 +86,53 @@ repo's worktrees. Use -g to show all repos.`,
 				return err
 			}
 
-			recordHistory(ctx, cfg, targetPath, repoName, branchName)
+			// Hooks must not write to stdout, which the shell reads the path from
+			ctx = hooks.WithStdout(ctx, os.Stderr)
+			repo, err := reg.FindByName(repoName)
+			if err != nil {
+				repo = registry.Repo{Name: repoName}
+			}
+			effCfg := cfg
+			if repo.Path != "" {
+				effCfg = resolveEffectiveConfig(ctx, repo.Path)
+			}
+			hp, err := buildHookParams(effCfg, repo, targetPath, branchName, hooks.CommandCd, "", hf)
+			if err != nil {
+				return err
+			}
+
+			return withHooks(ctx, hp, func() error {
+				recordHistory(ctx, cfg, targetPath, repoName, branchName)
 
-			if copyToClipboard {
-				l := log.FromContext(ctx)
-				if err := clipboard.WriteAll(targetPath); err != nil {
-					l.Printf("Warning: failed to copy to clipboard: %v\n", err)
+				if copyToClipboard {
+					l := log.FromContext(ctx)
+					if err := clipboard.WriteAll(targetPath); err != nil {
+						l.Printf("Warning: failed to copy to clipboard: %v\n", err)
+					}
 				}
-			}
 
-			out.Println(targetPath)
+				out.Println(targetPath)
 
-			// Emit OSC 7 directory hint so supporting terminal emulators
-			// know the new CWD. Written directly to stderr (not via logger) because
-			// this is a terminal protocol escape, not a log message — it should be
-			// emitted even in --quiet mode.
-			hostname, err := os.Hostname()
-			if err != nil {
-				hostname = ""
-			}
-			u := url.URL{Scheme: "file", Host: hostname, Path: targetPath}
-			fmt.Fprintf(os.Stderr, "\033]7;%s\033\\", u.String())
+				// Emit OSC 7 directory hint so supporting terminal emulators
+				// know the new CWD. Written directly to stderr (not via logger) because
+				// this is a terminal protocol escape, not a log message — it should be
+				// emitted even in --quiet mode.
+				hostname, err := os.Hostname()
+				if err != nil {
+					hostname = ""
+				}
+				u := url.URL{Scheme: "file", Host: hostname, Path: targetPath}
+				fmt.Fprintf(os.Stderr, "\033]7;%s\033\\", u.String())
 
-			return nil
+				return nil
+			})
 		},
 	}
 
 	cmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "Interactive mode with fuzzy search")
 	cmd.Flags().BoolVar(&copyToClipboard, "copy", false, "Copy path to clipboard")
 	cmd.Flags().BoolVarP(&global, "global", "g", false, "Show worktrees from all repos (interactive mode)")
+	registerHookFlags(cmd, &hf)
 
 	// Register completions
 	cmd.ValidArgsFunction = completeCdArg
@@ -142,12 +170,16 @@ func runCdInteractive(ctx context.Context, reg *registry.Registry, histPath stri
 		l.Debug("skipping repo", "repo", w.RepoName, "error", w.Err)
 	}
 
+	cfg := config.FromContext(ctx)
+	prCache := loadPRCache(ctx, cfg)
+
 	var allWorktrees []flows.CdWorktreeInfo
 	for _, wt := range loaded {
 		info := flows.CdWorktreeInfo{
 			RepoName: wt.RepoName,
 			Branch:   wt.Branch,
 			Path:     wt.Path,
+			PRStatus: cdPRStatus(prCache.Get(prcache.CacheKey(wt.RepoPath, wt.Branch))),
 		}
 		if entry := hist.FindByPath(wt.Path); entry != nil {
 			info.LastAccess = entry.LastAccess
@@ -161,16 +193,20 @@ func runCdInteractive(ctx context.Context, reg *registry.Registry, histPath stri
 
 	// Opportunistically clean stale history entries
 	if removed := hist.RemoveStale(); removed > 0 {
-		if err := hist.Save(histPath); err != nil {
+		if err := removeStaleHistory(histPath); err != nil {
 			l.Printf("Warning: failed to save history after cleanup: %v\n", err)
 		}
 	}
 
 	sortCdWorktrees(allWorktrees)
 
+	// Refresh expired PR entries while the picker is open; shown on next use
+	stopRefresh := refreshPRsInBackground(ctx, cfg, repos, prCache)
+
 	result, err := flows.CdInteractive(flows.CdWizardParams{
 		Worktrees: allWorktrees,
 	})
+	stopRefresh()
 	if err != nil {
 		return "", "", "", err
 	}
@@ -181,6 +217,15 @@ func runCdInteractive(ctx context.Context, reg *registry.Registry, histPath stri
 	return result.SelectedPath, result.RepoName, result.Branch, nil
 }
 
+// cdPRStatus formats cached PR info for the cd picker.
+// Returns empty if there is no known PR.
+func cdPRStatus(pr *forge.PRInfo) string {
+	if pr == nil || !pr.Fetched || pr.Number == 0 {
+		return ""
+	}
+	return fmt.Sprintf("#%d %s", pr.Number, styles.FormatPRState(pr.State, pr.IsDraft))
+}
+
 // runCdRecent returns the most recently accessed worktree from history.
 func runCdRecent(ctx context.Context, cfg *config.Config, histPath string) (path, repoName, branch string, err error) {
 	hist, err := history.Load(histPath)
@@ -192,7 +237,7 @@ func runCdRecent(ctx context.Context, cfg *config.Config, histPath string) (path
 	}
 
 	if removed := hist.RemoveStale(); removed > 0 {
-		if err := hist.Save(histPath); err != nil {
+		if err := removeStaleHistory(histPath); err != nil {
 			l := log.FromContext(ctx)
 			l.Printf("Warning: failed to save history after cleanup: %v\n", err)
 		}
@@ -235,3 +280,13 @@ func runCdTarget(ctx context.Context, reg *registry.Registry, arg string) (path,
 	}
 	return match.Path, match.RepoName, match.Branch, nil
 }
+
+// removeStaleHistory drops history entries whose paths no longer exist.
+// Runs as a locked transaction on the file rather than saving the caller's
+// copy, so accesses recorded meanwhile by other wt processes are kept.
+func removeStaleHistory(histPath string) error {
+	return history.Transact(histPath, func(h *history.History) error {
+		h.RemoveStale()
+		return nil
+	})
+}
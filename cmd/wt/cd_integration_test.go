@@ -441,3 +441,60 @@ func TestCd_NoArgs_StaleHistory(t *testing.T) {
 		t.Errorf("expected path %q, got %q", wtPath, got)
 	}
 }
+
+// TestCd_Hooks tests that cd hooks run without writing to stdout.
+//
+// Scenario: Config has a cd hook that echoes, user runs `wt cd feature`
+// Expected: Hook runs in the worktree; stdout contains only the path
+func TestCd_Hooks(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := resolvePath(t, t.TempDir())
+	repoPath := setupTestRepo(t, tmpDir, "myrepo")
+	wtPath := createTestWorktree(t, repoPath, "feature")
+
+	regFile := filepath.Join(tmpDir, ".wt", "repos.json")
+	if err := os.MkdirAll(filepath.Dir(regFile), 0755); err != nil {
+		t.Fatalf("failed to create registry directory: %v", err)
+	}
+
+	reg := &registry.Registry{
+		Repos: []registry.Repo{
+			{Name: "myrepo", Path: repoPath},
+		},
+	}
+	if err := reg.Save(regFile); err != nil {
+		t.Fatalf("failed to save registry: %v", err)
+	}
+
+	markerPath := filepath.Join(tmpDir, "cd.txt")
+	cfg := &config.Config{
+		RegistryPath: regFile,
+		Hooks: config.HooksConfig{
+			Hooks: map[string]config.Hook{
+				"marker": {Command: "echo entering && echo {branch} > " + markerPath, On: []string{"cd"}},
+			},
+		},
+	}
+	ctx, out := testContextWithConfigAndOutput(t, cfg, repoPath)
+
+	cmd := newCdCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"feature"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("cd command failed: %v", err)
+	}
+
+	if got := strings.TrimSpace(out.String()); got != wtPath {
+		t.Errorf("expected path %q, got %q", wtPath, got)
+	}
+
+	data, err := os.ReadFile(markerPath)
+	if err != nil {
+		t.Fatalf("cd hook did not run: %v", err)
+	}
+	if got := strings.TrimSpace(string(data)); got != "feature" {
+		t.Errorf("cd hook branch = %q, want %q", got, "feature")
+	}
+}
@@ -24,6 +24,7 @@ func newCheckoutCmd() *cobra.Command {
 		fetch       bool
 		autoStash   bool
 		note        string
+		stack       bool
 		hf          hookFlags
 		noPreserve  bool
 		interactive bool
@@ -42,12 +43,16 @@ Use -i for interactive mode to be prompted for options.
 Target uses [scope:]branch format where scope can be a repo name or label:
   - Without scope: uses current repo (or searches all repos for existing branch)
   - With repo scope: targets that specific repo
-  - With label scope (requires -b): targets all repos with that label`,
+  - With label scope (requires -b): targets all repos with that label
+
+Use --stack with -b to build the new branch on the current branch (or
+--base) instead of the default branch. The parent is recorded, see 'wt stack'.`,
 		Example: `  wt checkout feature-branch              # Existing branch in current repo
   wt checkout myrepo:feature              # Existing branch in myrepo
   wt checkout -b feature-branch           # Create new branch in current repo
   wt checkout -b myrepo:feature           # Create new branch in myrepo
   wt checkout -b backend:feature          # Create new branch in backend label repos
+  wt checkout -b feature-b --stack        # Stack feature-b on the current branch
   wt checkout -i                          # Interactive mode`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
@@ -84,6 +89,10 @@ Target uses [scope:]branch format where scope can be a repo name or label:
 				}
 			}
 
+			if stack && !newBranch {
+				return fmt.Errorf("--stack requires -b/--new-branch")
+			}
+
 			// Parse target
 			parsed, err := parseScopedTarget(reg, target)
 			if err != nil {
@@ -116,6 +125,7 @@ Target uses [scope:]branch format where scope can be a repo name or label:
 				AutoStash:     autoStash,
 				NoPreserve:    noPreserve,
 				Note:          note,
+				Stack:         stack,
 				Hooks:         hf,
 			}
 			for _, repo := range repos {
@@ -133,6 +143,7 @@ Target uses [scope:]branch format where scope can be a repo name or label:
 	cmd.Flags().BoolVarP(&fetch, "fetch", "f", false, "Fetch from origin before checkout")
 	cmd.Flags().BoolVarP(&autoStash, "autostash", "s", false, "Stash changes and apply to new worktree")
 	cmd.Flags().StringVar(&note, "note", "", "Set a note on the branch")
+	cmd.Flags().BoolVar(&stack, "stack", false, "Stack the new branch on the current branch (or --base)")
 	registerHookFlags(cmd, &hf)
 	cmd.Flags().BoolVar(&noPreserve, "no-preserve", false, "Skip file preservation")
 	cmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "Interactive mode")
@@ -153,10 +164,16 @@ type checkoutOpts struct {
 	AutoStash     bool
 	NoPreserve    bool
 	Note          string
+	Stack         bool // build on the current branch (or Base) and record it as parent
 	Hooks         hookFlags
 }
 
-func checkoutInRepo(ctx context.Context, repo registry.Repo, branch string, opts checkoutOpts) error {
+func checkoutInRepo(ctx context.Context, repo registry.Repo, branch string, opts checkoutOpts) (err error) {
+	ctx, op := startJournalOp(ctx, "checkout")
+	op.setRepo(repo)
+	op.Branch = branch
+	defer func() { op.finish(ctx, err) }()
+
 	l := log.FromContext(ctx)
 
 	cfg := resolveEffectiveConfig(ctx, repo.Path)
@@ -188,15 +205,30 @@ func checkoutInRepo(ctx context.Context, repo registry.Repo, branch string, opts
 		}
 	}
 
-	fetchForCheckout(ctx, gitDir, cfg, branch, opts, fetch, repoHasCommits)
+	// Stacked branches start from the local parent, which may have unpushed commits
+	baseRefMode := cfg.Checkout.BaseRef
+	if opts.Stack {
+		if opts.Base, err = resolveStackParent(ctx, repo, gitDir, opts.Base); err != nil {
+			return err
+		}
+		baseRefMode = "local"
+	} else {
+		fetchForCheckout(ctx, gitDir, cfg, branch, opts, fetch, repoHasCommits)
+	}
 
-	if err := createWorktreeForBranch(ctx, gitDir, wtPath, branch, opts, repoHasCommits, cfg.Checkout.BaseRef); err != nil {
+	if err := createWorktreeForBranch(ctx, gitDir, wtPath, branch, opts, repoHasCommits, baseRefMode); err != nil {
 		return err
 	}
 
 	setUpstreamTracking(ctx, gitDir, branch, opts.NewBranch, repoHasCommits, cfg)
 
 	fmt.Printf("Created worktree: %s (%s)\n", wtPath, branch)
+	op.Path = wtPath
+	op.SHA, _ = git.GetHeadCommit(ctx, wtPath) //nolint:errcheck // unborn branch
+	op.Detail = "created worktree"
+	if opts.NewBranch {
+		op.Detail = "created worktree with new branch"
+	}
 
 	if stashed {
 		if err := git.StashPop(ctx, wtPath); err != nil {
@@ -210,6 +242,14 @@ func checkoutInRepo(ctx context.Context, repo registry.Repo, branch string, opts
 		}
 	}
 
+	if opts.Stack {
+		if err := git.SetBranchParent(ctx, gitDir, branch, opts.Base); err != nil {
+			l.Printf("Warning: failed to record parent branch: %v\n", err)
+		} else {
+			op.addDetail("stacked on " + opts.Base)
+		}
+	}
+
 	preserveWorktreeFiles(ctx, repo.Path, wtPath, opts.NoPreserve, cfg.Preserve)
 
 	action := hooks.ActionOpen
@@ -228,6 +268,27 @@ func checkoutInRepo(ctx context.Context, repo registry.Repo, branch string, opts
 	})
 }
 
+// resolveStackParent returns the parent of a new stacked branch: base if
+// given, else the branch checked out in the current worktree of repo. The
+// parent must be a local branch.
+func resolveStackParent(ctx context.Context, repo registry.Repo, gitDir, base string) (string, error) {
+	parent := base
+	if parent == "" {
+		workDir := config.WorkDirFromContext(ctx)
+		if git.GetCurrentRepoMainPathFrom(ctx, workDir) != repo.Path {
+			return "", fmt.Errorf("--stack: run from a worktree of %s or pass --base", repo.Name)
+		}
+		var err error
+		if parent, err = git.GetCurrentBranch(ctx, workDir); err != nil {
+			return "", fmt.Errorf("--stack: %w", err)
+		}
+	}
+	if !git.RefExists(ctx, gitDir, "refs/heads/"+parent) {
+		return "", fmt.Errorf("--stack: parent %q is not a local branch", parent)
+	}
+	return parent, nil
+}
+
 // autoStashChanges stashes uncommitted changes in the current worktree if autostash
 // is enabled (via checkoutOpts.AutoStash). Returns true if changes were stashed.
 func autoStashChanges(ctx context.Context, repo registry.Repo, repoHasCommits bool) (bool, error) {
@@ -369,7 +430,15 @@ func preserveWorktreeFiles(ctx context.Context, repoPath, wtPath string, noPrese
 // openExistingWorktree handles the case where a worktree for the branch already exists.
 // It prints the worktree path to stdout, records history, and runs hooks with action="open",
 // skipping worktree creation.
-func openExistingWorktree(ctx context.Context, repo registry.Repo, branch, wtPath string, hf hookFlags) error {
+func openExistingWorktree(ctx context.Context, repo registry.Repo, branch, wtPath string, hf hookFlags) (err error) {
+	ctx, op := startJournalOp(ctx, "checkout")
+	op.setRepo(repo)
+	op.Branch = branch
+	op.Path = wtPath
+	op.Detail = "opened existing worktree"
+	op.SHA, _ = git.GetHeadCommit(ctx, wtPath) //nolint:errcheck
+	defer func() { op.finish(ctx, err) }()
+
 	cfg := resolveEffectiveConfig(ctx, repo.Path)
 
 	hp, err := buildHookParams(cfg, repo, wtPath, branch, hooks.CommandCheckout, hooks.ActionOpen, hf)
@@ -3,6 +3,7 @@
 package main
 
 import (
+	"encoding/json"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -1497,6 +1498,155 @@ func TestCheckout_HookWithArg(t *testing.T) {
 	}
 }
 
+// TestCheckout_HookEnvironment tests that hooks get placeholder values as
+// environment variables and via argv without shell interpretation.
+//
+// Scenario: User runs `wt checkout -b feature --hook env --hook argv --arg msg=...`
+// with a value containing quotes and a command substitution
+// Expected: Both hooks see the value unchanged and it is not executed
+func TestCheckout_HookEnvironment(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	tmpDir = resolvePath(t, tmpDir)
+
+	repoPath := setupTestRepo(t, tmpDir, "test-repo")
+
+	regFile := filepath.Join(tmpDir, ".wt", "repos.json")
+	os.MkdirAll(filepath.Dir(regFile), 0755)
+
+	reg := &registry.Registry{
+		Repos: []registry.Repo{
+			{Name: "test-repo", Path: repoPath, WorktreeFormat: "../{repo}-{branch}"},
+		},
+	}
+	if err := reg.Save(regFile); err != nil {
+		t.Fatalf("failed to save registry: %v", err)
+	}
+
+	envPath := filepath.Join(tmpDir, "env.txt")
+	argvPath := filepath.Join(tmpDir, "argv.txt")
+
+	cfg := &config.Config{
+		RegistryPath: regFile,
+		Checkout: config.CheckoutConfig{
+			WorktreeFormat: "../{repo}-{branch}",
+			BaseRef:        "local",
+		},
+		Hooks: config.HooksConfig{
+			Hooks: map[string]config.Hook{
+				"env": {
+					Command: `printf '%s|%s' "$WT_BRANCH" "$WT_ARG_MSG" > {out:q}`,
+				},
+				"argv": {
+					Argv: []string{"sh", "-c", `printf '%s' "$1" > "$2"`, "sh", "{msg}", argvPath},
+				},
+			},
+		},
+	}
+	msg := `it's $(touch injected)`
+	ctx := testContextWithConfig(t, cfg, repoPath)
+	cmd := newCheckoutCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"-b", "feature", "--hook", "env", "--hook", "argv", "--arg", "msg=" + msg, "--arg", "out=" + envPath})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("checkout command failed: %v", err)
+	}
+
+	if content, err := os.ReadFile(envPath); err != nil || string(content) != "feature|"+msg {
+		t.Errorf("env hook output = %q (err %v), want %q", content, err, "feature|"+msg)
+	}
+	if content, err := os.ReadFile(argvPath); err != nil || string(content) != msg {
+		t.Errorf("argv hook output = %q (err %v), want %q", content, err, msg)
+	}
+	wtPath := filepath.Join(tmpDir, "test-repo-feature")
+	if _, err := os.Stat(filepath.Join(wtPath, "injected")); !os.IsNotExist(err) {
+		t.Error("the --arg value was executed as shell code")
+	}
+}
+
+// TestCheckout_HookScripts tests that executable scripts in the repo's
+// .wt/hooks/<trigger>.d directory run as hooks.
+//
+// Scenario: Repo has .wt/hooks/checkout.d/setup.sh and a non-executable
+// README next to it, user runs `wt checkout -b feature`
+// Expected: setup.sh runs in the new worktree with the hook context as JSON
+// on stdin and as WT_ environment variables; README is ignored
+func TestCheckout_HookScripts(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	tmpDir = resolvePath(t, tmpDir)
+
+	repoPath := setupTestRepo(t, tmpDir, "test-repo")
+
+	regFile := filepath.Join(tmpDir, ".wt", "repos.json")
+	os.MkdirAll(filepath.Dir(regFile), 0755)
+
+	reg := &registry.Registry{
+		Repos: []registry.Repo{
+			{Name: "test-repo", Path: repoPath, WorktreeFormat: "../{repo}-{branch}"},
+		},
+	}
+	if err := reg.Save(regFile); err != nil {
+		t.Fatalf("failed to save registry: %v", err)
+	}
+
+	scriptsDir := filepath.Join(repoPath, ".wt", "hooks", "checkout.d")
+	if err := os.MkdirAll(scriptsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	script := "#!/bin/sh\ncat > input.json\nprintf '%s' \"$WT_BRANCH\" > branch.txt\n"
+	if err := os.WriteFile(filepath.Join(scriptsDir, "setup.sh"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(scriptsDir, "README"), []byte("touch readme-ran\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{
+		RegistryPath: regFile,
+		Checkout: config.CheckoutConfig{
+			WorktreeFormat: "../{repo}-{branch}",
+			BaseRef:        "local",
+		},
+	}
+	ctx := testContextWithConfig(t, cfg, repoPath)
+	cmd := newCheckoutCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"-b", "feature"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("checkout command failed: %v", err)
+	}
+
+	wtPath := filepath.Join(tmpDir, "test-repo-feature")
+	if content, err := os.ReadFile(filepath.Join(wtPath, "branch.txt")); err != nil || string(content) != "feature" {
+		t.Errorf("branch.txt = %q (err %v), want %q", content, err, "feature")
+	}
+
+	data, err := os.ReadFile(filepath.Join(wtPath, "input.json"))
+	if err != nil {
+		t.Fatalf("script input not written: %v", err)
+	}
+	var input struct {
+		Hook        string `json:"hook"`
+		WorktreeDir string `json:"worktree_dir"`
+		Trigger     string `json:"trigger"`
+	}
+	if err := json.Unmarshal(data, &input); err != nil {
+		t.Fatalf("script input is not JSON: %v", err)
+	}
+	if input.Hook != "checkout/setup.sh" || input.WorktreeDir != wtPath || input.Trigger != "checkout" {
+		t.Errorf("script input = %+v, want hook checkout/setup.sh, worktree_dir %s, trigger checkout", input, wtPath)
+	}
+
+	if _, err := os.Stat(filepath.Join(wtPath, "readme-ran")); !os.IsNotExist(err) {
+		t.Error("non-executable file in checkout.d should not run")
+	}
+}
+
 // TestCheckout_DefaultHookRuns tests that default hooks run automatically.
 //
 // Scenario: User runs `wt checkout -b feature` with a hook that has on=["checkout"]
@@ -3083,6 +3233,68 @@ func TestCheckout_BeforeHookAborts(t *testing.T) {
 	}
 }
 
+// TestCheckout_HookFailurePolicies tests on_failure and timeout of hooks.
+//
+// Scenario: A before:checkout hook with on_failure = "warn" exits 1, and an
+// after hook with on_failure = "abort" times out
+// Expected: Checkout proceeds past the before hook, then fails with the
+// timed-out after hook
+func TestCheckout_HookFailurePolicies(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	tmpDir = resolvePath(t, tmpDir)
+
+	repoPath := setupTestRepo(t, tmpDir, "test-repo")
+
+	regFile := filepath.Join(tmpDir, ".wt", "repos.json")
+	os.MkdirAll(filepath.Dir(regFile), 0755)
+
+	reg := &registry.Registry{
+		Repos: []registry.Repo{
+			{Name: "test-repo", Path: repoPath, WorktreeFormat: "../{repo}-{branch}"},
+		},
+	}
+	if err := reg.Save(regFile); err != nil {
+		t.Fatalf("failed to save registry: %v", err)
+	}
+
+	cfg := &config.Config{
+		RegistryPath: regFile,
+		Checkout: config.CheckoutConfig{
+			WorktreeFormat: "../{repo}-{branch}",
+			BaseRef:        "local",
+		},
+		Hooks: config.HooksConfig{
+			Hooks: map[string]config.Hook{
+				"lint": {
+					Command:   "exit 1",
+					On:        []string{"before:checkout"},
+					OnFailure: "warn",
+				},
+				"slow": {
+					Command:   "sleep 5",
+					On:        []string{"checkout"},
+					Timeout:   "100ms",
+					OnFailure: "abort",
+				},
+			},
+		},
+	}
+	ctx := testContextWithConfig(t, cfg, repoPath)
+	cmd := newCheckoutCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"-b", "feature"})
+
+	err := cmd.Execute()
+	if err == nil {
+		t.Fatal("expected error from the timed-out after hook")
+	}
+	if !strings.Contains(err.Error(), "after-hook failed") || !strings.Contains(err.Error(), "timed out after 100ms") {
+		t.Errorf("expected after-hook timeout error, got: %v", err)
+	}
+}
+
 // TestCheckout_BeforeHookAllows tests that a passing before hook allows checkout.
 //
 // Scenario: User has a before:checkout hook that exits 0
@@ -3673,6 +3885,85 @@ func TestCheckout_HooksRunAlphabetically(t *testing.T) {
 	}
 }
 
+// TestCheckout_HooksRunAfterDependencies tests that "after" orders hooks and
+// parallel hooks run concurrently.
+//
+// Scenario: "alpha" runs after "charlie"; "bravo" and "charlie" are parallel
+// and each waits for the other to have started
+// Expected: bravo and charlie both finish (they ran at the same time), and
+// alpha runs last
+func TestCheckout_HooksRunAfterDependencies(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	tmpDir = resolvePath(t, tmpDir)
+
+	repoPath := setupTestRepo(t, tmpDir, "test-repo")
+
+	regFile := filepath.Join(tmpDir, ".wt", "repos.json")
+	os.MkdirAll(filepath.Dir(regFile), 0755)
+
+	reg := &registry.Registry{
+		Repos: []registry.Repo{
+			{Name: "test-repo", Path: repoPath, WorktreeFormat: "../{repo}-{branch}"},
+		},
+	}
+	if err := reg.Save(regFile); err != nil {
+		t.Fatalf("failed to save registry: %v", err)
+	}
+
+	orderFile := filepath.Join(tmpDir, "order.txt")
+	waitFor := func(self, other string) string {
+		return "touch " + filepath.Join(tmpDir, self) + "; for i in $(seq 1 500); do [ -f " + filepath.Join(tmpDir, other) +
+			" ] && echo " + self + " >> " + orderFile + " && exit 0; sleep 0.01; done; exit 1"
+	}
+
+	cfg := &config.Config{
+		RegistryPath: regFile,
+		Checkout: config.CheckoutConfig{
+			WorktreeFormat: "../{repo}-{branch}",
+			BaseRef:        "local",
+		},
+		Hooks: config.HooksConfig{
+			Hooks: map[string]config.Hook{
+				"alpha": {
+					Command: "echo alpha >> " + orderFile,
+					On:      []string{"checkout"},
+					After:   []string{"charlie"},
+				},
+				"bravo": {
+					Command:  waitFor("bravo", "charlie"),
+					On:       []string{"checkout"},
+					Parallel: true,
+				},
+				"charlie": {
+					Command:  waitFor("charlie", "bravo"),
+					On:       []string{"checkout"},
+					Parallel: true,
+				},
+			},
+		},
+	}
+	ctx := testContextWithConfig(t, cfg, repoPath)
+	cmd := newCheckoutCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"-b", "feature"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("checkout command failed: %v", err)
+	}
+
+	content, err := os.ReadFile(orderFile)
+	if err != nil {
+		t.Fatalf("failed to read order file: %v", err)
+	}
+
+	lines := strings.Fields(string(content))
+	if len(lines) != 3 || lines[2] != "alpha" {
+		t.Errorf("expected bravo and charlie to finish, then alpha, got:\n%s", content)
+	}
+}
+
 // TestCheckout_BaseBranch_LocalOnlyFallback tests that --base falls back to
 // a local ref when the remote tracking branch does not exist.
 //
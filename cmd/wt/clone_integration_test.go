@@ -6,8 +6,10 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/raphi011/wt/internal/config"
 	"github.com/raphi011/wt/internal/registry"
 )
 
@@ -594,3 +596,60 @@ func mustExecGit(t *testing.T, dir string, args ...string) {
 		t.Fatalf("git %v failed: %v\n%s", args, err, out)
 	}
 }
+
+// TestRepoClone_Hooks tests that repo:clone hooks run around the clone.
+//
+// Scenario: Config has a before:repo:clone hook and a repo:clone hook,
+// user runs `wt repo clone file:///path/to/repo cloned-repo`
+// Expected: Before hook runs before the repo dir exists; after hook runs
+// in the cloned repo
+func TestRepoClone_Hooks(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	tmpDir = resolvePath(t, tmpDir)
+
+	sourceRepo := setupTestRepo(t, tmpDir, "source-repo")
+
+	regFile := filepath.Join(tmpDir, ".wt", "repos.json")
+	if err := os.MkdirAll(filepath.Dir(regFile), 0755); err != nil {
+		t.Fatalf("failed to create registry dir: %v", err)
+	}
+
+	beforePath := filepath.Join(tmpDir, "before.txt")
+	afterPath := filepath.Join(tmpDir, "after.txt")
+	cfg := testConfig()
+	cfg.RegistryPath = regFile
+	cfg.Hooks = config.HooksConfig{
+		Hooks: map[string]config.Hook{
+			"before": {Command: "test ! -e {repo-dir:q} && echo {repo} > " + beforePath, On: []string{"before:repo:clone"}},
+			"after":  {Command: "pwd > " + afterPath, On: []string{"repo:clone"}},
+		},
+	}
+	ctx := testContextWithConfig(t, cfg, tmpDir)
+
+	cmd := newRepoCloneCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"file://" + sourceRepo, "cloned-repo"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("clone command failed: %v", err)
+	}
+
+	before, err := os.ReadFile(beforePath)
+	if err != nil {
+		t.Fatalf("before hook did not run: %v", err)
+	}
+	if got := strings.TrimSpace(string(before)); got != "cloned-repo" {
+		t.Errorf("before hook repo = %q, want %q", got, "cloned-repo")
+	}
+
+	after, err := os.ReadFile(afterPath)
+	if err != nil {
+		t.Fatalf("after hook did not run: %v", err)
+	}
+	clonedPath := filepath.Join(tmpDir, "cloned-repo")
+	if got := strings.TrimSpace(string(after)); got != clonedPath {
+		t.Errorf("after hook ran in %q, want %q", got, clonedPath)
+	}
+}
@@ -1,10 +1,12 @@
 package main
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -13,6 +15,7 @@ import (
 	"github.com/raphi011/wt/internal/git"
 	"github.com/raphi011/wt/internal/history"
 	"github.com/raphi011/wt/internal/hooks"
+	"github.com/raphi011/wt/internal/journal"
 	"github.com/raphi011/wt/internal/log"
 	"github.com/raphi011/wt/internal/registry"
 )
@@ -31,6 +34,7 @@ type hookParams struct {
 	HooksCfg  config.HooksConfig
 	ConfigDir string // ~/.wt/ config dir
 	WtPath    string // worktree path (used as workDir for hook execution)
+	WorkDir   string // workDir for hook execution if not WtPath (e.g. for repo hooks)
 	RepoPath  string
 	RepoName  string
 	Branch    string
@@ -38,6 +42,9 @@ type hookParams struct {
 	Action    string
 	PRNumber  *int
 	PRRepo    string
+	PRURL     string
+	Note      string
+	Label     string
 	HookNames []string
 	NoHook    bool
 	Env       map[string]string
@@ -45,8 +52,30 @@ type hookParams struct {
 
 // withHooks runs before-hooks, then fn, then after-hooks.
 // If before-hooks fail, fn is not called and the error is returned.
-// After-hook failures are logged as warnings.
+// After-hook failures are logged as warnings, unless the hook's
+// on_failure is "abort".
 func withHooks(ctx context.Context, p hookParams, fn func() error) error {
+	if err := runHooks(ctx, p, hooks.PhaseBefore); err != nil {
+		return err
+	}
+
+	// Core logic
+	if err := fn(); err != nil {
+		return err
+	}
+
+	return runHooks(ctx, p, hooks.PhaseAfter)
+}
+
+// runHooks runs the hooks of p for phase. Before-hooks can abort: their
+// error is returned. After-hook failures are logged as warnings, unless the
+// hook's on_failure is "abort".
+func runHooks(ctx context.Context, p hookParams, phase hooks.PhaseType) error {
+	matches, err := hooks.SelectHooks(p.HooksCfg, p.HookNames, p.NoHook, hooks.HookSelector{Command: p.Trigger, Action: p.Action, Phase: phase})
+	if err != nil {
+		return err
+	}
+
 	hookCtx := hooks.Context{
 		WorktreeDir: p.WtPath,
 		RepoDir:     p.RepoPath,
@@ -54,40 +83,39 @@ func withHooks(ctx context.Context, p hookParams, fn func() error) error {
 		Repo:        p.RepoName,
 		Trigger:     string(p.Trigger),
 		Action:      p.Action,
+		Phase:       phase,
 		ConfigDir:   p.ConfigDir,
 		PRNumber:    p.PRNumber,
 		PRRepo:      p.PRRepo,
+		PRURL:       p.PRURL,
+		Note:        p.Note,
+		Label:       p.Label,
 		Env:         p.Env,
 	}
+	workDir := cmp.Or(p.WorkDir, p.WtPath)
 
-	// Before hooks (can abort)
-	beforeMatches, err := hooks.SelectHooks(p.HooksCfg, p.HookNames, p.NoHook, hooks.HookSelector{Command: p.Trigger, Action: p.Action, Phase: hooks.PhaseBefore})
-	if err != nil {
-		return err
-	}
-	hookCtx.Phase = hooks.PhaseBefore
-	if err := hooks.RunBeforeHooks(ctx, beforeMatches, hookCtx, p.WtPath); err != nil {
-		return fmt.Errorf("before-hook aborted %s: %w", p.Trigger, err)
-	}
-
-	// Core logic
-	if err := fn(); err != nil {
-		return err
-	}
-
-	// After hooks (non-fatal)
-	afterMatches, err := hooks.SelectHooks(p.HooksCfg, p.HookNames, p.NoHook, hooks.HookSelector{Command: p.Trigger, Action: p.Action, Phase: hooks.PhaseAfter})
-	if err != nil {
-		return err
+	if phase == hooks.PhaseBefore {
+		if err := hooks.RunBeforeHooks(ctx, matches, hookCtx, workDir); err != nil {
+			return fmt.Errorf("before-hook aborted %s: %w", p.Trigger, err)
+		}
+		return nil
 	}
-	if len(afterMatches) > 0 {
-		hookCtx.Phase = hooks.PhaseAfter
-		hooks.RunForEach(ctx, afterMatches, hookCtx, p.WtPath)
+	if len(matches) > 0 {
+		if err := hooks.RunForEach(ctx, matches, hookCtx, workDir); err != nil {
+			return fmt.Errorf("after-hook failed for %s: %w", p.Trigger, err)
+		}
 	}
-
 	return nil
 }
 
+// forRepo returns p for an operation on repo rather than a worktree: with
+// the hooks of the repo's effective config, run in the repo.
+func (p hookParams) forRepo(ctx context.Context, repo registry.Repo) hookParams {
+	p.HooksCfg = resolveEffectiveConfig(ctx, repo.Path).Hooks
+	p.RepoPath, p.RepoName, p.WorkDir = repo.Path, repo.Name, repo.Path
+	return p
+}
+
 // buildHookParams creates a hookParams from config and raw hook flags.
 // Returns error if env parsing or config dir resolution fails.
 func buildHookParams(cfg *config.Config, repo registry.Repo, wtPath, branch string, trigger hooks.CommandType, action string, hf hookFlags) (hookParams, error) {
@@ -130,6 +158,69 @@ func recordHistory(ctx context.Context, cfg *config.Config, wtPath, repoName, br
 	}
 }
 
+// journalOp is a mutating operation that is appended to the journal when it
+// finishes. Callers fill in the entry fields as they become known.
+type journalOp struct {
+	journal.Entry
+	start    time.Time
+	recorder *hooks.Recorder
+}
+
+// startJournalOp begins a journal entry for command. Hooks run with the
+// returned context are recorded in the entry.
+func startJournalOp(ctx context.Context, command string) (context.Context, *journalOp) {
+	op := &journalOp{
+		Entry:    journal.Entry{Command: command},
+		start:    time.Now(),
+		recorder: &hooks.Recorder{},
+	}
+	return hooks.WithRecorder(ctx, op.recorder), op
+}
+
+// setRepo sets the repo name and path of the entry.
+func (op *journalOp) setRepo(repo registry.Repo) {
+	op.Repo = repo.Name
+	op.RepoPath = repo.Path
+}
+
+// addDetail appends a comma-separated note to the entry's detail.
+func (op *journalOp) addDetail(detail string) {
+	if op.Detail != "" {
+		op.Detail += ", "
+	}
+	op.Detail += detail
+}
+
+// finish completes the entry with err, the recorded hook results and the
+// duration, and appends it to the journal. Errors are logged as warnings.
+func (op *journalOp) finish(ctx context.Context, err error) {
+	l := log.FromContext(ctx)
+	cfg := config.FromContext(ctx)
+
+	op.Time = op.start
+	op.DurationMS = time.Since(op.start).Milliseconds()
+	if err != nil {
+		op.Error = err.Error()
+	}
+	for _, r := range op.recorder.Take() {
+		res := journal.HookResult{Name: r.Name, Phase: string(r.Phase), DurationMS: r.Duration.Milliseconds()}
+		if r.Err != nil {
+			res.Error = r.Err.Error()
+			res.ExitCode = r.ExitCode
+		}
+		op.Hooks = append(op.Hooks, res)
+	}
+
+	path, perr := cfg.GetJournalPath()
+	if perr != nil {
+		l.Printf("Warning: failed to determine journal path: %v\n", perr)
+		return
+	}
+	if err := journal.Append(path, op.Entry); err != nil {
+		l.Printf("Warning: failed to write journal: %v\n", err)
+	}
+}
+
 // registerHookFlags adds the standard --hook, --no-hook, and --arg flags to a command.
 func registerHookFlags(cmd *cobra.Command, hf *hookFlags) {
 	cmd.Flags().StringSliceVar(&hf.HookNames, "hook", nil, "Run named hook(s)")
@@ -351,6 +351,7 @@ func renderConfigText(w io.Writer, cfg *config.Config, local *config.LocalConfig
 	printAlignedLines([]kv{
 		{"default", cfg.Forge.Default, srcStr(cfg.Forge.Default, local != nil && local.Forge.Default != "")},
 		{"default_org", cfg.Forge.DefaultOrg, srcStr(cfg.Forge.DefaultOrg, false)},
+		{"backend", cfg.Forge.Backend, srcStr(cfg.Forge.Backend, false)},
 	})
 	if len(cfg.Forge.Rules) > 0 {
 		fprint("  rules:\n")
@@ -374,12 +375,28 @@ func renderConfigText(w io.Writer, cfg *config.Config, local *config.LocalConfig
 		{"strategy", withDefault(cfg.Merge.Strategy, "squash"), srcStr(cfg.Merge.Strategy, local != nil && local.Merge.Strategy != "")},
 	})
 
+	// [pr]
+	printSection("[pr]", []kv{
+		{"title_format", withDefault(cfg.PR.TitleFormat, "{title}"), srcStr(cfg.PR.TitleFormat, local != nil && local.PR.TitleFormat != "")},
+	})
+
 	// [prune]
 	printSection("[prune]", []kv{
 		{"stale_days", fmt.Sprintf("%d", cfg.Prune.StaleDays), "(global)"},
 		{"delete_local_branches", fmt.Sprintf("%v", cfg.Prune.DeleteLocalBranches), src(local != nil && local.Prune.DeleteLocalBranches != nil)},
 	})
 
+	// [pr_cache]
+	printSection("[pr_cache]", []kv{
+		{"ttl", withDefault(cfg.PRCache.TTL, "off"), srcStr(cfg.PRCache.TTL, false)},
+		{"closed_ttl", withDefault(cfg.PRCache.ClosedTTL, withDefault(cfg.PRCache.TTL, "off")), srcStr(cfg.PRCache.ClosedTTL, false)},
+	})
+
+	// [trash]
+	printSection("[trash]", []kv{
+		{"enabled", fmt.Sprintf("%v", cfg.Trash.Enabled), "(global)"},
+	})
+
 	// [preserve]
 	pathsAnn := "(global)"
 	if local != nil && len(local.Preserve.Paths) > 0 {
@@ -493,25 +510,54 @@ When inside a repo (or with --repo), shows merged hooks with source annotations.
 
 			for name, hook := range effCfg.Hooks.Hooks {
 				// Determine source
-				hookSrc := "global"
+				hookSrc, note := "global", ""
 				if local != nil {
-					if _, inLocal := local.Hooks.Hooks[name]; inLocal {
-						if _, inGlobal := globalHooks[name]; inGlobal {
-							hookSrc = "local (override)"
-						} else {
+					if localHook, inLocal := local.Hooks.Hooks[name]; inLocal {
+						_, inGlobal := globalHooks[name]
+						switch {
+						case inGlobal && !localHook.DefinesCommand():
+							note = " (configured locally)"
+						case inGlobal:
+							hookSrc, note = "local", " (override)"
+						default:
 							hookSrc = "local"
 						}
 					}
 				}
+				if hook.Script != "" {
+					hookSrc += " script"
+				}
 
-				fmt.Fprintf(out.Writer(), "%s: [%s]\n", name, hookSrc)
-				fmt.Fprintf(out.Writer(), "  command: %s\n", hook.Command)
+				fmt.Fprintf(out.Writer(), "%s: [%s%s]\n", name, hookSrc, note)
+				switch {
+				case hook.Script != "":
+					fmt.Fprintf(out.Writer(), "  script: %s\n", hook.Script)
+				case len(hook.Argv) > 0:
+					fmt.Fprintf(out.Writer(), "  argv: %q\n", hook.Argv)
+				default:
+					fmt.Fprintf(out.Writer(), "  command: %s\n", hook.Command)
+				}
 				if hook.Description != "" {
 					fmt.Fprintf(out.Writer(), "  description: %s\n", hook.Description)
 				}
 				if len(hook.On) > 0 {
 					fmt.Fprintf(out.Writer(), "  on: %v\n", hook.On)
 				}
+				if len(hook.After) > 0 {
+					fmt.Fprintf(out.Writer(), "  after: %v\n", hook.After)
+				}
+				if hook.Parallel {
+					fmt.Fprintln(out.Writer(), "  parallel: true")
+				}
+				if hook.Timeout != "" {
+					fmt.Fprintf(out.Writer(), "  timeout: %s\n", hook.Timeout)
+				}
+				if hook.Retries > 0 {
+					fmt.Fprintf(out.Writer(), "  retries: %d\n", hook.Retries)
+				}
+				if hook.OnFailure != "" {
+					fmt.Fprintf(out.Writer(), "  on_failure: %s\n", hook.OnFailure)
+				}
 				fmt.Fprintln(out.Writer())
 			}
 
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/raphi011/wt/internal/config"
+	"github.com/raphi011/wt/internal/history"
+	"github.com/raphi011/wt/internal/migrate"
+	"github.com/raphi011/wt/internal/output"
+	"github.com/raphi011/wt/internal/prcache"
+	"github.com/raphi011/wt/internal/registry"
+	"github.com/raphi011/wt/internal/ui/static"
+	"github.com/raphi011/wt/internal/ui/styles"
+)
+
+// stateFile is a versioned JSON file in ~/.wt checked by `wt doctor`.
+type stateFile struct {
+	name    string
+	path    string
+	kind    string
+	migrate func(path string) (migrate.Result, error)
+}
+
+func newDoctorCmd() *cobra.Command {
+	var migrateFiles bool
+
+	cmd := &cobra.Command{
+		Use:     "doctor",
+		Short:   "Check wt's state files and upgrade old formats",
+		GroupID: GroupConfig,
+		Args:    cobra.NoArgs,
+		Long: `Check the schema version of wt's state files: repos.json (registry),
+prs.json (PR cache) and history.json (cd history).
+
+Files written by an older wt are upgraded automatically when loaded.
+Use --migrate to upgrade all of them at once, e.g. after rolling out a new
+wt release across a team. The original of every migrated file is kept
+next to it as <file>.v<N>-<timestamp>.bak.
+
+Files written by a newer wt are reported and never modified.`,
+		Example: `  wt doctor            # Show schema versions of all state files
+  wt doctor --migrate  # Upgrade old state files now`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			cfg := config.FromContext(ctx)
+			out := output.FromContext(ctx)
+
+			files, err := stateFiles(cfg)
+			if err != nil {
+				return err
+			}
+
+			var rows [][]string
+			var pending, failed int
+			for _, f := range files {
+				var res migrate.Result
+				var err error
+				if migrateFiles {
+					res, err = f.migrate(f.path)
+				} else {
+					res, err = migrate.Inspect(f.path, f.kind)
+				}
+
+				status := doctorStatus(res, err)
+				switch {
+				case err != nil:
+					failed++
+				case res.NeedsMigration():
+					pending++
+				}
+
+				version := fmt.Sprintf("v%d", res.From)
+				if res.Missing {
+					version = "-"
+				} else if res.To != res.From {
+					version = fmt.Sprintf("v%d → v%d", res.From, res.To)
+				}
+				rows = append(rows, []string{f.name, version, status, res.Path})
+			}
+
+			out.Print(static.RenderTable([]string{"FILE", "VERSION", "STATUS", "PATH"}, rows))
+
+			if pending > 0 {
+				out.Println("\nRun 'wt doctor --migrate' to upgrade old state files")
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d state file(s) have problems", failed)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&migrateFiles, "migrate", false, "Upgrade state files to the current schema version (keeps backups)")
+
+	return cmd
+}
+
+// stateFiles returns the versioned state files for cfg.
+func stateFiles(cfg *config.Config) ([]stateFile, error) {
+	regPath := cfg.RegistryPath
+	if regPath == "" {
+		dir, err := cfg.GetWtDir()
+		if err != nil {
+			return nil, err
+		}
+		regPath = filepath.Join(dir, "repos.json")
+	}
+	prPath, err := cfg.GetPRCachePath()
+	if err != nil {
+		return nil, err
+	}
+	histPath, err := cfg.GetHistoryPath()
+	if err != nil {
+		return nil, err
+	}
+
+	return []stateFile{
+		{name: "repos.json", path: regPath, kind: registry.SchemaKind, migrate: registry.Migrate},
+		{name: "prs.json", path: prPath, kind: prcache.SchemaKind, migrate: prcache.Migrate},
+		{name: "history.json", path: histPath, kind: history.SchemaKind, migrate: history.Migrate},
+	}, nil
+}
+
+// doctorStatus describes the result of inspecting or migrating a state file.
+func doctorStatus(res migrate.Result, err error) string {
+	switch {
+	case errors.Is(err, migrate.ErrNewerVersion):
+		return styles.ErrorStyle.Render("newer than this wt, upgrade wt")
+	case err != nil:
+		return styles.ErrorStyle.Render("error: " + err.Error())
+	case res.Missing:
+		return "not created yet"
+	case res.Backup != "":
+		return "migrated (backup: " + res.Backup + ")"
+	case res.NeedsMigration():
+		return fmt.Sprintf("needs migration to v%d", migrate.Latest(res.Kind))
+	default:
+		return "ok"
+	}
+}
@@ -0,0 +1,125 @@
+//go:build integration
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/raphi011/wt/internal/config"
+	"github.com/raphi011/wt/internal/history"
+	"github.com/raphi011/wt/internal/registry"
+)
+
+// TestDoctor_Migrate tests checking and upgrading unversioned state files.
+//
+// Scenario: ~/.wt holds repos.json and history.json written before schema
+// versioning (history in the old most_recent format); user runs `wt doctor`,
+// then `wt doctor --migrate`
+// Expected: doctor reports pending migrations without touching the files;
+// --migrate upgrades them, keeps labels, worktree format and history, and
+// leaves backups of the originals
+func TestDoctor_Migrate(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := resolvePath(t, t.TempDir())
+	wtDir := filepath.Join(tmpDir, ".wt")
+	if err := os.MkdirAll(wtDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	regFile := filepath.Join(wtDir, "repos.json")
+	regV0 := `{"repos":[{"path":"/src/app","name":"app","worktree_format":"../{repo}-{branch}","labels":["backend"]}]}`
+	if err := os.WriteFile(regFile, []byte(regV0), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	histFile := filepath.Join(wtDir, "history.json")
+	if err := os.WriteFile(histFile, []byte(`{"most_recent":"/src/app-feature"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{RegistryPath: regFile, HistoryPath: histFile}
+	ctx, out := testContextWithConfigAndOutput(t, cfg, tmpDir)
+
+	cmd := newDoctorCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("doctor failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "needs migration") || !strings.Contains(out.String(), "wt doctor --migrate") {
+		t.Errorf("doctor should report pending migrations, got:\n%s", out.String())
+	}
+	if data, _ := os.ReadFile(regFile); string(data) != regV0 {
+		t.Fatalf("doctor without --migrate modified repos.json: %s", data)
+	}
+
+	out.Reset()
+	cmd = newDoctorCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"--migrate"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("doctor --migrate failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "migrated") {
+		t.Errorf("expected migrated status, got:\n%s", out.String())
+	}
+
+	reg, err := registry.Load(regFile)
+	if err != nil {
+		t.Fatalf("load registry: %v", err)
+	}
+	if reg.Version != 1 || len(reg.Repos) != 1 {
+		t.Fatalf("registry = %+v, want v1 with one repo", reg)
+	}
+	if repo := reg.Repos[0]; repo.WorktreeFormat != "../{repo}-{branch}" || !repo.HasLabel("backend") {
+		t.Errorf("migration lost repo settings: %+v", repo)
+	}
+
+	hist, err := history.Load(histFile)
+	if err != nil {
+		t.Fatalf("load history: %v", err)
+	}
+	if len(hist.Entries) != 1 || hist.Entries[0].Path != "/src/app-feature" {
+		t.Errorf("migration lost history: %+v", hist.Entries)
+	}
+
+	for _, f := range []string{regFile, histFile} {
+		if backups, _ := filepath.Glob(f + ".v0-*.bak"); len(backups) != 1 {
+			t.Errorf("expected one backup of %s, got %v", filepath.Base(f), backups)
+		}
+	}
+}
+
+// TestDoctor_NewerVersion tests that files from a newer wt are left alone.
+//
+// Scenario: repos.json has a schema version this wt doesn't know
+// Expected: doctor --migrate fails, reports it and the file is unchanged
+func TestDoctor_NewerVersion(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := resolvePath(t, t.TempDir())
+	regFile := filepath.Join(tmpDir, "repos.json")
+	contents := `{"version":99,"repos":[]}`
+	if err := os.WriteFile(regFile, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{RegistryPath: regFile}
+	ctx, out := testContextWithConfigAndOutput(t, cfg, tmpDir)
+
+	cmd := newDoctorCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"--migrate"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected doctor to fail for a newer repos.json")
+	}
+	if !strings.Contains(out.String(), "newer than this wt") {
+		t.Errorf("expected newer version status, got:\n%s", out.String())
+	}
+	if data, _ := os.ReadFile(regFile); string(data) != contents {
+		t.Errorf("repos.json was modified: %s", data)
+	}
+}
@@ -42,15 +42,17 @@ Target worktrees using [scope:]branch format where scope can be a repo name or l
 Trigger syntax for "on" field:
   [before:|after:]trigger[:subtype]
 
-  Triggers: checkout, prune, merge, all
-  Subtypes (checkout only): create, open, pr
+  Triggers: checkout, prune, pr, repo, note, label, cd, all
+  Subtypes: checkout (create, open, pr), pr (create, merge),
+            repo (add, clone), note (set, clear), label (add, remove, clear)
   Timing: before (can cancel operation), after (default)
 
 Examples:
   on = ["checkout"]              # All checkouts (after)
   on = ["checkout:pr"]           # PR checkouts only
   on = ["before:prune"]          # Pre-prune guard (can abort)
-  on = ["before:checkout:pr"]    # Before PR checkout only`,
+  on = ["before:checkout:pr"]    # Before PR checkout only
+  on = ["before:pr:merge"]       # Before PR merge (can abort)`,
 		Example: `  wt hook code                        # Run 'code' hook in current worktree
   wt hook main code                   # Run 'code' in main worktree (all repos)
   wt hook myrepo:main code            # Run in specific repo's worktree
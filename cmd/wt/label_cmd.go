@@ -1,12 +1,14 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/raphi011/wt/internal/config"
+	"github.com/raphi011/wt/internal/hooks"
 	"github.com/raphi011/wt/internal/output"
 	"github.com/raphi011/wt/internal/registry"
 )
@@ -19,7 +21,11 @@ func newLabelCmd() *cobra.Command {
 		GroupID: GroupUtility,
 		Long: `Manage labels on repositories.
 
-Labels are stored in the registry and can be used to target repos.`,
+Labels are stored in the registry and can be used to target repos.
+
+Adding, removing or clearing labels runs the hooks with on = ["label"] (or
+"label:add", "label:remove", "label:clear") in each repo, with the label as
+{label}.`,
 		Example: `  wt label add backend           # Add label to current repo
   wt label add backend api       # Add label to specific repo
   wt label add backend mygroup   # Add label to repos with 'mygroup' label
@@ -37,6 +43,8 @@ Labels are stored in the registry and can be used to target repos.`,
 }
 
 func newLabelAddCmd() *cobra.Command {
+	var hf hookFlags
+
 	cmd := &cobra.Command{
 		Use:   "add <label> [scope...]",
 		Short: "Add a label to repositories",
@@ -67,21 +75,32 @@ Scopes are resolved as repo name first, then label.`,
 				return err
 			}
 
+			hp, err := buildHookParams(cfg, registry.Repo{}, "", "", hooks.CommandLabel, hooks.ActionAdd, hf)
+			if err != nil {
+				return err
+			}
+			hp.Label = label
+
 			for _, repo := range repos {
-				if err := reg.AddLabel(repo.Name, label); err != nil {
-					return fmt.Errorf("%s: %w", repo.Name, err)
+				err := changeLabels(ctx, hp, repo, func(r *registry.Registry) error {
+					return r.AddLabel(repo.Name, label)
+				}, fmt.Sprintf("Added label %q to %s", label, repo.Name))
+				if err != nil {
+					return err
 				}
-				fmt.Printf("Added label %q to %s\n", label, repo.Name)
 			}
-
-			return reg.Save(cfg.RegistryPath)
+			return nil
 		},
 	}
 
+	registerHookFlags(cmd, &hf)
+
 	return cmd
 }
 
 func newLabelRemoveCmd() *cobra.Command {
+	var hf hookFlags
+
 	cmd := &cobra.Command{
 		Use:   "remove <label> [scope...]",
 		Short: "Remove a label from repositories",
@@ -112,17 +131,26 @@ Scopes are resolved as repo name first, then label.`,
 				return err
 			}
 
+			hp, err := buildHookParams(cfg, registry.Repo{}, "", "", hooks.CommandLabel, hooks.ActionRemove, hf)
+			if err != nil {
+				return err
+			}
+			hp.Label = label
+
 			for _, repo := range repos {
-				if err := reg.RemoveLabel(repo.Name, label); err != nil {
-					return fmt.Errorf("%s: %w", repo.Name, err)
+				err := changeLabels(ctx, hp, repo, func(r *registry.Registry) error {
+					return r.RemoveLabel(repo.Name, label)
+				}, fmt.Sprintf("Removed label %q from %s", label, repo.Name))
+				if err != nil {
+					return err
 				}
-				fmt.Printf("Removed label %q from %s\n", label, repo.Name)
 			}
-
-			return reg.Save(cfg.RegistryPath)
+			return nil
 		},
 	}
 
+	registerHookFlags(cmd, &hf)
+
 	return cmd
 }
 
@@ -192,6 +220,8 @@ Scopes are resolved as repo name first, then label.`,
 }
 
 func newLabelClearCmd() *cobra.Command {
+	var hf hookFlags
+
 	cmd := &cobra.Command{
 		Use:   "clear [scope...]",
 		Short: "Clear all labels from repositories",
@@ -220,16 +250,48 @@ Scopes are resolved as repo name first, then label.`,
 				return err
 			}
 
+			hp, err := buildHookParams(cfg, registry.Repo{}, "", "", hooks.CommandLabel, hooks.ActionClear, hf)
+			if err != nil {
+				return err
+			}
+
 			for _, repo := range repos {
-				if err := reg.ClearLabels(repo.Name); err != nil {
-					return fmt.Errorf("%s: %w", repo.Name, err)
+				err := changeLabels(ctx, hp, repo, func(r *registry.Registry) error {
+					return r.ClearLabels(repo.Name)
+				}, fmt.Sprintf("Cleared labels from %s", repo.Name))
+				if err != nil {
+					return err
 				}
-				fmt.Printf("Cleared labels from %s\n", repo.Name)
 			}
-
-			return reg.Save(cfg.RegistryPath)
+			return nil
 		},
 	}
 
+	registerHookFlags(cmd, &hf)
+
 	return cmd
 }
+
+// changeLabels saves the label change update makes to the registry for repo,
+// printing msg, with the label hooks of repo and appends it to the journal.
+func changeLabels(ctx context.Context, hp hookParams, repo registry.Repo, update func(r *registry.Registry) error, msg string) (err error) {
+	ctx, op := startJournalOp(ctx, "label "+hp.Action)
+	defer func() { op.finish(ctx, err) }()
+	op.setRepo(repo)
+	op.Detail = hp.Label
+
+	cfg := config.FromContext(ctx)
+	return withHooks(ctx, hp.forRepo(ctx, repo), func() error {
+		err := registry.Transact(cfg.RegistryPath, func(r *registry.Registry) error {
+			if err := update(r); err != nil {
+				return fmt.Errorf("%s: %w", repo.Name, err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Println(msg)
+		return nil
+	})
+}
@@ -696,3 +696,60 @@ func TestLabel_Clear_CurrentRepo(t *testing.T) {
 		t.Errorf("expected 0 labels after clear, got %d: %v", len(repo.Labels), repo.Labels)
 	}
 }
+
+// TestLabel_Add_Hooks tests that label hooks run for each labeled repo.
+//
+// Scenario: Config has a label:add hook, user runs `wt label add backend`
+// for two repos
+// Expected: Hook runs once per repo with {repo} and {label} set
+func TestLabel_Add_Hooks(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := resolvePath(t, t.TempDir())
+	repoA := setupTestRepo(t, tmpDir, "repo-a")
+	repoB := setupTestRepo(t, tmpDir, "repo-b")
+
+	regFile := filepath.Join(tmpDir, ".wt", "repos.json")
+	if err := os.MkdirAll(filepath.Dir(regFile), 0755); err != nil {
+		t.Fatalf("failed to create registry directory: %v", err)
+	}
+
+	reg := &registry.Registry{
+		Repos: []registry.Repo{
+			{Name: "repo-a", Path: repoA},
+			{Name: "repo-b", Path: repoB},
+		},
+	}
+	if err := reg.Save(regFile); err != nil {
+		t.Fatalf("failed to save registry: %v", err)
+	}
+
+	logPath := filepath.Join(tmpDir, "hook.log")
+	cfg := &config.Config{
+		RegistryPath: regFile,
+		Hooks: config.HooksConfig{
+			Hooks: map[string]config.Hook{
+				"log": {Command: "echo {repo} {action} {label} >> " + logPath, On: []string{"label:add"}},
+			},
+		},
+	}
+	ctx := testContextWithConfig(t, cfg, tmpDir)
+
+	cmd := newLabelCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"add", "backend", "repo-a", "repo-b"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("label add command failed: %v", err)
+	}
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("hook did not run: %v", err)
+	}
+	got := strings.TrimSpace(string(data))
+	want := "repo-a add backend\nrepo-b add backend"
+	if got != want {
+		t.Errorf("hook log = %q, want %q", got, want)
+	}
+}
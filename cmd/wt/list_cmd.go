@@ -11,7 +11,6 @@ import (
 	"github.com/raphi011/wt/internal/git"
 	"github.com/raphi011/wt/internal/log"
 	"github.com/raphi011/wt/internal/output"
-	"github.com/raphi011/wt/internal/prcache"
 	"github.com/raphi011/wt/internal/registry"
 	"github.com/raphi011/wt/internal/ui/static"
 )
@@ -37,7 +36,8 @@ Use positional args to filter by repo name(s) or label(s).
 Resolution order: repo name → label.
 
 Worktrees are sorted by commit date (most recent first) by default.
-Use --refresh-pr/-R to fetch PR status from GitHub/GitLab.`,
+Use --refresh-pr/-R to fetch PR status from GitHub/GitLab. With
+[pr_cache] ttl configured, expired entries are refreshed automatically.`,
 		Example: `  wt list                      # List worktrees for current repo
   wt list --global             # List all worktrees (all repos)
   wt list myrepo               # Filter by repository name
@@ -87,13 +87,17 @@ Use --refresh-pr/-R to fetch PR status from GitHub/GitLab.`,
 			}
 
 			// Load PR cache
-			prCache := prcache.Load()
+			prCache := loadPRCache(ctx, cfg)
 
-			// Refresh PR status if requested
+			gcPRCache(ctx, prCache, reg, repos)
+
+			// Refresh PR status if requested, otherwise only expired entries
 			if refresh {
 				if failed := refreshPRs(ctx, allWorktrees, prCache, cfg.Hosts, &cfg.Forge); len(failed) > 0 {
 					l.Printf("Warning: failed to fetch PR status for: %v\n", failed)
 				}
+			} else {
+				autoRefreshPRs(ctx, allWorktrees, prCache, cfg)
 			}
 
 			populatePRFields(allWorktrees, prCache)
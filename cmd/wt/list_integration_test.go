@@ -7,8 +7,11 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/raphi011/wt/internal/config"
+	"github.com/raphi011/wt/internal/forge"
+	"github.com/raphi011/wt/internal/prcache"
 	"github.com/raphi011/wt/internal/registry"
 )
 
@@ -622,3 +625,63 @@ func TestList_GlobalFromNonRepo(t *testing.T) {
 		t.Errorf("expected output to contain 'feature', got %q", output)
 	}
 }
+
+// TestList_PRCacheGarbageCollection tests that list drops PR cache entries
+// for branches and repos that no longer exist.
+//
+// Scenario: prs.json has entries for an existing branch, a deleted branch and an unregistered repo
+// Expected: After `wt list`, only the entry for the existing branch remains
+func TestList_PRCacheGarbageCollection(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	tmpDir = resolvePath(t, tmpDir)
+
+	repoPath := setupTestRepoWithBranches(t, tmpDir, "test-repo", []string{"feature"})
+	createTestWorktree(t, repoPath, "feature")
+
+	regFile := filepath.Join(tmpDir, ".wt", "repos.json")
+	if err := os.MkdirAll(filepath.Dir(regFile), 0755); err != nil {
+		t.Fatalf("failed to create registry directory: %v", err)
+	}
+	reg := &registry.Registry{
+		Repos: []registry.Repo{
+			{Name: "test-repo", Path: repoPath},
+		},
+	}
+	if err := reg.Save(regFile); err != nil {
+		t.Fatalf("failed to save registry: %v", err)
+	}
+
+	cfg := &config.Config{RegistryPath: regFile}
+	cachePath, err := cfg.GetPRCachePath()
+	if err != nil {
+		t.Fatalf("GetPRCachePath failed: %v", err)
+	}
+
+	cache := prcache.New()
+	cache.Set(prcache.CacheKey(repoPath, "feature"), &forge.PRInfo{Number: 1, State: forge.PRStateOpen, Fetched: true, CachedAt: time.Now()})
+	cache.Set(prcache.CacheKey(repoPath, "deleted-branch"), &forge.PRInfo{Number: 2, State: forge.PRStateMerged, Fetched: true})
+	cache.Set(prcache.CacheKey(filepath.Join(tmpDir, "unregistered"), "main"), &forge.PRInfo{Number: 3, Fetched: true})
+	if err := cache.SaveTo(cachePath); err != nil {
+		t.Fatalf("failed to save PR cache: %v", err)
+	}
+
+	ctx, _ := testContextWithOutput(t)
+	ctx = config.WithConfig(ctx, cfg)
+	ctx = config.WithWorkDir(ctx, repoPath)
+	cmd := newListCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("list command failed: %v", err)
+	}
+
+	loaded := prcache.LoadFrom(cachePath)
+	if len(loaded.PRs) != 1 {
+		t.Errorf("expected 1 remaining cache entry, got %d: %v", len(loaded.PRs), loaded.PRs)
+	}
+	if loaded.Get(prcache.CacheKey(repoPath, "feature")) == nil {
+		t.Error("entry for existing branch should be kept")
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/raphi011/wt/internal/config"
+	"github.com/raphi011/wt/internal/journal"
+	"github.com/raphi011/wt/internal/output"
+	"github.com/raphi011/wt/internal/ui/static"
+	"github.com/raphi011/wt/internal/ui/styles"
+)
+
+func newLogCmd() *cobra.Command {
+	var (
+		jsonOutput bool
+		repo       string
+		branch     string
+		command    string
+		since      string
+		until      string
+		limit      int
+	)
+
+	cmd := &cobra.Command{
+		Use:     "log",
+		Short:   "Show the journal of past operations",
+		GroupID: GroupUtility,
+		Args:    cobra.NoArgs,
+		Long: `Show the journal of mutating wt operations, newest first.
+
+Every checkout, prune, PR checkout/merge, repo add/clone/remove/convert,
+label and note change is appended to ~/.wt/journal.jsonl with the repo,
+branch, worktree path, HEAD commit, hook results and duration.
+
+--since and --until accept a duration ago (e.g. 24h), a date (2026-01-02)
+or an RFC 3339 timestamp. --command matches a full command ("pr merge")
+or its first word ("pr").`,
+		Example: `  wt log                          # Last 50 operations
+  wt log --repo myrepo            # Operations on one repo
+  wt log --branch feature -n 0    # Full history of a branch
+  wt log --command prune --since 24h
+  wt log --since 2026-01-01 --until 2026-02-01 --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			cfg := config.FromContext(ctx)
+			out := output.FromContext(ctx)
+
+			now := time.Now()
+			filter := journal.Filter{Repo: repo, Branch: branch, Command: command}
+			var err error
+			if filter.Since, err = parseTimeArg(since, now); err != nil {
+				return fmt.Errorf("invalid --since: %w", err)
+			}
+			if filter.Until, err = parseTimeArg(until, now); err != nil {
+				return fmt.Errorf("invalid --until: %w", err)
+			}
+
+			path, err := cfg.GetJournalPath()
+			if err != nil {
+				return err
+			}
+			entries, err := journal.Read(path)
+			if err != nil {
+				return fmt.Errorf("read journal: %w", err)
+			}
+
+			entries = filter.Apply(entries)
+			slices.Reverse(entries)
+			if limit > 0 && len(entries) > limit {
+				entries = entries[:limit]
+			}
+
+			if jsonOutput {
+				if entries == nil {
+					entries = []journal.Entry{}
+				}
+				enc := json.NewEncoder(out.Writer())
+				enc.SetIndent("", "  ")
+				return enc.Encode(entries)
+			}
+
+			if len(entries) == 0 {
+				out.Println("No journal entries found")
+				return nil
+			}
+
+			var rows [][]string
+			for _, e := range entries {
+				rows = append(rows, journalTableRow(e))
+			}
+			out.Print(static.RenderTable([]string{"TIME", "COMMAND", "REPO", "BRANCH", "SHA", "HOOKS", "TOOK", "DETAIL"}, rows))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+	cmd.Flags().StringVarP(&repo, "repo", "r", "", "Only show operations on this repo")
+	cmd.Flags().StringVarP(&branch, "branch", "b", "", "Only show operations on this branch")
+	cmd.Flags().StringVarP(&command, "command", "c", "", "Only show this command (e.g. prune, \"pr merge\")")
+	cmd.Flags().StringVar(&since, "since", "", "Only show operations after this time")
+	cmd.Flags().StringVar(&until, "until", "", "Only show operations before this time")
+	cmd.Flags().IntVarP(&limit, "limit", "n", 50, "Maximum number of entries (0 for all)")
+
+	cmd.RegisterFlagCompletionFunc("repo", completeRepoNames)
+	cmd.RegisterFlagCompletionFunc("branch", cobra.NoFileCompletions)
+	cmd.RegisterFlagCompletionFunc("command", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"checkout", "prune", "pr", "repo", "label", "note", "trash"}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
+
+// journalTableRow formats a journal entry for the `wt log` table.
+func journalTableRow(e journal.Entry) []string {
+	sha := e.SHA
+	if len(sha) > 7 {
+		sha = sha[:7]
+	}
+
+	var hookCol string
+	if n := len(e.Hooks); n > 0 {
+		if failed := e.FailedHooks(); failed > 0 {
+			hookCol = styles.ErrorStyle.Render(fmt.Sprintf("%d/%d failed", failed, n))
+		} else {
+			hookCol = fmt.Sprintf("%d ok", n)
+		}
+	}
+
+	detail := e.Detail
+	if e.Failed() {
+		detail = styles.ErrorStyle.Render("error: " + e.Error)
+	}
+
+	took := (time.Duration(e.DurationMS) * time.Millisecond).String()
+
+	return []string{e.Time.Local().Format("2006-01-02 15:04:05"), e.Command, e.Repo, e.Branch, sha, hookCol, took, detail}
+}
+
+// parseTimeArg parses a --since/--until value: a duration before now
+// (e.g. "24h"), a local date ("2006-01-02") or an RFC 3339 timestamp.
+// Returns the zero time for an empty value.
+func parseTimeArg(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		if d < 0 {
+			return time.Time{}, fmt.Errorf("duration %q must not be negative", value)
+		}
+		return now.Add(-d), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("%q is not a duration (24h), date (2006-01-02) or RFC 3339 time", value)
+}
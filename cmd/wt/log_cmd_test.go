@@ -0,0 +1,36 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimeArg(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{"", time.Time{}, false},
+		{"24h", now.Add(-24 * time.Hour), false},
+		{"90m", now.Add(-90 * time.Minute), false},
+		{"2026-03-01", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), false},
+		{"2026-03-01T08:30:00Z", time.Date(2026, 3, 1, 8, 30, 0, 0, time.UTC), false},
+		{"-1h", time.Time{}, true},
+		{"yesterday", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		got, err := parseTimeArg(tt.value, now)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseTimeArg(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseTimeArg(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+//go:build integration
+
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/raphi011/wt/internal/config"
+	"github.com/raphi011/wt/internal/journal"
+)
+
+// TestLog_JournalsCheckoutAndPrune tests that mutating commands are journaled.
+//
+// Scenario: User checks out a branch (with a failing after-hook), labels the
+// repo and prunes the worktree
+// Expected: Journal has checkout, label add and prune entries with repo,
+// branch, path, sha and the hook result
+func TestLog_JournalsCheckoutAndPrune(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := resolvePath(t, t.TempDir())
+	repoPath := setupTestRepoWithBranches(t, tmpDir, "test-repo", []string{"feature"})
+	regFile := saveSingleRepoRegistry(t, tmpDir, repoPath)
+
+	cfg := &config.Config{
+		RegistryPath: regFile,
+		Checkout:     config.CheckoutConfig{WorktreeFormat: "../{repo}-{branch}"},
+		Hooks: config.HooksConfig{
+			Hooks: map[string]config.Hook{
+				"broken": {Command: "exit 3", On: []string{"checkout"}},
+			},
+		},
+	}
+	ctx := testContextWithConfig(t, cfg, repoPath)
+
+	cmd := newCheckoutCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"feature"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("checkout failed: %v", err)
+	}
+
+	cmd = newLabelCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"add", "backend"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("label add failed: %v", err)
+	}
+
+	cmd = newPruneCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"feature", "-f"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("prune failed: %v", err)
+	}
+
+	entries, err := journal.Read(cfg.JournalPath)
+	if err != nil {
+		t.Fatalf("failed to read journal: %v", err)
+	}
+	var commands []string
+	for _, e := range entries {
+		commands = append(commands, e.Command)
+	}
+	if strings.Join(commands, ",") != "checkout,label add,prune" {
+		t.Fatalf("journal commands = %v, want checkout, label add, prune", commands)
+	}
+
+	co := entries[0]
+	if co.Repo != "test-repo" || co.Branch != "feature" || co.Path == "" || co.SHA == "" {
+		t.Errorf("checkout entry missing fields: %+v", co)
+	}
+	if len(co.Hooks) != 1 || co.Hooks[0].Name != "broken" || !strings.Contains(co.Hooks[0].Error, "exit 3") || co.Hooks[0].ExitCode != 3 {
+		t.Errorf("checkout entry should record the failed hook, got %+v", co.Hooks)
+	}
+	if entries[1].Detail != "backend" {
+		t.Errorf("label entry detail = %q, want backend", entries[1].Detail)
+	}
+	if entries[2].Path != co.Path || entries[2].SHA != co.SHA {
+		t.Errorf("prune entry should record path and sha, got %+v", entries[2])
+	}
+}
+
+// TestLog_Filters tests filtering and JSON output of `wt log`.
+//
+// Scenario: Journal holds entries for two repos and commands;
+// user runs `wt log --repo app --command pr --json`
+// Expected: Only the matching entry is printed
+func TestLog_Filters(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := resolvePath(t, t.TempDir())
+	cfg := &config.Config{}
+	ctx, out := testContextWithConfigAndOutput(t, cfg, tmpDir)
+
+	if err := journal.Append(cfg.JournalPath,
+		journal.Entry{Command: "checkout", Repo: "app", Branch: "feature"},
+		journal.Entry{Command: "pr merge", Repo: "app", Branch: "feature"},
+		journal.Entry{Command: "pr merge", Repo: "api", Branch: "fix"},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := newLogCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"--repo", "app", "--command", "pr", "--json"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("log failed: %v", err)
+	}
+
+	var got []journal.Entry
+	if err := json.Unmarshal([]byte(out.String()), &got); err != nil {
+		t.Fatalf("failed to parse JSON output: %v\n%s", err, out.String())
+	}
+	if len(got) != 1 || got[0].Command != "pr merge" || got[0].Repo != "app" {
+		t.Errorf("expected the app pr merge entry, got %+v", got)
+	}
+}
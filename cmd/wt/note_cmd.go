@@ -8,6 +8,7 @@ import (
 
 	"github.com/raphi011/wt/internal/config"
 	"github.com/raphi011/wt/internal/git"
+	"github.com/raphi011/wt/internal/hooks"
 	"github.com/raphi011/wt/internal/output"
 	"github.com/raphi011/wt/internal/registry"
 )
@@ -22,6 +23,9 @@ func newNoteCmd() *cobra.Command {
 
 Notes are stored in git config and displayed in list output.
 
+Setting or clearing a note runs the hooks with on = ["note"] (or
+"note:set", "note:clear") in the repo, with the note as {note}.
+
 Target a worktree using [scope:]branch where scope can be a repo name or label.
 If no target is specified, uses the current worktree's branch.`,
 		Example: `  wt note set "WIP"                    # Set note on current branch
@@ -41,6 +45,8 @@ If no target is specified, uses the current worktree's branch.`,
 }
 
 func newNoteSetCmd() *cobra.Command {
+	var hf hookFlags
+
 	cmd := &cobra.Command{
 		Use:               "set <text> [scope:]branch",
 		Short:             "Set a note on a branch",
@@ -64,18 +70,31 @@ func newNoteSetCmd() *cobra.Command {
 				return err
 			}
 
+			hp, err := buildHookParams(cfg, registry.Repo{}, "", "", hooks.CommandNote, hooks.ActionSet, hf)
+			if err != nil {
+				return err
+			}
+
 			// Set note on each target
 			for _, t := range targets {
-				if err := git.SetBranchNote(ctx, t.RepoPath, t.Branch, text); err != nil {
+				err := changeNote(ctx, hp, t, text, func() error {
+					if err := git.SetBranchNote(ctx, t.RepoPath, t.Branch, text); err != nil {
+						return err
+					}
+					fmt.Printf("Note set on %s:%s\n", t.RepoName, t.Branch)
+					return nil
+				})
+				if err != nil {
 					return err
 				}
-				fmt.Printf("Note set on %s:%s\n", t.RepoName, t.Branch)
 			}
 
 			return nil
 		},
 	}
 
+	registerHookFlags(cmd, &hf)
+
 	return cmd
 }
 
@@ -128,6 +147,8 @@ func newNoteGetCmd() *cobra.Command {
 }
 
 func newNoteClearCmd() *cobra.Command {
+	var hf hookFlags
+
 	cmd := &cobra.Command{
 		Use:               "clear [[scope:]branch]",
 		Short:             "Clear the note from a branch",
@@ -150,21 +171,50 @@ func newNoteClearCmd() *cobra.Command {
 				return err
 			}
 
+			hp, err := buildHookParams(cfg, registry.Repo{}, "", "", hooks.CommandNote, hooks.ActionClear, hf)
+			if err != nil {
+				return err
+			}
+
 			// Clear note from each target
 			for _, t := range targets {
-				if err := git.ClearBranchNote(ctx, t.RepoPath, t.Branch); err != nil {
+				err := changeNote(ctx, hp, t, "", func() error {
+					if err := git.ClearBranchNote(ctx, t.RepoPath, t.Branch); err != nil {
+						return err
+					}
+					fmt.Printf("Note cleared on %s:%s\n", t.RepoName, t.Branch)
+					return nil
+				})
+				if err != nil {
 					return err
 				}
-				fmt.Printf("Note cleared on %s:%s\n", t.RepoName, t.Branch)
 			}
 
 			return nil
 		},
 	}
 
+	registerHookFlags(cmd, &hf)
+
 	return cmd
 }
 
+// changeNote runs fn, which sets the note of target t to text (or clears
+// it), with the note hooks of t's repo and appends it to the journal.
+func changeNote(ctx context.Context, hp hookParams, t noteTarget, text string, fn func() error) (err error) {
+	ctx, op := startJournalOp(ctx, "note "+hp.Action)
+	defer func() { op.finish(ctx, err) }()
+	op.Repo = t.RepoName
+	op.RepoPath = t.RepoPath
+	op.Branch = t.Branch
+	op.Detail = text
+
+	hp = hp.forRepo(ctx, registry.Repo{Name: t.RepoName, Path: t.RepoPath})
+	hp.Branch = t.Branch
+	hp.Note = text
+	return withHooks(ctx, hp, fn)
+}
+
 // noteTarget holds a resolved note target
 type noteTarget struct {
 	RepoName string
@@ -251,10 +301,7 @@ func getCurrentRepoBranch(ctx context.Context, cfg *config.Config, workDir strin
 			Name:   git.GetRepoDisplayName(repoPath),
 			Labels: cfg.DefaultLabels,
 		}
-		if err := reg.Add(newRepo); err != nil {
-			return registry.Repo{}, "", err
-		}
-		if err := reg.Save(cfg.RegistryPath); err != nil {
+		if err := autoRegisterRepo(cfg, reg, newRepo); err != nil {
 			return registry.Repo{}, "", err
 		}
 		repo, err = reg.FindByPath(repoPath)
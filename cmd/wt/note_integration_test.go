@@ -378,3 +378,123 @@ func TestNoteSet_LabelScope(t *testing.T) {
 		}
 	}
 }
+
+// TestNoteSet_Hooks tests that note hooks run around setting a note.
+//
+// Scenario: Config has hooks on before:note:set and note, user runs
+// `wt note set "WIP"` in a worktree
+// Expected: Both hooks run in the repo dir with {branch} and {note} set
+func TestNoteSet_Hooks(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := resolvePath(t, t.TempDir())
+	repoPath := setupTestRepo(t, tmpDir, "myrepo")
+	wtPath := createTestWorktree(t, repoPath, "feature")
+
+	regFile := filepath.Join(tmpDir, ".wt", "repos.json")
+	if err := os.MkdirAll(filepath.Dir(regFile), 0755); err != nil {
+		t.Fatalf("failed to create registry directory: %v", err)
+	}
+
+	reg := &registry.Registry{
+		Repos: []registry.Repo{
+			{Name: "myrepo", Path: repoPath},
+		},
+	}
+	if err := reg.Save(regFile); err != nil {
+		t.Fatalf("failed to save registry: %v", err)
+	}
+
+	beforePath := filepath.Join(tmpDir, "before.txt")
+	afterPath := filepath.Join(tmpDir, "after.txt")
+	cfg := &config.Config{
+		RegistryPath: regFile,
+		Hooks: config.HooksConfig{
+			Hooks: map[string]config.Hook{
+				"before": {Command: "echo {action} {branch} {note:q} > " + beforePath, On: []string{"before:note:set"}},
+				"after":  {Command: "pwd > " + afterPath, On: []string{"note"}},
+			},
+		},
+	}
+	ctx := testContextWithConfig(t, cfg, wtPath)
+
+	cmd := newNoteCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"set", "WIP"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("note set command failed: %v", err)
+	}
+
+	before, err := os.ReadFile(beforePath)
+	if err != nil {
+		t.Fatalf("before hook did not run: %v", err)
+	}
+	if got := strings.TrimSpace(string(before)); got != "set feature WIP" {
+		t.Errorf("before hook output = %q, want %q", got, "set feature WIP")
+	}
+
+	after, err := os.ReadFile(afterPath)
+	if err != nil {
+		t.Fatalf("after hook did not run: %v", err)
+	}
+	if got := strings.TrimSpace(string(after)); got != repoPath {
+		t.Errorf("after hook ran in %q, want %q", got, repoPath)
+	}
+}
+
+// TestNoteClear_BeforeHookAborts tests that a failing before hook keeps the note.
+//
+// Scenario: Config has a before:note hook that exits 1, user runs `wt note clear`
+// Expected: Command fails and the note is not cleared
+func TestNoteClear_BeforeHookAborts(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := resolvePath(t, t.TempDir())
+	repoPath := setupTestRepo(t, tmpDir, "myrepo")
+	wtPath := createTestWorktree(t, repoPath, "feature")
+
+	if _, err := runGitCommand(repoPath, "config", "branch.feature.description", "keep me"); err != nil {
+		t.Fatalf("failed to set note: %v", err)
+	}
+
+	regFile := filepath.Join(tmpDir, ".wt", "repos.json")
+	if err := os.MkdirAll(filepath.Dir(regFile), 0755); err != nil {
+		t.Fatalf("failed to create registry directory: %v", err)
+	}
+
+	reg := &registry.Registry{
+		Repos: []registry.Repo{
+			{Name: "myrepo", Path: repoPath},
+		},
+	}
+	if err := reg.Save(regFile); err != nil {
+		t.Fatalf("failed to save registry: %v", err)
+	}
+
+	cfg := &config.Config{
+		RegistryPath: regFile,
+		Hooks: config.HooksConfig{
+			Hooks: map[string]config.Hook{
+				"guard": {Command: "exit 1", On: []string{"before:note"}},
+			},
+		},
+	}
+	ctx := testContextWithConfig(t, cfg, wtPath)
+
+	cmd := newNoteCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"clear"})
+
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected note clear to fail when before hook fails")
+	}
+
+	note, err := runGitCommand(repoPath, "config", "branch.feature.description")
+	if err != nil {
+		t.Fatalf("note was cleared: %v", err)
+	}
+	if strings.TrimSpace(note) != "keep me" {
+		t.Errorf("expected note 'keep me', got %q", strings.TrimSpace(note))
+	}
+}
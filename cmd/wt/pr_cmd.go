@@ -4,14 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"os"
-	"os/exec"
-	"runtime"
+	"maps"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/raphi011/wt/internal/browser"
 	"github.com/raphi011/wt/internal/config"
 	"github.com/raphi011/wt/internal/forge"
 	"github.com/raphi011/wt/internal/git"
@@ -20,6 +21,7 @@ import (
 	"github.com/raphi011/wt/internal/output"
 	"github.com/raphi011/wt/internal/prcache"
 	"github.com/raphi011/wt/internal/registry"
+	"github.com/raphi011/wt/internal/ui/styles"
 	"github.com/raphi011/wt/internal/worktree"
 )
 
@@ -32,14 +34,20 @@ func newPrCmd() *cobra.Command {
 		Example: `  wt pr checkout 123                # Checkout PR from current repo
   wt pr checkout myrepo 123         # Checkout PR from local repo
   wt pr checkout org/repo 123       # Clone repo and checkout PR
+  wt pr comments --unresolved
   wt pr create --title "Add feature"
+  wt pr list --global --review-requested @me
   wt pr merge
+  wt pr sync --all
   wt pr view`,
 	}
 
 	cmd.AddCommand(newPrCheckoutCmd())
+	cmd.AddCommand(newPrCommentsCmd())
 	cmd.AddCommand(newPrCreateCmd())
+	cmd.AddCommand(newPrListCmd())
 	cmd.AddCommand(newPrMergeCmd())
+	cmd.AddCommand(newPrSyncCmd())
 	cmd.AddCommand(newPrViewCmd())
 
 	return cmd
@@ -117,15 +125,21 @@ func newPrCheckoutCmd() *cobra.Command {
 If repo contains '/', it's treated as org/repo and matched against remotes of
 registered repos. Use --clone to clone the repo if no local match is found.
 Otherwise, the repo argument is looked up in the local registry by name.
-Use --clone-mode (with --clone) to control whether the repo is cloned as bare or regular.`,
+Use --clone-mode (with --clone) to control whether the repo is cloned as bare or regular.
+
+For PRs from forks that maintainers may edit, the fork is added as a remote
+named after its owner (or an existing remote for it is reused) and the branch
+tracks the contributor's branch, so git push updates the PR. Other fork PRs
+are fetched from the PR ref on origin and checked out without upstream.`,
 		Example: `  wt pr checkout 123                                    # PR from current repo
   wt pr checkout myrepo 123                             # PR from local repo in registry
   wt pr checkout org/repo 123                           # PR from registered repo matched by remote
   wt pr checkout --clone org/repo 123                   # Clone repo and checkout PR
   wt pr checkout --clone --clone-mode regular org/repo 123  # Regular clone + checkout`,
 		ValidArgsFunction: completePrCheckoutArgs,
-		RunE: func(cmd *cobra.Command, args []string) error {
-			ctx := cmd.Context()
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			ctx, op := startJournalOp(cmd.Context(), "pr checkout")
+			defer func() { op.finish(ctx, err) }()
 			cfg := config.FromContext(ctx)
 			l := log.FromContext(ctx)
 
@@ -148,6 +162,7 @@ Use --clone-mode (with --clone) to control whether the repo is cloned as bare or
 				}
 				prNumber = num
 			}
+			op.Detail = fmt.Sprintf("PR #%d", prNumber)
 
 			// Load registry
 			reg, err := registry.Load(cfg.RegistryPath)
@@ -188,7 +203,7 @@ Use --clone-mode (with --clone) to control whether the repo is cloned as bare or
 					if forgeName == "" {
 						forgeName = cfg.Forge.GetForgeTypeForRepo(orgRepo)
 					}
-					f = forge.ByNameWithConfig(forgeName, &cfg.Forge)
+					f = forge.ByNameForHost(forgeName, cfg.Forge.GetHostForRepo(orgRepo), &cfg.Forge)
 					if err := f.Check(ctx); err != nil {
 						return err
 					}
@@ -221,12 +236,9 @@ Use --clone-mode (with --clone) to control whether the repo is cloned as bare or
 						Name:   repoName,
 						Labels: cfg.DefaultLabels,
 					}
-					if err := reg.Add(repo); err != nil {
+					if err := autoRegisterRepo(cfg, reg, repo); err != nil {
 						return fmt.Errorf("register repo: %w", err)
 					}
-					if err := reg.Save(cfg.RegistryPath); err != nil {
-						return fmt.Errorf("save registry: %w", err)
-					}
 
 					l.Printf("✓ Cloned and registered: %s\n", repoPath)
 				}
@@ -262,105 +274,19 @@ Use --clone-mode (with --clone) to control whether the repo is cloned as bare or
 				}
 			}
 
-			// Get PR branch
-			l.Printf("Fetching PR #%d...\n", prNumber)
-			branch, err := f.GetPRBranch(ctx, originURL, prNumber)
-			if err != nil {
-				return fmt.Errorf("failed to get PR branch: %w", err)
-			}
-
-			l.Debug("pr checkout", "branch", branch, "repo", repoPath)
-
-			// Get worktree format
-			format := repo.GetEffectiveWorktreeFormat(effCfg.Checkout.WorktreeFormat)
-			wtPath := worktree.ResolvePath(repoPath, repo.Name, branch, format)
-
-			// Detect repo type
-			repoType, err := git.DetectRepoType(repoPath)
-			if err != nil {
-				return err
-			}
-			gitDir := git.GetGitDir(repoPath, repoType)
-
-			var found bool
-			var existingPath string
-
-			// Fetch the branch
-			if err := git.FetchBranch(ctx, gitDir, branch); err != nil {
-				l.Printf("Warning: fetch failed: %v\n", err)
-			}
-
-			if justClonedRegular {
-				// Regular clone: checkout PR branch in the working tree directly
-				if err := git.RunGitCommand(ctx, repoPath, "checkout", branch); err != nil {
-					return fmt.Errorf("checkout branch: %w", err)
-				}
-				wtPath = repoPath
-			} else if existingPath, found = findWorktreeForBranch(ctx, repoPath, branch); found {
-				// Worktree already exists for this branch — open it instead of creating
-				wtPath = existingPath
-			} else {
-				// Bare clone or existing repo: create worktree
-				if err := git.CreateWorktree(ctx, gitDir, wtPath, branch); err != nil {
-					return fmt.Errorf("create worktree: %w", err)
-				}
-			}
-
-			// Set upstream - branch was fetched so remote exists
-			if effCfg.Checkout.ShouldSetUpstream() {
-				if err := git.SetUpstreamBranch(ctx, gitDir, branch, branch); err != nil {
-					l.Debug("failed to set upstream", "error", err)
-				}
-			}
-
-			// Cache PR info for the new worktree
-			cache := prcache.Load()
-			prInfo, err := f.GetPRForBranch(ctx, originURL, branch)
-			if err != nil {
-				l.Debug("failed to fetch PR info", "branch", branch, "error", err)
-			} else {
-				cache.Set(prcache.CacheKey(repoPath, branch), prInfo)
-				if err := cache.Save(); err != nil {
-					l.Printf("Warning: failed to save PR cache: %v\n", err)
-				}
-			}
-
-			// Set note if provided
-			if note != "" {
-				if err := git.SetBranchNote(ctx, gitDir, branch, note); err != nil {
-					l.Printf("Warning: failed to set note: %v\n", err)
-				}
-			}
-
-			// Determine output message
-			var outputMsg string
-			if justClonedRegular {
-				outputMsg = fmt.Sprintf("Checked out PR branch: %s (%s)\n", repoPath, branch)
-			} else if found {
-				outputMsg = fmt.Sprintf("Opened worktree: %s (%s)\n", wtPath, branch)
-			} else {
-				outputMsg = fmt.Sprintf("Created worktree: %s (%s)\n", wtPath, branch)
-			}
-
-			// Run hooks around output and history recording
-			hp, err := buildHookParams(effCfg, repo, wtPath, branch, hooks.CommandCheckout, hooks.ActionPR, hf)
-			if err != nil {
-				return err
-			}
-			hp.PRNumber = new(prNumber)
-			if repoPath := forge.ExtractRepoPath(originURL); strings.Contains(repoPath, "/") {
-				hp.PRRepo = repoPath
-			}
-
-			return withHooks(ctx, hp, func() error {
-				fmt.Print(outputMsg)
-				recordHistory(ctx, effCfg, wtPath, repo.Name, branch)
-				return nil
-			})
+			return checkoutPR(ctx, op, prCheckout{
+				repo:      repo,
+				effCfg:    effCfg,
+				originURL: originURL,
+				forge:     f,
+				number:    prNumber,
+				note:      note,
+				inPlace:   justClonedRegular,
+			}, hf)
 		},
 	}
 
-	cmd.Flags().StringVar(&forgeName, "forge", "", "Forge type: github or gitlab")
+	cmd.Flags().StringVar(&forgeName, "forge", "", "Forge type: github, gitlab, gitea or bitbucket")
 	cmd.Flags().BoolVar(&cloneRepo, "clone", false, "Clone the repo if no local match (for org/repo format)")
 	cmd.Flags().StringVar(&cloneMode, "clone-mode", "", "Clone mode: bare or regular (default: config)")
 	cmd.Flags().StringVar(&note, "note", "", "Set a note on the branch")
@@ -369,106 +295,285 @@ Use --clone-mode (with --clone) to control whether the repo is cloned as bare or
 		return []string{"bare", "regular"}, cobra.ShellCompDirectiveNoFileComp
 	})
 	cmd.RegisterFlagCompletionFunc("forge", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		return []string{"github", "gitlab"}, cobra.ShellCompDirectiveNoFileComp
+		return config.ValidForgeTypes, cobra.ShellCompDirectiveNoFileComp
 	})
 	cmd.RegisterFlagCompletionFunc("note", cobra.NoFileCompletions)
 
 	return cmd
 }
 
-func newPrCreateCmd() *cobra.Command {
-	var (
-		title    string
-		body     string
-		bodyFile string
-		base     string
-		draft    bool
-		web      bool
-	)
+// prCheckout describes a PR to check out into a resolved repo.
+type prCheckout struct {
+	repo      registry.Repo
+	effCfg    *config.Config
+	originURL string
+	forge     forge.Forge
+	number    int
+	note      string
+	inPlace   bool // check out in the repo's own working tree (fresh regular clone)
+}
 
-	cmd := &cobra.Command{
-		Use:               "create [repo]",
-		Short:             "Create PR for worktree",
-		Aliases:           []string{"c", "new"},
-		Args:              cobra.MaximumNArgs(1),
-		ValidArgsFunction: completeRepoNames,
-		Long:              `Create a PR for the current branch.`,
-		Example: `  wt pr create --title "Add feature"
-  wt pr create myrepo --title "Add feature"  # Create for specific repo
-  wt pr create --title "Add feature" --body "Details"
-  wt pr create --title "Add feature" --draft
-  wt pr create --title "Add feature" -w      # Open in browser`,
-		RunE: func(cmd *cobra.Command, args []string) error {
-			ctx := cmd.Context()
-			l := log.FromContext(ctx)
-			out := output.FromContext(ctx)
+// checkoutPR checks out PR p into a worktree (or opens the existing one),
+// caches its PR info and runs the checkout hooks. op is filled in with the
+// result.
+func checkoutPR(ctx context.Context, op *journalOp, p prCheckout, hf hookFlags) error {
+	l := log.FromContext(ctx)
 
-			var repoArg string
-			if len(args) > 0 {
-				repoArg = args[0]
-			}
-			res, err := resolveRepoForge(ctx, repoArg)
-			if err != nil {
-				return err
-			}
+	// Get PR branch
+	l.Printf("Fetching PR #%d...\n", p.number)
+	head, err := p.forge.GetPRHead(ctx, p.originURL, p.number)
+	if err != nil {
+		return fmt.Errorf("failed to get PR branch: %w", err)
+	}
 
-			// Read body from file if specified
-			prBody := body
-			if bodyFile != "" {
-				content, err := os.ReadFile(bodyFile)
-				if err != nil {
-					return fmt.Errorf("failed to read body file: %w", err)
-				}
-				prBody = string(content)
-			}
+	// Detect repo type
+	repoType, err := git.DetectRepoType(p.repo.Path)
+	if err != nil {
+		return err
+	}
+	gitDir := git.GetGitDir(p.repo.Path, repoType)
+
+	// Fetch the branch
+	branch := head.Branch
+	prHeadRev := "origin/" + branch
+	var fork *forkBranch
+	if head.IsFork {
+		if fork, err = fetchForkBranch(ctx, gitDir, p, head); err != nil {
+			return err
+		}
+		branch, prHeadRev = fork.local, fork.start
+	} else if err := git.FetchBranch(ctx, gitDir, branch); err != nil {
+		l.Printf("Warning: fetch failed: %v\n", err)
+	}
 
-			l.Debug("pr create", "title", title, "branch", res.branch, "base", base)
+	l.Debug("pr checkout", "branch", branch, "repo", p.repo.Path, "fork", head.RepoPath)
 
-			// Push branch first
-			l.Printf("Pushing branch %s...\n", res.branch)
-			if err := git.RunGitCommand(ctx, res.repo.Path, "push", "-u", "origin", res.branch); err != nil {
-				return fmt.Errorf("push failed: %w", err)
-			}
+	// Get worktree format
+	format := p.repo.GetEffectiveWorktreeFormat(p.effCfg.Checkout.WorktreeFormat)
+	wtPath := worktree.ResolvePath(p.repo.Path, p.repo.Name, branch, format)
 
-			// Create PR
-			l.Printf("Creating PR...\n")
-			result, err := res.forge.CreatePR(ctx, res.originURL, forge.CreatePRParams{
-				Title: title,
-				Body:  prBody,
-				Base:  base,
-				Head:  res.branch,
-				Draft: draft,
-			})
-			if err != nil {
-				return fmt.Errorf("create PR failed: %w", err)
+	var found bool
+	var existingPath string
+
+	// A fork's branch is created locally at the fetched commit
+	newFromFork := fork != nil && !fork.exists
+
+	if p.inPlace {
+		// Regular clone: checkout PR branch in the working tree directly
+		args := []string{"checkout", branch}
+		if newFromFork {
+			args = []string{"checkout", "-b", branch, fork.start}
+		}
+		if err := git.RunGitCommand(ctx, p.repo.Path, args...); err != nil {
+			return fmt.Errorf("checkout branch: %w", err)
+		}
+		wtPath = p.repo.Path
+	} else if existingPath, found = findWorktreeForBranch(ctx, p.repo.Path, branch); found {
+		// Worktree already exists for this branch — open it instead of creating
+		wtPath = existingPath
+	} else if newFromFork {
+		if err := git.CreateWorktreeNewBranch(ctx, gitDir, wtPath, branch, fork.start); err != nil {
+			return fmt.Errorf("create worktree: %w", err)
+		}
+	} else {
+		// Bare clone or existing repo: create worktree
+		if err := git.CreateWorktree(ctx, gitDir, wtPath, branch); err != nil {
+			return fmt.Errorf("create worktree: %w", err)
+		}
+	}
+
+	// Local commits are counted against the fetched head by wt pr sync
+	if prHead, err := git.ResolveCommit(ctx, gitDir, prHeadRev); err == nil {
+		recordPRHead(ctx, gitDir, branch, prHead)
+	}
+
+	if fork != nil {
+		// Mark the branch so a later checkout of this PR reuses it
+		if err := git.SetBranchPR(ctx, gitDir, branch, p.number); err != nil {
+			l.Debug("failed to record PR of branch", "error", err)
+		}
+		// Track the fork's branch so git push goes to the contributor
+		if fork.remote != "" {
+			if err := git.SetUpstreamToRemote(ctx, gitDir, branch, fork.remote, head.Branch); err != nil {
+				l.Printf("Warning: failed to set upstream: %v\n", err)
+			} else if branch != head.Branch {
+				l.Printf("Note: push with 'git push %s HEAD:%s' (local branch is %s)\n", fork.remote, head.Branch, branch)
 			}
+		} else if head.RepoPath != "" {
+			l.Printf("Note: PR #%d is from fork %s, which maintainers can't push to; checked out without upstream\n", p.number, head.RepoPath)
+		}
+	} else if p.effCfg.Checkout.ShouldSetUpstream() {
+		// Set upstream - branch was fetched so remote exists
+		if err := git.SetUpstreamBranch(ctx, gitDir, branch, branch); err != nil {
+			l.Debug("failed to set upstream", "error", err)
+		}
+	}
 
-			out.Printf("Created PR #%d: %s\n", result.Number, result.URL)
+	// Cache PR info for the new worktree. Forks may reuse branch names of
+	// other PRs, so only a match for this PR number is cached.
+	cache := loadPRCache(ctx, p.effCfg)
+	prInfo, err := p.forge.GetPRForBranch(ctx, p.originURL, head.Branch)
+	if err != nil {
+		l.Debug("failed to fetch PR info", "branch", head.Branch, "error", err)
+	} else if prInfo.Number == p.number {
+		cache.Set(prcache.CacheKey(p.repo.Path, branch), prInfo)
+		if err := cache.Save(); err != nil {
+			l.Printf("Warning: failed to save PR cache: %v\n", err)
+		}
+	}
 
-			// Open in browser if requested
-			if web {
-				openBrowser(result.URL)
+	// Set note if provided
+	if p.note != "" {
+		if err := git.SetBranchNote(ctx, gitDir, branch, p.note); err != nil {
+			l.Printf("Warning: failed to set note: %v\n", err)
+		}
+	}
+
+	// Determine output message
+	var outputMsg string
+	if p.inPlace {
+		outputMsg = fmt.Sprintf("Checked out PR branch: %s (%s)\n", p.repo.Path, branch)
+	} else if found {
+		outputMsg = fmt.Sprintf("Opened worktree: %s (%s)\n", wtPath, branch)
+	} else {
+		outputMsg = fmt.Sprintf("Created worktree: %s (%s)\n", wtPath, branch)
+	}
+
+	op.setRepo(p.repo)
+	op.Branch = branch
+	op.Path = wtPath
+	op.SHA, _ = git.GetHeadCommit(ctx, wtPath) //nolint:errcheck
+
+	// Run hooks around output and history recording
+	hp, err := buildHookParams(p.effCfg, p.repo, wtPath, branch, hooks.CommandCheckout, hooks.ActionPR, hf)
+	if err != nil {
+		return err
+	}
+	setPRHookParams(&hp, p.originURL, p.number, "")
+
+	return withHooks(ctx, hp, func() error {
+		fmt.Print(outputMsg)
+		recordHistory(ctx, p.effCfg, wtPath, p.repo.Name, branch)
+		return nil
+	})
+}
+
+// setPRHookParams sets the PR placeholders of hp for PR number of the repo
+// at originURL.
+func setPRHookParams(hp *hookParams, originURL string, number int, url string) {
+	hp.PRNumber = new(number)
+	if repoPath := forge.ExtractRepoPath(originURL); strings.Contains(repoPath, "/") {
+		hp.PRRepo = repoPath
+	}
+	hp.PRURL = url
+}
+
+// forkBranch is the local branch for a PR from a fork.
+type forkBranch struct {
+	local  string // local branch name
+	remote string // remote of the fork; empty if fetched via the PR ref on origin
+	start  string // commit or remote-tracking branch to create the local branch at
+	exists bool   // local branch exists (from an earlier checkout)
+}
+
+// fetchForkBranch fetches the branch of a PR from a fork. If maintainers may
+// push to the fork's branch (or the forge has no PR ref), the fork is
+// fetched from a remote: an existing one pointing at the fork, or a new one
+// named after the fork's owner. Otherwise the PR ref is fetched from origin.
+func fetchForkBranch(ctx context.Context, gitDir string, p prCheckout, head *forge.PRHead) (*forkBranch, error) {
+	l := log.FromContext(ctx)
+
+	fb := &forkBranch{}
+	prRef := p.forge.PRHeadRef(p.number)
+	switch {
+	case head.CloneURL != "" && (head.MaintainerCanModify || prRef == ""):
+		remotes, err := git.GetRemoteURLs(ctx, gitDir)
+		if err != nil {
+			return nil, err
+		}
+		remote, exists := forkRemoteName(remotes, head.RepoPath)
+		if remote == "" {
+			return nil, fmt.Errorf("no free remote name for fork %s", head.RepoPath)
+		}
+		if !exists {
+			if err := git.AddRemote(ctx, gitDir, remote, head.CloneURL); err != nil {
+				return nil, err
 			}
+			l.Printf("Added remote %s for fork %s\n", remote, head.RepoPath)
+		}
+		if err := git.FetchBranchFromRemote(ctx, gitDir, remote, head.Branch); err != nil {
+			return nil, err
+		}
+		fb.remote, fb.start = remote, remote+"/"+head.Branch
+	case prRef != "":
+		sha, err := git.FetchRef(ctx, gitDir, "origin", prRef)
+		if err != nil {
+			return nil, err
+		}
+		fb.start = sha
+	default:
+		return nil, fmt.Errorf("PR #%d is from a fork that no longer exists", p.number)
+	}
 
-			return nil
-		},
+	if err := chooseForkLocalBranch(ctx, gitDir, fb, head, p.number); err != nil {
+		return nil, err
 	}
+	return fb, nil
+}
 
-	cmd.Flags().StringVarP(&title, "title", "t", "", "PR title")
-	cmd.Flags().StringVarP(&body, "body", "b", "", "PR body")
-	cmd.Flags().StringVar(&bodyFile, "body-file", "", "Read body from file")
-	cmd.Flags().StringVar(&base, "base", "", "Base branch")
-	cmd.Flags().BoolVar(&draft, "draft", false, "Create as draft PR")
-	cmd.Flags().BoolVarP(&web, "web", "w", false, "Open in browser after creation")
+// chooseForkLocalBranch sets the local branch of fb for PR number: the
+// fork's branch name, or else <owner>-<branch>. An existing branch is only
+// reused if an earlier checkout created it for this PR (see
+// [git.GetBranchPR]) or it tracks the fork's branch. Any other branch of
+// the same name, like the user's own main, is left alone.
+func chooseForkLocalBranch(ctx context.Context, gitDir string, fb *forkBranch, head *forge.PRHead, number int) error {
+	owner := forkOwner(head.RepoPath)
+	if owner == "" {
+		owner = fmt.Sprintf("pr-%d", number)
+	}
+	for _, name := range []string{head.Branch, owner + "-" + head.Branch} {
+		if !git.LocalBranchExists(ctx, gitDir, name) {
+			fb.local = name
+			return nil
+		}
+		if pr, _ := git.GetBranchPR(ctx, gitDir, name); pr == number {
+			fb.local, fb.exists = name, true
+			return nil
+		}
+		if fb.remote != "" && git.GetUpstreamRemote(ctx, gitDir, name) == fb.remote && git.GetUpstreamBranch(ctx, gitDir, name) == head.Branch {
+			fb.local, fb.exists = name, true
+			return nil
+		}
+	}
+	return fmt.Errorf("local branches %s and %s-%s already exist and weren't checked out for PR #%d", head.Branch, owner, head.Branch, number)
+}
 
-	cmd.MarkFlagRequired("title")
-	cmd.MarkFlagFilename("body-file") // Enable file completion for body-file flag
-	cmd.MarkFlagsMutuallyExclusive("body", "body-file")
-	cmd.RegisterFlagCompletionFunc("base", completeBranches)
-	cmd.RegisterFlagCompletionFunc("title", cobra.NoFileCompletions)
-	cmd.RegisterFlagCompletionFunc("body", cobra.NoFileCompletions)
+// forkRemoteName returns the remote for the fork repoPath: an existing remote
+// whose URL points at the fork (exists), or else a free name derived from
+// the fork's owner. Returns "" if no name is free.
+func forkRemoteName(remotes map[string]string, repoPath string) (name string, exists bool) {
+	for _, name := range slices.Sorted(maps.Keys(remotes)) {
+		if strings.EqualFold(forge.ExtractRepoPath(remotes[name]), repoPath) {
+			return name, true
+		}
+	}
+	owner := forkOwner(repoPath)
+	if owner == "" {
+		return "", false
+	}
+	for _, name := range []string{owner, owner + "-fork"} {
+		if _, taken := remotes[name]; !taken {
+			return name, false
+		}
+	}
+	return "", false
+}
 
-	return cmd
+// forkOwner returns the owner of a fork's repo path, usable in remote and
+// branch names (Bitbucket's "~user" personal projects lose the "~").
+func forkOwner(repoPath string) string {
+	owner, _, _ := strings.Cut(repoPath, "/")
+	return strings.TrimPrefix(owner, "~")
 }
 
 func newPrMergeCmd() *cobra.Command {
@@ -486,13 +591,18 @@ func newPrMergeCmd() *cobra.Command {
 		ValidArgsFunction: completeRepoNames,
 		Long: `Merge the PR for the current branch.
 
-Merges the PR, removes the worktree (if applicable), and deletes the local branch.`,
+Merges the PR, removes the worktree (if applicable), and deletes the local branch.
+
+Hooks with on = ["before:pr:merge"] run before the PR is merged and abort
+the command if they fail, e.g. to run the tests. "merge" is an alias of
+"pr:merge".`,
 		Example: `  wt pr merge                  # Merge current branch's PR
   wt pr merge myrepo           # Merge for specific repo
   wt pr merge --keep           # Keep worktree after merge
   wt pr merge -s rebase        # Use rebase strategy`,
-		RunE: func(cmd *cobra.Command, args []string) error {
-			ctx := cmd.Context()
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			ctx, op := startJournalOp(cmd.Context(), "pr merge")
+			defer func() { op.finish(ctx, err) }()
 			l := log.FromContext(ctx)
 			out := output.FromContext(ctx)
 
@@ -510,6 +620,9 @@ Merges the PR, removes the worktree (if applicable), and deletes the local branc
 				strategy = res.effCfg.Merge.Strategy
 			}
 
+			op.setRepo(res.repo)
+			op.Branch = res.branch
+
 			l.Debug("pr merge", "branch", res.branch, "strategy", strategy)
 
 			// Get PR for branch
@@ -519,14 +632,22 @@ Merges the PR, removes the worktree (if applicable), and deletes the local branc
 			}
 
 			// Load PR cache for updates
-			cache := prcache.Load()
+			cache := loadPRCache(ctx, res.effCfg)
 			cacheKey := prcache.CacheKey(res.repo.Path, res.branch)
 
 			cwd := config.WorkDirFromContext(ctx)
-			hp, err := buildHookParams(res.effCfg, res.repo, cwd, res.branch, hooks.CommandMerge, "", hf)
+			op.Path = cwd
+			op.SHA, _ = git.GetHeadCommit(ctx, cwd) //nolint:errcheck
+			op.Detail = fmt.Sprintf("PR #%d", pr.Number)
+			if strategy != "" {
+				op.Detail += " (" + strategy + ")"
+			}
+
+			hp, err := buildHookParams(res.effCfg, res.repo, cwd, res.branch, hooks.CommandPR, hooks.ActionMerge, hf)
 			if err != nil {
 				return err
 			}
+			setPRHookParams(&hp, res.originURL, pr.Number, pr.URL)
 
 			return withHooks(ctx, hp, func() error {
 				if pr.State == forge.PRStateMerged {
@@ -557,6 +678,7 @@ Merges the PR, removes the worktree (if applicable), and deletes the local branc
 						l.Printf("Warning: failed to remove worktree: %v\n", err)
 					} else {
 						out.Printf("Removed worktree: %s\n", cwd)
+						op.addDetail("removed worktree")
 						// Remove from cache since worktree no longer exists
 						cache.Delete(cacheKey)
 						if err := cache.SaveIfDirty(); err != nil {
@@ -616,17 +738,37 @@ func newPrViewCmd() *cobra.Command {
 			}
 
 			if web {
-				openBrowser(pr.URL)
-				return nil
+				return browser.Open(ctx, pr.URL)
 			}
 
 			// Display PR info
 			out.Printf("PR #%d\n", pr.Number)
+			if pr.Title != "" {
+				out.Printf("Title: %s\n", pr.Title)
+			}
 			out.Printf("State: %s\n", pr.State)
 			out.Printf("URL: %s\n", pr.URL)
 			if pr.Author != "" {
 				out.Printf("Author: %s\n", pr.Author)
 			}
+			if pr.BaseBranch != "" {
+				out.Printf("Base: %s\n", pr.BaseBranch)
+			}
+			if pr.Checks != "" {
+				out.Printf("Checks: %s\n", styles.FormatChecks(pr.Checks))
+			}
+			if pr.Mergeable != "" {
+				out.Printf("Mergeable: %s\n", strings.ToLower(pr.Mergeable))
+			}
+			if len(pr.ReviewRequested) > 0 {
+				out.Printf("Review requested: %s\n", strings.Join(pr.ReviewRequested, ", "))
+			}
+			if len(pr.Labels) > 0 {
+				out.Printf("Labels: %s\n", strings.Join(pr.Labels, ", "))
+			}
+			if !pr.UpdatedAt.IsZero() {
+				out.Printf("Updated: %s\n", pr.UpdatedAt.Local().Format(time.DateTime))
+			}
 
 			return nil
 		},
@@ -636,37 +778,3 @@ func newPrViewCmd() *cobra.Command {
 
 	return cmd
 }
-
-// openBrowser opens a URL in the default browser
-func openBrowser(url string) {
-	var cmd string
-	var args []string
-
-	switch {
-	case isWSL():
-		cmd = "wslview"
-		args = []string{url}
-	case isMac():
-		cmd = "open"
-		args = []string{url}
-	default:
-		cmd = "xdg-open"
-		args = []string{url}
-	}
-
-	_ = runCommand(cmd, args...)
-}
-
-func isWSL() bool {
-	_, err := os.Stat("/proc/sys/fs/binfmt_misc/WSLInterop")
-	return err == nil
-}
-
-func isMac() bool {
-	return runtime.GOOS == "darwin"
-}
-
-func runCommand(name string, args ...string) error {
-	cmd := exec.Command(name, args...)
-	return cmd.Run()
-}
@@ -0,0 +1,64 @@
+package main
+
+import "testing"
+
+func TestForkRemoteName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		remotes    map[string]string
+		repoPath   string
+		wantName   string
+		wantExists bool
+	}{
+		{
+			name:     "new remote named after owner",
+			remotes:  map[string]string{"origin": "git@github.com:org/repo.git"},
+			repoPath: "alice/repo",
+			wantName: "alice",
+		},
+		{
+			name: "existing remote for the fork",
+			remotes: map[string]string{
+				"origin":  "git@github.com:org/repo.git",
+				"contrib": "https://github.com/Alice/repo.git",
+			},
+			repoPath:   "alice/repo",
+			wantName:   "contrib",
+			wantExists: true,
+		},
+		{
+			name: "owner name taken by another repo",
+			remotes: map[string]string{
+				"origin": "git@github.com:org/repo.git",
+				"alice":  "git@github.com:alice/other.git",
+			},
+			repoPath: "alice/repo",
+			wantName: "alice-fork",
+		},
+		{
+			name: "no free name",
+			remotes: map[string]string{
+				"alice":      "git@github.com:alice/other.git",
+				"alice-fork": "git@github.com:alice/another.git",
+			},
+			repoPath: "alice/repo",
+		},
+		{
+			name:     "bitbucket personal project",
+			remotes:  map[string]string{"origin": "https://bitbucket.corp/scm/PROJ/repo.git"},
+			repoPath: "~JDOE/repo",
+			wantName: "JDOE",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, exists := forkRemoteName(tt.remotes, tt.repoPath)
+			if name != tt.wantName || exists != tt.wantExists {
+				t.Errorf("forkRemoteName() = %q, %v; want %q, %v", name, exists, tt.wantName, tt.wantExists)
+			}
+		})
+	}
+}
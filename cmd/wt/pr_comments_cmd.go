@@ -0,0 +1,232 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/raphi011/wt/internal/config"
+	"github.com/raphi011/wt/internal/forge"
+	"github.com/raphi011/wt/internal/git"
+	"github.com/raphi011/wt/internal/log"
+	"github.com/raphi011/wt/internal/output"
+	"github.com/raphi011/wt/internal/prcache"
+	"github.com/raphi011/wt/internal/registry"
+)
+
+func newPrCommentsCmd() *cobra.Command {
+	var (
+		unresolved bool
+		jsonOutput bool
+		quickfix   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "comments [[scope:]branch]",
+		Short: "Show review comments of a worktree's PR",
+		Args:  cobra.MaximumNArgs(1),
+		Long: `Show the review threads of a worktree's PR, grouped by file.
+
+Only comments attached to a file are shown; general PR comments are not.
+Each thread shows its line, whether it is resolved or outdated (the code
+changed since), and its comments in order.
+
+Target a worktree using [scope:]branch. With no arguments, shows the PR of
+the current worktree.
+
+Use --quickfix to print one "file:line: message" line per thread with
+absolute paths into the worktree, which editors can load as a location
+list (e.g. vim -q, :cexpr, Emacs compilation-mode).`,
+		Example: `  wt pr comments                     # Review threads of the current PR
+  wt pr comments feature-x           # Threads of the PR of feature-x
+  wt pr comments myrepo:feature-x    # Worktree in a specific repo
+  wt pr comments --unresolved        # Only threads still open
+  wt pr comments --json              # Output as JSON
+  vim -q <(wt pr comments -u --quickfix)`,
+		ValidArgsFunction: completeCdArg,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			cfg := config.FromContext(ctx)
+			l := log.FromContext(ctx)
+			out := output.FromContext(ctx)
+
+			reg, err := registry.Load(cfg.RegistryPath)
+			if err != nil {
+				return fmt.Errorf("load registry: %w", err)
+			}
+
+			var repoPath, branch, wtPath string
+			if len(args) > 0 {
+				t, err := resolveOneWorktreeTarget(ctx, reg, args[0])
+				if err != nil {
+					return err
+				}
+				repoPath, branch, wtPath = t.RepoPath, t.Branch, t.Path
+			} else {
+				repo, err := findOrRegisterCurrentRepoFromContext(ctx, reg)
+				if err != nil {
+					return err
+				}
+				if branch, err = git.GetCurrentBranch(ctx, config.WorkDirFromContext(ctx)); err != nil {
+					return err
+				}
+				path, found := findWorktreeForBranch(ctx, repo.Path, branch)
+				if !found {
+					return fmt.Errorf("not in a worktree (specify a branch)")
+				}
+				repoPath, wtPath = repo.Path, path
+			}
+
+			originURL, err := git.GetOriginURL(ctx, repoPath)
+			if err != nil {
+				return fmt.Errorf("failed to get origin URL: %w", err)
+			}
+			effCfg := resolveEffectiveConfig(ctx, repoPath)
+			f := forge.Detect(originURL, effCfg.Hosts, &effCfg.Forge)
+			if err := f.Check(ctx); err != nil {
+				return err
+			}
+
+			// Use the cached PR number if there is one, else look it up
+			prCache := loadPRCache(ctx, cfg)
+			key := prcache.CacheKey(repoPath, branch)
+			pr := prCache.Get(key)
+			if pr == nil || !pr.Fetched || pr.Number == 0 {
+				if pr, err = f.GetPRForBranch(ctx, originURL, branch); err != nil {
+					return fmt.Errorf("failed to fetch PR for %s: %w", branch, err)
+				}
+				prCache.Set(key, pr)
+				if err := prCache.SaveIfDirty(); err != nil {
+					l.Printf("Warning: failed to save PR cache: %v\n", err)
+				}
+			}
+			if pr.Number == 0 {
+				return fmt.Errorf("no PR found for branch %s", branch)
+			}
+
+			l.Debug("pr comments", "branch", branch, "pr", pr.Number)
+
+			threads, err := f.ListReviewThreads(ctx, originURL, pr.Number)
+			if err != nil {
+				return fmt.Errorf("failed to fetch review comments: %w", err)
+			}
+			if unresolved {
+				threads = filterUnresolvedThreads(threads)
+			}
+
+			switch {
+			case jsonOutput:
+				entries := make([]prCommentThread, len(threads))
+				for i, t := range threads {
+					entries[i] = prCommentThread{ReviewThread: t, File: filepath.Join(wtPath, filepath.FromSlash(t.Path))}
+				}
+				enc := json.NewEncoder(out.Writer())
+				enc.SetIndent("", "  ")
+				return enc.Encode(entries)
+			case quickfix:
+				out.Print(formatQuickfix(threads, wtPath))
+			case len(threads) == 0 && unresolved:
+				out.Printf("No unresolved review comments on PR #%d\n", pr.Number)
+			case len(threads) == 0:
+				out.Printf("No review comments on PR #%d\n", pr.Number)
+			default:
+				out.Print(renderReviewThreads(threads))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&unresolved, "unresolved", "u", false, "Only show unresolved threads")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+	cmd.Flags().BoolVar(&quickfix, "quickfix", false, "Output file:line: message lines for an editor location list")
+
+	cmd.MarkFlagsMutuallyExclusive("json", "quickfix")
+
+	return cmd
+}
+
+// prCommentThread is a review thread as printed by wt pr comments --json.
+type prCommentThread struct {
+	forge.ReviewThread
+	File string `json:"file"` // absolute path of the file in the worktree
+}
+
+// filterUnresolvedThreads returns the threads that aren't resolved yet.
+func filterUnresolvedThreads(threads []forge.ReviewThread) []forge.ReviewThread {
+	var open []forge.ReviewThread
+	for _, t := range threads {
+		if !t.Resolved {
+			open = append(open, t)
+		}
+	}
+	return open
+}
+
+// reviewThreadStatus describes a thread's state, e.g. "resolved, outdated".
+func reviewThreadStatus(t forge.ReviewThread) string {
+	status := "unresolved"
+	if t.Resolved {
+		status = "resolved"
+	}
+	if t.Outdated {
+		status += ", outdated"
+	}
+	return status
+}
+
+// renderReviewThreads renders threads (sorted by file) grouped under a
+// header per file, each comment indented below its thread's line.
+func renderReviewThreads(threads []forge.ReviewThread) string {
+	var b strings.Builder
+	var path string
+	for i, t := range threads {
+		if i == 0 || t.Path != path {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			path = t.Path
+			b.WriteString(path + "\n")
+		}
+
+		line := "-"
+		if t.Line > 0 {
+			line = fmt.Sprintf("L%d", t.Line)
+		}
+		fmt.Fprintf(&b, "  %s (%s)\n", line, reviewThreadStatus(t))
+		for _, c := range t.Comments {
+			body := strings.Split(strings.TrimSpace(c.Body), "\n")
+			fmt.Fprintf(&b, "    %s: %s\n", c.Author, strings.TrimRight(body[0], "\r"))
+			for _, l := range body[1:] {
+				fmt.Fprintf(&b, "      %s\n", strings.TrimRight(l, "\r"))
+			}
+		}
+	}
+	return b.String()
+}
+
+// formatQuickfix formats one "file:line: message" line per thread, with the
+// file's absolute path in the worktree at wtPath, as understood by editors'
+// default error formats. The message is the first line of the thread's
+// first comment.
+func formatQuickfix(threads []forge.ReviewThread, wtPath string) string {
+	var b strings.Builder
+	for _, t := range threads {
+		var msg string
+		if len(t.Comments) > 0 {
+			c := t.Comments[0]
+			first, _, _ := strings.Cut(strings.TrimSpace(c.Body), "\n")
+			msg = c.Author + ": " + strings.TrimRight(first, "\r")
+			if n := len(t.Comments) - 1; n > 0 {
+				msg += fmt.Sprintf(" (+%d more)", n)
+			}
+		}
+		if t.Resolved || t.Outdated {
+			msg = "[" + reviewThreadStatus(t) + "] " + msg
+		}
+		fmt.Fprintf(&b, "%s:%d: %s\n", filepath.Join(wtPath, filepath.FromSlash(t.Path)), max(t.Line, 1), msg)
+	}
+	return b.String()
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/raphi011/wt/internal/forge"
+)
+
+// testReviewThreads are sorted by file and line, as returned by forges.
+var testReviewThreads = []forge.ReviewThread{
+	{Path: "cmd/app.go", Line: 3, Resolved: true, Outdated: true, Comments: []forge.ReviewComment{
+		{Author: "carol", Body: "Typo"},
+	}},
+	{Path: "main.go", Line: 12, Comments: []forge.ReviewComment{
+		{Author: "bob", Body: "Handle the error\r\nand log it"},
+		{Author: "alice", Body: "Done"},
+	}},
+	{Path: "main.go", Line: 30, Outdated: true, Comments: []forge.ReviewComment{
+		{Author: "bob", Body: "Why?"},
+	}},
+	{Path: "docs/README.md", Comments: []forge.ReviewComment{
+		{Author: "dave", Body: "Needs an example"},
+	}},
+}
+
+func TestFilterUnresolvedThreads(t *testing.T) {
+	t.Parallel()
+
+	got := filterUnresolvedThreads(testReviewThreads)
+	want := testReviewThreads[1:]
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("filterUnresolvedThreads() = %+v, want %+v", got, want)
+	}
+	if got := filterUnresolvedThreads(testReviewThreads[:1]); got != nil {
+		t.Errorf("filterUnresolvedThreads() with only resolved = %+v, want nil", got)
+	}
+}
+
+func TestRenderReviewThreads(t *testing.T) {
+	t.Parallel()
+
+	got := renderReviewThreads(testReviewThreads)
+	want := `cmd/app.go
+  L3 (resolved, outdated)
+    carol: Typo
+
+main.go
+  L12 (unresolved)
+    bob: Handle the error
+      and log it
+    alice: Done
+  L30 (unresolved, outdated)
+    bob: Why?
+
+docs/README.md
+  - (unresolved)
+    dave: Needs an example
+`
+	if got != want {
+		t.Errorf("renderReviewThreads() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestFormatQuickfix(t *testing.T) {
+	t.Parallel()
+
+	got := formatQuickfix(testReviewThreads, "/work/repo-feature")
+	want := `/work/repo-feature/cmd/app.go:3: [resolved, outdated] carol: Typo
+/work/repo-feature/main.go:12: bob: Handle the error (+1 more)
+/work/repo-feature/main.go:30: [unresolved, outdated] bob: Why?
+/work/repo-feature/docs/README.md:1: dave: Needs an example
+`
+	if got != want {
+		t.Errorf("formatQuickfix() =\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,442 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+
+	"github.com/raphi011/wt/internal/browser"
+	"github.com/raphi011/wt/internal/config"
+	"github.com/raphi011/wt/internal/forge"
+	"github.com/raphi011/wt/internal/git"
+	"github.com/raphi011/wt/internal/hooks"
+	"github.com/raphi011/wt/internal/log"
+	"github.com/raphi011/wt/internal/output"
+	"github.com/raphi011/wt/internal/prcache"
+)
+
+// prPushAction is what wt pr create does with the branch before creating the PR.
+type prPushAction int
+
+const (
+	prPushUpToDate    prPushAction = iota // remote branch matches the local branch
+	prPushNew                             // remote branch doesn't exist: push it
+	prPushFastForward                     // local commits on top of the remote branch: push them
+	prPushForce                           // diverged, --force-with-lease given: replace the remote branch
+	prPushBehind                          // remote branch has commits the local one lacks: don't push
+	prPushDiverged                        // diverged without --force-with-lease: refuse
+)
+
+// planPRPush decides whether the branch must be pushed before creating a PR,
+// given whether the remote branch exists and how far the local branch is
+// ahead of and behind it.
+func planPRPush(remoteExists bool, ahead, behind int, forceWithLease bool) prPushAction {
+	switch {
+	case !remoteExists:
+		return prPushNew
+	case ahead == 0 && behind == 0:
+		return prPushUpToDate
+	case behind == 0:
+		return prPushFastForward
+	case ahead == 0:
+		return prPushBehind
+	case forceWithLease:
+		return prPushForce
+	default:
+		return prPushDiverged
+	}
+}
+
+func newPrCreateCmd() *cobra.Command {
+	var (
+		title    string
+		body     string
+		bodyFile string
+		base     string
+		draft    bool
+		web      bool
+		fill     bool
+		force    bool
+		hf       hookFlags
+	)
+
+	cmd := &cobra.Command{
+		Use:               "create [repo]",
+		Short:             "Create PR for worktree",
+		Aliases:           []string{"c", "new"},
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeRepoNames,
+		Long: `Create a PR for the current branch.
+
+Without --base, the PR targets the default branch, or the parent of a
+stacked branch (see 'wt stack').
+
+Without --body or --body-file, the body is the repo's PR template
+(.github/pull_request_template.md, .gitlab/merge_request_templates/*, ...).
+
+Without --title, the title and body are generated from the branch's commits
+since origin/<base>: a single commit gives its subject and message, several
+commits the branch name and a list of their subjects (a PR template still
+takes precedence for the body). The generated title is formatted with
+[pr] title_format, whose placeholders are {title}, {branch}, {note} (the
+branch note) and {repo}. The result is opened in $VISUAL or $EDITOR for a
+final edit: the first line is the title, the rest the body. Use --fill to
+skip the editor.
+
+Before the PR is created, the branch is pushed if it is missing on the
+remote or has local commits the remote branch lacks, and its upstream is
+set. The branch's existing upstream is used, or else origin/<branch>. A
+branch that diverged from its remote branch (e.g. after a rebase) is only
+pushed with --force-with-lease.
+
+The new PR is stored in the PR cache right away, so wt list shows it
+without a refresh.
+
+Hooks with on = ["before:pr:create"] run before the title is generated and
+the branch is pushed, and abort the command if they fail; "pr:create" hooks
+run after the PR was created, with {pr-number} and {pr-url} set.`,
+		Example: `  wt pr create                               # Generate title/body, edit in $EDITOR
+  wt pr create --fill                        # Generate title/body, no editor
+  wt pr create --title "Add feature"
+  wt pr create myrepo --title "Add feature"  # Create for specific repo
+  wt pr create --title "Add feature" --body "Details"
+  wt pr create --title "Add feature" --draft
+  wt pr create --title "Add feature" -w      # Open in browser
+  wt pr create --fill --force-with-lease     # Push a rebased branch`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			l := log.FromContext(ctx)
+			out := output.FromContext(ctx)
+
+			var repoArg string
+			if len(args) > 0 {
+				repoArg = args[0]
+			}
+			res, err := resolveRepoForge(ctx, repoArg)
+			if err != nil {
+				return err
+			}
+			cwd := config.WorkDirFromContext(ctx)
+
+			// Stacked branches target their parent
+			if base == "" {
+				if base, err = git.GetBranchParent(ctx, res.repo.Path, res.branch); err != nil {
+					return err
+				}
+				if base != "" {
+					l.Printf("Using parent branch %s as base\n", base)
+					if !git.RemoteBranchExists(ctx, res.repo.Path, base) {
+						l.Printf("Warning: parent branch %s is not pushed to origin\n", base)
+					}
+				}
+			}
+
+			hp, err := buildHookParams(res.effCfg, res.repo, cwd, res.branch, hooks.CommandPR, hooks.ActionCreate, hf)
+			if err != nil {
+				return err
+			}
+			if err := runHooks(ctx, hp, hooks.PhaseBefore); err != nil {
+				return err
+			}
+
+			// Read body from file if specified
+			prBody := body
+			if bodyFile != "" {
+				content, err := os.ReadFile(bodyFile)
+				if err != nil {
+					return fmt.Errorf("failed to read body file: %w", err)
+				}
+				prBody = string(content)
+			}
+			hasBody := cmd.Flags().Changed("body") || bodyFile != ""
+			template := forge.FindPRTemplate(cwd, res.forge.Name())
+			if !hasBody {
+				prBody = template
+			}
+
+			if title == "" {
+				title, prBody = generatePRMessage(ctx, res, cwd, base, prBody, hasBody || template != "")
+				if !fill && isatty.IsTerminal(os.Stdin.Fd()) {
+					if title, prBody, err = editPRMessage(ctx, title, prBody); err != nil {
+						return err
+					}
+				}
+				if title == "" {
+					return fmt.Errorf("PR title is empty")
+				}
+			}
+
+			l.Debug("pr create", "title", title, "branch", res.branch, "base", base)
+
+			// Push branch first
+			head, err := pushPRBranch(ctx, res.repo.Path, res.branch, force)
+			if err != nil {
+				return err
+			}
+
+			// Create PR
+			l.Printf("Creating PR...\n")
+			result, err := res.forge.CreatePR(ctx, res.originURL, forge.CreatePRParams{
+				Title: title,
+				Body:  prBody,
+				Base:  base,
+				Head:  head,
+				Draft: draft,
+			})
+			if err != nil {
+				return fmt.Errorf("create PR failed: %w", err)
+			}
+
+			// Cache the new PR so wt list shows it without a refresh
+			cache := loadPRCache(ctx, config.FromContext(ctx))
+			cache.Set(prcache.CacheKey(res.repo.Path, res.branch), &forge.PRInfo{
+				Number:     result.Number,
+				State:      forge.PRStateOpen,
+				IsDraft:    draft,
+				URL:        result.URL,
+				Title:      title,
+				BaseBranch: base,
+				Fetched:    true,
+				CachedAt:   time.Now(),
+			})
+			if err := cache.Save(); err != nil {
+				l.Printf("Warning: failed to save PR cache: %v\n", err)
+			}
+
+			out.Printf("Created PR #%d: %s\n", result.Number, result.URL)
+
+			// Open in browser if requested
+			if web {
+				if err := browser.Open(ctx, result.URL); err != nil {
+					l.Printf("Warning: failed to open browser: %v\n", err)
+				}
+			}
+
+			setPRHookParams(&hp, res.originURL, result.Number, result.URL)
+			return runHooks(ctx, hp, hooks.PhaseAfter)
+		},
+	}
+
+	cmd.Flags().StringVarP(&title, "title", "t", "", "PR title (default: generated from commits)")
+	cmd.Flags().StringVarP(&body, "body", "b", "", "PR body")
+	cmd.Flags().StringVar(&bodyFile, "body-file", "", "Read body from file")
+	cmd.Flags().StringVar(&base, "base", "", "Base branch")
+	cmd.Flags().BoolVar(&draft, "draft", false, "Create as draft PR")
+	cmd.Flags().BoolVarP(&web, "web", "w", false, "Open in browser after creation")
+	cmd.Flags().BoolVar(&fill, "fill", false, "Use the generated title and body without opening an editor")
+	cmd.Flags().BoolVar(&force, "force-with-lease", false, "Push a branch that diverged from its remote branch (e.g. after a rebase)")
+	registerHookFlags(cmd, &hf)
+
+	cmd.MarkFlagFilename("body-file") // Enable file completion for body-file flag
+	cmd.MarkFlagsMutuallyExclusive("body", "body-file")
+	cmd.MarkFlagsMutuallyExclusive("title", "fill")
+	cmd.RegisterFlagCompletionFunc("base", completeBranches)
+	cmd.RegisterFlagCompletionFunc("title", cobra.NoFileCompletions)
+	cmd.RegisterFlagCompletionFunc("body", cobra.NoFileCompletions)
+
+	return cmd
+}
+
+// pushPRBranch pushes branch to its upstream (origin/<branch> if it has
+// none) unless the remote branch is already up to date, as decided by
+// planPRPush, and makes sure the upstream is set. It returns the name of the
+// remote branch to open the PR from.
+func pushPRBranch(ctx context.Context, repoPath, branch string, forceWithLease bool) (string, error) {
+	l := log.FromContext(ctx)
+
+	remote := git.GetUpstreamRemote(ctx, repoPath, branch)
+	remoteBranch := git.GetUpstreamBranch(ctx, repoPath, branch)
+	hasUpstream := remote != "" && remoteBranch != ""
+	if !hasUpstream {
+		remote, remoteBranch = "origin", branch
+	}
+	remoteRef := remote + "/" + remoteBranch
+
+	// A failed fetch usually means the branch was never pushed; otherwise
+	// the push below reports the actual problem.
+	var ahead, behind int
+	remoteHead, err := git.FetchRef(ctx, repoPath, remote, "refs/heads/"+remoteBranch)
+	remoteExists := err == nil
+	if err != nil {
+		l.Debug("failed to fetch remote branch", "ref", remoteRef, "error", err)
+	} else if ahead, behind, err = git.GetAheadBehind(ctx, repoPath, "refs/heads/"+branch, remoteHead); err != nil {
+		return "", err
+	}
+
+	action := planPRPush(remoteExists, ahead, behind, forceWithLease)
+	switch action {
+	case prPushDiverged:
+		return "", fmt.Errorf("branch %s diverged from %s (%d ahead, %d behind): rebase onto it or use --force-with-lease", branch, remoteRef, ahead, behind)
+	case prPushBehind:
+		l.Printf("Warning: %s is %d commit(s) behind %s, not pushing\n", branch, behind, remoteRef)
+	case prPushNew, prPushFastForward, prPushForce:
+		l.Printf("Pushing branch %s to %s...\n", branch, remoteRef)
+		var lease string
+		if action == prPushForce {
+			lease = remoteHead
+		}
+		if err := git.PushBranchTo(ctx, repoPath, remote, branch, remoteBranch, lease); err != nil {
+			return "", err
+		}
+		return remoteBranch, nil
+	}
+
+	if !hasUpstream {
+		if err := git.SetUpstreamToRemote(ctx, repoPath, branch, remote, remoteBranch); err != nil {
+			return "", err
+		}
+	}
+	return remoteBranch, nil
+}
+
+// generatePRMessage returns a PR title and body for the branch checked out
+// at dir, derived from its commits since origin/<base> (the default branch
+// if base is empty). The title is formatted with [pr] title_format. body is
+// kept if keepBody (given explicitly or from a template).
+func generatePRMessage(ctx context.Context, res *repoForgeResult, dir, base, body string, keepBody bool) (string, string) {
+	l := log.FromContext(ctx)
+
+	if base == "" {
+		base = git.GetDefaultBranch(ctx, dir)
+	}
+	commits, err := git.ListCommits(ctx, dir, "origin/"+base)
+	if err != nil {
+		l.Debug("failed to list commits", "base", base, "error", err)
+	}
+
+	title, commitBody := prDefaults(res.branch, commits)
+	if !keepBody {
+		body = commitBody
+	}
+
+	note, _ := git.GetBranchNote(ctx, dir, res.branch) //nolint:errcheck
+	title = formatPRTitle(res.effCfg.PR.TitleFormat, title, res.branch, note, res.repo.Name)
+	return title, body
+}
+
+// prDefaults derives a PR title and body from the commits of branch. A
+// single commit gives its subject and message body; otherwise the title is
+// the humanized branch name and the body lists the commit subjects.
+func prDefaults(branch string, commits []git.Commit) (title, body string) {
+	if len(commits) == 1 {
+		return commits[0].Subject, commits[0].Body
+	}
+
+	title = strings.NewReplacer("-", " ", "_", " ").Replace(branch)
+	if r, size := utf8.DecodeRuneInString(title); r != utf8.RuneError {
+		title = string(unicode.ToUpper(r)) + title[size:]
+	}
+
+	var lines []string
+	for _, c := range commits {
+		lines = append(lines, "- "+c.Subject)
+	}
+	return title, strings.Join(lines, "\n")
+}
+
+// prTitleSeparators are the characters trimmed around an empty placeholder
+// in [pr] title_format.
+const prTitleSeparators = " :-|/"
+
+// formatPRTitle applies [pr] title_format to a generated title. Brackets and
+// separators next to an empty placeholder (usually a missing note) are
+// removed from the format, so "{note}: {title}" without a note is just the
+// title. The placeholder values themselves are never changed.
+func formatPRTitle(format, title, branch, note, repo string) string {
+	if format == "" {
+		return title
+	}
+	values := []string{
+		"{title}", title,
+		"{branch}", branch,
+		"{note}", note,
+		"{repo}", repo,
+	}
+
+	for i := 0; i < len(values); i += 2 {
+		if values[i+1] == "" {
+			format = removePlaceholder(format, values[i])
+		}
+	}
+	return strings.NewReplacer(values...).Replace(strings.TrimSpace(format))
+}
+
+// removePlaceholder removes every occurrence of placeholder from format along
+// with enclosing brackets and the separators that joined it to its
+// neighbour: the ones after it, or before it at the end of format.
+func removePlaceholder(format, placeholder string) string {
+	format = strings.NewReplacer(
+		"["+placeholder+"]", placeholder,
+		"("+placeholder+")", placeholder,
+	).Replace(format)
+
+	for {
+		i := strings.Index(format, placeholder)
+		if i < 0 {
+			return format
+		}
+		before, after := format[:i], format[i+len(placeholder):]
+		if rest := strings.TrimLeft(after, prTitleSeparators); rest != "" {
+			format = before + rest
+		} else {
+			format = strings.TrimRight(before, prTitleSeparators) + after
+		}
+	}
+}
+
+// editPRMessage opens title and body in the user's editor ($VISUAL, $EDITOR
+// or vi) and returns the edited values.
+func editPRMessage(ctx context.Context, title, body string) (string, string, error) {
+	f, err := os.CreateTemp("", "wt-pr-*.md")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create PR message file: %w", err)
+	}
+	path := f.Name()
+	defer os.Remove(path)
+
+	_, err = f.WriteString(title + "\n\n" + body + "\n")
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("failed to write PR message file: %w", err)
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	args := append(strings.Fields(editor), path)
+	c := exec.CommandContext(ctx, args[0], args[1:]...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		return "", "", fmt.Errorf("editor %s failed: %w", filepath.Base(args[0]), err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read PR message file: %w", err)
+	}
+	title, body = parsePRMessage(string(content))
+	return title, body, nil
+}
+
+// parsePRMessage splits an edited PR message into title (the first
+// non-empty line) and body (the rest, trimmed).
+func parsePRMessage(content string) (title, body string) {
+	content = strings.TrimLeft(strings.ReplaceAll(content, "\r\n", "\n"), "\n \t")
+	title, body, _ = strings.Cut(content, "\n")
+	return strings.TrimSpace(title), strings.TrimSpace(body)
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/raphi011/wt/internal/git"
+)
+
+func TestPrDefaults(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		branch    string
+		commits   []git.Commit
+		wantTitle string
+		wantBody  string
+	}{
+		{
+			name:      "single commit",
+			branch:    "feature-login",
+			commits:   []git.Commit{{Subject: "Add login page", Body: "Uses OAuth."}},
+			wantTitle: "Add login page",
+			wantBody:  "Uses OAuth.",
+		},
+		{
+			name:      "several commits",
+			branch:    "feature/login_page",
+			commits:   []git.Commit{{Subject: "Add login page"}, {Subject: "Fix typo"}},
+			wantTitle: "Feature/login page",
+			wantBody:  "- Add login page\n- Fix typo",
+		},
+		{
+			name:      "no commits",
+			branch:    "fix-crash",
+			wantTitle: "Fix crash",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			title, body := prDefaults(tt.branch, tt.commits)
+			if title != tt.wantTitle || body != tt.wantBody {
+				t.Errorf("prDefaults() = %q, %q; want %q, %q", title, body, tt.wantTitle, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestFormatPRTitle(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		format string
+		title  string
+		note   string
+		want   string
+	}{
+		{"", "Add login", "JIRA-1", "Add login"},
+		{"{title}", "Add login", "", "Add login"},
+		{"{note}: {title}", "Add login", "JIRA-1", "JIRA-1: Add login"},
+		{"{note}: {title}", "Add login", "", "Add login"},
+		{"[{note}] {title}", "Add login", "", "Add login"},
+		{"{title} ({note})", "Add login", "", "Add login"},
+		{"{repo}/{branch}: {title}", "Add login", "", "myrepo/feature: Add login"},
+		{"{repo} - {note} - {title}", "Add login", "", "myrepo - Add login"},
+		{"[{note}] {title}", "Fix parse() for [] input", "", "Fix parse() for [] input"},
+		{"{title} ({note})", "Fix parse()", "", "Fix parse()"},
+		{"{title} ({note})", "Fix parse()", "JIRA-1", "Fix parse() (JIRA-1)"},
+		{"{note}: {title}", "Drop trailing /", "", "Drop trailing /"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format+"/"+tt.title, func(t *testing.T) {
+			if got := formatPRTitle(tt.format, tt.title, "feature", tt.note, "myrepo"); got != tt.want {
+				t.Errorf("formatPRTitle(%q, %q, note=%q) = %q, want %q", tt.format, tt.title, tt.note, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePRMessage(t *testing.T) {
+	t.Parallel()
+
+	title, body := parsePRMessage("\n  Add login\n\n## Summary\r\nDetails\n\n")
+	if title != "Add login" || body != "## Summary\nDetails" {
+		t.Errorf("parsePRMessage() = %q, %q", title, body)
+	}
+
+	if title, body := parsePRMessage("  \n\n"); title != "" || body != "" {
+		t.Errorf("parsePRMessage(empty) = %q, %q; want empty", title, body)
+	}
+}
+
+func TestPlanPRPush(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		remoteExists  bool
+		ahead, behind int
+		force         bool
+		want          prPushAction
+	}{
+		{"not pushed", false, 0, 0, false, prPushNew},
+		{"up to date", true, 0, 0, true, prPushUpToDate},
+		{"ahead", true, 2, 0, false, prPushFastForward},
+		{"behind", true, 0, 1, true, prPushBehind},
+		{"diverged", true, 2, 1, false, prPushDiverged},
+		{"diverged, force-with-lease", true, 2, 1, true, prPushForce},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := planPRPush(tt.remoteExists, tt.ahead, tt.behind, tt.force); got != tt.want {
+				t.Errorf("planPRPush() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
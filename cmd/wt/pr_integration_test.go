@@ -3,14 +3,18 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/raphi011/wt/internal/config"
+	"github.com/raphi011/wt/internal/forge"
+	"github.com/raphi011/wt/internal/prcache"
 	"github.com/raphi011/wt/internal/registry"
 )
 
@@ -403,6 +407,33 @@ func TestPrCreate_BodyAndBodyFileMutuallyExclusive(t *testing.T) {
 	}
 }
 
+// TestPrCreate_TitleAndFillMutuallyExclusive tests that --fill (generated
+// title) cannot be combined with an explicit --title.
+//
+// Scenario: User runs `wt pr create --title test --fill`
+// Expected: Returns cobra mutual exclusivity error
+func TestPrCreate_TitleAndFillMutuallyExclusive(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := resolvePath(t, t.TempDir())
+	repoPath := setupTestRepo(t, tmpDir, "myrepo")
+
+	cfg := &config.Config{RegistryPath: filepath.Join(tmpDir, ".wt", "repos.json")}
+	ctx := testContextWithConfig(t, cfg, repoPath)
+
+	cmd := newPrCreateCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"--title", "test", "--fill"})
+
+	err := cmd.Execute()
+	if err == nil {
+		t.Fatal("expected error for mutually exclusive flags, got nil")
+	}
+	if !strings.Contains(err.Error(), "title") || !strings.Contains(err.Error(), "fill") {
+		t.Errorf("expected error about title/fill mutual exclusivity, got %q", err.Error())
+	}
+}
+
 // TestPrCheckout_HookNoHookMutuallyExclusive tests that --hook and --no-hook cannot both be used.
 //
 // Scenario: User runs `wt pr checkout --hook myhook --no-hook 123`
@@ -973,3 +1004,290 @@ func TestPrCheckout_AlreadyCheckedOut(t *testing.T) {
 		t.Fatalf("worktree should still exist at %s", wtPath)
 	}
 }
+
+// TestPrCheckout_ForkBranchKeepsLocalBranch tests the local branch chosen for
+// a fork PR whose head branch name is taken by the user's own branch.
+//
+// We cannot run the full pr checkout command (requires a forge), so we
+// exercise chooseForkLocalBranch, which pr checkout uses for fork PRs.
+//
+// Scenario: Repo has its own local main, fork PR #7 from bob's main is fetched via the PR ref
+// Expected: The PR gets branch bob-main; main is only reused once marked for PR #7
+func TestPrCheckout_ForkBranchKeepsLocalBranch(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := resolvePath(t, t.TempDir())
+	repoPath, _ := setupTestRepoWithOrigin(t, tmpDir, "myrepo")
+	ctx := context.Background()
+	head := &forge.PRHead{Branch: "main", IsFork: true, RepoPath: "bob/repo"}
+
+	fb := &forkBranch{start: headCommit(t, repoPath)}
+	if err := chooseForkLocalBranch(ctx, repoPath, fb, head, 7); err != nil {
+		t.Fatalf("chooseForkLocalBranch failed: %v", err)
+	}
+	if fb.local != "bob-main" || fb.exists {
+		t.Errorf("local branch = %q (exists %v), want new bob-main", fb.local, fb.exists)
+	}
+
+	// A branch checked out for the PR earlier is reused
+	if out, err := runGitCommand(repoPath, "config", "branch.main.wt-pr", "7"); err != nil {
+		t.Fatalf("failed to mark branch: %v\n%s", err, out)
+	}
+	fb = &forkBranch{start: headCommit(t, repoPath)}
+	if err := chooseForkLocalBranch(ctx, repoPath, fb, head, 7); err != nil {
+		t.Fatalf("chooseForkLocalBranch failed: %v", err)
+	}
+	if fb.local != "main" || !fb.exists {
+		t.Errorf("local branch = %q (exists %v), want existing main", fb.local, fb.exists)
+	}
+
+	// ... but not for another PR from a fork's main
+	fb = &forkBranch{start: headCommit(t, repoPath)}
+	if err := chooseForkLocalBranch(ctx, repoPath, fb, head, 8); err != nil {
+		t.Fatalf("chooseForkLocalBranch failed: %v", err)
+	}
+	if fb.local != "bob-main" || fb.exists {
+		t.Errorf("local branch = %q (exists %v), want new bob-main", fb.local, fb.exists)
+	}
+}
+
+// TestPrList_ScopeNotFound tests error when a scope matches no repo or label.
+//
+// Scenario: User runs `wt pr list nonexistent`
+// Expected: Returns error about the unknown scope
+func TestPrList_ScopeNotFound(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := resolvePath(t, t.TempDir())
+	repoPath := setupTestRepo(t, tmpDir, "myrepo")
+
+	regFile := filepath.Join(tmpDir, ".wt", "repos.json")
+	if err := os.MkdirAll(filepath.Dir(regFile), 0755); err != nil {
+		t.Fatalf("failed to create registry directory: %v", err)
+	}
+
+	reg := &registry.Registry{
+		Repos: []registry.Repo{
+			{Name: "myrepo", Path: repoPath},
+		},
+	}
+	if err := reg.Save(regFile); err != nil {
+		t.Fatalf("failed to save registry: %v", err)
+	}
+
+	cfg := &config.Config{RegistryPath: regFile}
+	ctx := testContextWithConfig(t, cfg, tmpDir)
+
+	cmd := newPrListCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"nonexistent"})
+
+	err := cmd.Execute()
+	if err == nil {
+		t.Fatal("expected error for unknown scope, got nil")
+	}
+	if !strings.Contains(err.Error(), "nonexistent") {
+		t.Errorf("expected error mentioning the scope, got %q", err.Error())
+	}
+}
+
+// TestPrList_CheckoutJSONMutuallyExclusive tests that --checkout and --json cannot both be used.
+//
+// Scenario: User runs `wt pr list --checkout --json`
+// Expected: Returns cobra mutual exclusivity error
+func TestPrList_CheckoutJSONMutuallyExclusive(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := resolvePath(t, t.TempDir())
+	repoPath := setupTestRepo(t, tmpDir, "myrepo")
+
+	cfg := &config.Config{RegistryPath: filepath.Join(tmpDir, ".wt", "repos.json")}
+	ctx := testContextWithConfig(t, cfg, repoPath)
+
+	cmd := newPrListCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"--checkout", "--json"})
+
+	err := cmd.Execute()
+	if err == nil {
+		t.Fatal("expected error for mutually exclusive flags, got nil")
+	}
+	if !strings.Contains(err.Error(), "checkout") || !strings.Contains(err.Error(), "json") {
+		t.Errorf("expected error about checkout/json mutual exclusivity, got %q", err.Error())
+	}
+}
+
+// setupPRSyncTest creates a registered repo with a local origin and a
+// worktree for branch "feature" whose open PR #1 is in the PR cache.
+// Returns the repo path, the worktree path and a context for commands.
+func setupPRSyncTest(t *testing.T) (string, string, context.Context) {
+	t.Helper()
+
+	tmpDir := resolvePath(t, t.TempDir())
+	repoPath, _ := setupTestRepoWithOrigin(t, tmpDir, "myrepo")
+	wtPath := createTestWorktree(t, repoPath, "feature")
+	addCommit(t, wtPath, "feature.txt", "Feature work")
+	if out, err := runGitCommand(wtPath, "push", "-u", "origin", "feature", "feature:refs/pull/1/head"); err != nil {
+		t.Fatalf("failed to push PR branch: %v\n%s", err, out)
+	}
+
+	regFile := filepath.Join(tmpDir, ".wt", "repos.json")
+	if err := os.MkdirAll(filepath.Dir(regFile), 0755); err != nil {
+		t.Fatalf("failed to create registry directory: %v", err)
+	}
+	reg := &registry.Registry{Repos: []registry.Repo{{Name: "myrepo", Path: repoPath}}}
+	if err := reg.Save(regFile); err != nil {
+		t.Fatalf("failed to save registry: %v", err)
+	}
+
+	cfg := &config.Config{RegistryPath: regFile}
+	cachePath, err := cfg.GetPRCachePath()
+	if err != nil {
+		t.Fatalf("GetPRCachePath failed: %v", err)
+	}
+	cache := prcache.New()
+	cache.Set(prcache.CacheKey(repoPath, "feature"), &forge.PRInfo{Number: 1, State: forge.PRStateOpen, Fetched: true, CachedAt: time.Now()})
+	if err := cache.SaveTo(cachePath); err != nil {
+		t.Fatalf("failed to save PR cache: %v", err)
+	}
+
+	return repoPath, wtPath, testContextWithConfig(t, cfg, wtPath)
+}
+
+// publishPRHead pushes a new commit on top of parent as the head of PR #1
+// (refs/pull/1/head) and of branch on origin, like the PR author pushing
+// from another clone.
+// Returns the new commit.
+func publishPRHead(t *testing.T, repoPath, parent, branch string, force bool) string {
+	t.Helper()
+
+	out, err := runGitCommand(repoPath, "commit-tree", parent+"^{tree}", "-p", parent, "-m", "PR update")
+	if err != nil {
+		t.Fatalf("failed to create commit: %v\n%s", err, out)
+	}
+	sha := strings.TrimSpace(out)
+	prefix := ""
+	if force {
+		prefix = "+"
+	}
+	// Push by URL so the local remote-tracking branch isn't updated
+	originURL, err := runGitCommand(repoPath, "remote", "get-url", "origin")
+	if err != nil {
+		t.Fatalf("failed to get origin URL: %v\n%s", err, originURL)
+	}
+	if out, err := runGitCommand(repoPath, "push", strings.TrimSpace(originURL), prefix+sha+":refs/pull/1/head", prefix+sha+":refs/heads/"+branch); err != nil {
+		t.Fatalf("failed to push PR head: %v\n%s", err, out)
+	}
+	return sha
+}
+
+// headCommit returns the HEAD commit of the worktree at path.
+func headCommit(t *testing.T, path string) string {
+	t.Helper()
+	out, err := runGitCommand(path, "rev-parse", "HEAD")
+	if err != nil {
+		t.Fatalf("failed to get HEAD: %v\n%s", err, out)
+	}
+	return strings.TrimSpace(out)
+}
+
+// TestPrSync_FastForward tests that a PR worktree behind the PR head is fast-forwarded.
+//
+// Scenario: PR author pushes a new commit, user runs `wt pr sync` in the PR worktree
+// Expected: Worktree is fast-forwarded to the new PR head
+func TestPrSync_FastForward(t *testing.T) {
+	t.Parallel()
+
+	repoPath, wtPath, ctx := setupPRSyncTest(t)
+	newHead := publishPRHead(t, repoPath, "feature", "feature", false)
+
+	cmd := newPrSyncCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("pr sync failed: %v", err)
+	}
+
+	if got := headCommit(t, wtPath); got != newHead {
+		t.Errorf("HEAD = %s, want fast-forward to %s", got, newHead)
+	}
+}
+
+// TestPrSync_ResetRewrittenHistory tests that a clean PR worktree follows a force-push.
+//
+// Scenario: PR author force-pushes a rewritten branch, user runs `wt pr sync myrepo:feature`
+// Expected: Worktree is reset to the new PR head
+func TestPrSync_ResetRewrittenHistory(t *testing.T) {
+	t.Parallel()
+
+	repoPath, wtPath, ctx := setupPRSyncTest(t)
+	newHead := publishPRHead(t, repoPath, "main", "feature", true)
+
+	cmd := newPrSyncCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"myrepo:feature"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("pr sync failed: %v", err)
+	}
+
+	if got := headCommit(t, wtPath); got != newHead {
+		t.Errorf("HEAD = %s, want reset to %s", got, newHead)
+	}
+}
+
+// TestPrSync_RewrittenWithLocalCommits tests that local work is never reset away.
+//
+// Scenario: Worktree has a local commit, PR author force-pushes, user runs `wt pr sync --all`
+// Expected: Returns error, worktree HEAD is unchanged
+func TestPrSync_RewrittenWithLocalCommits(t *testing.T) {
+	t.Parallel()
+
+	repoPath, wtPath, ctx := setupPRSyncTest(t)
+	addCommit(t, wtPath, "local.txt", "Local work")
+	localHead := headCommit(t, wtPath)
+	publishPRHead(t, repoPath, "main", "feature", true)
+
+	cmd := newPrSyncCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"--all"})
+	err := cmd.Execute()
+	if err == nil {
+		t.Fatal("expected error for rewritten PR with local commits, got nil")
+	}
+
+	if got := headCommit(t, wtPath); got != localHead {
+		t.Errorf("HEAD = %s, want unchanged %s", got, localHead)
+	}
+}
+
+// TestPrSync_ResetRewrittenForkPR tests that a fork PR checked out via the PR
+// ref follows a force-push.
+//
+// Scenario: Worktree has no upstream and no remote-tracking branch (like a
+// fork PR fetched from refs/pull/N/head), the fetched PR head is recorded;
+// PR author force-pushes, user runs `wt pr sync`
+// Expected: The PR commits don't count as local commits; worktree is reset to
+// the new PR head, which is recorded for the next sync
+func TestPrSync_ResetRewrittenForkPR(t *testing.T) {
+	t.Parallel()
+
+	repoPath, wtPath, ctx := setupPRSyncTest(t)
+	mustRunGit(t, wtPath, "branch", "--unset-upstream")
+	mustRunGit(t, repoPath, "update-ref", "-d", "refs/remotes/origin/feature")
+	mustRunGit(t, repoPath, "config", "branch.feature.wt-pr-head", headCommit(t, wtPath))
+	newHead := publishPRHead(t, repoPath, "main", "feature", true)
+
+	cmd := newPrSyncCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("pr sync failed: %v", err)
+	}
+
+	if got := headCommit(t, wtPath); got != newHead {
+		t.Errorf("HEAD = %s, want reset to %s", got, newHead)
+	}
+	if recorded, _ := runGitCommand(repoPath, "config", "branch.feature.wt-pr-head"); strings.TrimSpace(recorded) != newHead {
+		t.Errorf("recorded PR head = %q, want %s", recorded, newHead)
+	}
+}
@@ -0,0 +1,445 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/raphi011/wt/internal/config"
+	"github.com/raphi011/wt/internal/forge"
+	"github.com/raphi011/wt/internal/git"
+	"github.com/raphi011/wt/internal/log"
+	"github.com/raphi011/wt/internal/output"
+	"github.com/raphi011/wt/internal/prcache"
+	"github.com/raphi011/wt/internal/registry"
+	"github.com/raphi011/wt/internal/ui/progress"
+	"github.com/raphi011/wt/internal/ui/static"
+)
+
+// currentUserPlaceholder stands for the forge user of the configured token
+// in --author and --review-requested.
+const currentUserPlaceholder = "@me"
+
+// prListFilter selects open PRs. Empty fields match every PR.
+type prListFilter struct {
+	author          string
+	reviewRequested string
+	draft           bool     // only draft PRs
+	labels          []string // PR must have all labels
+}
+
+// needsCurrentUser reports whether the filter refers to the current forge user.
+func (f prListFilter) needsCurrentUser() bool {
+	return f.author == currentUserPlaceholder || f.reviewRequested == currentUserPlaceholder
+}
+
+// withCurrentUser returns a copy of f with @me replaced by user.
+func (f prListFilter) withCurrentUser(user string) prListFilter {
+	if f.author == currentUserPlaceholder {
+		f.author = user
+	}
+	if f.reviewRequested == currentUserPlaceholder {
+		f.reviewRequested = user
+	}
+	return f
+}
+
+// matches reports whether pr passes the filter. Names and labels are
+// compared case-insensitively.
+func (f prListFilter) matches(pr forge.OpenPR) bool {
+	if f.author != "" && !strings.EqualFold(pr.Author, f.author) {
+		return false
+	}
+	if f.reviewRequested != "" && !containsFold(pr.ReviewRequested, f.reviewRequested) {
+		return false
+	}
+	if f.draft && !pr.IsDraft {
+		return false
+	}
+	for _, label := range f.labels {
+		if !containsFold(pr.Labels, label) {
+			return false
+		}
+	}
+	return true
+}
+
+// containsFold reports whether values contains s, ignoring case.
+func containsFold(values []string, s string) bool {
+	return slices.ContainsFunc(values, func(v string) bool { return strings.EqualFold(v, s) })
+}
+
+// prListEntry is an open PR as printed by wt pr list.
+type prListEntry struct {
+	Repo            string    `json:"repo"`
+	Number          int       `json:"number"`
+	Title           string    `json:"title"`
+	Author          string    `json:"author"`
+	Branch          string    `json:"branch"`
+	URL             string    `json:"url,omitempty"`
+	Draft           bool      `json:"draft"`
+	Labels          []string  `json:"labels,omitempty"`
+	ReviewRequested []string  `json:"review_requested,omitempty"`
+	UpdatedAt       time.Time `json:"updated_at,omitzero"`
+	Worktree        string    `json:"worktree,omitempty"` // path of the local worktree, if any
+
+	pr   forge.OpenPR
+	repo *prListRepo
+}
+
+// prListRepo is a repo queried by wt pr list, with what's needed to check
+// out its PRs.
+type prListRepo struct {
+	repo      registry.Repo
+	effCfg    *config.Config
+	originURL string
+	forge     forge.Forge
+}
+
+// prListWorktree is a worktree of a repo queried by wt pr list, with what
+// tells which PR it has checked out.
+type prListWorktree struct {
+	path         string
+	branch       string
+	prNumber     int    // PR of the branch in the PR cache, 0 if unknown
+	remote       string // upstream remote, empty without upstream
+	remoteBranch string // upstream branch name
+	remoteRepo   string // repo path (owner/repo) of the upstream remote
+}
+
+// checkedOut reports whether wt has pr checked out. The head branch name
+// alone isn't enough: PRs from forks often use branch names like main.
+// The upstream decides if it can be compared with the PR's head repository
+// (origin for PRs that aren't from forks), then the PR number in the PR
+// cache, which also covers PRs checked out without upstream. Otherwise only
+// a PR from the repo itself matches a local branch of the same name.
+func (wt prListWorktree) checkedOut(pr forge.OpenPR) bool {
+	if wt.remote != "" && (!pr.IsFork || pr.HeadRepo != "") {
+		if wt.remoteBranch != pr.Branch {
+			return false
+		}
+		if pr.IsFork {
+			return strings.EqualFold(wt.remoteRepo, pr.HeadRepo)
+		}
+		return wt.remote == "origin"
+	}
+	if wt.prNumber != 0 {
+		return wt.prNumber == pr.Number
+	}
+	return !pr.IsFork && wt.branch == pr.Branch
+}
+
+// listRepoWorktrees returns the worktrees of repo with their upstreams and
+// cached PR numbers.
+func listRepoWorktrees(ctx context.Context, r *prListRepo) []prListWorktree {
+	infos, err := git.ListWorktreesFromRepo(ctx, r.repo.Path)
+	if err != nil {
+		return nil
+	}
+	remoteURLs, _ := git.GetRemoteURLs(ctx, r.repo.Path)
+	cache := loadPRCache(ctx, r.effCfg)
+
+	var worktrees []prListWorktree
+	for _, info := range infos {
+		if info.Branch == "" {
+			continue
+		}
+		wt := prListWorktree{
+			path:         info.Path,
+			branch:       info.Branch,
+			remote:       git.GetUpstreamRemote(ctx, r.repo.Path, info.Branch),
+			remoteBranch: git.GetUpstreamBranch(ctx, r.repo.Path, info.Branch),
+		}
+		if url, ok := remoteURLs[wt.remote]; ok {
+			wt.remoteRepo = forge.ExtractRepoPath(url)
+		}
+		if pr := cache.Get(prcache.CacheKey(r.repo.Path, info.Branch)); pr != nil {
+			wt.prNumber = pr.Number
+		}
+		worktrees = append(worktrees, wt)
+	}
+	return worktrees
+}
+
+// listRepoPRs returns the open PRs of repo that match filter. Each PR is
+// marked with the path of its worktree if it is checked out locally (see
+// [prListWorktree.checkedOut]).
+func listRepoPRs(ctx context.Context, repo registry.Repo, filter prListFilter) ([]prListEntry, error) {
+	r := &prListRepo{repo: repo, effCfg: resolveEffectiveConfig(ctx, repo.Path)}
+
+	originURL, err := git.GetOriginURL(ctx, repo.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get origin URL: %w", err)
+	}
+	r.originURL = originURL
+
+	r.forge = forge.Detect(originURL, r.effCfg.Hosts, &r.effCfg.Forge)
+	if err := r.forge.Check(ctx); err != nil {
+		return nil, err
+	}
+
+	if filter.needsCurrentUser() {
+		user, err := r.forge.CurrentUser(ctx, originURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current user: %w", err)
+		}
+		filter = filter.withCurrentUser(user)
+	}
+
+	prs, err := r.forge.ListOpenPRs(ctx, originURL)
+	if err != nil {
+		return nil, err
+	}
+
+	worktrees := listRepoWorktrees(ctx, r)
+
+	var entries []prListEntry
+	for _, pr := range prs {
+		if !filter.matches(pr) {
+			continue
+		}
+		var wtPath string
+		for _, wt := range worktrees {
+			if wt.checkedOut(pr) {
+				wtPath = wt.path
+				break
+			}
+		}
+		entries = append(entries, prListEntry{
+			Repo:            repo.Name,
+			Number:          pr.Number,
+			Title:           pr.Title,
+			Author:          pr.Author,
+			Branch:          pr.Branch,
+			URL:             pr.URL,
+			Draft:           pr.IsDraft,
+			Labels:          pr.Labels,
+			ReviewRequested: pr.ReviewRequested,
+			UpdatedAt:       pr.UpdatedAt,
+			Worktree:        wtPath,
+			pr:              pr,
+			repo:            r,
+		})
+	}
+	return entries, nil
+}
+
+// listPRs queries repos concurrently with a progress bar and returns the
+// matching PRs sorted by repo and PR number (newest first). Repos that fail
+// are reported as warnings.
+func listPRs(ctx context.Context, repos []registry.Repo, filter prListFilter) []prListEntry {
+	l := log.FromContext(ctx)
+
+	pb := progress.NewProgressBar(len(repos), "Fetching PRs...")
+	pb.Start()
+
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		entries   []prListEntry
+		completed int
+		warnings  []string
+	)
+	semaphore := make(chan struct{}, forge.MaxConcurrentFetches)
+
+	for _, repo := range repos {
+		wg.Go(func() {
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			repoEntries, err := listRepoPRs(ctx, repo, filter)
+
+			mu.Lock()
+			defer mu.Unlock()
+			completed++
+			if err != nil {
+				l.Debug("pr list failed", "repo", repo.Name, "err", err)
+				warnings = append(warnings, fmt.Sprintf("Warning: %s: %v\n", repo.Name, err))
+			}
+			entries = append(entries, repoEntries...)
+			pb.SetProgress(completed, "Fetching PRs...")
+		})
+	}
+	wg.Wait()
+	pb.Stop()
+
+	sort.Strings(warnings)
+	for _, w := range warnings {
+		l.Printf("%s", w)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Repo != entries[j].Repo {
+			return entries[i].Repo < entries[j].Repo
+		}
+		return entries[i].Number > entries[j].Number
+	})
+	return entries
+}
+
+func newPrListCmd() *cobra.Command {
+	var (
+		jsonOutput bool
+		global     bool
+		checkout   bool
+		filter     prListFilter
+		hf         hookFlags
+	)
+
+	cmd := &cobra.Command{
+		Use:     "list [scope...]",
+		Short:   "List open PRs across repos",
+		Aliases: []string{"ls"},
+		Args:    cobra.ArbitraryArgs,
+		Long: `List open PRs of registered repos.
+
+Inside a repo: lists only that repo's PRs. Use --global for all repos.
+Use positional args to filter by repo name(s) or label(s).
+Resolution order: repo name → label.
+
+Repos are queried concurrently. PRs already checked out in a worktree are
+marked in the WORKTREE column, matched by the upstream of the worktree's
+branch or by the PR number cached by 'wt list --refresh-pr'. Use @me with
+--author or --review-requested for the user of the forge token.
+
+With --checkout, a worktree is created for every matching PR that doesn't
+have one yet (like 'wt pr checkout').`,
+		Example: `  wt pr list                            # Open PRs of current repo
+  wt pr list --global                   # Open PRs of all repos
+  wt pr list backend                    # Repos with label 'backend'
+  wt pr list -g --review-requested @me  # PRs waiting for my review
+  wt pr list --author @me --draft       # My draft PRs
+  wt pr list --label bug --label p1     # PRs with both labels
+  wt pr list -g --review-requested @me --checkout  # Check them all out
+  wt pr list --json                     # Output as JSON`,
+		ValidArgsFunction: completeScopeArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			cfg := config.FromContext(ctx)
+			l := log.FromContext(ctx)
+			out := output.FromContext(ctx)
+
+			reg, err := registry.Load(cfg.RegistryPath)
+			if err != nil {
+				return fmt.Errorf("load registry: %w", err)
+			}
+
+			// Determine which repos to query
+			var repos []registry.Repo
+			if global {
+				repos = reg.Repos
+			} else if len(args) > 0 {
+				repos, err = resolveScopeArgs(reg, args)
+				if err != nil {
+					return err
+				}
+			} else {
+				repo, err := findOrRegisterCurrentRepoFromContext(ctx, reg)
+				if err != nil {
+					// Not in a repo, query all
+					repos = reg.Repos
+				} else {
+					repos = []registry.Repo{repo}
+				}
+			}
+
+			repos = filterOrphanedRepos(l, repos)
+
+			l.Debug("listing PRs", "repos", len(repos))
+
+			entries := listPRs(ctx, repos, filter)
+
+			if checkout {
+				return checkoutListedPRs(ctx, entries, hf)
+			}
+
+			if jsonOutput {
+				if entries == nil {
+					entries = []prListEntry{}
+				}
+				enc := json.NewEncoder(out.Writer())
+				enc.SetIndent("", "  ")
+				return enc.Encode(entries)
+			}
+
+			if len(entries) == 0 {
+				out.Println("No open PRs found")
+				return nil
+			}
+
+			var rows [][]string
+			for _, e := range entries {
+				rows = append(rows, static.PRListTableRow(e.Repo, e.pr, e.Worktree != ""))
+			}
+			out.Print(static.RenderTable(static.PRListTableHeaders, rows))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+	cmd.Flags().BoolVarP(&global, "global", "g", false, "List PRs of all repos (not just current repo)")
+	cmd.Flags().StringVar(&filter.author, "author", "", "Only PRs by this author (@me for yourself)")
+	cmd.Flags().StringVar(&filter.reviewRequested, "review-requested", "", "Only PRs requesting review from this user or team (@me for yourself)")
+	cmd.Flags().BoolVar(&filter.draft, "draft", false, "Only draft PRs")
+	cmd.Flags().StringSliceVar(&filter.labels, "label", nil, "Only PRs with this label (repeatable, all must match)")
+	cmd.Flags().BoolVar(&checkout, "checkout", false, "Create worktrees for all matching PRs")
+	registerHookFlags(cmd, &hf)
+	cmd.MarkFlagsMutuallyExclusive("checkout", "json")
+
+	cmd.RegisterFlagCompletionFunc("author", cobra.NoFileCompletions)
+	cmd.RegisterFlagCompletionFunc("review-requested", cobra.NoFileCompletions)
+	cmd.RegisterFlagCompletionFunc("label", cobra.NoFileCompletions)
+
+	return cmd
+}
+
+// checkoutListedPRs checks out every PR in entries that has no worktree yet.
+// Each checkout is a separate journal entry and runs the checkout hooks.
+// Failures are reported and don't stop the remaining checkouts.
+func checkoutListedPRs(ctx context.Context, entries []prListEntry, hf hookFlags) error {
+	l := log.FromContext(ctx)
+
+	var todo []prListEntry
+	for _, e := range entries {
+		if e.Worktree == "" {
+			todo = append(todo, e)
+		}
+	}
+	if len(todo) == 0 {
+		l.Printf("No PRs to check out\n")
+		return nil
+	}
+
+	var failed int
+	for _, e := range todo {
+		prCtx, op := startJournalOp(ctx, "pr checkout")
+		op.setRepo(e.repo.repo)
+		op.Detail = fmt.Sprintf("PR #%d", e.Number)
+
+		err := checkoutPR(prCtx, op, prCheckout{
+			repo:      e.repo.repo,
+			effCfg:    e.repo.effCfg,
+			originURL: e.repo.originURL,
+			forge:     e.repo.forge,
+			number:    e.Number,
+		}, hf)
+		op.finish(prCtx, err)
+		if err != nil {
+			failed++
+			l.Printf("Warning: %s PR #%d: %v\n", e.Repo, e.Number, err)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to check out %d of %d PRs", failed, len(todo))
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/raphi011/wt/internal/forge"
+)
+
+func TestPrListFilter_Matches(t *testing.T) {
+	t.Parallel()
+
+	pr := forge.OpenPR{
+		Number:          1,
+		Author:          "Alice",
+		IsDraft:         true,
+		Labels:          []string{"bug", "P1"},
+		ReviewRequested: []string{"bob", "core-team"},
+	}
+
+	tests := []struct {
+		name   string
+		filter prListFilter
+		want   bool
+	}{
+		{"empty filter", prListFilter{}, true},
+		{"author", prListFilter{author: "alice"}, true},
+		{"other author", prListFilter{author: "bob"}, false},
+		{"review requested", prListFilter{reviewRequested: "bob"}, true},
+		{"review requested team", prListFilter{reviewRequested: "Core-Team"}, true},
+		{"review not requested", prListFilter{reviewRequested: "carol"}, false},
+		{"draft", prListFilter{draft: true}, true},
+		{"all labels", prListFilter{labels: []string{"bug", "p1"}}, true},
+		{"missing label", prListFilter{labels: []string{"bug", "p2"}}, false},
+		{"combined", prListFilter{author: "alice", reviewRequested: "bob", labels: []string{"bug"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.matches(pr); got != tt.want {
+				t.Errorf("matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if (prListFilter{draft: true}).matches(forge.OpenPR{Number: 2}) {
+		t.Error("draft filter should reject ready PRs")
+	}
+}
+
+func TestPrListFilter_WithCurrentUser(t *testing.T) {
+	t.Parallel()
+
+	f := prListFilter{author: "@me", reviewRequested: "bob"}
+	if !f.needsCurrentUser() {
+		t.Fatal("needsCurrentUser() = false, want true")
+	}
+	got := f.withCurrentUser("alice")
+	if got.author != "alice" || got.reviewRequested != "bob" {
+		t.Errorf("withCurrentUser() = %+v, want author alice, reviewer bob", got)
+	}
+	if got.needsCurrentUser() {
+		t.Error("needsCurrentUser() after substitution = true, want false")
+	}
+	if (prListFilter{author: "alice"}).needsCurrentUser() {
+		t.Error("needsCurrentUser() without @me = true, want false")
+	}
+}
+
+func TestPrListWorktree_CheckedOut(t *testing.T) {
+	t.Parallel()
+
+	samePR := forge.OpenPR{Number: 1, Branch: "feature"}
+	forkPR := forge.OpenPR{Number: 2, Branch: "main", IsFork: true, HeadRepo: "bob/repo"}
+	forkPRUnknownRepo := forge.OpenPR{Number: 3, Branch: "main", IsFork: true}
+
+	mainWT := prListWorktree{branch: "main", remote: "origin", remoteBranch: "main", remoteRepo: "org/repo"}
+	featureWT := prListWorktree{branch: "feature", remote: "origin", remoteBranch: "feature", remoteRepo: "org/repo"}
+	forkWT := prListWorktree{branch: "bob-main", remote: "bob", remoteBranch: "main", remoteRepo: "Bob/repo"}
+
+	tests := []struct {
+		name string
+		wt   prListWorktree
+		pr   forge.OpenPR
+		want bool
+	}{
+		{"same repo by upstream", featureWT, samePR, true},
+		{"same repo other upstream branch", mainWT, samePR, false},
+		{"same repo upstream on other remote", prListWorktree{branch: "feature", remote: "bob", remoteBranch: "feature"}, samePR, false},
+		{"same repo by name without upstream", prListWorktree{branch: "feature"}, samePR, true},
+		{"fork by upstream", forkWT, forkPR, true},
+		{"fork from main is not the main worktree", mainWT, forkPR, false},
+		{"fork from main is not a local main without upstream", prListWorktree{branch: "main"}, forkPR, false},
+		{"fork by cached number without upstream", prListWorktree{branch: "bob-main", prNumber: 2}, forkPR, true},
+		{"cached number of another PR", prListWorktree{branch: "feature", prNumber: 5}, samePR, false},
+		{"upstream wins over cached number", prListWorktree{branch: "main", prNumber: 2, remote: "origin", remoteBranch: "main", remoteRepo: "org/repo"}, forkPR, false},
+		{"fork with unknown head repo by cached number", prListWorktree{branch: "bob-main", prNumber: 3, remote: "bob", remoteBranch: "main"}, forkPRUnknownRepo, true},
+		{"fork with unknown head repo without cache", mainWT, forkPRUnknownRepo, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.wt.checkedOut(tt.pr); got != tt.want {
+				t.Errorf("checkedOut() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,13 +3,18 @@ package main
 import (
 	"context"
 	"fmt"
+	"os"
 	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
 
 	"github.com/raphi011/wt/internal/config"
 	"github.com/raphi011/wt/internal/forge"
 	"github.com/raphi011/wt/internal/git"
 	"github.com/raphi011/wt/internal/log"
 	"github.com/raphi011/wt/internal/prcache"
+	"github.com/raphi011/wt/internal/registry"
 	"github.com/raphi011/wt/internal/ui/progress"
 )
 
@@ -21,12 +26,26 @@ type prFetchItem struct {
 	cacheKey  string // key for prCache (repoName:folderName)
 }
 
-// refreshPRs fetches PR status for the given worktrees in parallel with a
-// progress bar. It updates prCache in-place and returns the branches that failed.
-func refreshPRs(ctx context.Context, worktrees []git.Worktree, prCache *prcache.Cache, hosts map[string]string, forgeConfig *config.ForgeConfig) (failedBranches []string) {
-	l := log.FromContext(ctx)
+// groupPRFetchItems groups fetch items by origin URL so that each repository
+// is queried once. Groups are returned in order of first appearance.
+func groupPRFetchItems(items []prFetchItem) [][]prFetchItem {
+	index := make(map[string]int)
+	var groups [][]prFetchItem
+	for _, item := range items {
+		i, ok := index[item.originURL]
+		if !ok {
+			i = len(groups)
+			index[item.originURL] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], item)
+	}
+	return groups
+}
 
-	// Build fetch items, skipping worktrees without origin or already merged
+// prFetchItems builds fetch items for worktrees, skipping worktrees without
+// origin or upstream and those whose PR is already known to be merged.
+func prFetchItems(worktrees []git.Worktree, prCache *prcache.Cache) []prFetchItem {
 	var items []prFetchItem
 	for _, wt := range worktrees {
 		if wt.OriginURL == "" {
@@ -46,7 +65,15 @@ func refreshPRs(ctx context.Context, worktrees []git.Worktree, prCache *prcache.
 			cacheKey:  cacheKey,
 		})
 	}
+	return items
+}
 
+// refreshPRs fetches PR status for the given worktrees with a progress bar.
+// Worktrees are grouped by origin URL and each repository is queried with a
+// single batched request; repositories are fetched in parallel. It updates
+// prCache in-place and returns the branches that failed.
+func refreshPRs(ctx context.Context, worktrees []git.Worktree, prCache *prcache.Cache, hosts map[string]string, forgeConfig *config.ForgeConfig) (failedBranches []string) {
+	items := prFetchItems(worktrees, prCache)
 	if len(items) == 0 {
 		return nil
 	}
@@ -55,63 +82,97 @@ func refreshPRs(ctx context.Context, worktrees []git.Worktree, prCache *prcache.
 	pb.Start()
 	defer pb.Stop()
 
+	return fetchPRs(ctx, items, prCache, hosts, forgeConfig, func(completed, failed int) {
+		msg := "Fetching PR status..."
+		if failed > 0 {
+			msg = fmt.Sprintf("Fetching PR status... (%d failed)", failed)
+		}
+		pb.SetProgress(completed, msg)
+	})
+}
+
+// fetchPRs fetches PR status for items, one batched request per repository,
+// and stores the results in prCache. onProgress (optional) is called with the
+// number of completed and failed items after each repository.
+// Returns the branches that failed.
+func fetchPRs(ctx context.Context, items []prFetchItem, prCache *prcache.Cache, hosts map[string]string, forgeConfig *config.ForgeConfig, onProgress func(completed, failed int)) (failedBranches []string) {
+	l := log.FromContext(ctx)
+
 	var prMutex sync.Mutex
 	var wg sync.WaitGroup
 	semaphore := make(chan struct{}, forge.MaxConcurrentFetches)
-	var completedCount, failedCount int
+	var completedCount int
 	var countMutex sync.Mutex
 
-	// recordProgress must be called under countMutex.
-	recordProgress := func(branch string) {
-		completedCount++
-		if branch != "" {
-			failedCount++
-			failedBranches = append(failedBranches, branch)
-		}
-		msg := "Fetching PR status..."
-		if failedCount > 0 {
-			msg = fmt.Sprintf("Fetching PR status... (%d failed)", failedCount)
+	// recordProgress marks done items as completed, of which failed failed.
+	recordProgress := func(done int, failed []string) {
+		countMutex.Lock()
+		defer countMutex.Unlock()
+		completedCount += done
+		failedBranches = append(failedBranches, failed...)
+		if onProgress != nil {
+			onProgress(completedCount, len(failedBranches))
 		}
-		pb.SetProgress(completedCount, msg)
 	}
 
-	for _, item := range items {
+	for _, group := range groupPRFetchItems(items) {
 		wg.Go(func() {
 			semaphore <- struct{}{}
 			defer func() { <-semaphore }()
 
-			f := forge.Detect(item.originURL, hosts, forgeConfig)
+			originURL := group[0].originURL
+			failAll := func() {
+				branches := make([]string, len(group))
+				for i, item := range group {
+					branches[i] = item.branch
+				}
+				recordProgress(len(group), branches)
+			}
+
+			f := forge.Detect(originURL, hosts, forgeConfig)
 
 			if err := f.Check(ctx); err != nil {
-				l.Debug("forge check failed", "origin", item.originURL, "err", err)
-				countMutex.Lock()
-				recordProgress(item.branch)
-				countMutex.Unlock()
+				l.Debug("forge check failed", "origin", originURL, "err", err)
+				failAll()
 				return
 			}
 
-			// Get upstream branch name (may differ from local branch name)
-			upstreamBranch := git.GetUpstreamBranch(ctx, item.repoPath, item.branch)
-			if upstreamBranch == "" {
-				upstreamBranch = item.branch
+			// Get upstream branch names (may differ from local branch names)
+			upstreams := make([]string, len(group))
+			var branches []string
+			seen := make(map[string]bool)
+			for i, item := range group {
+				upstream := git.GetUpstreamBranch(ctx, item.repoPath, item.branch)
+				if upstream == "" {
+					upstream = item.branch
+				}
+				upstreams[i] = upstream
+				if !seen[upstream] {
+					seen[upstream] = true
+					branches = append(branches, upstream)
+				}
 			}
 
-			pr, err := f.GetPRForBranch(ctx, item.originURL, upstreamBranch)
+			prs, err := f.GetPRsForBranches(ctx, originURL, branches)
 			if err != nil {
-				l.Debug("PR fetch failed", "branch", item.branch, "err", err)
-				countMutex.Lock()
-				recordProgress(item.branch)
-				countMutex.Unlock()
+				l.Debug("PR fetch failed", "origin", originURL, "branches", len(branches), "err", err)
+				failAll()
 				return
 			}
 
+			var failed []string
 			prMutex.Lock()
-			prCache.Set(item.cacheKey, pr)
+			for i, item := range group {
+				pr := prs[upstreams[i]]
+				if pr == nil {
+					failed = append(failed, item.branch)
+					continue
+				}
+				prCache.Set(item.cacheKey, pr)
+			}
 			prMutex.Unlock()
 
-			countMutex.Lock()
-			recordProgress("")
-			countMutex.Unlock()
+			recordProgress(len(group), failed)
 		})
 	}
 
@@ -129,6 +190,153 @@ func populatePRFields(worktrees []git.Worktree, prCache *prcache.Cache) {
 			worktrees[i].PRState = pr.State
 			worktrees[i].PRURL = pr.URL
 			worktrees[i].PRDraft = pr.IsDraft
+			worktrees[i].PRChecks = pr.Checks
+		}
+	}
+}
+
+// loadPRCache loads the PR cache stored in the wt directory of cfg
+// (see [config.Config.GetPRCachePath]).
+func loadPRCache(ctx context.Context, cfg *config.Config) *prcache.Cache {
+	path, err := cfg.GetPRCachePath()
+	if err != nil {
+		log.FromContext(ctx).Debug("cannot determine PR cache path", "error", err)
+		return prcache.New()
+	}
+	return prcache.LoadFrom(path)
+}
+
+// prCacheTTL returns the PR cache TTLs configured in [pr_cache].
+func prCacheTTL(cfg *config.Config) prcache.TTL {
+	open, closed := cfg.PRCache.Durations()
+	return prcache.TTL{Open: open, Closed: closed}
+}
+
+// stalePRWorktrees returns the worktrees whose cached PR info has expired
+// under ttl. Returns nil when no TTL is configured.
+func stalePRWorktrees(worktrees []git.Worktree, prCache *prcache.Cache, ttl prcache.TTL, now time.Time) []git.Worktree {
+	if !ttl.Enabled() {
+		return nil
+	}
+	var stale []git.Worktree
+	for _, wt := range worktrees {
+		if ttl.IsStale(prCache.Get(prcache.CacheKey(wt.RepoPath, wt.Branch)), now) {
+			stale = append(stale, wt)
+		}
+	}
+	return stale
+}
+
+// autoRefreshPRs refreshes PR cache entries that have expired under the
+// [pr_cache] TTLs. Failures are only logged at debug level since the refresh
+// wasn't requested explicitly.
+func autoRefreshPRs(ctx context.Context, worktrees []git.Worktree, prCache *prcache.Cache, cfg *config.Config) {
+	stale := stalePRWorktrees(worktrees, prCache, prCacheTTL(cfg), time.Now())
+	if len(stale) == 0 {
+		return
+	}
+
+	l := log.FromContext(ctx)
+	l.Debug("refreshing stale PR cache entries", "count", len(stale))
+	if failed := refreshPRs(ctx, stale, prCache, cfg.Hosts, &cfg.Forge); len(failed) > 0 {
+		l.Debug("failed to refresh PR status", "branches", failed)
+	}
+}
+
+// refreshPRsInBackground starts refreshing expired PR cache entries of repos
+// without a progress bar, for use while an interactive UI owns the terminal.
+// The returned stop function cancels outstanding requests, waits for the
+// refresh to return and saves whatever was fetched. prCache must not be used
+// by the caller until stop returns.
+func refreshPRsInBackground(ctx context.Context, cfg *config.Config, repos []registry.Repo, prCache *prcache.Cache) (stop func()) {
+	ttl := prCacheTTL(cfg)
+	if !ttl.Enabled() {
+		return func() {}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		// Full load needed for origin URL and upstream config
+		worktrees, _ := git.LoadWorktreesForRepos(ctx, reposToRefs(repos))
+		stale := stalePRWorktrees(worktrees, prCache, ttl, time.Now())
+		if items := prFetchItems(stale, prCache); len(items) > 0 {
+			fetchPRs(ctx, items, prCache, cfg.Hosts, &cfg.Forge, nil)
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-done
+		if err := prCache.SaveIfDirty(); err != nil {
+			log.FromContext(ctx).Debug("failed to save PR cache", "error", err)
+		}
+	}
+}
+
+// gcPRCache removes PR cache entries for repos that are no longer registered
+// (or no longer exist on disk) and for branches that no longer exist in the
+// given repos. Entries of registered repos outside repos are left untouched.
+// Returns the number of removed entries.
+func gcPRCache(ctx context.Context, prCache *prcache.Cache, reg *registry.Registry, repos []registry.Repo) int {
+	registered := make(map[string]bool, len(reg.Repos))
+	for _, r := range reg.Repos {
+		registered[r.Path] = true
+	}
+
+	// Only list branches of in-scope repos that actually have cache entries
+	cached := make(map[string]bool)
+	for key := range prCache.PRs {
+		repoPath, _ := prcache.SplitKey(key)
+		cached[repoPath] = true
+	}
+
+	var mu sync.Mutex
+	branches := make(map[string]map[string]bool)
+	missing := make(map[string]bool)
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(8)
+	for _, repo := range repos {
+		if !cached[repo.Path] {
+			continue
+		}
+		g.Go(func() error {
+			if _, err := os.Stat(repo.Path); os.IsNotExist(err) {
+				mu.Lock()
+				missing[repo.Path] = true
+				mu.Unlock()
+				return nil
+			}
+			list, err := git.ListLocalBranches(gctx, repo.Path)
+			if err != nil {
+				// Keep entries when branches can't be listed
+				return nil
+			}
+			set := make(map[string]bool, len(list))
+			for _, b := range list {
+				set[b] = true
+			}
+			mu.Lock()
+			branches[repo.Path] = set
+			mu.Unlock()
+			return nil
+		})
+	}
+	_ = g.Wait() // Never fails — unlistable repos are skipped
+
+	removed := prCache.RemoveIf(func(repoPath, branch string) bool {
+		if !registered[repoPath] || missing[repoPath] {
+			return true
+		}
+		if set, ok := branches[repoPath]; ok {
+			return !set[branch]
 		}
+		return false
+	})
+	if removed > 0 {
+		log.FromContext(ctx).Debug("removed stale PR cache entries", "count", removed)
 	}
+	return removed
 }
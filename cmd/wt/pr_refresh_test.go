@@ -1,7 +1,10 @@
 package main
 
 import (
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/raphi011/wt/internal/forge"
 	"github.com/raphi011/wt/internal/git"
@@ -135,3 +138,85 @@ func TestPopulatePRFields(t *testing.T) {
 		populatePRFields([]git.Worktree{}, cache)
 	})
 }
+
+func TestGroupPRFetchItems(t *testing.T) {
+	t.Parallel()
+
+	items := []prFetchItem{
+		{originURL: "git@github.com:org/a.git", repoPath: "/repo/a", branch: "feat-1"},
+		{originURL: "git@github.com:org/b.git", repoPath: "/repo/b", branch: "feat-2"},
+		{originURL: "git@github.com:org/a.git", repoPath: "/repo/a", branch: "feat-3"},
+		{originURL: "git@github.com:org/a.git", repoPath: "/repo/a-clone", branch: "feat-4"},
+	}
+
+	groups := groupPRFetchItems(items)
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 groups, got %d", len(groups))
+	}
+
+	var got [][]string
+	for _, g := range groups {
+		var branches []string
+		for _, item := range g {
+			if item.originURL != g[0].originURL {
+				t.Errorf("group mixes origins: %q and %q", g[0].originURL, item.originURL)
+			}
+			branches = append(branches, item.branch)
+		}
+		got = append(got, branches)
+	}
+
+	want := [][]string{{"feat-1", "feat-3", "feat-4"}, {"feat-2"}}
+	if !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("groupPRFetchItems() branches = %v, want %v", got, want)
+	}
+
+	if groups := groupPRFetchItems(nil); len(groups) != 0 {
+		t.Errorf("expected no groups for nil input, got %d", len(groups))
+	}
+}
+
+func TestStalePRWorktrees(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	worktrees := []git.Worktree{
+		{RepoPath: "/repo", Branch: "fresh"},
+		{RepoPath: "/repo", Branch: "expired"},
+		{RepoPath: "/repo", Branch: "merged"},
+		{RepoPath: "/repo", Branch: "uncached"},
+	}
+
+	cache := prcache.New()
+	cache.Set(prcache.CacheKey("/repo", "fresh"), &forge.PRInfo{State: forge.PRStateOpen, Fetched: true, CachedAt: now})
+	cache.Set(prcache.CacheKey("/repo", "expired"), &forge.PRInfo{State: forge.PRStateOpen, Fetched: true, CachedAt: now.Add(-time.Hour)})
+	cache.Set(prcache.CacheKey("/repo", "merged"), &forge.PRInfo{State: forge.PRStateMerged, Fetched: true, CachedAt: now.Add(-time.Hour)})
+
+	if got := stalePRWorktrees(worktrees, cache, prcache.TTL{}, now); got != nil {
+		t.Errorf("expected no stale worktrees without TTL, got %v", got)
+	}
+
+	got := stalePRWorktrees(worktrees, cache, prcache.TTL{Open: 15 * time.Minute}, now)
+	var branches []string
+	for _, wt := range got {
+		branches = append(branches, wt.Branch)
+	}
+	if want := []string{"expired", "uncached"}; !slices.Equal(branches, want) {
+		t.Errorf("stalePRWorktrees() = %v, want %v", branches, want)
+	}
+}
+
+func TestCdPRStatus(t *testing.T) {
+	t.Parallel()
+
+	if got := cdPRStatus(nil); got != "" {
+		t.Errorf("cdPRStatus(nil) = %q, want empty", got)
+	}
+	if got := cdPRStatus(&forge.PRInfo{Fetched: true}); got != "" {
+		t.Errorf("cdPRStatus(no PR) = %q, want empty", got)
+	}
+	got := cdPRStatus(&forge.PRInfo{Number: 42, State: forge.PRStateOpen, Fetched: true})
+	if !strings.HasPrefix(got, "#42 ") || !strings.Contains(got, "Open") {
+		t.Errorf("cdPRStatus(open #42) = %q, want \"#42 ... Open\"", got)
+	}
+}
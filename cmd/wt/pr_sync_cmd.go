@@ -0,0 +1,348 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/raphi011/wt/internal/config"
+	"github.com/raphi011/wt/internal/forge"
+	"github.com/raphi011/wt/internal/git"
+	"github.com/raphi011/wt/internal/log"
+	"github.com/raphi011/wt/internal/output"
+	"github.com/raphi011/wt/internal/prcache"
+	"github.com/raphi011/wt/internal/registry"
+)
+
+// prSyncAction is what wt pr sync does with a PR worktree.
+type prSyncAction int
+
+const (
+	prSyncUpToDate    prSyncAction = iota // already at the PR head
+	prSyncFastForward                     // PR head is ahead: fast-forward
+	prSyncAhead                           // local commits on top of the PR head: nothing to do
+	prSyncReset                           // PR history was rewritten: reset to the new head
+	prSyncSkip                            // can't update without losing local work
+)
+
+// prSyncState describes a PR worktree after fetching the PR head.
+type prSyncState struct {
+	head             string // worktree HEAD
+	prHead           string // fetched PR head
+	headIsAncestor   bool   // head is reachable from prHead
+	prHeadIsAncestor bool   // prHead is reachable from head
+	dirty            bool   // uncommitted changes or untracked files
+	localCommits     int    // commits on HEAD that aren't part of the PR (see countLocalPRCommits)
+	operation        string // rebase/merge/... in progress
+}
+
+// planPRSync decides how to update a PR worktree. A rewritten PR history
+// only resets worktrees without uncommitted changes and local commits;
+// otherwise the returned reason explains why it was skipped.
+func planPRSync(s prSyncState) (prSyncAction, string) {
+	switch {
+	case s.head == s.prHead:
+		return prSyncUpToDate, ""
+	case s.operation != "":
+		return prSyncSkip, s.operation + " in progress"
+	case s.headIsAncestor:
+		return prSyncFastForward, ""
+	case s.prHeadIsAncestor:
+		return prSyncAhead, ""
+	case s.dirty:
+		return prSyncSkip, "PR history was rewritten and the worktree has uncommitted changes"
+	case s.localCommits > 0:
+		return prSyncSkip, fmt.Sprintf("PR history was rewritten and the worktree has %d local commit(s)", s.localCommits)
+	default:
+		return prSyncReset, ""
+	}
+}
+
+func newPrSyncCmd() *cobra.Command {
+	var all bool
+
+	cmd := &cobra.Command{
+		Use:   "sync [[scope:]branch...]",
+		Short: "Update PR worktrees to the latest PR head",
+		Args:  cobra.ArbitraryArgs,
+		Long: `Update PR worktrees when the PR branch moved on the forge.
+
+Fetches the head of each worktree's PR (from the PR ref on the base repo,
+so PRs from forks work too) and:
+  - fast-forwards the worktree if it is behind
+  - resets it to the new head if the PR history was rewritten (force-push),
+    but only if the worktree is clean and has no local commits
+  - leaves it alone if it only has new local commits
+
+Local commits are those on neither the PR head fetched by the last
+'wt pr checkout' or 'wt pr sync' nor the upstream branch.
+
+The PR status in the cache is refreshed as well.
+
+Target worktrees using [scope:]branch arguments. With no arguments, syncs
+the current worktree. Use --all to sync every worktree with an open PR in
+all registered repos.`,
+		Example: `  wt pr sync                   # Sync current worktree
+  wt pr sync feature-x         # Sync worktree of branch feature-x
+  wt pr sync myrepo:feature-x  # Sync worktree in a specific repo
+  wt pr sync --all             # Sync all PR worktrees`,
+		ValidArgsFunction: completeCdArg,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			cfg := config.FromContext(ctx)
+			l := log.FromContext(ctx)
+
+			if all && len(args) > 0 {
+				return fmt.Errorf("cannot combine --all with branch arguments")
+			}
+
+			reg, err := registry.Load(cfg.RegistryPath)
+			if err != nil {
+				return fmt.Errorf("load registry: %w", err)
+			}
+
+			worktrees, err := resolvePRSyncWorktrees(ctx, reg, args, all)
+			if err != nil {
+				return err
+			}
+
+			prCache := loadPRCache(ctx, cfg)
+			if failed := refreshPRs(ctx, worktrees, prCache, cfg.Hosts, &cfg.Forge); len(failed) > 0 {
+				l.Printf("Warning: failed to fetch PR status for: %v\n", failed)
+			}
+			if err := prCache.SaveIfDirty(); err != nil {
+				l.Printf("Warning: failed to save PR cache: %v\n", err)
+			}
+
+			var synced, failed int
+			for _, wt := range worktrees {
+				pr := prCache.Get(prcache.CacheKey(wt.RepoPath, wt.Branch))
+				if pr == nil || !pr.Fetched || pr.Number == 0 || pr.State != forge.PRStateOpen {
+					if !all {
+						l.Printf("%s:%s: no open PR\n", wt.RepoName, wt.Branch)
+						failed++
+					}
+					continue
+				}
+				synced++
+				if err := syncPRWorktree(ctx, wt, pr.Number); err != nil {
+					l.Printf("%s:%s: %v\n", wt.RepoName, wt.Branch, err)
+					failed++
+				}
+			}
+
+			if all && synced == 0 && failed == 0 {
+				l.Printf("No worktrees with open PRs found\n")
+			}
+			if failed > 0 {
+				return fmt.Errorf("failed to sync %d worktree(s)", failed)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&all, "all", "a", false, "Sync all worktrees with an open PR")
+
+	return cmd
+}
+
+// resolvePRSyncWorktrees returns the worktrees to sync: those of targets,
+// every worktree of all repos with all, or else the current worktree.
+func resolvePRSyncWorktrees(ctx context.Context, reg *registry.Registry, targets []string, all bool) ([]git.Worktree, error) {
+	l := log.FromContext(ctx)
+
+	var repos []registry.Repo
+	want := make(map[string]bool) // worktree paths, or nil for all
+	switch {
+	case all:
+		repos = filterOrphanedRepos(l, reg.Repos)
+		want = nil
+	case len(targets) > 0:
+		resolved, err := resolveWorktreeTargets(ctx, reg, targets)
+		if err != nil {
+			return nil, err
+		}
+		seen := make(map[string]bool)
+		for _, t := range resolved {
+			want[t.Path] = true
+			if !seen[t.RepoPath] {
+				seen[t.RepoPath] = true
+				repos = append(repos, registry.Repo{Name: t.RepoName, Path: t.RepoPath})
+			}
+		}
+	default:
+		repo, err := findOrRegisterCurrentRepoFromContext(ctx, reg)
+		if err != nil {
+			return nil, err
+		}
+		workDir := config.WorkDirFromContext(ctx)
+		branch, err := git.GetCurrentBranch(ctx, workDir)
+		if err != nil {
+			return nil, err
+		}
+		wtPath, found := findWorktreeForBranch(ctx, repo.Path, branch)
+		if !found {
+			return nil, fmt.Errorf("not in a worktree (specify a branch or use --all)")
+		}
+		want[wtPath] = true
+		repos = []registry.Repo{repo}
+	}
+
+	loaded, warnings := git.LoadWorktreesForRepos(ctx, reposToRefs(repos))
+	for _, w := range warnings {
+		l.Printf("Warning: %s: %v\n", w.RepoName, w.Err)
+	}
+
+	var worktrees []git.Worktree
+	for _, wt := range loaded {
+		if wt.Branch == "" || wt.Branch == "(detached)" {
+			continue
+		}
+		if want == nil || want[wt.Path] {
+			worktrees = append(worktrees, wt)
+		}
+	}
+	return worktrees, nil
+}
+
+// syncPRWorktree fetches the head of PR number and updates worktree wt as
+// decided by planPRSync. Updates are recorded in the journal.
+func syncPRWorktree(ctx context.Context, wt git.Worktree, number int) (err error) {
+	l := log.FromContext(ctx)
+	out := output.FromContext(ctx)
+
+	effCfg := resolveEffectiveConfig(ctx, wt.RepoPath)
+	f := forge.Detect(wt.OriginURL, effCfg.Hosts, &effCfg.Forge)
+
+	upstreamRemote := git.GetUpstreamRemote(ctx, wt.Path, wt.Branch)
+	upstreamBranch := git.GetUpstreamBranch(ctx, wt.Path, wt.Branch)
+
+	// Count local commits before fetching: afterwards, a force-pushed
+	// upstream no longer contains the old PR commits.
+	state := prSyncState{}
+	if state.localCommits, err = countLocalPRCommits(ctx, wt, upstreamRemote, upstreamBranch); err != nil {
+		return err
+	}
+	status, err := git.GetStatus(ctx, wt.Path)
+	if err != nil {
+		return err
+	}
+	state.dirty = status.IsDirty()
+	if state.operation, err = git.GetInProgressOperation(ctx, wt.Path); err != nil {
+		return err
+	}
+
+	// Prefer the forge's PR ref, which also covers PRs from forks
+	remote, ref := "origin", f.PRHeadRef(number)
+	if ref == "" {
+		if upstreamRemote == "" || upstreamBranch == "" {
+			return fmt.Errorf("no upstream branch to fetch PR #%d from", number)
+		}
+		remote, ref = upstreamRemote, "refs/heads/"+upstreamBranch
+	}
+	if state.prHead, err = git.FetchRef(ctx, wt.Path, remote, ref); err != nil {
+		return err
+	}
+	if ref != "refs/heads/"+upstreamBranch && upstreamRemote != "" && upstreamBranch != "" {
+		// Keep the remote-tracking branch in sync for status and ahead/behind counts
+		if err := git.FetchBranchFromRemote(ctx, wt.Path, upstreamRemote, upstreamBranch); err != nil {
+			l.Debug("failed to fetch upstream", "branch", upstreamBranch, "error", err)
+		}
+	}
+
+	if state.head, err = git.GetHeadCommit(ctx, wt.Path); err != nil {
+		return err
+	}
+	if state.headIsAncestor, err = git.IsAncestor(ctx, wt.Path, state.head, state.prHead); err != nil {
+		return err
+	}
+	if state.prHeadIsAncestor, err = git.IsAncestor(ctx, wt.Path, state.prHead, state.head); err != nil {
+		return err
+	}
+
+	prefix := fmt.Sprintf("%s:%s (#%d)", wt.RepoName, wt.Branch, number)
+	action, reason := planPRSync(state)
+	if action != prSyncSkip {
+		// The worktree contains the fetched head from now on
+		defer func() {
+			if err == nil {
+				recordPRHead(ctx, wt.Path, wt.Branch, state.prHead)
+			}
+		}()
+	}
+	switch action {
+	case prSyncUpToDate:
+		out.Printf("%s: up to date\n", prefix)
+		return nil
+	case prSyncAhead:
+		out.Printf("%s: up to date, %d local commit(s) not pushed\n", prefix, state.localCommits)
+		return nil
+	case prSyncSkip:
+		return fmt.Errorf("not updated: %s", reason)
+	}
+
+	ctx, op := startJournalOp(ctx, "pr sync")
+	defer func() { op.finish(ctx, err) }()
+	op.setRepo(registry.Repo{Name: wt.RepoName, Path: wt.RepoPath})
+	op.Branch = wt.Branch
+	op.Path = wt.Path
+	op.Detail = fmt.Sprintf("PR #%d", number)
+
+	if action == prSyncFastForward {
+		op.addDetail("fast-forward")
+		if err := git.FastForward(ctx, wt.Path, state.prHead); err != nil {
+			return err
+		}
+		out.Printf("%s: fast-forwarded %s..%s\n", prefix, shortSHA(state.head), shortSHA(state.prHead))
+	} else {
+		op.addDetail("reset")
+		if err := git.ResetHard(ctx, wt.Path, state.prHead); err != nil {
+			return err
+		}
+		out.Printf("%s: reset to %s (PR history was rewritten)\n", prefix, shortSHA(state.prHead))
+	}
+	op.SHA = state.prHead
+	return nil
+}
+
+// countLocalPRCommits counts the commits of worktree wt that aren't part of
+// its PR: those reachable neither from the PR head last fetched into the
+// branch (see [git.GetBranchPRHead]) nor from its upstream. PRs from forks
+// checked out via the PR ref have no remote-tracking branch, so commits on
+// no remote-tracking branch are only counted if neither is known.
+func countLocalPRCommits(ctx context.Context, wt git.Worktree, upstreamRemote, upstreamBranch string) (int, error) {
+	var bases []string
+	if head, _ := git.GetBranchPRHead(ctx, wt.Path, wt.Branch); head != "" {
+		// The old head may be gone after a force-push and gc
+		if sha, err := git.ResolveCommit(ctx, wt.Path, head); err == nil {
+			bases = append(bases, sha)
+		}
+	}
+	if upstreamRemote != "" && upstreamBranch != "" {
+		if ref := "refs/remotes/" + upstreamRemote + "/" + upstreamBranch; git.RefExists(ctx, wt.Path, ref) {
+			bases = append(bases, ref)
+		}
+	}
+	if len(bases) == 0 {
+		return git.CountUnpushedCommits(ctx, wt.Path, "")
+	}
+	return git.CountCommitsNotIn(ctx, wt.Path, bases...)
+}
+
+// recordPRHead records prHead as the PR head last fetched into branch.
+// Failures are logged only: without it, pr sync falls back to counting
+// commits on no remote-tracking branch.
+func recordPRHead(ctx context.Context, repoPath, branch, prHead string) {
+	if err := git.SetBranchPRHead(ctx, repoPath, branch, prHead); err != nil {
+		log.FromContext(ctx).Debug("failed to record PR head", "branch", branch, "error", err)
+	}
+}
+
+// shortSHA abbreviates a commit hash to 7 characters.
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
@@ -0,0 +1,32 @@
+package main
+
+import "testing"
+
+func TestPlanPRSync(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		state prSyncState
+		want  prSyncAction
+	}{
+		{"up to date", prSyncState{head: "a", prHead: "a", dirty: true}, prSyncUpToDate},
+		{"behind", prSyncState{head: "a", prHead: "b", headIsAncestor: true, dirty: true}, prSyncFastForward},
+		{"local commits on top", prSyncState{head: "b", prHead: "a", prHeadIsAncestor: true, localCommits: 1}, prSyncAhead},
+		{"rewritten, clean", prSyncState{head: "a", prHead: "b"}, prSyncReset},
+		{"rewritten, dirty", prSyncState{head: "a", prHead: "b", dirty: true}, prSyncSkip},
+		{"rewritten, local commits", prSyncState{head: "a", prHead: "b", localCommits: 2}, prSyncSkip},
+		{"rebase in progress", prSyncState{head: "a", prHead: "b", headIsAncestor: true, operation: "rebase"}, prSyncSkip},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reason := planPRSync(tt.state)
+			if got != tt.want {
+				t.Errorf("planPRSync() = %v, want %v", got, tt.want)
+			}
+			if (got == prSyncSkip) != (reason != "") {
+				t.Errorf("planPRSync() reason = %q for action %v", reason, got)
+			}
+		})
+	}
+}
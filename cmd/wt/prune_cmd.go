@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"os"
 	"path/filepath"
 	"slices"
 	"strings"
@@ -19,6 +20,7 @@ import (
 	"github.com/raphi011/wt/internal/output"
 	"github.com/raphi011/wt/internal/prcache"
 	"github.com/raphi011/wt/internal/registry"
+	"github.com/raphi011/wt/internal/trash"
 	"github.com/raphi011/wt/internal/ui/static"
 	"github.com/raphi011/wt/internal/ui/styles"
 	"github.com/raphi011/wt/internal/ui/wizard/flows"
@@ -45,18 +47,39 @@ func newPruneCmd() *cobra.Command {
 		GroupID: GroupCore,
 		Long: `Remove worktrees with merged PRs.
 
-Without arguments, removes all worktrees with merged PRs in current repo.
+Without arguments, removes all merged worktrees in current repo. A worktree
+is considered merged when its PR is merged (from the PR cache), or when local
+git data shows its branch was merged into origin/<default>:
+
+  pr             PR/MR is merged on the forge
+  ancestor       branch tip is on origin/<default> via a merge commit, or after
+                 commits made on the branch (fast-forward)
+  squash         branch changes are on origin/<default> as other commits (squash/rebase)
+  upstream-gone  branch's upstream was deleted on the remote
+
+Use -d -v to also see which worktrees are skipped and why.
 Use --stale to also prune worktrees older than stale_days (default 14).
 Use --global to prune all registered repos.
 Use --interactive to select worktrees to prune.
 
+Stacked branches (see 'wt stack') built on a merged branch are retargeted
+to the merged branch's base, both locally and their PRs on the forge.
+
+Worktrees with uncommitted changes, untracked files, unpushed commits or
+stash entries are never removed without -f; they are listed as blocked
+together with the reason.
+
+With [trash] enabled = true in the config, removed worktrees are moved to
+~/.wt/trash and can be restored with 'wt undo' (see 'wt trash').
+
 Target specific worktrees using [scope:]branch arguments where scope can be
-a repo name or label. Worktrees with a merged PR can be pruned without -f.
-Use -f to prune worktrees whose PR is not yet confirmed merged.`,
+a repo name or label. Merged worktrees can be pruned without -f.
+Use -f to prune worktrees that are not detected as merged.`,
 		Example: `  wt prune                         # Remove worktrees with merged PRs
   wt prune --stale                 # Also prune stale worktrees
   wt prune --global                # Prune all repos
   wt prune -d                      # Dry-run: preview without removing
+  wt prune -d -v                   # Dry-run: also show skipped worktrees and why
   wt prune -i                      # Interactive mode
   wt prune feature                 # Remove merged feature worktree
   wt prune feature -f              # Remove unmerged feature worktree
@@ -128,7 +151,7 @@ Use -f to prune worktrees whose PR is not yet confirmed merged.`,
 			}
 
 			// Load PR cache
-			prCache := prcache.Load()
+			prCache := loadPRCache(ctx, cfg)
 
 			// Reset cache if requested
 			if resetCache {
@@ -136,14 +159,24 @@ Use -f to prune worktrees whose PR is not yet confirmed merged.`,
 				l.Println("Cache reset: PR info cleared")
 			}
 
-			// Refresh PR status if requested
+			gcPRCache(ctx, prCache, reg, repos)
+
+			// Refresh PR status if requested, otherwise only expired entries
 			if refresh {
 				if failed := refreshPRs(ctx, allWorktrees, prCache, cfg.Hosts, &cfg.Forge); len(failed) > 0 {
 					l.Printf("Warning: failed to fetch PR status for: %v\n", failed)
 				}
+			} else {
+				autoRefreshPRs(ctx, allWorktrees, prCache, cfg)
+			}
+
+			// Persist refreshed entries now; interactive mode may return early
+			if err := prCache.SaveIfDirty(); err != nil {
+				l.Printf("Warning: failed to save cache: %v\n", err)
 			}
 
 			populatePRFields(allWorktrees, prCache)
+			detectMerges(ctx, allWorktrees)
 
 			// Sort by repo name
 			slices.SortFunc(allWorktrees, func(a, b git.Worktree) int {
@@ -159,7 +192,7 @@ Use -f to prune worktrees whose PR is not yet confirmed merged.`,
 			}
 
 			for _, wt := range allWorktrees {
-				if wt.PRState == forge.PRStateMerged {
+				if wt.MergeReason != "" {
 					toRemove = append(toRemove, wt)
 				} else if stale && isStaleWorktree(wt, cfg.Prune.StaleDays) {
 					toRemove = append(toRemove, wt)
@@ -172,9 +205,12 @@ Use -f to prune worktrees whose PR is not yet confirmed merged.`,
 			if interactive {
 				wizardInfos := make([]flows.PruneWorktreeInfo, 0, len(allWorktrees))
 				for i, wt := range allWorktrees {
-					isPrunable := wt.PRState == forge.PRStateMerged
+					isPrunable := wt.MergeReason != ""
 					isStaleWt := false
-					reason := styles.FormatPRState(wt.PRState, wt.PRDraft)
+					reason := styles.FormatMergeReason(string(wt.MergeReason))
+					if reason == "" {
+						reason = styles.FormatPRState(wt.PRState, wt.PRDraft)
+					}
 					if !isPrunable && stale && isStaleWorktree(wt, cfg.Prune.StaleDays) {
 						isPrunable = true
 						isStaleWt = true
@@ -223,6 +259,22 @@ Use -f to prune worktrees whose PR is not yet confirmed merged.`,
 				}
 			}
 
+			// Refuse worktrees with unsaved work unless --force
+			var blocked []git.Worktree
+			var blockReasons map[string]string
+			if !force && len(toRemove) > 0 {
+				blockReasons = checkUnsavedWork(ctx, toRemove)
+				var safe []git.Worktree
+				for _, wt := range toRemove {
+					if blockReasons[wt.Path] != "" {
+						blocked = append(blocked, wt)
+					} else {
+						safe = append(safe, wt)
+					}
+				}
+				toRemove = safe
+			}
+
 			deleteBranchesExplicit := cmd.Flags().Changed("delete-branches") || cmd.Flags().Changed("no-delete-branches")
 			// Determine if we should delete local branches (default from global config)
 			shouldDeleteBranches := cfg.Prune.DeleteLocalBranches
@@ -245,9 +297,9 @@ Use -f to prune worktrees whose PR is not yet confirmed merged.`,
 
 			// Print summary
 			if dryRun {
-				out.Printf("Would remove %d worktree(s), skip %d\n", len(removed), len(toSkip)+len(failed))
+				out.Printf("Would remove %d worktree(s), skip %d\n", len(removed), len(toSkip)+len(blocked)+len(failed))
 			} else {
-				out.Printf("Removed %d worktree(s), skipped %d\n", len(removed), len(toSkip)+len(failed))
+				out.Printf("Removed %d worktree(s), skipped %d\n", len(removed), len(toSkip)+len(blocked)+len(failed))
 			}
 
 			// Display results table
@@ -255,9 +307,31 @@ Use -f to prune worktrees whose PR is not yet confirmed merged.`,
 				out.Println()
 				var rows [][]string
 				for _, wt := range removed {
-					rows = append(rows, static.WorktreeTableRow(wt, cfg.Prune.StaleDays))
+					rows = append(rows, static.PruneTableRow(wt, cfg.Prune.StaleDays, pruneReason(wt, cfg.Prune.StaleDays)))
+				}
+				out.Print(static.RenderTable(static.PruneTableHeaders, rows))
+			}
+
+			// Worktrees that would lose work are always reported
+			if len(blocked) > 0 {
+				out.Println()
+				out.Println("Blocked (use -f to remove anyway):")
+				var rows [][]string
+				for _, wt := range blocked {
+					rows = append(rows, []string{wt.RepoName, wt.Branch, blockReasons[wt.Path]})
 				}
-				out.Print(static.RenderTable(static.WorktreeTableHeaders, rows))
+				out.Print(static.RenderTable([]string{"REPO", "BRANCH", "REASON"}, rows))
+			}
+
+			// Verbose dry-run: explain why each remaining worktree is kept
+			if dryRun && l.IsVerbose() && !interactive && len(toSkip) > 0 {
+				out.Println()
+				out.Println("Skipped:")
+				var rows [][]string
+				for _, wt := range toSkip {
+					rows = append(rows, []string{wt.RepoName, wt.Branch, pruneSkipReason(wt, cfg.Prune.StaleDays)})
+				}
+				out.Print(static.RenderTable([]string{"REPO", "BRANCH", "REASON"}, rows))
 			}
 
 			// Save PR cache once at the end
@@ -270,7 +344,7 @@ Use -f to prune worktrees whose PR is not yet confirmed merged.`,
 	}
 
 	cmd.Flags().BoolVarP(&dryRun, "dry-run", "d", false, "Preview without removing")
-	cmd.Flags().BoolVarP(&force, "force", "f", false, "Force remove unmerged worktrees")
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "Force remove unmerged worktrees and worktrees with unsaved work")
 	cmd.Flags().BoolVarP(&global, "global", "g", false, "Prune all repos")
 	cmd.Flags().BoolVarP(&refresh, "refresh-pr", "R", false, "Refresh PR status first")
 	cmd.Flags().BoolVar(&resetCache, "reset-cache", false, "Clear all cached data")
@@ -307,12 +381,132 @@ func isStaleWorktree(wt git.Worktree, staleDays int) bool {
 	return time.Since(wt.CommitDate) > time.Duration(staleDays)*24*time.Hour
 }
 
+// detectMerges sets MergeReason on each worktree: forge-confirmed PR merges
+// (from PRState) first, then local detection against origin/<default>
+// via [git.DetectLocalMerges]. Worktrees whose PR is open or was closed
+// without merging are not merged, whatever local git data suggests.
+func detectMerges(ctx context.Context, worktrees []git.Worktree) {
+	var local []git.Worktree
+	var localIdx []int
+	for i := range worktrees {
+		switch worktrees[i].PRState {
+		case forge.PRStateMerged:
+			worktrees[i].MergeReason = git.MergeReasonPR
+		case forge.PRStateOpen, forge.PRStateDraft, forge.PRStateClosed:
+		default:
+			local = append(local, worktrees[i])
+			localIdx = append(localIdx, i)
+		}
+	}
+	l := log.FromContext(ctx)
+	for _, w := range git.DetectLocalMerges(ctx, local) {
+		l.Printf("Warning: %s: merge detection failed: %v\n", w.RepoName, w.Err)
+	}
+	for j, i := range localIdx {
+		worktrees[i].MergeReason = local[j].MergeReason
+	}
+}
+
+// pruneReason returns the REASON column value for a worktree being pruned:
+// the merge reason, "stale" for stale worktrees, or "selected" for
+// worktrees picked manually in interactive mode.
+func pruneReason(wt git.Worktree, staleDays int) string {
+	if wt.MergeReason != "" {
+		return string(wt.MergeReason)
+	}
+	if isStaleWorktree(wt, staleDays) {
+		return "stale"
+	}
+	return "selected"
+}
+
+// pruneSkipReason explains why auto-prune keeps a worktree (shown with -d -v).
+func pruneSkipReason(wt git.Worktree, staleDays int) string {
+	switch {
+	case wt.Path == wt.RepoPath:
+		return "main worktree"
+	case wt.PRState == forge.PRStateOpen:
+		return "PR open"
+	case wt.PRState == forge.PRStateClosed:
+		return "PR closed without merge"
+	case isStaleWorktree(wt, staleDays):
+		return "stale (use --stale)"
+	default:
+		return "not merged"
+	}
+}
+
+// unsavedWorkReasons lists the work that would be lost by removing the worktree:
+// an in-progress operation, uncommitted changes, untracked files, unpushed
+// commits and stash entries. Returns nil when the worktree is safe to remove.
+func unsavedWorkReasons(st git.WorktreeStatus) []string {
+	var reasons []string
+	if st.Operation != "" {
+		reasons = append(reasons, st.Operation+" in progress")
+	}
+	if st.Staged+st.Unstaged+st.Conflicted > 0 {
+		reasons = append(reasons, "uncommitted changes")
+	}
+	if st.Untracked > 0 {
+		reasons = append(reasons, "untracked files")
+	}
+	if st.Unpushed > 0 {
+		reasons = append(reasons, fmt.Sprintf("%d unpushed commit(s)", st.Unpushed))
+	}
+	if st.Stashes > 0 {
+		reasons = append(reasons, fmt.Sprintf("%d stash(es)", st.Stashes))
+	}
+	return reasons
+}
+
+// checkUnsavedWork returns, keyed by worktree path, why removing each worktree
+// would lose work (see [unsavedWorkReasons]). Safe worktrees are not in the map.
+// Unpushed commits of a branch without a live upstream are ignored when its
+// changes are already on origin/<default> (squash or rebase merge).
+// Worktrees whose directory no longer exists have nothing to lose.
+func checkUnsavedWork(ctx context.Context, worktrees []git.Worktree) map[string]string {
+	l := log.FromContext(ctx)
+
+	statuses, warnings := git.LoadWorktreeStatuses(ctx, worktrees)
+	for _, w := range warnings {
+		l.Debug("status check failed", "repo", w.RepoName, "error", w.Err)
+	}
+
+	blocked := make(map[string]string)
+	checked := make(map[string]bool, len(statuses))
+	for _, st := range statuses {
+		checked[st.Path] = true
+		if st.Unpushed > 0 && (st.Upstream == "" || st.UpstreamGone) && st.DefaultBranch != "" {
+			merged, err := git.IsSquashMerged(ctx, st.RepoPath, st.Branch, "origin/"+st.DefaultBranch)
+			if err == nil && merged {
+				st.Unpushed = 0
+			}
+		}
+		if reasons := unsavedWorkReasons(st); len(reasons) > 0 {
+			blocked[st.Path] = strings.Join(reasons, ", ")
+		}
+	}
+
+	for _, wt := range worktrees {
+		if checked[wt.Path] {
+			continue
+		}
+		if _, err := os.Stat(wt.Path); os.IsNotExist(err) {
+			continue
+		}
+		blocked[wt.Path] = "status unavailable"
+	}
+
+	return blocked
+}
+
 // runPruneTargets handles removal of specific worktrees by [scope:]branch args.
 // When global is false, unscoped targets are scoped to the current repo.
 // Force is only required when at least one target is not prunable (not merged).
 func runPruneTargets(ctx context.Context, reg *registry.Registry, targets []string, global, force, dryRun bool, opts pruneOpts) error {
 	l := log.FromContext(ctx)
 	out := output.FromContext(ctx)
+	cfg := config.FromContext(ctx)
 
 	// When not global, scope unscoped targets to current repo
 	if !global {
@@ -348,10 +542,11 @@ func runPruneTargets(ctx context.Context, reg *registry.Registry, targets []stri
 	}
 
 	// Enrich with merge info to determine if force is needed
-	prCache := prcache.Load()
+	prCache := loadPRCache(ctx, cfg)
 
 	// Enrich with PR state from cache
 	populatePRFields(toRemove, prCache)
+	detectMerges(ctx, toRemove)
 
 	// Require force only when at least one target is not prunable
 	if !force {
@@ -364,12 +559,27 @@ func runPruneTargets(ctx context.Context, reg *registry.Registry, targets []stri
 		if len(unprunable) > 0 {
 			return fmt.Errorf("cannot prune unmerged worktrees without -f/--force: %s\nHint: run with -R/--refresh-pr to fetch latest PR status, or use -f to force removal", strings.Join(unprunable, ", "))
 		}
+
+		blockReasons := checkUnsavedWork(ctx, toRemove)
+		var blocked []string
+		for _, wt := range toRemove {
+			if reason := blockReasons[wt.Path]; reason != "" {
+				blocked = append(blocked, fmt.Sprintf("  %s:%s: %s", wt.RepoName, wt.Branch, reason))
+			}
+		}
+		if len(blocked) > 0 {
+			return fmt.Errorf("cannot prune worktrees with unsaved work without -f/--force:\n%s", strings.Join(blocked, "\n"))
+		}
 	}
 
 	if dryRun {
 		out.Println("Would remove:")
 		for _, wt := range toRemove {
-			out.Printf("  %s:%s (%s)\n", wt.RepoName, wt.Branch, wt.Path)
+			if wt.MergeReason != "" {
+				out.Printf("  %s:%s (%s) [%s]\n", wt.RepoName, wt.Branch, wt.Path, wt.MergeReason)
+			} else {
+				out.Printf("  %s:%s (%s)\n", wt.RepoName, wt.Branch, wt.Path)
+			}
 		}
 		return nil
 	}
@@ -400,9 +610,9 @@ func runPruneTargets(ctx context.Context, reg *registry.Registry, targets []stri
 }
 
 // isWorktreePrunable returns true if the worktree is safe to prune without force
-// (merged via forge-confirmed PR).
+// (merged via forge-confirmed PR or detected as merged locally).
 func isWorktreePrunable(wt git.Worktree) bool {
-	return wt.PRState == forge.PRStateMerged
+	return wt.PRState == forge.PRStateMerged || wt.MergeReason != ""
 }
 
 // pruneWorktrees removes the given worktrees and runs hooks.
@@ -419,17 +629,12 @@ func pruneWorktrees(ctx context.Context, toRemove []git.Worktree, opts pruneOpts
 	l := log.FromContext(ctx)
 	cfg := config.FromContext(ctx)
 
-	// Load history for cleanup
+	// History entries of removed worktrees, dropped after the loop
 	histPath, err := cfg.GetHistoryPath()
 	if err != nil {
 		l.Printf("Warning: failed to determine history path: %v\n", err)
 	}
-	hist, err := history.Load(histPath)
-	if err != nil {
-		l.Printf("Warning: failed to load history for prune cleanup: %v\n", err)
-		hist = &history.History{}
-	}
-	historyChanged := false
+	var removedPaths []string
 
 	hookEnv, err := hooks.ParseEnvWithStdin(opts.Hooks.RawArgs)
 	if err != nil {
@@ -442,6 +647,9 @@ func pruneWorktrees(ctx context.Context, toRemove []git.Worktree, opts pruneOpts
 		l.Printf("Warning: failed to determine config dir: %v\n", err)
 	}
 
+	// With the trash enabled, worktrees are moved there instead of deleted
+	trashDir := trashDirFromConfig(ctx, cfg)
+
 	// pruneHookCtx builds a hooks.Context for a worktree in the prune loop.
 	pruneHookCtx := func(wt git.Worktree, phase hooks.PhaseType) hooks.Context {
 		return hooks.Context{
@@ -460,24 +668,55 @@ func pruneWorktrees(ctx context.Context, toRemove []git.Worktree, opts pruneOpts
 		// Resolve per-repo config for hooks and delete_local_branches
 		effCfg := resolveEffectiveConfig(ctx, wt.RepoPath)
 
+		// Journal each worktree separately, hooks run with wtCtx are recorded
+		wtCtx, op := startJournalOp(ctx, "prune")
+		op.Repo = wt.RepoName
+		op.RepoPath = wt.RepoPath
+		op.Branch = wt.Branch
+		op.Path = wt.Path
+		op.SHA, _ = git.GetHeadCommit(ctx, wt.Path) //nolint:errcheck
+		if wt.MergeReason != "" {
+			op.addDetail("merged (" + string(wt.MergeReason) + ")")
+		}
+
 		// Run before-prune hooks (can skip this worktree)
 		beforeMatches, err := hooks.SelectHooks(effCfg.Hooks, opts.Hooks.HookNames, opts.Hooks.NoHook, hooks.HookSelector{Command: hooks.CommandPrune, Phase: hooks.PhaseBefore})
 		if err != nil {
 			l.Printf("Warning: failed to select before hooks for %s: %v\n", wt.RepoName, err)
+			op.finish(ctx, err)
 			continue
 		}
 		if len(beforeMatches) > 0 {
-			if err := hooks.RunBeforeHooks(ctx, beforeMatches, pruneHookCtx(wt, hooks.PhaseBefore), wt.Path); err != nil {
+			if err := hooks.RunBeforeHooks(wtCtx, beforeMatches, pruneHookCtx(wt, hooks.PhaseBefore), wt.Path); err != nil {
 				l.Printf("Skipping %s: before-hook aborted: %v\n", wt.Branch, err)
+				op.finish(ctx, fmt.Errorf("before-hook aborted: %w", err))
 				continue
 			}
 		}
 
-		if err := git.RemoveWorktree(ctx, wt, opts.Force); err != nil {
+		var trashed *trash.Entry
+		if trashDir != "" {
+			trashed, err = trashWorktree(ctx, trashDir, wt, "prune")
+		} else {
+			err = git.RemoveWorktree(ctx, wt, opts.Force)
+		}
+		if err != nil {
 			l.Printf("Warning: failed to remove %s: %v\n", wt.Path, err)
 			failed = append(failed, wt)
+			op.finish(ctx, err)
 			continue
 		}
+		if trashed != nil {
+			op.addDetail("moved to trash " + trashed.ID)
+		}
+
+		// Stacked children of a merged branch move to its base before the
+		// branch (and with it the recorded parents) is deleted
+		if isWorktreePrunable(wt) {
+			if children := retargetStackChildren(ctx, wt, opts.PRCache); len(children) > 0 {
+				op.addDetail("retargeted " + strings.Join(children, ", "))
+			}
+		}
 
 		// Remove from PR cache
 		if opts.PRCache != nil {
@@ -485,9 +724,7 @@ func pruneWorktrees(ctx context.Context, toRemove []git.Worktree, opts pruneOpts
 		}
 
 		// Remove from history
-		if hist.RemoveByPath(wt.Path) {
-			historyChanged = true
-		}
+		removedPaths = append(removedPaths, wt.Path)
 
 		removed = append(removed, wt)
 
@@ -497,14 +734,21 @@ func pruneWorktrees(ctx context.Context, toRemove []git.Worktree, opts pruneOpts
 			shouldDelete = effCfg.Prune.DeleteLocalBranches
 		}
 		if shouldDelete {
-			// Force delete if forge confirmed merge (handles squash merges),
-			// safe delete (-d) otherwise (including locally-merged branches,
-			// where git's own ancestry check in -d provides a safety net).
-			forceDelete := wt.PRState == forge.PRStateMerged
+			// Force delete if forge confirmed merge or squash/rebase merge was
+			// detected (git's ancestry check in -d would refuse those), safe
+			// delete (-d) otherwise, where git's own check provides a safety net.
+			forceDelete := wt.PRState == forge.PRStateMerged || wt.MergeReason == git.MergeReasonSquash
 			if err := git.DeleteLocalBranch(ctx, wt.RepoPath, wt.Branch, forceDelete); err != nil {
 				l.Printf("Warning: failed to delete branch %s: %v\n", wt.Branch, err)
 			} else {
 				l.Debug("deleted branch", "branch", wt.Branch)
+				op.addDetail("deleted branch")
+				if trashed != nil {
+					trashed.BranchDeleted = true
+					if err := trashed.Save(); err != nil {
+						l.Printf("Warning: failed to update trash entry %s: %v\n", trashed.ID, err)
+					}
+				}
 			}
 		}
 
@@ -514,13 +758,25 @@ func pruneWorktrees(ctx context.Context, toRemove []git.Worktree, opts pruneOpts
 			l.Printf("Warning: failed to select hooks for %s: %v\n", wt.RepoName, err)
 		}
 		if len(afterMatches) > 0 {
-			hooks.RunForEach(ctx, afterMatches, pruneHookCtx(wt, hooks.PhaseAfter), wt.RepoPath)
+			if err := hooks.RunForEach(wtCtx, afterMatches, pruneHookCtx(wt, hooks.PhaseAfter), wt.RepoPath); err != nil {
+				l.Printf("Stopping prune: after-hook failed for %s: %v\n", wt.Branch, err)
+				op.finish(ctx, fmt.Errorf("after-hook failed: %w", err))
+				break
+			}
 		}
+
+		op.finish(ctx, nil)
 	}
 
-	// Save history if any entries were removed
-	if historyChanged {
-		if err := hist.Save(histPath); err != nil {
+	// Drop history entries of removed worktrees
+	if len(removedPaths) > 0 && histPath != "" {
+		err := history.Transact(histPath, func(h *history.History) error {
+			for _, path := range removedPaths {
+				h.RemoveByPath(path)
+			}
+			return nil
+		})
+		if err != nil {
 			l.Printf("Warning: failed to save history after prune: %v\n", err)
 		}
 	}
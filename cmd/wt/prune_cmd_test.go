@@ -1,6 +1,7 @@
 package main
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -124,22 +125,79 @@ func TestIsWorktreePrunable(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
-		name  string
-		state string
-		want  bool
+		name   string
+		state  string
+		reason git.MergeReason
+		want   bool
 	}{
-		{"merged PR is prunable", forge.PRStateMerged, true},
-		{"open PR is not prunable", forge.PRStateOpen, false},
-		{"closed PR is not prunable", forge.PRStateClosed, false},
-		{"no PR is not prunable", "", false},
+		{"merged PR is prunable", forge.PRStateMerged, "", true},
+		{"open PR is not prunable", forge.PRStateOpen, "", false},
+		{"closed PR is not prunable", forge.PRStateClosed, "", false},
+		{"no PR is not prunable", "", "", false},
+		{"ancestor merge is prunable", "", git.MergeReasonAncestor, true},
+		{"squash merge is prunable", "", git.MergeReasonSquash, true},
+		{"upstream gone is prunable", "", git.MergeReasonUpstreamGone, true},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			wt := git.Worktree{PRState: tt.state}
+			wt := git.Worktree{PRState: tt.state, MergeReason: tt.reason}
 			got := isWorktreePrunable(wt)
 			if got != tt.want {
-				t.Errorf("isWorktreePrunable(%q) = %v, want %v", tt.state, got, tt.want)
+				t.Errorf("isWorktreePrunable(%q, %q) = %v, want %v", tt.state, tt.reason, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPruneSkipReason(t *testing.T) {
+	t.Parallel()
+
+	old := time.Now().Add(-30 * 24 * time.Hour)
+
+	tests := []struct {
+		name string
+		wt   git.Worktree
+		want string
+	}{
+		{"main worktree", git.Worktree{Path: "/repo", RepoPath: "/repo"}, "main worktree"},
+		{"open PR", git.Worktree{Path: "/wt", RepoPath: "/repo", PRState: forge.PRStateOpen}, "PR open"},
+		{"closed PR", git.Worktree{Path: "/wt", RepoPath: "/repo", PRState: forge.PRStateClosed}, "PR closed without merge"},
+		{"stale", git.Worktree{Path: "/wt", RepoPath: "/repo", CommitDate: old}, "stale (use --stale)"},
+		{"not merged", git.Worktree{Path: "/wt", RepoPath: "/repo", CommitDate: time.Now()}, "not merged"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := pruneSkipReason(tt.wt, 14); got != tt.want {
+				t.Errorf("pruneSkipReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnsavedWorkReasons(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		st   git.WorktreeStatus
+		want []string
+	}{
+		{"clean", git.WorktreeStatus{}, nil},
+		{"behind only", git.WorktreeStatus{Behind: 3, DefaultBehind: 5}, nil},
+		{"staged and untracked", git.WorktreeStatus{Staged: 1, Untracked: 2}, []string{"uncommitted changes", "untracked files"}},
+		{"conflict during rebase", git.WorktreeStatus{Conflicted: 1, Operation: git.OperationRebase}, []string{"rebase in progress", "uncommitted changes"}},
+		{"unpushed and stashes", git.WorktreeStatus{Unpushed: 2, Stashes: 1}, []string{"2 unpushed commit(s)", "1 stash(es)"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := unsavedWorkReasons(tt.st)
+			if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+				t.Errorf("unsavedWorkReasons() = %q, want %q", got, tt.want)
 			}
 		})
 	}
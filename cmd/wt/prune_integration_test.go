@@ -3,14 +3,20 @@
 package main
 
 import (
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"testing"
 
 	"github.com/raphi011/wt/internal/config"
 	"github.com/raphi011/wt/internal/forge"
 	"github.com/raphi011/wt/internal/git"
+	"github.com/raphi011/wt/internal/log"
+	"github.com/raphi011/wt/internal/prcache"
 	"github.com/raphi011/wt/internal/registry"
 )
 
@@ -947,7 +953,8 @@ func TestPrune_StaleFlag_RemovesOldWorktrees(t *testing.T) {
 	ctx := testContextWithConfig(t, cfg, repoPath)
 	cmd := newPruneCmd()
 	cmd.SetContext(ctx)
-	cmd.SetArgs([]string{"--stale"})
+	// -f: the backdated commit was never pushed, which blocks removal otherwise
+	cmd.SetArgs([]string{"--stale", "-f"})
 
 	if err := cmd.Execute(); err != nil {
 		t.Fatalf("prune --stale command failed: %v", err)
@@ -1759,12 +1766,12 @@ func TestPrune_ExplicitHookFlag(t *testing.T) {
 	}
 }
 
-// TestPrune_LocallyMergedBranch_RequiresForce tests that a branch merged via
-// git ancestry alone still requires --force, since only forge-confirmed PR merges
-// allow force-free pruning.
+// TestPrune_LocallyMergedBranch_RequiresForce tests that a branch merged only
+// into the local main branch still requires --force, since local merge
+// detection compares against origin/<default>.
 //
-// Scenario: User runs `wt prune feature` where feature is merged into main via
-// git but has no PR cache entry
+// Scenario: User runs `wt prune feature` where feature is merged into local main
+// (no origin remote) and has no PR cache entry
 // Expected: Error requiring -f
 func TestPrune_LocallyMergedBranch_RequiresForce(t *testing.T) {
 	t.Parallel()
@@ -1958,8 +1965,9 @@ func TestPrune_MixedTargets_RequiresForce(t *testing.T) {
 	if !strings.Contains(err.Error(), "test-repo:unmerged") {
 		t.Fatalf("error should list the unmerged branch: %v", err)
 	}
-	// Without a forge-confirmed PR merge in the cache, git-merged branches also
-	// require -f — so both branches appear in the error.
+	// Without a forge-confirmed PR merge in the cache or an origin/<default> to
+	// compare against, git-merged branches also require -f — so both branches
+	// appear in the error.
 	if !strings.Contains(err.Error(), "test-repo:merged") {
 		t.Fatalf("error should list the git-merged branch too (no PR cache entry): %v", err)
 	}
@@ -1972,3 +1980,596 @@ func TestPrune_MixedTargets_RequiresForce(t *testing.T) {
 		t.Error("unmerged worktree should not be removed on error")
 	}
 }
+
+// saveSingleRepoRegistry writes a registry containing only test-repo and returns its path.
+func saveSingleRepoRegistry(t *testing.T, tmpDir, repoPath string) string {
+	t.Helper()
+	regFile := filepath.Join(tmpDir, ".wt", "repos.json")
+	if err := os.MkdirAll(filepath.Dir(regFile), 0755); err != nil {
+		t.Fatalf("failed to create registry dir: %v", err)
+	}
+	reg := &registry.Registry{
+		Repos: []registry.Repo{
+			{Name: "test-repo", Path: repoPath},
+		},
+	}
+	if err := reg.Save(regFile); err != nil {
+		t.Fatalf("failed to save registry: %v", err)
+	}
+	return regFile
+}
+
+// mustRunGit runs a git command and fails the test on error.
+func mustRunGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	if out, err := runGitCommand(dir, args...); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, out)
+	}
+}
+
+// TestPrune_AncestorMergedIntoOrigin tests that auto-prune removes worktrees
+// whose branch was merged into origin/<default>, without any PR cache entry.
+//
+// Scenario: feature is merged with --no-ff into main and pushed; user runs `wt prune`
+// Expected: Worktree is removed and the results table shows reason "ancestor"
+func TestPrune_AncestorMergedIntoOrigin(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := resolvePath(t, t.TempDir())
+
+	repoPath, _ := setupTestRepoWithOrigin(t, tmpDir, "test-repo")
+	wtPath := createTestWorktree(t, repoPath, "feature")
+	addCommit(t, wtPath, "feature.txt", "feature commit")
+
+	mustRunGit(t, repoPath, "merge", "--no-ff", "-m", "Merge feature", "feature")
+	mustRunGit(t, repoPath, "push", "origin", "main")
+
+	regFile := saveSingleRepoRegistry(t, tmpDir, repoPath)
+
+	cfg := &config.Config{RegistryPath: regFile}
+	ctx, out := testContextWithConfigAndOutput(t, cfg, repoPath)
+	cmd := newPruneCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("prune command failed: %v", err)
+	}
+
+	if _, err := os.Stat(wtPath); err == nil {
+		t.Error("ancestor-merged worktree should be removed")
+	}
+	if !strings.Contains(out.String(), "ancestor") {
+		t.Errorf("output should show merge reason 'ancestor', got:\n%s", out.String())
+	}
+}
+
+// TestPrune_SquashMergedIntoOrigin tests that auto-prune removes squash-merged
+// worktrees and force-deletes their branch (git branch -d would refuse).
+//
+// Scenario: feature has two commits squash-merged into main and pushed;
+// user runs `wt prune -b`
+// Expected: Worktree is removed, branch is deleted, reason "squash" is shown
+func TestPrune_SquashMergedIntoOrigin(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := resolvePath(t, t.TempDir())
+
+	repoPath, _ := setupTestRepoWithOrigin(t, tmpDir, "test-repo")
+	wtPath := createTestWorktree(t, repoPath, "feature")
+	addCommit(t, wtPath, "feature-a.txt", "feature commit a")
+	addCommit(t, wtPath, "feature-b.txt", "feature commit b")
+
+	mustRunGit(t, repoPath, "merge", "--squash", "feature")
+	mustRunGit(t, repoPath, "commit", "-m", "Squashed feature")
+	mustRunGit(t, repoPath, "push", "origin", "main")
+
+	regFile := saveSingleRepoRegistry(t, tmpDir, repoPath)
+
+	cfg := &config.Config{RegistryPath: regFile}
+	ctx, out := testContextWithConfigAndOutput(t, cfg, repoPath)
+	cmd := newPruneCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"-b"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("prune command failed: %v", err)
+	}
+
+	if _, err := os.Stat(wtPath); err == nil {
+		t.Error("squash-merged worktree should be removed")
+	}
+	if !strings.Contains(out.String(), "squash") {
+		t.Errorf("output should show merge reason 'squash', got:\n%s", out.String())
+	}
+	if _, err := runGitCommand(repoPath, "rev-parse", "--verify", "refs/heads/feature"); err == nil {
+		t.Error("squash-merged branch should be deleted")
+	}
+}
+
+// TestPrune_UpstreamGone tests that auto-prune removes worktrees whose
+// upstream branch was deleted on the remote.
+//
+// Scenario: feature was pushed with tracking, then deleted on origin and fetched with --prune;
+// user runs `wt prune -f` (the commit now exists on no remote, which blocks removal otherwise)
+// Expected: Worktree is removed
+func TestPrune_UpstreamGone(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := resolvePath(t, t.TempDir())
+
+	repoPath, _ := setupTestRepoWithOrigin(t, tmpDir, "test-repo")
+	wtPath := createTestWorktree(t, repoPath, "feature")
+	addCommit(t, wtPath, "feature.txt", "feature commit")
+
+	mustRunGit(t, wtPath, "push", "-u", "origin", "feature")
+	mustRunGit(t, repoPath, "push", "origin", "--delete", "feature")
+	mustRunGit(t, repoPath, "fetch", "--prune")
+
+	regFile := saveSingleRepoRegistry(t, tmpDir, repoPath)
+
+	cfg := &config.Config{RegistryPath: regFile}
+	ctx := testContextWithConfig(t, cfg, repoPath)
+	cmd := newPruneCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"-f"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("prune command failed: %v", err)
+	}
+
+	if _, err := os.Stat(wtPath); err == nil {
+		t.Error("worktree with gone upstream should be removed")
+	}
+}
+
+// TestPrune_UpstreamGone_ClosedPR tests that a worktree whose PR was closed
+// without merging is kept even though its upstream branch was deleted.
+//
+// Scenario: feature was pushed with tracking, its PR was closed and the branch
+// deleted on origin and fetched with --prune; user runs `wt prune -f`
+// Expected: Worktree is not removed
+func TestPrune_UpstreamGone_ClosedPR(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := resolvePath(t, t.TempDir())
+
+	repoPath, _ := setupTestRepoWithOrigin(t, tmpDir, "test-repo")
+	wtPath := createTestWorktree(t, repoPath, "feature")
+	addCommit(t, wtPath, "feature.txt", "feature commit")
+
+	mustRunGit(t, wtPath, "push", "-u", "origin", "feature")
+	mustRunGit(t, repoPath, "push", "origin", "--delete", "feature")
+	mustRunGit(t, repoPath, "fetch", "--prune")
+
+	regFile := saveSingleRepoRegistry(t, tmpDir, repoPath)
+	cfg := &config.Config{RegistryPath: regFile}
+
+	cachePath, err := cfg.GetPRCachePath()
+	if err != nil {
+		t.Fatalf("GetPRCachePath failed: %v", err)
+	}
+	cache := prcache.New()
+	cache.Set(prcache.CacheKey(repoPath, "feature"), &forge.PRInfo{Number: 1, State: forge.PRStateClosed, Fetched: true})
+	if err := cache.SaveTo(cachePath); err != nil {
+		t.Fatalf("failed to save PR cache: %v", err)
+	}
+
+	ctx := testContextWithConfig(t, cfg, repoPath)
+	cmd := newPruneCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"-f"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("prune command failed: %v", err)
+	}
+
+	if _, err := os.Stat(wtPath); os.IsNotExist(err) {
+		t.Error("worktree of a closed PR should not be removed")
+	}
+}
+
+// TestPrune_SquashMergedTarget_NoForce tests that a targeted prune of a locally
+// detected merge does not require --force.
+//
+// Scenario: User runs `wt prune feature` where feature was squash-merged into origin/main
+// Expected: Worktree is removed without -f
+func TestPrune_SquashMergedTarget_NoForce(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := resolvePath(t, t.TempDir())
+
+	repoPath, _ := setupTestRepoWithOrigin(t, tmpDir, "test-repo")
+	wtPath := createTestWorktree(t, repoPath, "feature")
+	addCommit(t, wtPath, "feature.txt", "feature commit")
+
+	mustRunGit(t, repoPath, "merge", "--squash", "feature")
+	mustRunGit(t, repoPath, "commit", "-m", "Squashed feature")
+	mustRunGit(t, repoPath, "push", "origin", "main")
+
+	regFile := saveSingleRepoRegistry(t, tmpDir, repoPath)
+
+	cfg := &config.Config{RegistryPath: regFile}
+	ctx := testContextWithConfig(t, cfg, repoPath)
+	cmd := newPruneCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"feature"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("prune of squash-merged target should not require -f: %v", err)
+	}
+
+	if _, err := os.Stat(wtPath); err == nil {
+		t.Error("worktree should be removed")
+	}
+}
+
+// TestPrune_DryRunVerbose_ShowsSkipReasons tests that a verbose dry-run lists
+// skipped worktrees with the reason they are kept.
+//
+// Scenario: feature has an unmerged commit; user runs `wt prune -d -v`
+// Expected: Output contains a "Skipped:" section listing feature as "not merged"
+func TestPrune_DryRunVerbose_ShowsSkipReasons(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := resolvePath(t, t.TempDir())
+
+	repoPath, _ := setupTestRepoWithOrigin(t, tmpDir, "test-repo")
+	wtPath := createTestWorktree(t, repoPath, "feature")
+	addCommit(t, wtPath, "feature.txt", "unmerged commit")
+
+	regFile := saveSingleRepoRegistry(t, tmpDir, repoPath)
+
+	cfg := &config.Config{RegistryPath: regFile}
+	ctx, out := testContextWithConfigAndOutput(t, cfg, repoPath)
+	ctx = log.WithLogger(ctx, log.New(io.Discard, true, false))
+	cmd := newPruneCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"-d"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("prune command failed: %v", err)
+	}
+
+	if !strings.Contains(out.String(), "Skipped:") {
+		t.Fatalf("verbose dry-run should list skipped worktrees, got:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "not merged") {
+		t.Errorf("skipped feature should have reason 'not merged', got:\n%s", out.String())
+	}
+	if _, err := os.Stat(wtPath); os.IsNotExist(err) {
+		t.Error("worktree should survive dry-run")
+	}
+}
+
+// TestPrune_BlocksUncommittedWork tests that auto-prune refuses merged worktrees
+// with uncommitted changes or untracked files unless --force is given.
+//
+// Scenario: feature is merged into origin/main, but has a modified and an untracked
+// file; user runs `wt prune -d`, then `wt prune`, then `wt prune -f`
+// Expected: Dry-run lists feature as blocked with both reasons, prune keeps it,
+// prune -f removes it
+func TestPrune_BlocksUncommittedWork(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := resolvePath(t, t.TempDir())
+
+	repoPath, _ := setupTestRepoWithOrigin(t, tmpDir, "test-repo")
+	wtPath := createTestWorktree(t, repoPath, "feature")
+	addCommit(t, wtPath, "feature.txt", "feature commit")
+
+	mustRunGit(t, repoPath, "merge", "--no-ff", "-m", "Merge feature", "feature")
+	mustRunGit(t, repoPath, "push", "origin", "main")
+
+	if err := os.WriteFile(filepath.Join(wtPath, "feature.txt"), []byte("edited\n"), 0644); err != nil {
+		t.Fatalf("failed to modify file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(wtPath, "notes.txt"), []byte("scratch\n"), 0644); err != nil {
+		t.Fatalf("failed to write untracked file: %v", err)
+	}
+
+	regFile := saveSingleRepoRegistry(t, tmpDir, repoPath)
+	cfg := &config.Config{RegistryPath: regFile}
+
+	ctx, out := testContextWithConfigAndOutput(t, cfg, repoPath)
+	cmd := newPruneCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"-d"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("prune -d failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "Blocked") {
+		t.Fatalf("dry-run should list blocked worktrees, got:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "uncommitted changes, untracked files") {
+		t.Errorf("blocked reason should name uncommitted changes and untracked files, got:\n%s", out.String())
+	}
+
+	ctx = testContextWithConfig(t, cfg, repoPath)
+	cmd = newPruneCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("prune failed: %v", err)
+	}
+	if _, err := os.Stat(wtPath); os.IsNotExist(err) {
+		t.Fatal("worktree with uncommitted work should not be removed without -f")
+	}
+
+	cmd = newPruneCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"-f"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("prune -f failed: %v", err)
+	}
+	if _, err := os.Stat(wtPath); err == nil {
+		t.Error("worktree should be removed with -f")
+	}
+}
+
+// TestPrune_BlocksUnpushedCommitsAfterMerge tests that a worktree whose PR was
+// merged is kept when commits were added after pushing.
+//
+// Scenario: feature is pushed with tracking, the forge reports its PR merged,
+// then another commit is added locally; user runs `wt prune -d` and `wt prune`
+// Expected: feature is blocked with "1 unpushed commit(s)" and not removed
+func TestPrune_BlocksUnpushedCommitsAfterMerge(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := resolvePath(t, t.TempDir())
+
+	repoPath, _ := setupTestRepoWithOrigin(t, tmpDir, "test-repo")
+	wtPath := createTestWorktree(t, repoPath, "feature")
+	addCommit(t, wtPath, "feature.txt", "feature commit")
+	mustRunGit(t, wtPath, "push", "-u", "origin", "feature")
+	addCommit(t, wtPath, "late.txt", "commit after merge")
+
+	regFile := saveSingleRepoRegistry(t, tmpDir, repoPath)
+	cfg := &config.Config{RegistryPath: regFile}
+
+	cachePath, err := cfg.GetPRCachePath()
+	if err != nil {
+		t.Fatalf("GetPRCachePath failed: %v", err)
+	}
+	cache := prcache.New()
+	cache.Set(prcache.CacheKey(repoPath, "feature"), &forge.PRInfo{Number: 1, State: forge.PRStateMerged, Fetched: true})
+	if err := cache.SaveTo(cachePath); err != nil {
+		t.Fatalf("failed to save PR cache: %v", err)
+	}
+
+	ctx, out := testContextWithConfigAndOutput(t, cfg, repoPath)
+	cmd := newPruneCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"-d"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("prune -d failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "1 unpushed commit(s)") {
+		t.Errorf("dry-run should block feature for its unpushed commit, got:\n%s", out.String())
+	}
+
+	ctx = testContextWithConfig(t, cfg, repoPath)
+	cmd = newPruneCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("prune failed: %v", err)
+	}
+	if _, err := os.Stat(wtPath); os.IsNotExist(err) {
+		t.Error("worktree with unpushed commits should not be removed without -f")
+	}
+}
+
+// TestPrune_Target_BlocksStash tests that a targeted prune refuses a merged
+// worktree whose branch has stash entries.
+//
+// Scenario: feature is merged into origin/main and has a stash entry;
+// user runs `wt prune feature`
+// Expected: Command fails naming the stash, worktree is kept
+func TestPrune_Target_BlocksStash(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := resolvePath(t, t.TempDir())
+
+	repoPath, _ := setupTestRepoWithOrigin(t, tmpDir, "test-repo")
+	wtPath := createTestWorktree(t, repoPath, "feature")
+	addCommit(t, wtPath, "feature.txt", "feature commit")
+	mustRunGit(t, repoPath, "merge", "--no-ff", "-m", "Merge feature", "feature")
+	mustRunGit(t, repoPath, "push", "origin", "main")
+
+	if err := os.WriteFile(filepath.Join(wtPath, "wip.txt"), []byte("wip\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	mustRunGit(t, wtPath, "stash", "push", "-u", "-m", "wip")
+
+	regFile := saveSingleRepoRegistry(t, tmpDir, repoPath)
+	cfg := &config.Config{RegistryPath: regFile}
+	ctx := testContextWithConfig(t, cfg, repoPath)
+	cmd := newPruneCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"feature"})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "1 stash(es)") {
+		t.Errorf("expected unsaved work error naming the stash, got %v", err)
+	}
+	if _, err := os.Stat(wtPath); os.IsNotExist(err) {
+		t.Error("worktree with stash should not be removed without -f")
+	}
+}
+
+// TestPrune_RefreshPR_Gitea tests that `wt prune -R` fetches PR status from a
+// Gitea instance and prunes worktrees whose PR was merged there.
+//
+// Scenario: feature is pushed with an unmerged commit, origin points at a local
+// Gitea API stand-in mapped via [hosts] that reports the PR as merged;
+// user runs `wt prune -R`
+// Expected: Worktree is removed without -f after querying the Gitea API
+func TestPrune_RefreshPR_Gitea(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := resolvePath(t, t.TempDir())
+
+	var pullsQueried atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/v1/user":
+			io.WriteString(w, `{"login":"tester"}`)
+		case "/api/v1/repos/org/test-repo/pulls":
+			pullsQueried.Store(true)
+			io.WriteString(w, `[{"number":12,"state":"closed","merged":true,"head":{"ref":"feature"}}]`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	repoPath, _ := setupTestRepoWithOrigin(t, tmpDir, "test-repo")
+	wtPath := createTestWorktree(t, repoPath, "feature")
+	addCommit(t, wtPath, "feature.txt", "feature commit")
+	mustRunGit(t, wtPath, "push", "-u", "origin", "feature")
+
+	// PR status comes from the forge, git only needs the remote URL
+	mustRunGit(t, repoPath, "remote", "set-url", "origin", srv.URL+"/org/test-repo.git")
+
+	regFile := saveSingleRepoRegistry(t, tmpDir, repoPath)
+
+	cfg := &config.Config{
+		RegistryPath: regFile,
+		Hosts:        map[string]string{"127.0.0.1": "gitea"},
+		Forge: config.ForgeConfig{
+			Tokens: map[string]string{"127.0.0.1": "test-token"},
+		},
+	}
+	ctx := testContextWithConfig(t, cfg, repoPath)
+	cmd := newPruneCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"-R"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("prune command failed: %v", err)
+	}
+
+	if _, err := os.Stat(wtPath); err == nil {
+		t.Error("worktree with merged Gitea PR should be removed")
+	}
+
+	if !pullsQueried.Load() {
+		t.Error("PR status should be fetched from the Gitea API")
+	}
+}
+
+// TestPrune_RetargetsStackedChildren tests that pruning a merged branch moves
+// branches stacked on it to its base.
+//
+// Scenario: feature-b is stacked on feature-a, feature-a is merged into main and pushed; user runs `wt prune`
+// Expected: feature-a's worktree is removed, feature-b is kept and its parent becomes main
+func TestPrune_RetargetsStackedChildren(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := resolvePath(t, t.TempDir())
+
+	repoPath, _ := setupTestRepoWithOrigin(t, tmpDir, "test-repo")
+	wtA := createTestWorktree(t, repoPath, "feature-a")
+	addCommit(t, wtA, "a.txt", "feature a")
+	mustRunGit(t, repoPath, "config", "branch.feature-a.wt-parent", "main")
+
+	wtB := createTestWorktree(t, repoPath, "feature-b")
+	mustRunGit(t, wtB, "reset", "--hard", "feature-a")
+	addCommit(t, wtB, "b.txt", "feature b")
+	mustRunGit(t, repoPath, "config", "branch.feature-b.wt-parent", "feature-a")
+
+	mustRunGit(t, repoPath, "merge", "--no-ff", "-m", "Merge feature-a", "feature-a")
+	mustRunGit(t, repoPath, "push", "origin", "main")
+
+	regFile := saveSingleRepoRegistry(t, tmpDir, repoPath)
+
+	cfg := &config.Config{RegistryPath: regFile}
+	ctx := testContextWithConfig(t, cfg, repoPath)
+	cmd := newPruneCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("prune command failed: %v", err)
+	}
+
+	if _, err := os.Stat(wtA); err == nil {
+		t.Error("merged feature-a worktree should be removed")
+	}
+	if _, err := os.Stat(wtB); err != nil {
+		t.Errorf("feature-b worktree should be kept: %v", err)
+	}
+	parent, err := runGitCommand(repoPath, "config", "branch.feature-b.wt-parent")
+	if err != nil || strings.TrimSpace(parent) != "main" {
+		t.Errorf("feature-b parent = %q (%v), want main", strings.TrimSpace(parent), err)
+	}
+}
+
+// TestPrune_StackRebaseAfterSquashMerge tests that rebasing a child of a
+// squash-merged branch only replays the child's own commits.
+//
+// Scenario: feature-b is stacked on feature-a (two commits editing a.txt),
+// feature-a is squash-merged into main and pushed; user runs `wt prune`, then
+// `wt stack rebase feature-b`
+// Expected: feature-b is rebased onto origin/main without conflicts, keeping only its own commit
+func TestPrune_StackRebaseAfterSquashMerge(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := resolvePath(t, t.TempDir())
+
+	repoPath, _ := setupTestRepoWithOrigin(t, tmpDir, "test-repo")
+	wtA := createTestWorktree(t, repoPath, "feature-a")
+	addCommit(t, wtA, "a.txt", "feature a")
+	if err := os.WriteFile(filepath.Join(wtA, "a.txt"), []byte("feature a v2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mustRunGit(t, wtA, "commit", "-am", "feature a v2")
+	mustRunGit(t, repoPath, "config", "branch.feature-a.wt-parent", "main")
+
+	wtB := createTestWorktree(t, repoPath, "feature-b")
+	mustRunGit(t, wtB, "reset", "--hard", "feature-a")
+	addCommit(t, wtB, "b.txt", "feature b")
+	mustRunGit(t, repoPath, "config", "branch.feature-b.wt-parent", "feature-a")
+
+	mustRunGit(t, repoPath, "merge", "--squash", "feature-a")
+	mustRunGit(t, repoPath, "commit", "-m", "Squashed feature-a")
+	mustRunGit(t, repoPath, "push", "origin", "main")
+
+	regFile := saveSingleRepoRegistry(t, tmpDir, repoPath)
+
+	cfg := &config.Config{RegistryPath: regFile}
+	ctx := testContextWithConfig(t, cfg, repoPath)
+	cmd := newPruneCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("prune command failed: %v", err)
+	}
+	if _, err := os.Stat(wtA); err == nil {
+		t.Fatal("squash-merged feature-a worktree should be removed")
+	}
+
+	ctx = testContextWithConfig(t, cfg, repoPath)
+	cmd = newStackCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"rebase", "feature-b"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("stack rebase failed: %v", err)
+	}
+
+	log, err := runGitCommand(wtB, "log", "--format=%s", "origin/main..HEAD")
+	if err != nil {
+		t.Fatalf("git log failed: %v", err)
+	}
+	if got := strings.TrimSpace(log); got != "feature b" {
+		t.Errorf("feature-b commits on top of origin/main = %q, want only feature b", got)
+	}
+	if _, err := runGitCommand(repoPath, "config", "branch.feature-b.wt-parent-base"); err == nil {
+		t.Error("recorded parent base should be cleared after the rebase")
+	}
+}
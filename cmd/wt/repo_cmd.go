@@ -1,6 +1,7 @@
 package main
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -14,6 +15,7 @@ import (
 	"github.com/raphi011/wt/internal/config"
 	"github.com/raphi011/wt/internal/forge"
 	"github.com/raphi011/wt/internal/git"
+	"github.com/raphi011/wt/internal/hooks"
 	"github.com/raphi011/wt/internal/log"
 	"github.com/raphi011/wt/internal/output"
 	"github.com/raphi011/wt/internal/registry"
@@ -168,6 +170,7 @@ func newRepoAddCmd() *cobra.Command {
 		name           string
 		worktreeFormat string
 		labels         []string
+		hf             hookFlags
 	)
 
 	cmd := &cobra.Command{
@@ -178,7 +181,11 @@ func newRepoAddCmd() *cobra.Command {
 		Long: `Register existing git repositories with wt.
 
 Repositories will be added to the registry (~/.wt/repos.json) and can then
-be managed with other wt commands. Non-git directories are silently skipped.`,
+be managed with other wt commands. Non-git directories are silently skipped.
+
+Hooks with on = ["repo:add"] run in each added repo; a failing
+"before:repo:add" hook skips the repo. Only global hooks run, not those of
+the repo's .wt.toml.`,
 		Example: `  wt repo add ~/work/my-project                    # Register single repo
   wt repo add ~/work/*                             # Register all repos in directory
   wt repo add ~/work/my-project -n myproj          # Custom display name (single repo only)
@@ -200,7 +207,15 @@ be managed with other wt commands. Non-git directories are silently skipped.`,
 				return fmt.Errorf("load registry: %w", err)
 			}
 
-			var added int
+			hp, err := buildHookParams(cfg, registry.Repo{}, "", "", hooks.CommandRepo, hooks.ActionAdd, hf)
+			if err != nil {
+				return err
+			}
+
+			var (
+				added    []registry.Repo
+				addedOps []repoHookOp
+			)
 			for _, path := range args {
 				// Resolve to absolute path
 				absPath, err := filepath.Abs(path)
@@ -237,21 +252,55 @@ be managed with other wt commands. Non-git directories are silently skipped.`,
 					continue
 				}
 
+				op := startRepoHookOp(ctx, "repo add", hp, repo)
+				if err := runHooks(op.ctx, op.hp, hooks.PhaseBefore); err != nil {
+					l.Printf("skipping %s: %v\n", absPath, err)
+					op.finish(ctx, err)
+					reg.Remove(absPath) //nolint:errcheck
+					continue
+				}
+
 				typeStr := "regular"
 				if repoType == git.RepoTypeBare {
 					typeStr = "bare"
 				}
 				fmt.Printf("Registered %s repo: %s (%s)\n", typeStr, repoName, absPath)
-				added++
+				added = append(added, repo)
+				addedOps = append(addedOps, op)
 			}
 
-			if added == 0 {
+			if len(added) == 0 {
 				return fmt.Errorf("no repositories added")
 			}
 
-			// Save registry
-			if err := reg.Save(cfg.RegistryPath); err != nil {
-				return fmt.Errorf("save registry: %w", err)
+			// Save registry; re-adding under the lock keeps repos registered
+			// concurrently by other wt processes
+			err = registry.Transact(cfg.RegistryPath, func(r *registry.Registry) error {
+				for _, repo := range added {
+					if err := r.Add(repo); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				err = fmt.Errorf("save registry: %w", err)
+				for _, op := range addedOps {
+					op.finish(ctx, err)
+				}
+				return err
+			}
+
+			// After hooks per repo; an aborting one stops the remaining repos
+			for i, op := range addedOps {
+				err := runHooks(op.ctx, op.hp, hooks.PhaseAfter)
+				op.finish(ctx, err)
+				if err != nil {
+					for _, op := range addedOps[i+1:] {
+						op.finish(ctx, nil)
+					}
+					return err
+				}
 			}
 
 			return nil
@@ -261,6 +310,7 @@ be managed with other wt commands. Non-git directories are silently skipped.`,
 	cmd.Flags().StringVarP(&name, "name", "n", "", "Display name (default: directory name)")
 	cmd.Flags().StringVarP(&worktreeFormat, "worktree-format", "w", "", "Worktree format override")
 	cmd.Flags().StringSliceVarP(&labels, "label", "l", nil, "Labels for grouping (repeatable)")
+	registerHookFlags(cmd, &hf)
 
 	// Completions
 	cmd.RegisterFlagCompletionFunc("label", completeLabels)
@@ -270,6 +320,24 @@ be managed with other wt commands. Non-git directories are silently skipped.`,
 	return cmd
 }
 
+// repoHookOp is a journaled operation on a repo that isn't trusted yet
+// (added or cloned), with the hooks to run for it.
+type repoHookOp struct {
+	*journalOp
+	ctx context.Context // records hooks in the journal entry
+	hp  hookParams
+}
+
+// startRepoHookOp begins a journal entry for command on repo and returns it
+// with hp for repo. hp has the global hooks only: adding or cloning a repo
+// must not run code from its .wt.toml or .wt/hooks scripts.
+func startRepoHookOp(ctx context.Context, command string, hp hookParams, repo registry.Repo) repoHookOp {
+	ctx, op := startJournalOp(ctx, command)
+	op.setRepo(repo)
+	hp.RepoPath, hp.RepoName, hp.WorkDir = repo.Path, repo.Name, repo.Path
+	return repoHookOp{journalOp: op, ctx: ctx, hp: hp}
+}
+
 func newRepoRemoveCmd() *cobra.Command {
 	var (
 		deleteFiles bool
@@ -285,12 +353,14 @@ func newRepoRemoveCmd() *cobra.Command {
 		Long: `Unregister a repository from wt.
 
 The repository will be removed from the registry (~/.wt/repos.json).
-By default, files are kept on disk. Use --delete to also remove files.`,
+By default, files are kept on disk. Use --delete to also remove files.
+With [trash] enabled = true in the config, deleted repos are moved to
+~/.wt/trash and can be restored with 'wt undo'.`,
 		Example: `  wt repo remove my-project           # Unregister, keep files
   wt repo remove my-project --delete  # Unregister and delete from disk
   wt repo remove my-project -D -f     # Delete without confirmation`,
-		RunE: func(cmd *cobra.Command, args []string) error {
-			ctx := cmd.Context()
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			ctx, op := startJournalOp(cmd.Context(), "repo remove")
 			cfg := config.FromContext(ctx)
 			l := log.FromContext(ctx)
 
@@ -309,6 +379,7 @@ By default, files are kept on disk. Use --delete to also remove files.`,
 			}
 
 			l.Debug("removing repo", "name", repo.Name, "path", repo.Path)
+			op.setRepo(repo)
 
 			// Confirm deletion if --delete and not --force
 			if deleteFiles && !force {
@@ -321,24 +392,32 @@ By default, files are kept on disk. Use --delete to also remove files.`,
 					return nil
 				}
 			}
+			defer func() { op.finish(ctx, err) }()
 
 			// Remove from registry
-			if err := reg.Remove(nameOrPath); err != nil {
+			err = registry.Transact(cfg.RegistryPath, func(r *registry.Registry) error {
+				return r.Remove(nameOrPath)
+			})
+			if err != nil {
 				return err
 			}
 
-			// Save registry
-			if err := reg.Save(cfg.RegistryPath); err != nil {
-				return fmt.Errorf("save registry: %w", err)
-			}
-
-			// Delete files if requested
+			// Delete files if requested (moved to the trash when enabled)
 			if deleteFiles {
-				// First remove the repo directory
-				if err := os.RemoveAll(repo.Path); err != nil {
-					return fmt.Errorf("delete repo: %w", err)
+				if trashDir := trashDirFromConfig(ctx, cfg); trashDir != "" {
+					entry, err := trashRepo(ctx, trashDir, repo)
+					if err != nil {
+						return fmt.Errorf("delete repo: %w", err)
+					}
+					fmt.Fprintf(cmd.OutOrStdout(), "Moved to trash: %s (restore with 'wt trash restore %s')\n", repo.Path, entry.ID)
+					op.addDetail("moved to trash " + entry.ID)
+				} else {
+					if err := os.RemoveAll(repo.Path); err != nil {
+						return fmt.Errorf("delete repo: %w", err)
+					}
+					fmt.Fprintf(cmd.OutOrStdout(), "Deleted: %s\n", repo.Path)
+					op.addDetail("deleted files")
 				}
-				fmt.Fprintf(cmd.OutOrStdout(), "Deleted: %s\n", repo.Path)
 			}
 
 			fmt.Fprintf(cmd.OutOrStdout(), "Unregistered: %s (%s)\n", repo.Name, filepath.Base(repo.Path))
@@ -363,6 +442,7 @@ func newRepoCloneCmd() *cobra.Command {
 		destination    string
 		branch         string
 		cloneMode      string
+		hf             hookFlags
 	)
 
 	cmd := &cobra.Command{
@@ -387,7 +467,12 @@ Supports both full URLs and short-form org/repo format:
   - org/repo format uses gh/glab CLI (determined by forge config)
   - repo-only format uses default_org from config
 
-If destination is not specified, clones into the current directory.`,
+If destination is not specified, clones into the current directory.
+
+Hooks with on = ["repo:clone"] run in the cloned repo once it is registered,
+e.g. to install tooling. "before:repo:clone" hooks run in the current
+directory before cloning, and abort the clone if they fail. Only global
+hooks run, not those of the cloned repo's .wt.toml.`,
 		Example: `  wt repo clone https://github.com/org/repo           # Clone via git URL
   wt repo clone git@github.com:org/repo.git           # Clone via SSH URL
   wt repo clone org/repo                              # Clone via gh/glab (uses forge config)
@@ -395,13 +480,15 @@ If destination is not specified, clones into the current directory.`,
   wt repo clone org/repo -b main                      # Clone and create worktree for main
   wt repo clone org/repo -l work                      # Clone with label
   wt repo clone org/repo --clone-mode regular         # Standard (non-bare) clone`,
-		RunE: func(cmd *cobra.Command, args []string) error {
-			ctx := cmd.Context()
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			ctx, op := startJournalOp(cmd.Context(), "repo clone")
+			defer func() { op.finish(ctx, err) }()
 			cfg := config.FromContext(ctx)
 			l := log.FromContext(ctx)
 			workDir := config.WorkDirFromContext(ctx)
 
 			input := args[0]
+			op.Detail = input
 
 			// Determine destination name
 			dest := destination
@@ -441,6 +528,15 @@ If destination is not specified, clones into the current directory.`,
 				return fmt.Errorf("--branch is only supported in bare clone mode; remove --branch or use --clone-mode bare")
 			}
 
+			hp, err := buildHookParams(cfg, registry.Repo{}, "", "", hooks.CommandRepo, hooks.ActionClone, hf)
+			if err != nil {
+				return err
+			}
+			hp.RepoPath, hp.RepoName, hp.WorkDir = absPath, cmp.Or(name, filepath.Base(absPath)), workDir
+			if err := runHooks(ctx, hp, hooks.PhaseBefore); err != nil {
+				return err
+			}
+
 			// Clone based on input type
 			if isGitURL(input) {
 				// Full URL: use git clone directly
@@ -466,7 +562,7 @@ If destination is not specified, clones into the current directory.`,
 
 				// Determine forge type from config rules
 				forgeName := cfg.Forge.GetForgeTypeForRepo(orgRepo)
-				f := forge.ByNameWithConfig(forgeName, &cfg.Forge)
+				f := forge.ByNameForHost(forgeName, cfg.Forge.GetHostForRepo(orgRepo), &cfg.Forge)
 
 				// Check forge CLI is available
 				if err := f.Check(ctx); err != nil {
@@ -494,12 +590,6 @@ If destination is not specified, clones into the current directory.`,
 				repoName = filepath.Base(absPath)
 			}
 
-			// Load registry
-			reg, err := registry.Load(cfg.RegistryPath)
-			if err != nil {
-				return fmt.Errorf("load registry: %w", err)
-			}
-
 			// Register the repo
 			repo := registry.Repo{
 				Path:           absPath,
@@ -508,17 +598,17 @@ If destination is not specified, clones into the current directory.`,
 				Labels:         labels,
 			}
 
-			if err := reg.Add(repo); err != nil {
+			err = registry.Transact(cfg.RegistryPath, func(r *registry.Registry) error {
+				return r.Add(repo)
+			})
+			if err != nil {
 				// Clean up on failure
 				os.RemoveAll(absPath)
 				return fmt.Errorf("register repo: %w", err)
 			}
 
-			if err := reg.Save(cfg.RegistryPath); err != nil {
-				return fmt.Errorf("save registry: %w", err)
-			}
-
 			fmt.Printf("Cloned repo: %s (%s)\n", repoName, absPath)
+			op.setRepo(repo)
 
 			// Create initial worktree only in bare mode
 			// Regular clones already have a working tree at root
@@ -558,11 +648,14 @@ If destination is not specified, clones into the current directory.`,
 					} else {
 						fmt.Printf("Created worktree: %s (%s)\n", wtPath, worktreeBranch)
 						recordHistory(ctx, cfg, wtPath, repoName, worktreeBranch)
+						op.Branch = worktreeBranch
+						op.Path = wtPath
 					}
 				}
 			}
 
-			return nil
+			hp.RepoPath, hp.RepoName, hp.WorkDir = absPath, repoName, absPath
+			return runHooks(ctx, hp, hooks.PhaseAfter)
 		},
 	}
 	cmd.Flags().StringVarP(&name, "name", "n", "", "Display name (default: directory name)")
@@ -571,6 +664,7 @@ If destination is not specified, clones into the current directory.`,
 	cmd.Flags().StringVarP(&destination, "destination", "d", "", "Destination directory")
 	cmd.Flags().StringVarP(&branch, "branch", "b", "", "Create initial worktree for branch (bare mode only)")
 	cmd.Flags().StringVar(&cloneMode, "clone-mode", "", "Clone mode: bare or regular (default: config)")
+	registerHookFlags(cmd, &hf)
 
 	cmd.RegisterFlagCompletionFunc("clone-mode", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return []string{"bare", "regular"}, cobra.ShellCompDirectiveNoFileComp
@@ -654,7 +748,7 @@ The conversion:
   wt repo convert --clone-mode regular            # Convert bare to regular
   wt repo convert --clone-mode bare -n myapp      # Convert with custom name
   wt repo convert --clone-mode bare --dry-run     # Preview without changes`,
-		RunE: func(cmd *cobra.Command, args []string) error {
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
 			ctx := cmd.Context()
 			cfg := config.FromContext(ctx)
 			l := log.FromContext(ctx)
@@ -716,7 +810,6 @@ The conversion:
 				out:               out,
 				l:                 l,
 				cfg:               cfg,
-				reg:               reg,
 				absPath:           absPath,
 				repoName:          repoName,
 				effectiveFormat:   effectiveFormat,
@@ -726,6 +819,15 @@ The conversion:
 				alreadyRegistered: alreadyRegistered,
 			}
 
+			if !dryRun {
+				var op *journalOp
+				p.ctx, op = startJournalOp(ctx, "repo convert")
+				op.Repo = repoName
+				op.RepoPath = absPath
+				op.Detail = "to " + cloneMode
+				defer func() { op.finish(ctx, err) }()
+			}
+
 			// Route based on clone-mode
 			switch cloneMode {
 			case "bare":
@@ -769,7 +871,6 @@ type convertParams struct {
 	out               *output.Printer
 	l                 *log.Logger
 	cfg               *config.Config
-	reg               *registry.Registry
 	absPath           string
 	repoName          string
 	effectiveFormat   string
@@ -793,13 +894,12 @@ func registerAndVerify(p convertParams, warnings []string) error {
 			Labels:         p.labels,
 		}
 
-		if err := p.reg.Add(repo); err != nil {
+		err := registry.Transact(p.cfg.RegistryPath, func(r *registry.Registry) error {
+			return r.Add(repo)
+		})
+		if err != nil {
 			return fmt.Errorf("register repo: %w", err)
 		}
-
-		if err := p.reg.Save(p.cfg.RegistryPath); err != nil {
-			return fmt.Errorf("save registry: %w", err)
-		}
 	}
 
 	if p.alreadyRegistered {
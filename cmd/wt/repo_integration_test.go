@@ -1893,3 +1893,58 @@ func TestRepoConvertRegular_DryRun(t *testing.T) {
 		t.Error("main worktree should still exist after dry run")
 	}
 }
+
+// TestRepoAdd_Hooks tests that repo:add hooks run for each added repo.
+//
+// Scenario: Config has a before:repo:add hook that rejects repo-b and a
+// repo:add hook, user runs `wt repo add repo-a repo-b`
+// Expected: repo-b is skipped; repo-a is registered and the after hook runs
+// in it
+func TestRepoAdd_Hooks(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := resolvePath(t, t.TempDir())
+	repoA := setupTestRepo(t, tmpDir, "repo-a")
+	repoB := setupTestRepo(t, tmpDir, "repo-b")
+
+	regFile := filepath.Join(tmpDir, ".wt", "repos.json")
+	if err := os.MkdirAll(filepath.Dir(regFile), 0755); err != nil {
+		t.Fatalf("failed to create registry directory: %v", err)
+	}
+
+	logPath := filepath.Join(tmpDir, "hook.log")
+	cfg := &config.Config{
+		RegistryPath: regFile,
+		Hooks: config.HooksConfig{
+			Hooks: map[string]config.Hook{
+				"guard": {Command: "test {repo} != repo-b", On: []string{"before:repo:add"}},
+				"log":   {Command: "echo {repo} $(pwd) >> " + logPath, On: []string{"repo:add"}},
+			},
+		},
+	}
+
+	ctx := testContextWithConfig(t, cfg, tmpDir)
+	cmd := newRepoAddCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{repoA, repoB})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("repo add command failed: %v", err)
+	}
+
+	reg, err := registry.Load(regFile)
+	if err != nil {
+		t.Fatalf("failed to load registry: %v", err)
+	}
+	if len(reg.Repos) != 1 || reg.Repos[0].Name != "repo-a" {
+		t.Fatalf("expected only repo-a to be registered, got %+v", reg.Repos)
+	}
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("after hook did not run: %v", err)
+	}
+	if got, want := strings.TrimSpace(string(data)), "repo-a "+repoA; got != want {
+		t.Errorf("hook log = %q, want %q", got, want)
+	}
+}
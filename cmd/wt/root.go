@@ -136,6 +136,7 @@ func init() {
 	// Core commands
 	rootCmd.AddCommand(newCheckoutCmd())
 	rootCmd.AddCommand(newListCmd())
+	rootCmd.AddCommand(newStatusCmd())
 	rootCmd.AddCommand(newPruneCmd())
 
 	// Registry commands
@@ -149,11 +150,16 @@ func init() {
 	rootCmd.AddCommand(newExecCmd())
 	rootCmd.AddCommand(newCdCmd())
 	rootCmd.AddCommand(newNoteCmd())
+	rootCmd.AddCommand(newStackCmd())
 	rootCmd.AddCommand(newLabelCmd())
 	rootCmd.AddCommand(newHookCmd())
+	rootCmd.AddCommand(newTrashCmd())
+	rootCmd.AddCommand(newUndoCmd())
+	rootCmd.AddCommand(newLogCmd())
 
 	// Config commands
 	rootCmd.AddCommand(newConfigCmd())
 	rootCmd.AddCommand(newCompletionCmd())
 	rootCmd.AddCommand(newInitCmd())
+	rootCmd.AddCommand(newDoctorCmd())
 }
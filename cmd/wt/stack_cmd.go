@@ -0,0 +1,472 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/raphi011/wt/internal/config"
+	"github.com/raphi011/wt/internal/forge"
+	"github.com/raphi011/wt/internal/git"
+	"github.com/raphi011/wt/internal/log"
+	"github.com/raphi011/wt/internal/output"
+	"github.com/raphi011/wt/internal/prcache"
+	"github.com/raphi011/wt/internal/registry"
+	"github.com/raphi011/wt/internal/ui/styles"
+)
+
+func newStackCmd() *cobra.Command {
+	var refresh bool
+
+	cmd := &cobra.Command{
+		Use:               "stack [repo]",
+		Short:             "Show stacked branches",
+		GroupID:           GroupUtility,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeRepoNames,
+		Long: `Show the stacked branches of a repo as a tree.
+
+A stacked branch is built on another branch instead of the default branch,
+e.g. feature-b on feature-a on main. Create one with
+'wt checkout -b <branch> --stack', which records the current branch (or
+--base) as its parent in git config (branch.<name>.wt-parent).
+
+Stacked branches are used by:
+  - wt pr create: the PR targets the parent branch
+  - wt stack rebase: rebases each worktree onto its updated parent
+  - wt prune: when a parent is merged, its children are retargeted to the
+    parent's base, locally and on the forge
+
+PR states are read from the PR cache; use -R to refresh them first.`,
+		Example: `  wt stack            # Show stacks of the current repo
+  wt stack myrepo     # Show stacks of myrepo
+  wt stack -R         # Refresh PR status first`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			cfg := config.FromContext(ctx)
+			l := log.FromContext(ctx)
+			out := output.FromContext(ctx)
+
+			reg, err := registry.Load(cfg.RegistryPath)
+			if err != nil {
+				return fmt.Errorf("load registry: %w", err)
+			}
+
+			var repo registry.Repo
+			if len(args) > 0 {
+				if repo, err = reg.FindByName(args[0]); err != nil {
+					return fmt.Errorf("repository %q not found", args[0])
+				}
+			} else if repo, err = findOrRegisterCurrentRepoFromContext(ctx, reg); err != nil {
+				return err
+			}
+
+			parents, err := git.GetBranchParents(ctx, repo.Path)
+			if err != nil {
+				return err
+			}
+			if len(parents) == 0 {
+				out.Println("No stacked branches (create one with 'wt checkout -b <branch> --stack')")
+				return nil
+			}
+
+			worktrees, warnings := git.LoadWorktreesForRepos(ctx, reposToRefs([]registry.Repo{repo}))
+			for _, w := range warnings {
+				l.Printf("Warning: %s: %v\n", w.RepoName, w.Err)
+			}
+			hasWorktree := make(map[string]bool)
+			for _, wt := range worktrees {
+				hasWorktree[wt.Branch] = true
+			}
+
+			prCache := loadPRCache(ctx, cfg)
+			if refresh {
+				var stacked []git.Worktree
+				for _, wt := range worktrees {
+					if parents[wt.Branch] != "" {
+						stacked = append(stacked, wt)
+					}
+				}
+				if failed := refreshPRs(ctx, stacked, prCache, cfg.Hosts, &cfg.Forge); len(failed) > 0 {
+					l.Printf("Warning: failed to fetch PR status for: %v\n", failed)
+				}
+				if err := prCache.SaveIfDirty(); err != nil {
+					l.Printf("Warning: failed to save PR cache: %v\n", err)
+				}
+			}
+
+			current, _ := git.GetCurrentBranch(ctx, config.WorkDirFromContext(ctx)) //nolint:errcheck // may be outside the repo
+			out.Print(renderStackTree(parents, func(branch string) string {
+				var info []string
+				if pr := prCache.Get(prcache.CacheKey(repo.Path, branch)); pr != nil && pr.Fetched && pr.Number != 0 {
+					info = append(info, fmt.Sprintf("#%d %s", pr.Number, styles.FormatPRState(pr.State, pr.IsDraft)))
+				}
+				if parents[branch] != "" && !hasWorktree[branch] {
+					info = append(info, "no worktree")
+				}
+				if branch == current {
+					info = append(info, "current")
+				}
+				if len(info) == 0 {
+					return branch
+				}
+				return branch + "  (" + strings.Join(info, ", ") + ")"
+			}))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&refresh, "refresh-pr", "R", false, "Refresh PR status first")
+
+	cmd.AddCommand(newStackRebaseCmd())
+
+	return cmd
+}
+
+func newStackRebaseCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rebase [[scope:]branch]",
+		Short: "Rebase a stack onto its updated parents",
+		Args:  cobra.MaximumNArgs(1),
+		Long: `Rebase every worktree of a stack onto its updated parent.
+
+The stack is the tree of stacked branches rooted at the bottom-most
+ancestor of the branch (default: the current worktree's branch). That
+branch is rebased onto its base branch (origin/<base> unless
+[checkout] base_ref = "local", fetched first), then each child onto its
+rebased parent. Commits of a parent that were rewritten are not replayed
+onto its children, nor are those of a parent that was merged (e.g.
+squash-merged) and retargeted by wt prune.
+
+Worktrees with uncommitted changes or an operation in progress are skipped,
+as are their children. On conflicts the rebase is left in progress: resolve
+it, run 'git rebase --continue' and rerun wt stack rebase. Branches without
+a worktree are left alone.`,
+		Example: `  wt stack rebase                    # Rebase the current stack
+  wt stack rebase feature-b          # Rebase the stack of feature-b
+  wt stack rebase myrepo:feature-b   # Stack in a specific repo`,
+		ValidArgsFunction: completeCdArg,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			cfg := config.FromContext(ctx)
+
+			reg, err := registry.Load(cfg.RegistryPath)
+			if err != nil {
+				return fmt.Errorf("load registry: %w", err)
+			}
+
+			var repo registry.Repo
+			var branch string
+			if len(args) > 0 {
+				t, err := resolveOneWorktreeTarget(ctx, reg, args[0])
+				if err != nil {
+					return err
+				}
+				repo, branch = registry.Repo{Name: t.RepoName, Path: t.RepoPath}, t.Branch
+			} else {
+				if repo, err = findOrRegisterCurrentRepoFromContext(ctx, reg); err != nil {
+					return err
+				}
+				if branch, err = git.GetCurrentBranch(ctx, config.WorkDirFromContext(ctx)); err != nil {
+					return err
+				}
+			}
+
+			return rebaseStack(ctx, repo, branch)
+		},
+	}
+
+	return cmd
+}
+
+// renderStackTree renders stacked branches (branch -> parent) as trees
+// rooted at their base branches, with label formatting each branch.
+// Branches of a parent cycle are not reachable from a base and omitted.
+func renderStackTree(parents map[string]string, label func(branch string) string) string {
+	children := stackChildren(parents)
+
+	var roots []string
+	for _, parent := range parents {
+		if parents[parent] == "" && !slices.Contains(roots, parent) {
+			roots = append(roots, parent)
+		}
+	}
+	slices.Sort(roots)
+
+	var b strings.Builder
+	var walk func(branch, prefix string)
+	walk = func(branch, prefix string) {
+		kids := children[branch]
+		for i, child := range kids {
+			connector, indent := "├── ", "│   "
+			if i == len(kids)-1 {
+				connector, indent = "└── ", "    "
+			}
+			b.WriteString(prefix + connector + label(child) + "\n")
+			walk(child, prefix+indent)
+		}
+	}
+	for _, root := range roots {
+		b.WriteString(label(root) + "\n")
+		walk(root, "")
+	}
+	return b.String()
+}
+
+// stackChildren inverts branch -> parent into parent -> sorted children.
+func stackChildren(parents map[string]string) map[string][]string {
+	children := make(map[string][]string)
+	for branch, parent := range parents {
+		children[parent] = append(children[parent], branch)
+	}
+	for _, kids := range children {
+		slices.Sort(kids)
+	}
+	return children
+}
+
+// stackOrder returns the branches of the stack containing branch, parents
+// before children: the bottom-most stacked ancestor of branch and all its
+// descendants. base is the branch the stack is built on. A branch that isn't
+// stacked (or is part of a parent cycle) has no stack.
+func stackOrder(parents map[string]string, branch string) (base string, order []string) {
+	seen := make(map[string]bool)
+	bottom := branch
+	for parents[bottom] != "" && parents[parents[bottom]] != "" {
+		if seen[bottom] {
+			return "", nil
+		}
+		seen[bottom] = true
+		bottom = parents[bottom]
+	}
+	if parents[bottom] == "" {
+		return "", nil
+	}
+
+	children := stackChildren(parents)
+	queue := []string{bottom}
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+		order = append(order, next)
+		queue = append(queue, children[next]...)
+	}
+	return parents[bottom], order
+}
+
+// rebaseStack rebases the worktrees of the stack containing branch onto
+// their parents, as ordered by stackOrder. Each rebase is recorded in the
+// journal.
+func rebaseStack(ctx context.Context, repo registry.Repo, branch string) error {
+	l := log.FromContext(ctx)
+	out := output.FromContext(ctx)
+
+	parents, err := git.GetBranchParents(ctx, repo.Path)
+	if err != nil {
+		return err
+	}
+	base, order := stackOrder(parents, branch)
+	if len(order) == 0 {
+		return fmt.Errorf("%s is not a stacked branch (create one with 'wt checkout -b <branch> --stack')", branch)
+	}
+
+	worktrees, err := git.ListWorktreesFromRepo(ctx, repo.Path)
+	if err != nil {
+		return err
+	}
+	paths := make(map[string]string)
+	for _, wt := range worktrees {
+		paths[wt.Branch] = wt.Path
+	}
+
+	// The bottom of the stack follows the remote base branch
+	onto := map[string]string{order[0]: base}
+	if resolveEffectiveConfig(ctx, repo.Path).Checkout.BaseRef != "local" {
+		if err := git.FetchBranchFromRemote(ctx, repo.Path, "origin", base); err != nil {
+			l.Printf("Warning: fetch failed for origin/%s: %v (continuing with local refs)\n", base, err)
+		}
+		if git.RefExists(ctx, repo.Path, "origin/"+base) {
+			onto[order[0]] = "origin/" + base
+		}
+	}
+
+	var failed int
+	blocked := make(map[string]bool) // branches whose children must not be rebased
+	for _, b := range order {
+		parent := parents[b]
+		if _, ok := onto[b]; !ok {
+			onto[b] = parent
+		}
+		prefix := repo.Name + ":" + b
+		switch {
+		case blocked[parent]:
+			l.Printf("%s: skipped, %s was not rebased\n", prefix, parent)
+			blocked[b] = true
+		case !git.RefExists(ctx, repo.Path, "refs/heads/"+parent):
+			l.Printf("%s: skipped, parent %s no longer exists (wt prune retargets children of merged branches)\n", prefix, parent)
+			blocked[b] = true
+			failed++
+		case paths[b] == "":
+			l.Printf("%s: skipped, no worktree\n", prefix)
+		default:
+			if err := rebaseStackWorktree(ctx, repo, b, paths[b], onto[b]); err != nil {
+				l.Printf("%s: %v\n", prefix, err)
+				blocked[b] = true
+				failed++
+			}
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to rebase %d branch(es)", failed)
+	}
+	out.Printf("Rebased stack of %d branch(es) onto %s\n", len(order), onto[order[0]])
+	return nil
+}
+
+// rebaseStackWorktree rebases the worktree of branch at path onto upstream.
+func rebaseStackWorktree(ctx context.Context, repo registry.Repo, branch, path, upstream string) (err error) {
+	out := output.FromContext(ctx)
+
+	status, err := git.GetStatus(ctx, path)
+	if err != nil {
+		return err
+	}
+	if status.IsDirty() {
+		return fmt.Errorf("not rebased: worktree has uncommitted changes")
+	}
+	if operation, err := git.GetInProgressOperation(ctx, path); err != nil {
+		return err
+	} else if operation != "" {
+		return fmt.Errorf("not rebased: %s in progress", operation)
+	}
+
+	before, err := git.GetHeadCommit(ctx, path)
+	if err != nil {
+		return err
+	}
+
+	ctx, op := startJournalOp(ctx, "stack rebase")
+	defer func() { op.finish(ctx, err) }()
+	op.setRepo(repo)
+	op.Branch = branch
+	op.Path = path
+	op.Detail = "onto " + upstream
+
+	// A parent that was merged and retargeted recorded its old tip, so its
+	// commits (squashed into upstream under different SHAs) aren't replayed
+	oldBase, err := git.GetBranchParentBase(ctx, repo.Path, branch)
+	if err != nil {
+		return err
+	}
+	if oldBase != "" {
+		if ok, _ := git.IsAncestor(ctx, path, oldBase, "HEAD"); !ok { //nolint:errcheck
+			oldBase = ""
+		}
+	}
+
+	if err := git.RebaseOnto(ctx, path, upstream, oldBase); err != nil {
+		if operation, _ := git.GetInProgressOperation(ctx, path); operation == git.OperationRebase { //nolint:errcheck
+			return fmt.Errorf("rebase onto %s stopped with conflicts: resolve them in %s, run 'git rebase --continue' and rerun 'wt stack rebase'", upstream, path)
+		}
+		return err
+	}
+
+	if op.SHA, err = git.GetHeadCommit(ctx, path); err != nil {
+		return err
+	}
+	if err := git.ClearBranchParentBase(ctx, repo.Path, branch); err != nil {
+		log.FromContext(ctx).Debug("failed to clear parent base", "branch", branch, "error", err)
+	}
+	if op.SHA == before {
+		out.Printf("%s:%s: up to date with %s\n", repo.Name, branch, upstream)
+	} else {
+		out.Printf("%s:%s: rebased onto %s\n", repo.Name, branch, upstream)
+	}
+	return nil
+}
+
+// retargetStackChildren retargets the stacked children of wt's branch,
+// which was merged, to the branch it was built on: its own parent, else its
+// PR's base branch, else the default branch. Children's open PRs (as known
+// from the PR cache) are retargeted on the forge as well, before the merged
+// branch may be deleted, which would close them. Returns the retargeted
+// children.
+func retargetStackChildren(ctx context.Context, wt git.Worktree, prCache *prcache.Cache) []string {
+	l := log.FromContext(ctx)
+
+	parents, err := git.GetBranchParents(ctx, wt.RepoPath)
+	if err != nil {
+		l.Debug("failed to read stacked branches", "repo", wt.RepoPath, "error", err)
+		return nil
+	}
+	children := stackChildren(parents)[wt.Branch]
+	if len(children) == 0 {
+		return nil
+	}
+
+	newBase := parents[wt.Branch]
+	if newBase == "" && prCache != nil {
+		if pr := prCache.Get(prcache.CacheKey(wt.RepoPath, wt.Branch)); pr != nil {
+			newBase = pr.BaseBranch
+		}
+	}
+	if newBase == "" {
+		newBase = git.GetDefaultBranch(ctx, wt.RepoPath)
+	}
+
+	// Children still contain the merged branch's commits; its tip tells
+	// wt stack rebase where their own commits start
+	oldTip, err := git.ResolveCommit(ctx, wt.RepoPath, "refs/heads/"+wt.Branch)
+	if err != nil {
+		l.Debug("failed to resolve merged branch", "branch", wt.Branch, "error", err)
+	}
+
+	var f forge.Forge
+	originURL, err := git.GetOriginURL(ctx, wt.RepoPath)
+	if err != nil {
+		l.Debug("no origin, retargeting locally only", "repo", wt.RepoPath, "error", err)
+	} else {
+		effCfg := resolveEffectiveConfig(ctx, wt.RepoPath)
+		f = forge.Detect(originURL, effCfg.Hosts, &effCfg.Forge)
+	}
+
+	for _, child := range children {
+		if err := git.SetBranchParent(ctx, wt.RepoPath, child, newBase); err != nil {
+			l.Printf("Warning: failed to retarget %s: %v\n", child, err)
+			continue
+		}
+		l.Printf("Retargeted %s onto %s\n", child, newBase)
+		if oldTip != "" {
+			if err := git.SetBranchParentBase(ctx, wt.RepoPath, child, oldTip); err != nil {
+				l.Printf("Warning: failed to record the old parent of %s: %v\n", child, err)
+			}
+		}
+
+		if f == nil {
+			continue
+		}
+		key := prcache.CacheKey(wt.RepoPath, child)
+		var pr *forge.PRInfo
+		if prCache != nil {
+			pr = prCache.Get(key)
+		}
+		if pr == nil || !pr.Fetched || pr.Number == 0 || pr.State != forge.PRStateOpen || pr.BaseBranch == newBase {
+			continue
+		}
+		if err := f.UpdatePRBase(ctx, originURL, pr.Number, newBase); err != nil {
+			l.Printf("Warning: failed to retarget PR #%d of %s: %v\n", pr.Number, child, err)
+			continue
+		}
+		l.Printf("Retargeted PR #%d of %s onto %s\n", pr.Number, child, newBase)
+		if prCache != nil {
+			updated := *pr
+			updated.BaseBranch = newBase
+			prCache.Set(key, &updated)
+		}
+	}
+	return children
+}
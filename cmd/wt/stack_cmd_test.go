@@ -0,0 +1,74 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStackOrder(t *testing.T) {
+	t.Parallel()
+
+	parents := map[string]string{
+		"a":     "main",
+		"b":     "a",
+		"c":     "b",
+		"d":     "a",
+		"other": "main",
+		"x":     "y", // cycle
+		"y":     "x",
+	}
+
+	tests := []struct {
+		branch    string
+		wantBase  string
+		wantOrder []string
+	}{
+		{"a", "main", []string{"a", "b", "d", "c"}},
+		{"c", "main", []string{"a", "b", "d", "c"}},
+		{"other", "main", []string{"other"}},
+		{"main", "", nil},
+		{"unknown", "", nil},
+		{"x", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.branch, func(t *testing.T) {
+			base, order := stackOrder(parents, tt.branch)
+			if base != tt.wantBase || !reflect.DeepEqual(order, tt.wantOrder) {
+				t.Errorf("stackOrder(%q) = %q, %v; want %q, %v", tt.branch, base, order, tt.wantBase, tt.wantOrder)
+			}
+		})
+	}
+}
+
+func TestRenderStackTree(t *testing.T) {
+	t.Parallel()
+
+	parents := map[string]string{
+		"a":       "main",
+		"b":       "a",
+		"c":       "b",
+		"d":       "a",
+		"hotfix":  "release",
+		"x":       "y", // cycle, not shown
+		"y":       "x",
+		"feature": "main",
+	}
+	got := renderStackTree(parents, func(branch string) string {
+		if branch == "b" {
+			return "b (#2)"
+		}
+		return branch
+	})
+	want := `main
+├── a
+│   ├── b (#2)
+│   │   └── c
+│   └── d
+└── feature
+release
+└── hotfix
+`
+	if got != want {
+		t.Errorf("renderStackTree() =\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,176 @@
+//go:build integration
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/raphi011/wt/internal/config"
+	"github.com/raphi011/wt/internal/registry"
+)
+
+// setupStackTest creates a registered repo and returns the repo path and a
+// config for it (sibling worktrees named <repo>-<branch>).
+func setupStackTest(t *testing.T) (string, *config.Config) {
+	t.Helper()
+
+	tmpDir := resolvePath(t, t.TempDir())
+	repoPath := setupTestRepo(t, tmpDir, "myrepo")
+
+	regFile := filepath.Join(tmpDir, ".wt", "repos.json")
+	if err := os.MkdirAll(filepath.Dir(regFile), 0755); err != nil {
+		t.Fatalf("failed to create registry directory: %v", err)
+	}
+	reg := &registry.Registry{
+		Repos: []registry.Repo{
+			{Name: "myrepo", Path: repoPath, WorktreeFormat: "../{repo}-{branch}"},
+		},
+	}
+	if err := reg.Save(regFile); err != nil {
+		t.Fatalf("failed to save registry: %v", err)
+	}
+
+	return repoPath, &config.Config{
+		RegistryPath: regFile,
+		Checkout:     config.CheckoutConfig{WorktreeFormat: "../{repo}-{branch}"},
+	}
+}
+
+// TestCheckout_Stack tests creating a stacked branch.
+//
+// Scenario: User is in the feature-a worktree (with a commit not on main) and runs `wt checkout -b feature-b --stack`
+// Expected: feature-b starts at feature-a and records feature-a as its parent
+func TestCheckout_Stack(t *testing.T) {
+	t.Parallel()
+
+	repoPath, cfg := setupStackTest(t)
+	wtA := createTestWorktree(t, repoPath, "feature-a")
+	addCommit(t, wtA, "a.txt", "Feature A")
+
+	cmd := newCheckoutCmd()
+	cmd.SetContext(testContextWithConfig(t, cfg, wtA))
+	cmd.SetArgs([]string{"-b", "feature-b", "--stack"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("checkout command failed: %v", err)
+	}
+
+	parent, err := runGitCommand(repoPath, "config", "branch.feature-b.wt-parent")
+	if err != nil || strings.TrimSpace(parent) != "feature-a" {
+		t.Errorf("parent = %q (%v), want feature-a", strings.TrimSpace(parent), err)
+	}
+
+	wtB := filepath.Join(filepath.Dir(repoPath), "myrepo-feature-b")
+	if _, err := os.Stat(filepath.Join(wtB, "a.txt")); err != nil {
+		t.Errorf("feature-b should contain feature-a's commit: %v", err)
+	}
+}
+
+// TestCheckout_StackRequiresNewBranch tests that --stack needs -b.
+//
+// Scenario: User runs `wt checkout feature --stack`
+// Expected: Command fails without creating a worktree
+func TestCheckout_StackRequiresNewBranch(t *testing.T) {
+	t.Parallel()
+
+	repoPath, cfg := setupStackTest(t)
+
+	cmd := newCheckoutCmd()
+	cmd.SetContext(testContextWithConfig(t, cfg, repoPath))
+	cmd.SetArgs([]string{"feature", "--stack"})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "--stack requires") {
+		t.Errorf("expected --stack requires -b error, got %v", err)
+	}
+}
+
+// TestStack_ShowTree tests showing the stacks of a repo.
+//
+// Scenario: feature-a is stacked on main and feature-b on feature-a, user runs `wt stack`
+// Expected: Tree rooted at main lists feature-a with feature-b below it
+func TestStack_ShowTree(t *testing.T) {
+	t.Parallel()
+
+	repoPath, cfg := setupStackTest(t)
+	createTestWorktree(t, repoPath, "feature-a")
+	wtB := createTestWorktree(t, repoPath, "feature-b")
+	runGitCommand(repoPath, "config", "branch.feature-a.wt-parent", "main")
+	runGitCommand(repoPath, "config", "branch.feature-b.wt-parent", "feature-a")
+
+	ctx, out := testContextWithConfigAndOutput(t, cfg, wtB)
+	cmd := newStackCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("stack command failed: %v", err)
+	}
+
+	want := "main\n└── feature-a\n    └── feature-b  (current)\n"
+	if got := out.String(); got != want {
+		t.Errorf("output =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+// TestStackRebase tests rebasing a stack after its parent was rewritten.
+//
+// Scenario: feature-b is stacked on feature-a, feature-a's commit is amended, user runs `wt stack rebase feature-b`
+// Expected: feature-b is rebased onto the new feature-a, keeping only its own commit
+func TestStackRebase(t *testing.T) {
+	t.Parallel()
+
+	repoPath, cfg := setupStackTest(t)
+	wtA := createTestWorktree(t, repoPath, "feature-a")
+	addCommit(t, wtA, "a.txt", "Feature A")
+	runGitCommand(repoPath, "config", "branch.feature-a.wt-parent", "main")
+
+	wtB := createTestWorktree(t, repoPath, "feature-b")
+	if _, err := runGitCommand(wtB, "reset", "--hard", "feature-a"); err != nil {
+		t.Fatalf("failed to reset feature-b: %v", err)
+	}
+	addCommit(t, wtB, "b.txt", "Feature B")
+	runGitCommand(repoPath, "config", "branch.feature-b.wt-parent", "feature-a")
+
+	if _, err := runGitCommand(wtA, "commit", "--amend", "-m", "Feature A v2"); err != nil {
+		t.Fatalf("failed to amend: %v", err)
+	}
+
+	ctx, out := testContextWithConfigAndOutput(t, cfg, repoPath)
+	cmd := newStackCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"rebase", "feature-b"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("stack rebase failed: %v", err)
+	}
+
+	log, err := runGitCommand(wtB, "log", "--format=%s", "main..HEAD")
+	if err != nil {
+		t.Fatalf("git log failed: %v", err)
+	}
+	if got := strings.TrimSpace(log); got != "Feature B\nFeature A v2" {
+		t.Errorf("feature-b history = %q, want Feature B on top of Feature A v2", got)
+	}
+	if !strings.Contains(out.String(), "myrepo:feature-b: rebased onto feature-a") {
+		t.Errorf("output should report the rebase, got:\n%s", out.String())
+	}
+}
+
+// TestStackRebase_NotStacked tests rebasing a branch without parent.
+//
+// Scenario: User runs `wt stack rebase` in a worktree that isn't stacked
+// Expected: Command fails with a hint to create a stacked branch
+func TestStackRebase_NotStacked(t *testing.T) {
+	t.Parallel()
+
+	repoPath, cfg := setupStackTest(t)
+	wt := createTestWorktree(t, repoPath, "feature")
+
+	cmd := newStackCmd()
+	cmd.SetContext(testContextWithConfig(t, cfg, wt))
+	cmd.SetArgs([]string{"rebase"})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "not a stacked branch") {
+		t.Errorf("expected not a stacked branch error, got %v", err)
+	}
+}
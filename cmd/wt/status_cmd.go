@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/raphi011/wt/internal/config"
+	"github.com/raphi011/wt/internal/git"
+	"github.com/raphi011/wt/internal/log"
+	"github.com/raphi011/wt/internal/output"
+	"github.com/raphi011/wt/internal/registry"
+	"github.com/raphi011/wt/internal/ui/static"
+)
+
+func newStatusCmd() *cobra.Command {
+	var (
+		jsonOutput bool
+		global     bool
+		refresh    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:     "status [scope...]",
+		Short:   "Show working tree status of worktrees",
+		Aliases: []string{"st"},
+		GroupID: GroupCore,
+		Args:    cobra.ArbitraryArgs,
+		Long: `Show the working tree status of each worktree.
+
+For every worktree shows whether it is clean or dirty, the number of
+staged (+), unstaged (~), untracked (?) and conflicted (!) files, commits
+ahead (↑) and behind (↓) its upstream and origin/<default>, the number of
+stashes, an in-progress rebase/merge/cherry-pick/revert, and the PR state.
+
+Inside a repo: shows only that repo's worktrees. Use --global for all.
+Use positional args to filter by repo name(s) or label(s).
+Resolution order: repo name → label.`,
+		Example: `  wt status                    # Status of worktrees in current repo
+  wt status --global           # Status of all worktrees (all repos)
+  wt status myrepo backend     # Filter by repo name or label
+  wt status -R                 # Refresh PR status first
+  wt status --json             # Output as JSON`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			cfg := config.FromContext(ctx)
+			l := log.FromContext(ctx)
+			out := output.FromContext(ctx)
+
+			reg, err := registry.Load(cfg.RegistryPath)
+			if err != nil {
+				return fmt.Errorf("load registry: %w", err)
+			}
+
+			// Determine which repos to show
+			var repos []registry.Repo
+			if global {
+				repos = reg.Repos
+			} else if len(args) > 0 {
+				repos, err = resolveScopeArgs(reg, args)
+				if err != nil {
+					return err
+				}
+			} else {
+				repo, err := findOrRegisterCurrentRepoFromContext(ctx, reg)
+				if err != nil {
+					// Not in a repo, show all
+					repos = reg.Repos
+				} else {
+					repos = []registry.Repo{repo}
+				}
+			}
+
+			repos = filterOrphanedRepos(l, repos)
+
+			l.Debug("loading worktree status", "repos", len(repos))
+
+			worktrees, warnings := git.LoadWorktreesForRepos(ctx, reposToRefs(repos))
+			for _, w := range warnings {
+				l.Printf("Warning: %s: %v\n", w.RepoName, w.Err)
+			}
+
+			prCache := loadPRCache(ctx, cfg)
+			if refresh {
+				if failed := refreshPRs(ctx, worktrees, prCache, cfg.Hosts, &cfg.Forge); len(failed) > 0 {
+					l.Printf("Warning: failed to fetch PR status for: %v\n", failed)
+				}
+			} else {
+				autoRefreshPRs(ctx, worktrees, prCache, cfg)
+			}
+			populatePRFields(worktrees, prCache)
+			if err := prCache.SaveIfDirty(); err != nil {
+				l.Printf("Warning: failed to save PR cache: %v\n", err)
+			}
+
+			statuses, warnings := git.LoadWorktreeStatuses(ctx, worktrees)
+			for _, w := range warnings {
+				l.Printf("Warning: %s: %v\n", w.RepoName, w.Err)
+			}
+
+			if jsonOutput {
+				if statuses == nil {
+					statuses = []git.WorktreeStatus{}
+				}
+				enc := json.NewEncoder(out.Writer())
+				enc.SetIndent("", "  ")
+				return enc.Encode(statuses)
+			}
+
+			if len(statuses) == 0 {
+				out.Println("No worktrees found")
+				return nil
+			}
+
+			var rows [][]string
+			for _, st := range statuses {
+				rows = append(rows, static.StatusTableRow(st))
+			}
+			out.Print(static.RenderTable(static.StatusTableHeaders, rows))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+	cmd.Flags().BoolVarP(&global, "global", "g", false, "Show all worktrees (not just current repo)")
+	cmd.Flags().BoolVarP(&refresh, "refresh-pr", "R", false, "Refresh PR status before showing status")
+
+	cmd.ValidArgsFunction = completeScopeArgs
+
+	return cmd
+}
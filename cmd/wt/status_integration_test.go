@@ -0,0 +1,142 @@
+//go:build integration
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/raphi011/wt/internal/config"
+	"github.com/raphi011/wt/internal/git"
+	"github.com/raphi011/wt/internal/registry"
+)
+
+// TestStatus_DirtyWorktree tests the status table for clean and dirty worktrees.
+//
+// Scenario: User runs `wt status` with one clean and one dirty worktree
+// Expected: The dirty worktree shows "dirty" and its untracked file count
+func TestStatus_DirtyWorktree(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	tmpDir = resolvePath(t, tmpDir)
+
+	repoPath := setupTestRepoWithBranches(t, tmpDir, "test-repo", []string{"feature"})
+	wtPath := createTestWorktree(t, repoPath, "feature")
+
+	if err := os.WriteFile(filepath.Join(wtPath, "wip.txt"), []byte("wip\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	regFile := filepath.Join(tmpDir, ".wt", "repos.json")
+	if err := os.MkdirAll(filepath.Dir(regFile), 0755); err != nil {
+		t.Fatalf("failed to create registry directory: %v", err)
+	}
+
+	reg := &registry.Registry{
+		Repos: []registry.Repo{
+			{Name: "test-repo", Path: repoPath},
+		},
+	}
+	if err := reg.Save(regFile); err != nil {
+		t.Fatalf("failed to save registry: %v", err)
+	}
+
+	cfg := &config.Config{RegistryPath: regFile}
+	ctx, out := testContextWithOutput(t)
+	ctx = config.WithConfig(ctx, cfg)
+	ctx = config.WithWorkDir(ctx, repoPath)
+	cmd := newStatusCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("status command failed: %v", err)
+	}
+
+	output := out.String()
+	var featureLine, mainLine string
+	for line := range strings.SplitSeq(output, "\n") {
+		if strings.Contains(line, "feature") {
+			featureLine = line
+		}
+		if strings.Contains(line, " main ") {
+			mainLine = line
+		}
+	}
+	if !strings.Contains(featureLine, "dirty") || !strings.Contains(featureLine, "?1") {
+		t.Errorf("expected feature to be dirty with 1 untracked file, got %q", featureLine)
+	}
+	if !strings.Contains(mainLine, "clean") {
+		t.Errorf("expected main to be clean, got %q", mainLine)
+	}
+}
+
+// TestStatus_JSON tests JSON output of the status command.
+//
+// Scenario: User runs `wt status --json` with a staged change and a stash
+// Expected: JSON contains the worktree with staged and stash counts
+func TestStatus_JSON(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	tmpDir = resolvePath(t, tmpDir)
+
+	repoPath := setupTestRepoWithBranches(t, tmpDir, "test-repo", []string{"feature"})
+	wtPath := createTestWorktree(t, repoPath, "feature")
+
+	if err := os.WriteFile(filepath.Join(wtPath, "stashed.txt"), []byte("x\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	mustRunGit(t, wtPath, "stash", "push", "-u", "-m", "wip")
+	if err := os.WriteFile(filepath.Join(wtPath, "staged.txt"), []byte("x\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	mustRunGit(t, wtPath, "add", "staged.txt")
+
+	regFile := filepath.Join(tmpDir, ".wt", "repos.json")
+	if err := os.MkdirAll(filepath.Dir(regFile), 0755); err != nil {
+		t.Fatalf("failed to create registry directory: %v", err)
+	}
+
+	reg := &registry.Registry{
+		Repos: []registry.Repo{
+			{Name: "test-repo", Path: repoPath},
+		},
+	}
+	if err := reg.Save(regFile); err != nil {
+		t.Fatalf("failed to save registry: %v", err)
+	}
+
+	cfg := &config.Config{RegistryPath: regFile}
+	ctx, out := testContextWithOutput(t)
+	ctx = config.WithConfig(ctx, cfg)
+	ctx = config.WithWorkDir(ctx, repoPath)
+	cmd := newStatusCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"--json"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("status command failed: %v", err)
+	}
+
+	var statuses []git.WorktreeStatus
+	if err := json.Unmarshal([]byte(out.String()), &statuses); err != nil {
+		t.Fatalf("failed to parse JSON output: %v\n%s", err, out.String())
+	}
+
+	var found bool
+	for _, st := range statuses {
+		if st.Branch != "feature" {
+			continue
+		}
+		found = true
+		if !st.Dirty || st.Staged != 1 || st.Stashes != 1 {
+			t.Errorf("feature: expected dirty with 1 staged file and 1 stash, got %+v", st)
+		}
+	}
+	if !found {
+		t.Errorf("expected feature worktree in JSON output, got %s", out.String())
+	}
+}
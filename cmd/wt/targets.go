@@ -56,17 +56,29 @@ func findOrRegisterCurrentRepo(ctx context.Context, reg *registry.Registry, cfg
 		Labels: cfg.DefaultLabels,
 	}
 
-	if err := reg.Add(newRepo); err != nil {
-		return registry.Repo{}, err
-	}
-
-	if err := reg.Save(cfg.RegistryPath); err != nil {
+	if err := autoRegisterRepo(cfg, reg, newRepo); err != nil {
 		return registry.Repo{}, err
 	}
 
 	return reg.FindByPath(repoPath)
 }
 
+// autoRegisterRepo adds repo to the registry file in a locked transaction
+// and to the in-memory reg. A concurrent wt process registering the same
+// path first is not an error.
+func autoRegisterRepo(cfg *config.Config, reg *registry.Registry, repo registry.Repo) error {
+	err := registry.Transact(cfg.RegistryPath, func(r *registry.Registry) error {
+		if _, err := r.FindByPath(repo.Path); err == nil {
+			return nil
+		}
+		return r.Add(repo)
+	})
+	if err != nil {
+		return err
+	}
+	return reg.Add(repo)
+}
+
 // findOrRegisterCurrentRepoFromContext is a convenience wrapper that gets cfg from context.
 func findOrRegisterCurrentRepoFromContext(ctx context.Context, reg *registry.Registry) (registry.Repo, error) {
 	cfg := config.FromContext(ctx)
@@ -0,0 +1,417 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/raphi011/wt/internal/config"
+	"github.com/raphi011/wt/internal/git"
+	"github.com/raphi011/wt/internal/log"
+	"github.com/raphi011/wt/internal/output"
+	"github.com/raphi011/wt/internal/registry"
+	"github.com/raphi011/wt/internal/trash"
+	"github.com/raphi011/wt/internal/ui/static"
+)
+
+func newTrashCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "trash",
+		Short:   "Manage pruned worktrees and removed repos",
+		GroupID: GroupUtility,
+		Long: `Manage the trash.
+
+With [trash] enabled = true in the config, 'wt prune' and 'wt repo remove -D'
+move directories into ~/.wt/trash/<timestamp>/ instead of deleting them.
+Each entry records the repo, branch, HEAD commit and branch note so it can
+be restored with 'wt trash restore' or 'wt undo'.`,
+		Example: `  wt trash list                     # List trashed worktrees and repos
+  wt trash restore                  # Restore the most recent entry
+  wt trash restore 20260102-150405  # Restore a specific entry
+  wt trash empty --older-than 168h  # Delete entries older than a week`,
+	}
+
+	cmd.AddCommand(newTrashListCmd())
+	cmd.AddCommand(newTrashRestoreCmd())
+	cmd.AddCommand(newTrashEmptyCmd())
+
+	return cmd
+}
+
+func newTrashListCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:     "list",
+		Short:   "List trashed worktrees and repos",
+		Aliases: []string{"ls"},
+		Args:    cobra.NoArgs,
+		Example: `  wt trash list         # Newest first
+  wt trash list --json  # Output as JSON`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			cfg := config.FromContext(ctx)
+			out := output.FromContext(ctx)
+
+			trashDir, err := cfg.GetTrashDir()
+			if err != nil {
+				return err
+			}
+			entries, err := trash.List(trashDir)
+			if err != nil {
+				return fmt.Errorf("list trash: %w", err)
+			}
+
+			if jsonOutput {
+				if entries == nil {
+					entries = []trash.Entry{}
+				}
+				enc := json.NewEncoder(out.Writer())
+				enc.SetIndent("", "  ")
+				return enc.Encode(entries)
+			}
+
+			if len(entries) == 0 {
+				out.Println("Trash is empty")
+				return nil
+			}
+
+			var rows [][]string
+			for _, e := range entries {
+				branch := e.Branch
+				if e.Kind == trash.KindWorktree && branch == "" {
+					branch = "(detached)"
+				}
+				rows = append(rows, []string{e.ID, string(e.Kind), e.RepoName, branch, e.TrashedAt.Format("2006-01-02 15:04")})
+			}
+			out.Print(static.RenderTable([]string{"ID", "KIND", "REPO", "BRANCH", "TRASHED"}, rows))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+
+	return cmd
+}
+
+func newTrashRestoreCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore [id]",
+		Short: "Restore a trashed worktree or repo",
+		Args:  cobra.MaximumNArgs(1),
+		Long: `Restore an entry from the trash to its original location.
+
+Worktrees are re-attached to their repo; a deleted branch is recreated at
+the recorded HEAD commit and its note is restored. Repos are moved back
+and re-registered.
+
+Without an id, the most recent entry is restored.`,
+		Example: `  wt trash restore                  # Restore the most recent entry
+  wt trash restore 20260102-150405  # Restore a specific entry`,
+		ValidArgsFunction: completeTrashIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var id string
+			if len(args) > 0 {
+				id = args[0]
+			}
+			return runTrashRestore(cmd.Context(), id)
+		},
+	}
+
+	return cmd
+}
+
+func newTrashEmptyCmd() *cobra.Command {
+	var olderThan time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "empty",
+		Short: "Permanently delete trashed entries",
+		Args:  cobra.NoArgs,
+		Example: `  wt trash empty                    # Delete everything in the trash
+  wt trash empty --older-than 168h  # Delete entries older than a week`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			cfg := config.FromContext(ctx)
+			out := output.FromContext(ctx)
+
+			if olderThan < 0 {
+				return fmt.Errorf("--older-than must not be negative")
+			}
+
+			trashDir, err := cfg.GetTrashDir()
+			if err != nil {
+				return err
+			}
+			n, err := trash.Empty(ctx, trashDir, olderThan, time.Now())
+			if err != nil {
+				return fmt.Errorf("empty trash: %w", err)
+			}
+			out.Printf("Deleted %d trash entry(s)\n", n)
+			return nil
+		},
+	}
+
+	cmd.Flags().DurationVar(&olderThan, "older-than", 0, "Only delete entries trashed longer ago than this (e.g. 72h)")
+
+	return cmd
+}
+
+func newUndoCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "undo",
+		Short:   "Restore the most recently trashed worktree or repo",
+		GroupID: GroupUtility,
+		Args:    cobra.NoArgs,
+		Long: `Restore the most recently trashed worktree or repo.
+
+Shorthand for 'wt trash restore'. Requires [trash] enabled = true in the
+config, so that 'wt prune' and 'wt repo remove -D' keep what they remove.`,
+		Example: `  wt prune feature  # Remove a worktree (moved to the trash)
+  wt undo           # Bring it back`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTrashRestore(cmd.Context(), "")
+		},
+	}
+
+	return cmd
+}
+
+// trashDirFromConfig returns the trash directory if the trash is enabled,
+// or "" if removed directories should be deleted.
+func trashDirFromConfig(ctx context.Context, cfg *config.Config) string {
+	if !cfg.Trash.Enabled {
+		return ""
+	}
+	trashDir, err := cfg.GetTrashDir()
+	if err != nil {
+		log.FromContext(ctx).Printf("Warning: trash disabled: %v\n", err)
+		return ""
+	}
+	return trashDir
+}
+
+// trashWorktree moves a worktree into the trash instead of removing it.
+// The saved manifest records the branch, HEAD commit (pinned by a ref, so
+// deleting the branch doesn't lose it) and branch note; the worktree's
+// index is kept so staged changes survive a restore. The stale
+// worktree reference is pruned right away so the branch can be deleted.
+func trashWorktree(ctx context.Context, trashDir string, wt git.Worktree, command string) (*trash.Entry, error) {
+	l := log.FromContext(ctx)
+
+	head, err := git.GetHeadCommit(ctx, wt.Path)
+	if err != nil {
+		return nil, fmt.Errorf("resolve HEAD: %w", err)
+	}
+	gitDir, err := git.GetAbsoluteGitDir(ctx, wt.Path)
+	if err != nil {
+		return nil, fmt.Errorf("resolve git dir: %w", err)
+	}
+
+	var note string
+	if wt.Branch != "" {
+		// Non-fatal: a missing note is simply not restored
+		note, _ = git.GetBranchNote(ctx, wt.RepoPath, wt.Branch) //nolint:errcheck
+	}
+
+	repoName := wt.RepoName
+	if repoName == "" {
+		repoName = filepath.Base(wt.RepoPath)
+	}
+
+	entry, err := trash.Put(ctx, trashDir, trash.Manifest{
+		Kind:     trash.KindWorktree,
+		Command:  command,
+		Path:     wt.Path,
+		RepoPath: wt.RepoPath,
+		RepoName: repoName,
+		Branch:   wt.Branch,
+		HeadSHA:  head,
+		Note:     note,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The admin dir (and its index) stays until the reference is pruned
+	if err := entry.SaveIndex(filepath.Join(gitDir, "index")); err != nil {
+		l.Printf("Warning: failed to save index of %s: %v\n", wt.Path, err)
+	}
+
+	if err := git.PruneWorktrees(ctx, wt.RepoPath); err != nil {
+		l.Printf("Warning: failed to prune stale references in %s: %v\n", wt.RepoPath, err)
+	}
+
+	l.Debug("trashed worktree", "path", wt.Path, "id", entry.ID)
+	return entry, nil
+}
+
+// trashRepo moves a repository directory into the trash, recording its
+// registry entry so a restore can re-register it.
+func trashRepo(ctx context.Context, trashDir string, repo registry.Repo) (*trash.Entry, error) {
+	return trash.Put(ctx, trashDir, trash.Manifest{
+		Kind:     trash.KindRepo,
+		Command:  "repo remove",
+		Path:     repo.Path,
+		RepoPath: repo.Path,
+		RepoName: repo.Name,
+		Repo:     &repo,
+	})
+}
+
+// runTrashRestore restores the trash entry with the given id, or the most
+// recent entry if id is empty.
+func runTrashRestore(ctx context.Context, id string) (err error) {
+	cfg := config.FromContext(ctx)
+	out := output.FromContext(ctx)
+
+	trashDir, err := cfg.GetTrashDir()
+	if err != nil {
+		return err
+	}
+
+	var entry *trash.Entry
+	if id == "" {
+		entries, err := trash.List(trashDir)
+		if err != nil {
+			return fmt.Errorf("list trash: %w", err)
+		}
+		if len(entries) == 0 {
+			return fmt.Errorf("nothing to restore: trash is empty")
+		}
+		entry = &entries[0]
+	} else {
+		entry, err = trash.Get(trashDir, id)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, op := startJournalOp(ctx, "trash restore")
+	op.Repo = entry.RepoName
+	op.RepoPath = entry.RepoPath
+	op.Branch = entry.Branch
+	op.Path = entry.Path
+	op.SHA = entry.HeadSHA
+	op.Detail = entry.ID
+	defer func() { op.finish(ctx, err) }()
+
+	if _, err := os.Lstat(entry.Path); err == nil {
+		return fmt.Errorf("cannot restore %s: path already exists", entry.Path)
+	}
+
+	switch entry.Kind {
+	case trash.KindWorktree:
+		if err := restoreWorktree(ctx, entry); err != nil {
+			return err
+		}
+		out.Printf("Restored worktree %s (%s) at %s\n", entry.Branch, entry.RepoName, entry.Path)
+	case trash.KindRepo:
+		if err := restoreRepo(cfg, entry); err != nil {
+			return err
+		}
+		out.Printf("Restored repo %s at %s\n", entry.RepoName, entry.Path)
+	default:
+		return fmt.Errorf("unknown trash entry kind %q", entry.Kind)
+	}
+
+	return entry.Delete(ctx)
+}
+
+// restoreWorktree re-attaches a trashed worktree: recreates its branch if it
+// was deleted, registers the worktree without checkout, moves the files back
+// and restores the index and branch note.
+func restoreWorktree(ctx context.Context, entry *trash.Entry) error {
+	l := log.FromContext(ctx)
+
+	if _, err := os.Stat(entry.RepoPath); err != nil {
+		return fmt.Errorf("cannot restore %s: repo %s not found", entry.Path, entry.RepoPath)
+	}
+
+	if entry.Branch != "" && !git.LocalBranchExists(ctx, entry.RepoPath, entry.Branch) {
+		if err := git.CreateLocalBranch(ctx, entry.RepoPath, entry.Branch, entry.HeadSHA); err != nil {
+			return err
+		}
+		l.Debug("recreated branch", "branch", entry.Branch, "commit", entry.HeadSHA)
+	}
+
+	if err := git.AttachWorktree(ctx, entry.RepoPath, entry.Path, entry.Branch, entry.HeadSHA); err != nil {
+		return fmt.Errorf("attach worktree: %w", err)
+	}
+
+	// Keep the fresh .git file, it points to the new admin directory
+	if err := entry.RestoreFiles(entry.Path, ".git"); err != nil {
+		return fmt.Errorf("restore files: %w", err)
+	}
+
+	restoredIndex := false
+	if entry.HasIndex() {
+		gitDir, err := git.GetAbsoluteGitDir(ctx, entry.Path)
+		if err == nil {
+			err = entry.RestoreIndex(filepath.Join(gitDir, "index"))
+		}
+		if err != nil {
+			l.Printf("Warning: failed to restore index, staged changes are lost: %v\n", err)
+		} else {
+			restoredIndex = true
+		}
+	}
+	if !restoredIndex {
+		if err := git.ResetIndex(ctx, entry.Path); err != nil {
+			return fmt.Errorf("reset index: %w", err)
+		}
+	}
+
+	if entry.Note != "" {
+		if err := git.SetBranchNote(ctx, entry.RepoPath, entry.Branch, entry.Note); err != nil {
+			l.Printf("Warning: failed to restore note: %v\n", err)
+		}
+	}
+
+	return nil
+}
+
+// restoreRepo moves a trashed repo back and re-registers it.
+func restoreRepo(cfg *config.Config, entry *trash.Entry) error {
+	if err := entry.RestoreFiles(entry.Path); err != nil {
+		return fmt.Errorf("restore files: %w", err)
+	}
+
+	if entry.Repo == nil {
+		return nil
+	}
+	err := registry.Transact(cfg.RegistryPath, func(r *registry.Registry) error {
+		return r.Add(*entry.Repo)
+	})
+	if err != nil {
+		return fmt.Errorf("re-register repo: %w", err)
+	}
+	return nil
+}
+
+// completeTrashIDs completes trash entry IDs, newest first.
+func completeTrashIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	cfg := config.FromContext(cmd.Context())
+	trashDir, err := cfg.GetTrashDir()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	entries, _ := trash.List(trashDir) //nolint:errcheck
+	var ids []string
+	for _, e := range entries {
+		desc := e.RepoName
+		if e.Branch != "" {
+			desc += ":" + e.Branch
+		}
+		ids = append(ids, e.ID+"\t"+desc)
+	}
+	return ids, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,286 @@
+//go:build integration
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/raphi011/wt/internal/config"
+	"github.com/raphi011/wt/internal/registry"
+	"github.com/raphi011/wt/internal/trash"
+)
+
+// TestTrash_PruneAndUndo tests that a pruned worktree can be restored.
+//
+// Scenario: With the trash enabled, user prunes a worktree with a note and a
+// staged file using --delete-branches, then runs `wt undo`
+// Expected: Worktree, branch, note and staged file are restored; trash is empty
+func TestTrash_PruneAndUndo(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := resolvePath(t, t.TempDir())
+
+	repoPath := setupTestRepoWithBranches(t, tmpDir, "test-repo", []string{"feature"})
+	wtPath := createTestWorktree(t, repoPath, "feature")
+	addCommit(t, wtPath, "feature.txt", "feature commit")
+	mustRunGit(t, repoPath, "merge", "--no-ff", "-m", "Merge feature", "feature")
+	head, err := runGitCommand(wtPath, "rev-parse", "HEAD")
+	if err != nil {
+		t.Fatalf("rev-parse failed: %v", err)
+	}
+	mustRunGit(t, repoPath, "config", "branch.feature.description", "login work")
+	if err := os.WriteFile(filepath.Join(wtPath, "staged.txt"), []byte("x\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	mustRunGit(t, wtPath, "add", "staged.txt")
+
+	regFile := saveSingleRepoRegistry(t, tmpDir, repoPath)
+	cfg := &config.Config{RegistryPath: regFile, Trash: config.TrashConfig{Enabled: true}}
+	ctx := testContextWithConfig(t, cfg, repoPath)
+
+	cmd := newPruneCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"feature", "-f", "--delete-branches"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("prune command failed: %v", err)
+	}
+
+	if _, err := os.Stat(wtPath); !os.IsNotExist(err) {
+		t.Fatal("worktree should be moved to the trash")
+	}
+	if _, err := runGitCommand(repoPath, "rev-parse", "--verify", "refs/heads/feature"); err == nil {
+		t.Fatal("branch should be deleted")
+	}
+	entries, err := trash.List(filepath.Join(tmpDir, ".wt", "trash"))
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected 1 trash entry, got %d (%v)", len(entries), err)
+	}
+	if !entries[0].BranchDeleted || entries[0].Note != "login work" {
+		t.Errorf("manifest should record deleted branch and note, got %+v", entries[0].Manifest)
+	}
+	ref := "refs/wt-trash/" + entries[0].ID
+	if pinned, err := runGitCommand(repoPath, "rev-parse", ref); err != nil || pinned != head {
+		t.Errorf("%s = %q (%v), want HEAD %s pinned", ref, pinned, err, head)
+	}
+
+	cmd = newUndoCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("undo command failed: %v", err)
+	}
+
+	restoredHead, err := runGitCommand(wtPath, "rev-parse", "HEAD")
+	if err != nil {
+		t.Fatalf("restored worktree is not a git worktree: %v", err)
+	}
+	if restoredHead != head {
+		t.Errorf("restored HEAD = %s, want %s", restoredHead, head)
+	}
+	if branch, _ := runGitCommand(wtPath, "branch", "--show-current"); strings.TrimSpace(branch) != "feature" {
+		t.Errorf("restored worktree should be on feature, got %q", branch)
+	}
+	if note, _ := runGitCommand(repoPath, "config", "branch.feature.description"); strings.TrimSpace(note) != "login work" {
+		t.Errorf("note should be restored, got %q", note)
+	}
+	if staged, _ := runGitCommand(wtPath, "diff", "--cached", "--name-only"); strings.TrimSpace(staged) != "staged.txt" {
+		t.Errorf("staged file should still be staged, got %q", staged)
+	}
+	if status, _ := runGitCommand(wtPath, "status", "--porcelain"); strings.Contains(status, "feature.txt") {
+		t.Errorf("committed files should be unchanged, got status %q", status)
+	}
+	if entries, _ := trash.List(filepath.Join(tmpDir, ".wt", "trash")); len(entries) != 0 {
+		t.Errorf("restored entry should be removed from the trash, got %d", len(entries))
+	}
+	if _, err := runGitCommand(repoPath, "rev-parse", "--verify", ref); err == nil {
+		t.Errorf("%s should be deleted after the restore", ref)
+	}
+}
+
+// TestTrash_UnpushedCommitsSurviveGC tests that trashed commits outlive the branch.
+//
+// Scenario: With the trash enabled, user prunes a worktree whose branch has an
+// unpushed commit and deletes the branch; git gc prunes unreachable objects,
+// then user runs `wt trash empty`
+// Expected: The commit survives gc and can be restored; emptying the trash
+// deletes the ref pinning it
+func TestTrash_UnpushedCommitsSurviveGC(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := resolvePath(t, t.TempDir())
+
+	repoPath := setupTestRepoWithBranches(t, tmpDir, "test-repo", []string{"feature"})
+	wtPath := createTestWorktree(t, repoPath, "feature")
+	addCommit(t, wtPath, "feature.txt", "unpushed work")
+	head := headCommit(t, wtPath)
+
+	regFile := saveSingleRepoRegistry(t, tmpDir, repoPath)
+	cfg := &config.Config{RegistryPath: regFile, Trash: config.TrashConfig{Enabled: true}}
+	ctx := testContextWithConfig(t, cfg, repoPath)
+
+	cmd := newPruneCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"feature", "-f"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("prune command failed: %v", err)
+	}
+	mustRunGit(t, repoPath, "branch", "-D", "feature")
+
+	mustRunGit(t, repoPath, "reflog", "expire", "--expire=now", "--all")
+	mustRunGit(t, repoPath, "gc", "--prune=now", "--quiet")
+	if _, err := runGitCommand(repoPath, "cat-file", "-e", head+"^{commit}"); err != nil {
+		t.Fatalf("trashed commit %s was garbage collected", head)
+	}
+
+	cmd = newTrashCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"empty"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("trash empty failed: %v", err)
+	}
+	if refs, _ := runGitCommand(repoPath, "for-each-ref", "refs/wt-trash/"); refs != "" {
+		t.Errorf("trash refs should be deleted by trash empty, got %q", refs)
+	}
+}
+
+// TestTrash_PruneDisabled tests that prune deletes worktrees when the trash is off.
+//
+// Scenario: User prunes a worktree without [trash] enabled
+// Expected: Worktree is deleted and nothing is written to the trash
+func TestTrash_PruneDisabled(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := resolvePath(t, t.TempDir())
+
+	repoPath := setupTestRepoWithBranches(t, tmpDir, "test-repo", []string{"feature"})
+	wtPath := createTestWorktree(t, repoPath, "feature")
+
+	regFile := saveSingleRepoRegistry(t, tmpDir, repoPath)
+	cfg := &config.Config{RegistryPath: regFile}
+	ctx := testContextWithConfig(t, cfg, repoPath)
+
+	cmd := newPruneCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"feature", "-f"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("prune command failed: %v", err)
+	}
+
+	if _, err := os.Stat(wtPath); !os.IsNotExist(err) {
+		t.Error("worktree should be removed")
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, ".wt", "trash")); !os.IsNotExist(err) {
+		t.Error("trash directory should not be created when the trash is disabled")
+	}
+}
+
+// TestTrash_RepoRemoveAndRestore tests that a deleted repo can be restored.
+//
+// Scenario: With the trash enabled, user runs `wt repo remove -D -f`, then
+// `wt trash restore <id>`
+// Expected: Repo files and registry entry (with labels) are restored
+func TestTrash_RepoRemoveAndRestore(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := resolvePath(t, t.TempDir())
+	repoPath := setupTestRepo(t, tmpDir, "remove-test")
+
+	regFile := filepath.Join(tmpDir, ".wt", "repos.json")
+	if err := os.MkdirAll(filepath.Dir(regFile), 0755); err != nil {
+		t.Fatalf("failed to create registry directory: %v", err)
+	}
+	reg := &registry.Registry{
+		Repos: []registry.Repo{
+			{Name: "remove-test", Path: repoPath, Labels: []string{"backend"}},
+		},
+	}
+	if err := reg.Save(regFile); err != nil {
+		t.Fatalf("failed to save registry: %v", err)
+	}
+
+	cfg := &config.Config{RegistryPath: regFile, Trash: config.TrashConfig{Enabled: true}}
+	ctx := testContextWithConfig(t, cfg, tmpDir)
+
+	cmd := newRepoRemoveCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"remove-test", "-D", "-f"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("repo remove command failed: %v", err)
+	}
+	if _, err := os.Stat(repoPath); !os.IsNotExist(err) {
+		t.Fatal("repo should be moved to the trash")
+	}
+
+	entries, err := trash.List(filepath.Join(tmpDir, ".wt", "trash"))
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected 1 trash entry, got %d (%v)", len(entries), err)
+	}
+
+	cmd = newTrashCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"restore", entries[0].ID})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("trash restore failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(repoPath, ".git")); err != nil {
+		t.Errorf("repo files should be restored: %v", err)
+	}
+	reg, err = registry.Load(regFile)
+	if err != nil {
+		t.Fatalf("failed to load registry: %v", err)
+	}
+	repo, err := reg.Find("remove-test")
+	if err != nil {
+		t.Fatalf("repo should be re-registered: %v", err)
+	}
+	if !repo.HasLabel("backend") {
+		t.Errorf("labels should be restored, got %v", repo.Labels)
+	}
+}
+
+// TestTrash_EmptyOlderThan tests deleting old trash entries.
+//
+// Scenario: Trash holds an entry from an hour ago and one from two days ago;
+// user runs `wt trash empty --older-than 24h`
+// Expected: Only the old entry is deleted
+func TestTrash_EmptyOlderThan(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := resolvePath(t, t.TempDir())
+	regFile := filepath.Join(tmpDir, ".wt", "repos.json")
+	trashDir := filepath.Join(tmpDir, ".wt", "trash")
+
+	for i, age := range []time.Duration{time.Hour, 48 * time.Hour} {
+		dir := filepath.Join(tmpDir, "wt", string(rune('a'+i)))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := trash.Put(context.Background(), trashDir, trash.Manifest{Kind: trash.KindWorktree, Path: dir, TrashedAt: time.Now().Add(-age)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &config.Config{RegistryPath: regFile}
+	ctx, out := testContextWithConfigAndOutput(t, cfg, tmpDir)
+
+	cmd := newTrashCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"empty", "--older-than", "24h"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("trash empty failed: %v", err)
+	}
+
+	if !strings.Contains(out.String(), "Deleted 1") {
+		t.Errorf("expected 1 deleted entry, got %q", out.String())
+	}
+	entries, _ := trash.List(trashDir)
+	if len(entries) != 1 || time.Since(entries[0].TrashedAt) > 24*time.Hour {
+		t.Errorf("expected only the recent entry to remain, got %+v", entries)
+	}
+}
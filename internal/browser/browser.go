@@ -0,0 +1,28 @@
+// Package browser opens URLs in the user's default browser.
+package browser
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// Open opens url in the default browser: open on macOS, wslview under WSL,
+// xdg-open otherwise.
+func Open(ctx context.Context, url string) error {
+	name := "xdg-open"
+	switch {
+	case runtime.GOOS == "darwin":
+		name = "open"
+	case isWSL():
+		name = "wslview"
+	}
+	return exec.CommandContext(ctx, name, url).Run()
+}
+
+// isWSL reports whether we're running under Windows Subsystem for Linux.
+func isWSL() bool {
+	_, err := os.Stat("/proc/sys/fs/binfmt_misc/WSLInterop")
+	return err == nil
+}
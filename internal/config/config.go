@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"slices"
+	"time"
 
 	"github.com/BurntSushi/toml"
 )
@@ -50,9 +51,23 @@ const LocalConfigFileName = ".wt.toml"
 // Hook defines a post-create hook
 type Hook struct {
 	Command     string   `toml:"command"`
+	Argv        []string `toml:"argv"` // program and arguments to run without a shell (instead of command)
+	Script      string   `toml:"-"`    // executable found in a hook scripts directory (see LoadHookScripts)
 	Description string   `toml:"description"`
-	On          []string `toml:"on"`      // commands this hook runs on (empty = only via --hook)
-	Enabled     *bool    `toml:"enabled"` // nil = true (default); false disables a global hook locally
+	On          []string `toml:"on"`         // commands this hook runs on (empty = only via --hook)
+	Enabled     *bool    `toml:"enabled"`    // nil = true (default); false disables a global hook locally
+	After       []string `toml:"after"`      // hooks that must finish first when they run too
+	Parallel    bool     `toml:"parallel"`   // may run concurrently with other parallel hooks
+	Timeout     string   `toml:"timeout"`    // max run time of each attempt, e.g. "2m" (empty = no limit)
+	Retries     int      `toml:"retries"`    // attempts after a failed first one
+	OnFailure   string   `toml:"on_failure"` // "warn", "abort" or "ignore" (empty = abort before, warn after)
+}
+
+// TimeoutDuration returns the parsed timeout (0 = no limit).
+// Values are validated in Load, so parse errors are treated as 0.
+func (h *Hook) TimeoutDuration() time.Duration {
+	d, _ := parseDuration(h.Timeout)
+	return d
 }
 
 // IsEnabled returns whether the hook is enabled (defaults to true when Enabled is nil)
@@ -71,15 +86,18 @@ type HooksConfig struct {
 // ForgeRule maps a pattern to forge settings
 type ForgeRule struct {
 	Pattern string `toml:"pattern"` // glob pattern like "n26/*" or "company/*"
-	Type    string `toml:"type"`    // "github" or "gitlab"
+	Type    string `toml:"type"`    // "github", "gitlab", "gitea" or "bitbucket"
 	User    string `toml:"user"`    // optional: gh/glab username for auth
+	Host    string `toml:"host"`    // optional: forge host for cloning (required for gitea and Bitbucket Data Center)
 }
 
 // ForgeConfig holds forge-related configuration
 type ForgeConfig struct {
-	Default    string      `toml:"default"`     // default forge type
-	DefaultOrg string      `toml:"default_org"` // default org for clone
-	Rules      []ForgeRule `toml:"rules"`
+	Default    string            `toml:"default"`     // default forge type
+	DefaultOrg string            `toml:"default_org"` // default org for clone
+	Backend    string            `toml:"backend"`     // "cli" (gh/glab, default) or "api" (direct HTTP)
+	Tokens     map[string]string `toml:"tokens"`      // host -> API token for backend = "api"
+	Rules      []ForgeRule       `toml:"rules"`
 }
 
 // MergeConfig holds merge-related configuration
@@ -87,12 +105,42 @@ type MergeConfig struct {
 	Strategy string `toml:"strategy"` // "squash", "rebase", or "merge"
 }
 
+// PRConfig holds settings for "wt pr create"
+type PRConfig struct {
+	TitleFormat string `toml:"title_format"` // template for generated PR titles, e.g. "{note}: {title}" (default: "{title}")
+}
+
+// PRTitlePlaceholders are the placeholders allowed in pr.title_format.
+var PRTitlePlaceholders = []string{"title", "branch", "note", "repo"}
+
 // PruneConfig holds prune-related configuration
 type PruneConfig struct {
 	DeleteLocalBranches bool `toml:"delete_local_branches"`
 	StaleDays           int  `toml:"stale_days"` // days after which worktrees are highlighted as stale and eligible for --stale pruning (0 = disabled)
 }
 
+// PRCacheConfig holds PR cache freshness settings
+type PRCacheConfig struct {
+	TTL       string `toml:"ttl"`        // max age of open PR entries (and branches without a PR) before auto-refresh, e.g. "15m" (empty = never)
+	ClosedTTL string `toml:"closed_ttl"` // max age of closed PR entries (empty = same as ttl)
+}
+
+// Durations returns the parsed open and closed TTLs (0 = never expires).
+// Values are validated in Load, so parse errors are treated as 0.
+func (c *PRCacheConfig) Durations() (open, closed time.Duration) {
+	open, _ = parseDuration(c.TTL)
+	closed = open
+	if c.ClosedTTL != "" {
+		closed, _ = parseDuration(c.ClosedTTL)
+	}
+	return open, closed
+}
+
+// TrashConfig holds settings for recoverable deletes
+type TrashConfig struct {
+	Enabled bool `toml:"enabled"` // move pruned worktrees and deleted repos to ~/.wt/trash instead of deleting them
+}
+
 // PreserveConfig holds file preservation settings for worktree creation.
 // Listed paths are symlinked from the repo root into new worktrees.
 type PreserveConfig struct {
@@ -152,6 +200,7 @@ type ThemeConfig struct {
 type Config struct {
 	RegistryPath  string            `toml:"-"`              // Override ~/.wt/repos.json path (for testing)
 	HistoryPath   string            `toml:"-"`              // Override ~/.wt/history.json path (for testing)
+	JournalPath   string            `toml:"-"`              // Override ~/.wt/journal.jsonl path (for testing)
 	DefaultSort   string            `toml:"default_sort"`   // "date", "repo", "branch" (default: "date")
 	DefaultLabels []string          `toml:"default_labels"` // labels for newly registered repos
 	Hooks         HooksConfig       `toml:"-"`              // custom parsing needed
@@ -159,7 +208,10 @@ type Config struct {
 	Checkout      CheckoutConfig    `toml:"checkout"`       // checkout settings
 	Forge         ForgeConfig       `toml:"forge"`
 	Merge         MergeConfig       `toml:"merge"`
+	PR            PRConfig          `toml:"pr"` // PR creation settings
 	Prune         PruneConfig       `toml:"prune"`
+	PRCache       PRCacheConfig     `toml:"pr_cache"` // PR cache TTLs for auto-refresh
+	Trash         TrashConfig       `toml:"trash"`    // recoverable deletes for prune and repo remove
 	Preserve      PreserveConfig    `toml:"preserve"` // file preservation for new worktrees
 	Hosts         map[string]string `toml:"hosts"`    // domain -> forge type mapping
 	Theme         ThemeConfig       `toml:"theme"`    // UI theme/colors for interactive mode
@@ -194,6 +246,37 @@ func (c *Config) GetHistoryPath() (string, error) {
 	return filepath.Join(home, ".wt", "history.json"), nil
 }
 
+// GetJournalPath returns the effective operation journal path.
+// Returns JournalPath if set (for testing), otherwise journal.jsonl in GetWtDir.
+func (c *Config) GetJournalPath() (string, error) {
+	if c.JournalPath != "" {
+		return c.JournalPath, nil
+	}
+	dir, err := c.GetWtDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "journal.jsonl"), nil
+}
+
+// GetPRCachePath returns the effective PR cache file path (prs.json in GetWtDir).
+func (c *Config) GetPRCachePath() (string, error) {
+	dir, err := c.GetWtDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "prs.json"), nil
+}
+
+// GetTrashDir returns the effective trash directory (trash/ in GetWtDir).
+func (c *Config) GetTrashDir() (string, error) {
+	dir, err := c.GetWtDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "trash"), nil
+}
+
 // ShouldSetUpstream returns true if upstream tracking should be set (default: false)
 func (c *CheckoutConfig) ShouldSetUpstream() bool {
 	if c.SetUpstream == nil {
@@ -213,6 +296,7 @@ func Default() Config {
 		},
 		Forge: ForgeConfig{
 			Default: "github",
+			Backend: "cli",
 		},
 		Prune: PruneConfig{
 			StaleDays: 14,
@@ -238,10 +322,13 @@ type rawConfig struct {
 	Checkout      CheckoutConfig `toml:"checkout"`
 	Forge         ForgeConfig    `toml:"forge"`
 	Merge         MergeConfig    `toml:"merge"`
+	PR            PRConfig       `toml:"pr"`
 	Prune         struct {
 		DeleteLocalBranches bool `toml:"delete_local_branches"`
 		StaleDays           *int `toml:"stale_days"`
 	} `toml:"prune"`
+	PRCache  PRCacheConfig     `toml:"pr_cache"`
+	Trash    TrashConfig       `toml:"trash"`
 	Preserve PreserveConfig    `toml:"preserve"`
 	Hosts    map[string]string `toml:"hosts"`
 	Theme    ThemeConfig       `toml:"theme"`
@@ -263,7 +350,12 @@ func Load() (Config, error) {
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
 			cfg := Default()
-			// Apply env vars even when no config file exists
+			// Hook scripts and env vars apply even when no config file exists
+			scripts, err := LoadHookScripts(filepath.Join(filepath.Dir(path), HookScriptsDir))
+			if err != nil {
+				return Default(), err
+			}
+			cfg.Hooks.Hooks = mergeHookScripts(cfg.Hooks.Hooks, scripts)
 			if err := applyEnvOverrides(&cfg); err != nil {
 				return Default(), err
 			}
@@ -285,9 +377,12 @@ func Load() (Config, error) {
 		Checkout:      raw.Checkout,
 		Forge:         raw.Forge,
 		Merge:         raw.Merge,
+		PR:            raw.PR,
 		Prune: PruneConfig{
 			DeleteLocalBranches: raw.Prune.DeleteLocalBranches,
 		},
+		PRCache:  raw.PRCache,
+		Trash:    raw.Trash,
 		Preserve: raw.Preserve,
 		Hosts:    raw.Hosts,
 		Theme:    raw.Theme,
@@ -297,6 +392,9 @@ func Load() (Config, error) {
 	if err := validateEnum(cfg.Forge.Default, "forge.default", ValidForgeTypes); err != nil {
 		return Default(), err
 	}
+	if err := validateEnum(cfg.Forge.Backend, "forge.backend", ValidForgeBackends); err != nil {
+		return Default(), err
+	}
 	for i, rule := range cfg.Forge.Rules {
 		if err := validateEnum(rule.Type, fmt.Sprintf("forge.rules[%d].type", i), ValidForgeTypes); err != nil {
 			return Default(), err
@@ -313,18 +411,39 @@ func Load() (Config, error) {
 	if err := validateEnum(cfg.Checkout.BaseRef, "checkout.base_ref", ValidBaseRefs); err != nil {
 		return Default(), err
 	}
+	if err := validatePlaceholders(cfg.PR.TitleFormat, "pr.title_format", PRTitlePlaceholders); err != nil {
+		return Default(), err
+	}
 	if err := validateEnum(cfg.Clone.Mode, "clone.mode", ValidCloneModes); err != nil {
 		return Default(), err
 	}
 	if err := validateEnum(cfg.DefaultSort, "default_sort", ValidDefaultSortModes); err != nil {
 		return Default(), err
 	}
+	if err := validateDuration(cfg.PRCache.TTL, "pr_cache.ttl"); err != nil {
+		return Default(), err
+	}
+	if err := validateDuration(cfg.PRCache.ClosedTTL, "pr_cache.closed_ttl"); err != nil {
+		return Default(), err
+	}
 	if err := validatePreservePaths(cfg.Preserve.Paths, ""); err != nil {
 		return Default(), err
 	}
 	if err := ValidateHookTriggers(cfg.Hooks.Hooks); err != nil {
 		return Default(), err
 	}
+	if err := ValidateHookDependencies(cfg.Hooks.Hooks); err != nil {
+		return Default(), err
+	}
+	if err := ValidateHookOptions(cfg.Hooks.Hooks); err != nil {
+		return Default(), err
+	}
+
+	scripts, err := LoadHookScripts(filepath.Join(filepath.Dir(path), HookScriptsDir))
+	if err != nil {
+		return Default(), err
+	}
+	cfg.Hooks.Hooks = mergeHookScripts(cfg.Hooks.Hooks, scripts)
 
 	// Note: theme.name is validated at runtime with a warning, not an error
 
@@ -335,6 +454,9 @@ func Load() (Config, error) {
 	if cfg.Forge.Default == "" {
 		cfg.Forge.Default = "github"
 	}
+	if cfg.Forge.Backend == "" {
+		cfg.Forge.Backend = "cli"
+	}
 	if cfg.Clone.Mode == "" {
 		cfg.Clone.Mode = "regular"
 	}
@@ -385,6 +507,13 @@ func parseHooksConfig(raw map[string]any) HooksConfig {
 			if cmd, ok := hookMap["command"].(string); ok {
 				hook.Command = cmd
 			}
+			if argv, ok := hookMap["argv"].([]any); ok {
+				for _, v := range argv {
+					if s, ok := v.(string); ok {
+						hook.Argv = append(hook.Argv, s)
+					}
+				}
+			}
 			if desc, ok := hookMap["description"].(string); ok {
 				hook.Description = desc
 			}
@@ -398,6 +527,25 @@ func parseHooksConfig(raw map[string]any) HooksConfig {
 			if enabled, ok := hookMap["enabled"].(bool); ok {
 				hook.Enabled = &enabled
 			}
+			if after, ok := hookMap["after"].([]any); ok {
+				for _, v := range after {
+					if s, ok := v.(string); ok {
+						hook.After = append(hook.After, s)
+					}
+				}
+			}
+			if parallel, ok := hookMap["parallel"].(bool); ok {
+				hook.Parallel = parallel
+			}
+			if timeout, ok := hookMap["timeout"].(string); ok {
+				hook.Timeout = timeout
+			}
+			if retries, ok := hookMap["retries"].(int64); ok {
+				hook.Retries = int(retries)
+			}
+			if onFailure, ok := hookMap["on_failure"].(string); ok {
+				hook.OnFailure = onFailure
+			}
 			hc.Hooks[key] = hook
 		}
 	}
@@ -416,6 +564,17 @@ func (c *ForgeConfig) GetForgeTypeForRepo(repoSpec string) string {
 	return c.Default
 }
 
+// GetHostForRepo returns the forge host for a repo spec
+// Matches against rules in order, returns empty string if no match (public instance)
+func (c *ForgeConfig) GetHostForRepo(repoSpec string) string {
+	for _, rule := range c.Rules {
+		if matchPattern(rule.Pattern, repoSpec) {
+			return rule.Host
+		}
+	}
+	return ""
+}
+
 // GetUserForRepo returns the gh/glab username for a repo spec
 // Matches against rules in order, returns empty string if no match (use active account)
 func (c *ForgeConfig) GetUserForRepo(repoSpec string) string {
@@ -514,8 +673,10 @@ worktree_format = ".worktrees/{branch}"
 # Hooks without "on" only run when explicitly called with --hook=name or wt hook.
 #
 # Trigger syntax: [before:|after:]trigger[:subtype]
-#   Triggers: checkout, prune, merge, all
-#   Subtypes (checkout only): create, open, pr
+#   Triggers: checkout, prune, pr, repo, note, label, cd, all
+#   Subtypes: checkout (create, open, pr), pr (create, merge), repo (add, clone),
+#             note (set, clear), label (add, remove, clear)
+#   "merge" is an alias of "pr:merge"; repo hooks only come from this file
 #   Timing: before (can cancel operation), after (default)
 #
 # Examples:
@@ -523,27 +684,56 @@ worktree_format = ".worktrees/{branch}"
 #   on = ["checkout:pr"]           - PR checkouts only
 #   on = ["before:prune"]          - pre-prune guard (can abort deletion)
 #   on = ["before:checkout:pr"]    - before PR checkout only
+#   on = ["before:pr:merge"]       - checks before merging a PR (can abort)
+#   on = ["repo:clone"]            - after wt repo clone
 #   on = ["all"]                   - all triggers (after)
 #
 # Before-hooks: non-zero exit aborts the operation.
 # After-hooks: failures are logged as warnings.
+#   on_failure = "warn"            - override: "abort", "warn" or "ignore"
+#   timeout = "2m"                 - kill the hook (and its child processes) after 2m
+#   retries = 2                    - run a failed hook up to 2 more times
+#
+# Hooks run in alphabetical order unless ordered with "after":
+#   after = ["install"]           - run once the "install" hook finished (if it runs)
+#   parallel = true               - run concurrently with other parallel hooks
+#                                   (output is prefixed with the hook name, no stdin)
 #
 # Hooks run with working directory set to the worktree path.
 # For "prune" after-hooks, working directory is the main repo (worktree is deleted).
 # For "prune" before-hooks, working directory is the worktree (still exists).
+# For "repo", "note" and "label" hooks, it is the repo root.
 #
 # Available placeholders:
 #   {worktree-dir}      - absolute worktree path
 #   {repo-dir}          - absolute main repo path
 #   {branch}            - branch name
 #   {repo}              - registered repo name
-#   {trigger}           - command trigger (checkout, prune, merge, run)
-#   {action}            - checkout subtype (create, open, pr, manual)
+#   {trigger}           - command trigger (checkout, prune, pr, repo, note, label, cd, run)
+#   {action}            - trigger subtype (create, open, pr, merge, clone, ..., manual)
 #   {phase}             - hook timing (before, after)
 #   {config-dir}        - absolute path to ~/.wt/ config directory
+#   {pr-number}         - PR number ({pr-repo}, {pr-url}: forge repo path, PR URL)
+#   {note}, {label}     - new branch note, label added or removed
 #   {key}               - custom variable passed via --arg key=value
 #   {key:-def}          - custom variable with default
 #   {key:+text}         - conditional: includes text only if key is set
+#   {branch:q}          - any placeholder with :q is shell-quoted ('it'\''s')
+# Placeholders without a value for a hook's triggers (e.g. {branch} for
+# "repo:clone") are rejected.
+#
+# Placeholder values are pasted into the command as-is. Quote them with :q,
+# or read them from the environment instead: every placeholder is exported
+# as WT_<NAME> (WT_WORKTREE_DIR, WT_BRANCH, WT_PR_NUMBER, ...) and every
+# --arg as WT_ARG_<KEY>. To run a program without a shell, use argv:
+#   argv = ["claude", "-p", "{prompt}"]   - each element is one argument
+#
+# Hooks can also be executable scripts in ~/.wt/hooks/<trigger>.d/ (or
+# <repo>/.wt/hooks/<trigger>.d/), e.g. ~/.wt/hooks/checkout.d/10-setup.sh.
+# They are named "<trigger>/<file>" and get the hook context as JSON on stdin.
+# Configure or disable one with a hooks entry of that name:
+#   [hooks."checkout/10-setup.sh"]
+#   enabled = false
 #
 # === Editor Examples ===
 #
@@ -610,7 +800,7 @@ worktree_format = ".worktrees/{branch}"
 # Used for PR operations and "wt pr checkout <number> org/repo" when cloning
 #
 # [forge]
-# default = "github"     # default forge type (github or gitlab)
+# default = "github"     # default forge type (github, gitlab, gitea or bitbucket)
 # default_org = "my-org" # default org when repo specified without org/ prefix
 #
 # [[forge.rules]]
@@ -628,21 +818,78 @@ worktree_format = ".worktrees/{branch}"
 # type = "gitlab"
 # # user omitted - uses default active glab account
 #
+# [[forge.rules]]
+# pattern = "infra/*"
+# type = "gitea"
+# host = "git.corp"           # forge host for cloning (required for gitea)
+#
+# [[forge.rules]]
+# pattern = "LEGACY/*"
+# type = "bitbucket"
+# host = "bitbucket.corp"     # Bitbucket Data Center host (omit for bitbucket.org)
+#
 # Rules are matched in order; first match wins.
 # The "user" field enables multi-account support for gh CLI.
 # Use "gh auth status" to see available accounts.
-# Supported forges: "github" (gh CLI), "gitlab" (glab CLI)
+# Supported forges: "github" (gh CLI), "gitlab" (glab CLI),
+# "gitea" (Gitea/Forgejo HTTP API) and "bitbucket" (Bitbucket Cloud and
+# Data Center HTTP APIs); the last two are always used regardless of backend
+#
+# Backend - how wt talks to the forge:
+#   "cli" (default) - shell out to gh/glab
+#   "api"           - call the GitHub/GitLab HTTP APIs directly (gh/glab not required)
+#
+# [forge]
+# backend = "api"
+#
+# With backend = "api", the token for a host is taken from (first match wins):
+#   1. [forge.tokens] below
+#   2. Environment: GH_TOKEN/GITHUB_TOKEN (github.com), GH_ENTERPRISE_TOKEN/
+#      GITHUB_ENTERPRISE_TOKEN (GitHub Enterprise), GITLAB_TOKEN/GITLAB_ACCESS_TOKEN (GitLab),
+#      GITEA_TOKEN/FORGEJO_TOKEN (Gitea/Forgejo), BITBUCKET_TOKEN (Bitbucket;
+#      "user:secret" tokens such as Cloud API tokens use Basic auth)
+#   3. Credentials stored by the CLI ("gh auth token", "glab config get token"), if installed
+#
+# [forge.tokens]
+# "github.com" = "ghp_..."
+# "gitlab.internal.corp" = "glpat-..."
+# "git.corp" = "..."
+# "bitbucket.org" = "user@example.com:ATATT..."
 
 # Merge settings for "wt pr merge"
 # [merge]
 # strategy = "squash"  # squash, rebase, or merge (default: squash)
 #                      # Note: rebase is not supported on GitLab
 
+# PR settings for "wt pr create"
+# Without --title, the title and body are prefilled from the branch's commits
+# (or the repo's PR template) and opened in $EDITOR.
+# title_format placeholders: {title} (generated title), {branch}, {note}, {repo}
+# [pr]
+# title_format = "{note}: {title}"  # e.g. ticket ID from the branch note (default: "{title}")
+
 # Prune settings for "wt prune" and stale worktree highlighting
 # [prune]
 # delete_local_branches = false  # Delete local branches after worktree removal
 # stale_days = 14                # Days before a worktree is highlighted as stale and eligible for --stale pruning (0 = disabled, default: 14)
 
+# PR cache settings - automatic refresh of cached PR status
+# "wt list", "wt prune" and "wt cd -i" refresh entries older than the TTL
+# (Go duration syntax, e.g. "15m", "2h"). Merged PRs are never refreshed.
+# Without a ttl, PR status is only updated with --refresh-pr (-R).
+#
+# [pr_cache]
+# ttl = "15m"          # open PRs and branches without a PR
+# closed_ttl = "24h"   # closed PRs (default: same as ttl)
+
+# Trash settings - make "wt prune" and "wt repo remove -D" undoable
+# Removed worktrees and repos are moved to ~/.wt/trash/<timestamp>/ instead of
+# being deleted. Restore with "wt undo" or "wt trash restore <id>", free space
+# with "wt trash empty --older-than 168h".
+#
+# [trash]
+# enabled = true       # default: false
+
 # Host mappings - for self-hosted GitHub Enterprise or GitLab instances
 # Maps custom domains to forge type for automatic detection
 #
@@ -650,6 +897,8 @@ worktree_format = ".worktrees/{branch}"
 # "github.mycompany.com" = "github"   # GitHub Enterprise
 # "gitlab.internal.corp" = "gitlab"   # Self-hosted GitLab
 # "code.company.com" = "gitlab"       # Another GitLab instance
+# "git.corp" = "gitea"                # Self-hosted Gitea/Forgejo
+# "bitbucket.corp" = "bitbucket"      # Bitbucket Data Center
 #
 # Note: You must also authenticate with the respective CLI:
 #   gh auth login --hostname github.mycompany.com
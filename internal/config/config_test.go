@@ -4,8 +4,10 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/BurntSushi/toml"
 )
@@ -495,6 +497,50 @@ func TestGetHistoryPath(t *testing.T) {
 	})
 }
 
+func TestGetPRCachePath(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{RegistryPath: "/custom/wt/repos.json"}
+	got, err := cfg.GetPRCachePath()
+	if err != nil {
+		t.Fatalf("GetPRCachePath returned error: %v", err)
+	}
+	if want := filepath.Join("/custom/wt", "prs.json"); got != want {
+		t.Errorf("GetPRCachePath = %q, want %q", got, want)
+	}
+}
+
+func TestGetTrashDir(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{RegistryPath: "/custom/wt/repos.json"}
+	got, err := cfg.GetTrashDir()
+	if err != nil {
+		t.Fatalf("GetTrashDir returned error: %v", err)
+	}
+	if want := filepath.Join("/custom/wt", "trash"); got != want {
+		t.Errorf("GetTrashDir = %q, want %q", got, want)
+	}
+}
+
+func TestGetJournalPath(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{RegistryPath: "/custom/wt/repos.json"}
+	got, err := cfg.GetJournalPath()
+	if err != nil {
+		t.Fatalf("GetJournalPath returned error: %v", err)
+	}
+	if want := filepath.Join("/custom/wt", "journal.jsonl"); got != want {
+		t.Errorf("GetJournalPath = %q, want %q", got, want)
+	}
+
+	cfg.JournalPath = "/tmp/journal.jsonl"
+	if got, _ := cfg.GetJournalPath(); got != "/tmp/journal.jsonl" {
+		t.Errorf("GetJournalPath with override = %q", got)
+	}
+}
+
 func TestShouldSetUpstream(t *testing.T) {
 	t.Parallel()
 
@@ -823,6 +869,120 @@ func TestCloneConfigValidation(t *testing.T) {
 	}
 }
 
+func TestForgeBackendParsing(t *testing.T) {
+	t.Parallel()
+
+	var raw rawConfig
+	if _, err := toml.Decode(`[forge]
+backend = "api"
+
+[forge.tokens]
+"github.corp" = "secret"`, &raw); err != nil {
+		t.Fatalf("failed to parse TOML: %v", err)
+	}
+	if raw.Forge.Backend != "api" {
+		t.Errorf("Forge.Backend = %q, want %q", raw.Forge.Backend, "api")
+	}
+	if raw.Forge.Tokens["github.corp"] != "secret" {
+		t.Errorf("Forge.Tokens = %v, want github.corp token", raw.Forge.Tokens)
+	}
+
+	if err := validateEnum("graphql", "forge.backend", ValidForgeBackends); err == nil {
+		t.Error("validateEnum(graphql) = nil, want error")
+	}
+	if got := Default().Forge.Backend; got != "cli" {
+		t.Errorf("default Forge.Backend = %q, want %q", got, "cli")
+	}
+}
+
+func TestPRCacheConfigDurations(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		toml       string
+		wantOpen   time.Duration
+		wantClosed time.Duration
+	}{
+		{"not set", `worktree_format = "x"`, 0, 0},
+		{"ttl only", `[pr_cache]
+ttl = "15m"`, 15 * time.Minute, 15 * time.Minute},
+		{"separate closed ttl", `[pr_cache]
+ttl = "15m"
+closed_ttl = "24h"`, 15 * time.Minute, 24 * time.Hour},
+		{"closed only", `[pr_cache]
+closed_ttl = "1h"`, 0, time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var raw rawConfig
+			if _, err := toml.Decode(tt.toml, &raw); err != nil {
+				t.Fatalf("failed to parse TOML: %v", err)
+			}
+			open, closed := raw.PRCache.Durations()
+			if open != tt.wantOpen || closed != tt.wantClosed {
+				t.Errorf("Durations() = (%v, %v), want (%v, %v)", open, closed, tt.wantOpen, tt.wantClosed)
+			}
+		})
+	}
+}
+
+func TestValidateDuration(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value   string
+		wantErr bool
+	}{
+		{"", false},
+		{"15m", false},
+		{"1h30m", false},
+		{"0", false},
+		{"15", true},
+		{"soon", true},
+		{"-5m", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+			err := validateDuration(tt.value, "pr_cache.ttl")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateDuration(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidatePlaceholders(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value   string
+		wantErr bool
+	}{
+		{"", false},
+		{"{title}", false},
+		{"[{note}] {title} ({branch}, {repo})", false},
+		{"no placeholders", false},
+		{"unclosed {title", false},
+		{"{ticket}: {title}", true},
+		{"{Title}", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+			err := validatePlaceholders(tt.value, "pr.title_format", PRTitlePlaceholders)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validatePlaceholders(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestValidateCloneMode(t *testing.T) {
 	t.Parallel()
 
@@ -1127,10 +1287,42 @@ func TestValidateHookTriggers(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name:    "invalid cd trigger",
-			hooks:   map[string]Hook{"h": {Command: "echo", On: []string{"cd"}}},
+			name:    "valid cd",
+			hooks:   map[string]Hook{"h": {Command: "echo {worktree-dir}", On: []string{"cd"}}},
+			wantErr: false,
+		},
+		{
+			name:    "valid repo:clone",
+			hooks:   map[string]Hook{"h": {Command: "echo {repo-dir:q}", On: []string{"after:repo:clone"}}},
+			wantErr: false,
+		},
+		{
+			name:    "pr-number available for checkout",
+			hooks:   map[string]Hook{"h": {Command: "echo {pr-number}", On: []string{"checkout"}}},
+			wantErr: false,
+		},
+		{
+			name:    "arg placeholder not validated",
+			hooks:   map[string]Hook{"h": {Command: "echo {prompt:q} {env:-dev}", On: []string{"repo"}}},
+			wantErr: false,
+		},
+		{
+			name:    "pr-number unavailable for checkout:create",
+			hooks:   map[string]Hook{"h": {Command: "echo {pr-number}", On: []string{"checkout:create"}}},
+			wantErr: true,
+			errMsg:  `placeholder {pr-number} is not available for trigger "checkout:create"`,
+		},
+		{
+			name:    "branch unavailable for repo:clone",
+			hooks:   map[string]Hook{"h": {Command: "echo {branch}", On: []string{"checkout", "repo:clone"}}},
+			wantErr: true,
+			errMsg:  `placeholder {branch} is not available for trigger "repo:clone"`,
+		},
+		{
+			name:    "quoted placeholder in argv",
+			hooks:   map[string]Hook{"h": {Argv: []string{"code", "{worktree-dir:q}"}, On: []string{"label"}}},
 			wantErr: true,
-			errMsg:  "no longer a valid trigger",
+			errMsg:  "placeholder {worktree-dir} is not available",
 		},
 		{
 			name:    "invalid subtype",
@@ -1165,3 +1357,171 @@ func TestValidateHookTriggers(t *testing.T) {
 		})
 	}
 }
+
+func TestParseHooksConfig_AfterAndParallel(t *testing.T) {
+	t.Parallel()
+
+	raw := map[string]any{
+		"build": map[string]any{
+			"command":  "make",
+			"after":    []any{"install", "generate"},
+			"parallel": true,
+		},
+		"install": map[string]any{
+			"command": "npm install",
+		},
+	}
+
+	result := parseHooksConfig(raw)
+
+	build := result.Hooks["build"]
+	if !reflect.DeepEqual(build.After, []string{"install", "generate"}) {
+		t.Errorf("build.After = %v, want [install generate]", build.After)
+	}
+	if !build.Parallel {
+		t.Error("build.Parallel = false, want true")
+	}
+	if install := result.Hooks["install"]; install.After != nil || install.Parallel {
+		t.Errorf("install = %+v, want no after and not parallel", install)
+	}
+}
+
+func TestValidateHookDependencies(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		hooks  map[string]Hook
+		errMsg string // empty = valid
+	}{
+		{
+			name: "chain",
+			hooks: map[string]Hook{
+				"install": {Command: "npm install"},
+				"build":   {Command: "make", After: []string{"install"}},
+				"editor":  {Command: "code .", After: []string{"build", "install"}},
+			},
+		},
+		{
+			name:  "unknown hook is ignored",
+			hooks: map[string]Hook{"build": {Command: "make", After: []string{"global-install"}}},
+		},
+		{
+			name:   "self reference",
+			hooks:  map[string]Hook{"build": {Command: "make", After: []string{"build"}}},
+			errMsg: `hook "build" can't run after itself`,
+		},
+		{
+			name: "cycle",
+			hooks: map[string]Hook{
+				"a": {Command: "echo a", After: []string{"c"}},
+				"b": {Command: "echo b", After: []string{"a"}},
+				"c": {Command: "echo c", After: []string{"b"}},
+				"d": {Command: "echo d", After: []string{"a"}},
+			},
+			errMsg: "hook dependency cycle: a -> c -> b -> a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := ValidateHookDependencies(tt.hooks)
+			if tt.errMsg == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.errMsg {
+				t.Errorf("error = %v, want %q", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestParseHooksConfig_FailureOptions(t *testing.T) {
+	t.Parallel()
+
+	raw := map[string]any{
+		"install": map[string]any{
+			"command":    "npm install",
+			"timeout":    "2m",
+			"retries":    int64(2),
+			"on_failure": "ignore",
+		},
+	}
+
+	hook := parseHooksConfig(raw).Hooks["install"]
+	if hook.Timeout != "2m" || hook.Retries != 2 || hook.OnFailure != "ignore" {
+		t.Errorf("hook = %+v, want timeout 2m, retries 2, on_failure ignore", hook)
+	}
+	if got := hook.TimeoutDuration(); got != 2*time.Minute {
+		t.Errorf("TimeoutDuration() = %v, want 2m", got)
+	}
+}
+
+func TestValidateHookOptions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		hook   Hook
+		errMsg string // empty = valid
+	}{
+		{name: "defaults", hook: Hook{Command: "make"}},
+		{name: "all set", hook: Hook{Command: "make", Timeout: "90s", Retries: 1, OnFailure: "abort"}},
+		{name: "argv", hook: Hook{Argv: []string{"make", "{branch}"}}},
+		{
+			name:   "invalid timeout",
+			hook:   Hook{Command: "make", Timeout: "soon"},
+			errMsg: `hook "build": invalid timeout "soon": must be a duration like "15m" or "2h"`,
+		},
+		{
+			name:   "negative retries",
+			hook:   Hook{Command: "make", Retries: -1},
+			errMsg: `hook "build": invalid retries -1: must not be negative`,
+		},
+		{
+			name:   "command and argv",
+			hook:   Hook{Command: "make", Argv: []string{"make"}},
+			errMsg: `hook "build": command and argv are mutually exclusive`,
+		},
+		{
+			name:   "invalid on_failure",
+			hook:   Hook{Command: "make", OnFailure: "retry"},
+			errMsg: `hook "build": invalid on_failure "retry": must be`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := ValidateHookOptions(map[string]Hook{"build": tt.hook})
+			if tt.errMsg == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.HasPrefix(err.Error(), tt.errMsg) {
+				t.Errorf("error = %v, want prefix %q", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestParseHooksConfig_Argv(t *testing.T) {
+	t.Parallel()
+
+	raw := map[string]any{
+		"claude": map[string]any{
+			"argv": []any{"claude", "-p", "{prompt}"},
+		},
+	}
+
+	hook := parseHooksConfig(raw).Hooks["claude"]
+	if !reflect.DeepEqual(hook.Argv, []string{"claude", "-p", "{prompt}"}) || hook.Command != "" {
+		t.Errorf("hook = %+v, want argv [claude -p {prompt}] and no command", hook)
+	}
+}
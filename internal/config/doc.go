@@ -25,7 +25,7 @@
 //	description = "Open VS Code"
 //	on = ["checkout"]  # auto-run for checkout command
 //
-// Hooks with "on" run automatically for matching commands (checkout, pr, prune, merge, all).
+// Hooks with "on" run automatically for matching commands (checkout, prune, pr, repo, note, label, cd, all).
 // Hooks without "on" only run via explicit --hook=name flag.
 //
 // # Forge Configuration
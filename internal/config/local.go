@@ -16,6 +16,7 @@ type LocalConfig struct {
 	Clone    LocalClone     `toml:"clone"`
 	Checkout LocalCheckout  `toml:"checkout"`
 	Merge    LocalMerge     `toml:"merge"`
+	PR       LocalPR        `toml:"pr"`
 	Prune    LocalPrune     `toml:"prune"`
 	Preserve PreserveConfig `toml:"preserve"` // appended to global
 	Forge    LocalForge     `toml:"forge"`
@@ -34,6 +35,11 @@ type LocalMerge struct {
 	Strategy string `toml:"strategy"`
 }
 
+// LocalPR holds local PR overrides
+type LocalPR struct {
+	TitleFormat string `toml:"title_format"`
+}
+
 // LocalPrune holds local prune overrides
 type LocalPrune struct {
 	DeleteLocalBranches *bool `toml:"delete_local_branches"`
@@ -55,27 +61,34 @@ type rawLocalConfig struct {
 	Clone    LocalClone     `toml:"clone"`
 	Checkout LocalCheckout  `toml:"checkout"`
 	Merge    LocalMerge     `toml:"merge"`
+	PR       LocalPR        `toml:"pr"`
 	Prune    LocalPrune     `toml:"prune"`
 	Preserve PreserveConfig `toml:"preserve"`
 	Forge    LocalForge     `toml:"forge"`
 }
 
-// LoadLocal reads a per-repo .wt.toml config from the given repo path.
-// Returns nil (no error) if the file doesn't exist.
+// LoadLocal reads a per-repo .wt.toml config from the given repo path,
+// including the hook scripts in <repo>/.wt/hooks (see LoadHookScripts).
+// Returns nil (no error) if neither exists.
 // Returns an error only on parse or validation failure.
 func LoadLocal(repoPath string) (*LocalConfig, error) {
 	configFile := filepath.Join(repoPath, LocalConfigFileName)
 
-	data, err := os.ReadFile(configFile)
+	scripts, err := LoadHookScripts(filepath.Join(repoPath, LocalHookScriptsDir))
 	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return nil, nil
-		}
-		return nil, fmt.Errorf("failed to read local config %s: %w", configFile, err)
+		return nil, err
 	}
 
 	var raw rawLocalConfig
-	if err := toml.Unmarshal(data, &raw); err != nil {
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read local config %s: %w", configFile, err)
+		}
+		if scripts == nil {
+			return nil, nil
+		}
+	} else if err := toml.Unmarshal(data, &raw); err != nil {
 		return nil, fmt.Errorf("failed to parse local config %s: %w", configFile, err)
 	}
 
@@ -84,6 +97,7 @@ func LoadLocal(repoPath string) (*LocalConfig, error) {
 		Clone:    raw.Clone,
 		Checkout: raw.Checkout,
 		Merge:    raw.Merge,
+		PR:       raw.PR,
 		Prune:    raw.Prune,
 		Preserve: raw.Preserve,
 		Forge:    raw.Forge,
@@ -101,12 +115,22 @@ func LoadLocal(repoPath string) (*LocalConfig, error) {
 	if err := validateEnum(local.Checkout.BaseRef, "checkout.base_ref", ValidBaseRefs); err != nil {
 		return nil, fmt.Errorf("%w in %s", err, configFile)
 	}
+	if err := validatePlaceholders(local.PR.TitleFormat, "pr.title_format", PRTitlePlaceholders); err != nil {
+		return nil, fmt.Errorf("%w in %s", err, configFile)
+	}
 	if err := validatePreservePaths(local.Preserve.Paths, configFile); err != nil {
 		return nil, err
 	}
 	if err := ValidateHookTriggers(local.Hooks.Hooks); err != nil {
 		return nil, fmt.Errorf("%s: %w", configFile, err)
 	}
+	if err := ValidateHookDependencies(local.Hooks.Hooks); err != nil {
+		return nil, fmt.Errorf("%s: %w", configFile, err)
+	}
+	if err := ValidateHookOptions(local.Hooks.Hooks); err != nil {
+		return nil, fmt.Errorf("%s: %w", configFile, err)
+	}
+	local.Hooks.Hooks = mergeHookScripts(local.Hooks.Hooks, scripts)
 
 	return local, nil
 }
@@ -132,6 +156,10 @@ const defaultLocalConfig = `# wt local config (per-repo overrides)
 # [merge]
 # strategy = "squash"
 
+# PR settings
+# [pr]
+# title_format = "{note}: {title}"
+
 # Prune settings
 # [prune]
 # delete_local_branches = false
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -144,7 +145,7 @@ func TestLoadLocal_InvalidForgeDefault(t *testing.T) {
 
 	dir := t.TempDir()
 	content := `[forge]
-default = "bitbucket"
+default = "sourcehut"
 `
 	if err := os.WriteFile(filepath.Join(dir, LocalConfigFileName), []byte(content), 0644); err != nil {
 		t.Fatalf("write file: %v", err)
@@ -173,6 +174,23 @@ base_ref = "invalid"
 	}
 }
 
+func TestLoadLocal_InvalidTitleFormat(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	content := `[pr]
+title_format = "{ticket}: {title}"
+`
+	if err := os.WriteFile(filepath.Join(dir, LocalConfigFileName), []byte(content), 0644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	_, err := LoadLocal(dir)
+	if err == nil || !strings.Contains(err.Error(), "{ticket}") {
+		t.Fatalf("expected error for unknown placeholder, got %v", err)
+	}
+}
+
 func TestLoadLocal_InvalidPreservePath(t *testing.T) {
 	t.Parallel()
 
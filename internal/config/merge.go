@@ -43,6 +43,11 @@ func MergeLocal(global *Config, local *LocalConfig) *Config {
 		merged.Merge.Strategy = local.Merge.Strategy
 	}
 
+	// Merge PR title format (replace)
+	if local.PR.TitleFormat != "" {
+		merged.PR.TitleFormat = local.PR.TitleFormat
+	}
+
 	// Merge prune (replace)
 	if local.Prune.DeleteLocalBranches != nil {
 		merged.Prune.DeleteLocalBranches = *local.Prune.DeleteLocalBranches
@@ -62,7 +67,8 @@ func MergeLocal(global *Config, local *LocalConfig) *Config {
 }
 
 // mergeHooks merges local hooks into global hooks.
-// Local hooks with the same name override global hooks.
+// Local hooks with the same name override global hooks, or configure them
+// if they don't set a command or argv.
 // Local hooks with enabled=false remove the global hook.
 func mergeHooks(global, local HooksConfig) HooksConfig {
 	merged := HooksConfig{
@@ -79,6 +85,11 @@ func mergeHooks(global, local HooksConfig) HooksConfig {
 			delete(merged.Hooks, name)
 			continue
 		}
+		if base, ok := global.Hooks[name]; ok && !hook.DefinesCommand() {
+			// Only options set: configure the global hook
+			merged.Hooks[name] = configureHook(base, hook)
+			continue
+		}
 		merged.Hooks[name] = hook
 	}
 
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -53,6 +54,7 @@ func TestMergeLocal_SimpleFieldReplace(t *testing.T) {
 			AutoFetch:      false,
 		},
 		Merge: MergeConfig{Strategy: "squash"},
+		PR:    PRConfig{TitleFormat: "{title}"},
 		Prune: PruneConfig{DeleteLocalBranches: false},
 		Forge: ForgeConfig{Default: "github"},
 		Hooks: HooksConfig{Hooks: map[string]Hook{}},
@@ -66,12 +68,16 @@ func TestMergeLocal_SimpleFieldReplace(t *testing.T) {
 			SetUpstream:    new(true),
 		},
 		Merge: LocalMerge{Strategy: "rebase"},
+		PR:    LocalPR{TitleFormat: "{note}: {title}"},
 		Prune: LocalPrune{DeleteLocalBranches: new(true)},
 		Forge: LocalForge{Default: "gitlab"},
 	}
 
 	result := MergeLocal(global, local)
 
+	if result.PR.TitleFormat != "{note}: {title}" {
+		t.Errorf("pr.title_format = %q, want {note}: {title}", result.PR.TitleFormat)
+	}
 	if result.Checkout.WorktreeFormat != "{branch}" {
 		t.Errorf("worktree_format = %q, want {branch}", result.Checkout.WorktreeFormat)
 	}
@@ -236,6 +242,37 @@ func TestMergeLocal_HooksMergeByName(t *testing.T) {
 	}
 }
 
+func TestMergeLocal_HooksConfigureGlobal(t *testing.T) {
+	t.Parallel()
+
+	global := &Config{
+		Hooks: HooksConfig{
+			Hooks: map[string]Hook{
+				"checkout/setup.sh": {Script: "/home/u/.wt/hooks/checkout.d/setup.sh", On: []string{"checkout"}},
+			},
+		},
+	}
+	local := &LocalConfig{
+		Hooks: HooksConfig{
+			Hooks: map[string]Hook{
+				// No command: only sets options of the global hook
+				"checkout/setup.sh": {Timeout: "10m", OnFailure: "warn"},
+			},
+		},
+	}
+
+	got := MergeLocal(global, local).Hooks.Hooks["checkout/setup.sh"]
+	want := Hook{
+		Script:    "/home/u/.wt/hooks/checkout.d/setup.sh",
+		On:        []string{"checkout"},
+		Timeout:   "10m",
+		OnFailure: "warn",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("checkout/setup.sh = %+v, want %+v", got, want)
+	}
+}
+
 func TestMergeLocal_PreserveAppendDedup(t *testing.T) {
 	t.Parallel()
 
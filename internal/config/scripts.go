@@ -0,0 +1,156 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/raphi011/wt/internal/hooktrigger"
+)
+
+// HookScriptsDir is the directory with hook scripts, relative to the wt
+// config directory (~/.wt/hooks) and to a repo root (<repo>/.wt/hooks).
+const HookScriptsDir = "hooks"
+
+// LocalHookScriptsDir is the hook scripts directory relative to a repo root.
+var LocalHookScriptsDir = filepath.Join(".wt", HookScriptsDir)
+
+// LoadHookScripts returns the executable files in dir/<trigger>.d/ as hooks
+// named "<trigger>/<file>" that run on <trigger>, an "on" value such as
+// "checkout" or "before:prune". Hidden and non-executable files are ignored.
+// Returns nil if dir doesn't exist. A directory that can't be read or a file
+// that can't be stat'ed is skipped with a warning, so a broken scripts
+// directory doesn't keep the rest of the config from loading.
+func LoadHookScripts(dir string) (map[string]Hook, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			warnf("skipping hook scripts: %v", err)
+		}
+		return nil, nil
+	}
+
+	var scripts map[string]Hook
+	for _, entry := range entries {
+		trigger, ok := strings.CutSuffix(entry.Name(), ".d")
+		if !ok || !entry.IsDir() {
+			continue
+		}
+		if _, err := hooktrigger.ParseTrigger(trigger); err != nil {
+			return nil, fmt.Errorf("invalid hook scripts directory %s: %w", filepath.Join(dir, entry.Name()), err)
+		}
+
+		triggerDir := filepath.Join(dir, entry.Name())
+		files, err := os.ReadDir(triggerDir)
+		if err != nil {
+			warnf("skipping hook scripts: %v", err)
+			continue
+		}
+		for _, file := range files {
+			if strings.HasPrefix(file.Name(), ".") {
+				continue
+			}
+			path := filepath.Join(triggerDir, file.Name())
+			info, err := os.Stat(path) // follow symlinks
+			if err != nil {
+				warnf("skipping hook script: %v", err)
+				continue
+			}
+			if !isExecutable(info) {
+				continue
+			}
+			if scripts == nil {
+				scripts = make(map[string]Hook)
+			}
+			scripts[trigger+"/"+file.Name()] = Hook{Script: path, On: []string{trigger}}
+		}
+	}
+	return scripts, nil
+}
+
+// warnf prints a warning to stderr. Config is loaded before the logger
+// exists, so it can't be used here.
+func warnf(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, "Warning: "+format+"\n", args...)
+}
+
+// isExecutable reports whether info is a file that can be run directly.
+func isExecutable(info fs.FileInfo) bool {
+	if !info.Mode().IsRegular() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		switch strings.ToLower(filepath.Ext(info.Name())) {
+		case ".exe", ".bat", ".cmd", ".com":
+			return true
+		}
+		return false
+	}
+	return info.Mode().Perm()&0o111 != 0
+}
+
+// mergeHookScripts adds scripts to hooks defined in TOML. A TOML hook with
+// the name of a script replaces it if it has a command or argv; otherwise it
+// configures the script (see configureHook), and enabled = false removes it.
+func mergeHookScripts(hooks map[string]Hook, scripts map[string]Hook) map[string]Hook {
+	if len(scripts) == 0 {
+		return hooks
+	}
+	merged := make(map[string]Hook, len(hooks)+len(scripts))
+	for name, script := range scripts {
+		merged[name] = script
+	}
+	for name, hook := range hooks {
+		script, isScript := scripts[name]
+		switch {
+		case !isScript || hook.DefinesCommand():
+			merged[name] = hook
+		case !hook.IsEnabled():
+			delete(merged, name)
+		default:
+			merged[name] = configureHook(script, hook)
+		}
+	}
+	return merged
+}
+
+// DefinesCommand reports whether the hook says what to run, as opposed to
+// only configuring a hook defined elsewhere.
+func (h *Hook) DefinesCommand() bool {
+	return h.Command != "" || len(h.Argv) > 0 || h.Script != ""
+}
+
+// configureHook returns base with the options set in opts applied, for
+// hooks entries without a command that configure a hook defined elsewhere
+// (a script, or a global hook in a local config).
+func configureHook(base, opts Hook) Hook {
+	if opts.Description != "" {
+		base.Description = opts.Description
+	}
+	if opts.On != nil {
+		base.On = opts.On
+	}
+	if opts.Enabled != nil {
+		base.Enabled = opts.Enabled
+	}
+	if opts.After != nil {
+		base.After = opts.After
+	}
+	if opts.Parallel {
+		base.Parallel = true
+	}
+	if opts.Timeout != "" {
+		base.Timeout = opts.Timeout
+	}
+	if opts.Retries != 0 {
+		base.Retries = opts.Retries
+	}
+	if opts.OnFailure != "" {
+		base.OnFailure = opts.OnFailure
+	}
+	return base
+}
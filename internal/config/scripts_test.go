@@ -0,0 +1,187 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeScript creates dir/name with the given mode, creating dir as needed.
+func writeScript(t *testing.T, dir, name string, mode os.FileMode) string {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"), mode); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadHookScripts(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	setup := writeScript(t, filepath.Join(dir, "checkout.d"), "10-setup.sh", 0755)
+	cleanup := writeScript(t, filepath.Join(dir, "before:prune.d"), "cleanup", 0700)
+	writeScript(t, filepath.Join(dir, "checkout.d"), "README", 0644)
+	writeScript(t, filepath.Join(dir, "checkout.d"), ".hidden", 0755)
+	writeScript(t, dir, "not-a-trigger-dir", 0755)
+	if err := os.Mkdir(filepath.Join(dir, "notes"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	scripts, err := LoadHookScripts(dir)
+	if err != nil {
+		t.Fatalf("LoadHookScripts() error = %v", err)
+	}
+	want := map[string]Hook{
+		"checkout/10-setup.sh": {Script: setup, On: []string{"checkout"}},
+		"before:prune/cleanup": {Script: cleanup, On: []string{"before:prune"}},
+	}
+	if !reflect.DeepEqual(scripts, want) {
+		t.Errorf("LoadHookScripts() = %+v, want %+v", scripts, want)
+	}
+}
+
+func TestLoadHookScripts_NoDir(t *testing.T) {
+	t.Parallel()
+
+	scripts, err := LoadHookScripts(filepath.Join(t.TempDir(), "hooks"))
+	if err != nil || scripts != nil {
+		t.Errorf("LoadHookScripts() = %v, %v, want nil, nil", scripts, err)
+	}
+}
+
+func TestLoadHookScripts_Unreadable(t *testing.T) {
+	t.Parallel()
+
+	// A file instead of a directory can't be read, even as root
+	dir := filepath.Join(t.TempDir(), "hooks")
+	writeScript(t, filepath.Dir(dir), "hooks", 0644)
+
+	scripts, err := LoadHookScripts(dir)
+	if err != nil || scripts != nil {
+		t.Errorf("LoadHookScripts() = %v, %v, want nil, nil", scripts, err)
+	}
+}
+
+func TestLoadHookScripts_BrokenSymlink(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	setup := writeScript(t, filepath.Join(dir, "checkout.d"), "setup.sh", 0755)
+	if err := os.Symlink(filepath.Join(dir, "missing"), filepath.Join(dir, "checkout.d", "broken.sh")); err != nil {
+		t.Fatal(err)
+	}
+
+	scripts, err := LoadHookScripts(dir)
+	if err != nil {
+		t.Fatalf("LoadHookScripts() error = %v", err)
+	}
+	want := map[string]Hook{"checkout/setup.sh": {Script: setup, On: []string{"checkout"}}}
+	if !reflect.DeepEqual(scripts, want) {
+		t.Errorf("LoadHookScripts() = %+v, want %+v", scripts, want)
+	}
+}
+
+func TestLoadHookScripts_InvalidTrigger(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeScript(t, filepath.Join(dir, "chekout.d"), "setup.sh", 0755)
+
+	_, err := LoadHookScripts(dir)
+	if err == nil || !strings.Contains(err.Error(), "chekout.d") {
+		t.Errorf("LoadHookScripts() error = %v, want invalid directory chekout.d", err)
+	}
+}
+
+func TestMergeHookScripts(t *testing.T) {
+	t.Parallel()
+
+	scripts := map[string]Hook{
+		"checkout/setup.sh":  {Script: "/hooks/checkout.d/setup.sh", On: []string{"checkout"}},
+		"checkout/editor.sh": {Script: "/hooks/checkout.d/editor.sh", On: []string{"checkout"}},
+		"checkout/notify.sh": {Script: "/hooks/checkout.d/notify.sh", On: []string{"checkout"}},
+		"prune/cleanup.sh":   {Script: "/hooks/prune.d/cleanup.sh", On: []string{"prune"}},
+	}
+	hooks := map[string]Hook{
+		// Configure a script
+		"checkout/setup.sh": {Timeout: "5m", After: []string{"deps"}},
+		// Disable a script
+		"checkout/editor.sh": {Enabled: new(false)},
+		// Replace a script
+		"checkout/notify.sh": {Command: "echo done", On: []string{"checkout"}},
+		"deps":               {Command: "npm install", On: []string{"checkout"}},
+	}
+
+	got := mergeHookScripts(hooks, scripts)
+	want := map[string]Hook{
+		"checkout/setup.sh": {
+			Script:  "/hooks/checkout.d/setup.sh",
+			On:      []string{"checkout"},
+			After:   []string{"deps"},
+			Timeout: "5m",
+		},
+		"checkout/notify.sh": {Command: "echo done", On: []string{"checkout"}},
+		"prune/cleanup.sh":   {Script: "/hooks/prune.d/cleanup.sh", On: []string{"prune"}},
+		"deps":               {Command: "npm install", On: []string{"checkout"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeHookScripts() = %+v, want %+v", got, want)
+	}
+}
+
+func TestLoadLocal_HookScriptsWithoutFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	script := writeScript(t, filepath.Join(dir, LocalHookScriptsDir, "checkout.d"), "setup.sh", 0755)
+
+	local, err := LoadLocal(dir)
+	if err != nil {
+		t.Fatalf("LoadLocal() error = %v", err)
+	}
+	if local == nil {
+		t.Fatal("expected non-nil local config for hook scripts")
+	}
+	want := map[string]Hook{"checkout/setup.sh": {Script: script, On: []string{"checkout"}}}
+	if !reflect.DeepEqual(local.Hooks.Hooks, want) {
+		t.Errorf("hooks = %+v, want %+v", local.Hooks.Hooks, want)
+	}
+}
+
+func TestLoadLocal_HookScriptsConfigured(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeScript(t, filepath.Join(dir, LocalHookScriptsDir, "checkout.d"), "setup.sh", 0755)
+	writeScript(t, filepath.Join(dir, LocalHookScriptsDir, "checkout.d"), "editor.sh", 0755)
+	content := `
+[hooks."checkout/setup.sh"]
+description = "Install dependencies"
+on_failure = "ignore"
+
+[hooks."checkout/editor.sh"]
+enabled = false
+`
+	if err := os.WriteFile(filepath.Join(dir, LocalConfigFileName), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	local, err := LoadLocal(dir)
+	if err != nil {
+		t.Fatalf("LoadLocal() error = %v", err)
+	}
+	if _, ok := local.Hooks.Hooks["checkout/editor.sh"]; ok {
+		t.Error("disabled script should be removed")
+	}
+	setup := local.Hooks.Hooks["checkout/setup.sh"]
+	if setup.Script == "" || setup.Description != "Install dependencies" || setup.OnFailure != "ignore" {
+		t.Errorf("checkout/setup.sh = %+v, want configured script", setup)
+	}
+}
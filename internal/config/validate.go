@@ -2,20 +2,25 @@ package config
 
 import (
 	"fmt"
+	"maps"
 	"path/filepath"
+	"regexp"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/raphi011/wt/internal/hooktrigger"
 )
 
 // Valid enum values for configuration fields.
 var (
-	ValidForgeTypes       = []string{"github", "gitlab"}
+	ValidForgeTypes       = []string{"github", "gitlab", "gitea", "bitbucket"}
+	ValidForgeBackends    = []string{"cli", "api"}
 	ValidMergeStrategies  = []string{"squash", "rebase", "merge"}
 	ValidBaseRefs         = []string{"local", "remote"}
 	ValidDefaultSortModes = []string{"date", "repo", "branch"}
 	ValidCloneModes       = []string{"bare", "regular"}
+	ValidHookFailures     = []string{"warn", "abort", "ignore"}
 )
 
 // ValidateCloneMode validates a clone mode value against ValidCloneModes.
@@ -36,13 +41,147 @@ func validateEnum(value, field string, allowed []string) error {
 	return nil
 }
 
-// ValidateHookTriggers validates all "on" values in hook config.
+// parseDuration parses a Go duration string. Empty means 0 (disabled).
+func parseDuration(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(value)
+}
+
+// validateDuration checks that value (if non-empty) is a non-negative Go duration.
+func validateDuration(value, field string) error {
+	d, err := parseDuration(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s %q: must be a duration like \"15m\" or \"2h\"", field, value)
+	}
+	if d < 0 {
+		return fmt.Errorf("invalid %s %q: must not be negative", field, value)
+	}
+	return nil
+}
+
+// validatePlaceholders checks that value only uses {placeholders} from allowed.
+func validatePlaceholders(value, field string, allowed []string) error {
+	rest := value
+	for {
+		_, after, ok := strings.Cut(rest, "{")
+		if !ok {
+			return nil
+		}
+		name, tail, ok := strings.Cut(after, "}")
+		if !ok {
+			return nil
+		}
+		if !slices.Contains(allowed, name) {
+			return fmt.Errorf("invalid %s %q: unknown placeholder {%s}, must be %s", field, value, name, formatOptions(allowed))
+		}
+		rest = tail
+	}
+}
+
+// ValidateHookTriggers validates all "on" values in hook config, and that
+// the placeholders in a hook's command or argv have a value for each of its
+// triggers (e.g. {pr-number} only for PR checkouts and pr hooks).
 func ValidateHookTriggers(hooksMap map[string]Hook) error {
 	for name, hook := range hooksMap {
 		for _, on := range hook.On {
-			if _, err := hooktrigger.ParseTrigger(on); err != nil {
+			trigger, err := hooktrigger.ParseTrigger(on)
+			if err != nil {
 				return fmt.Errorf("invalid hook trigger %q in hook %q: %w", on, name, err)
 			}
+			if placeholder := unavailablePlaceholder(hook, trigger); placeholder != "" {
+				return fmt.Errorf("hook %q: placeholder {%s} is not available for trigger %q", name, placeholder, on)
+			}
+		}
+	}
+	return nil
+}
+
+// staticPlaceholderRegex matches {name} and {name:q}, the forms in which
+// static placeholders are substituted.
+var staticPlaceholderRegex = regexp.MustCompile(`\{([a-z][a-z-]*)(?::q)?\}`)
+
+// unavailablePlaceholder returns the first static placeholder in the hook's
+// command or argv that has no value for trigger, or "" if there is none.
+func unavailablePlaceholder(hook Hook, trigger hooktrigger.ParsedTrigger) string {
+	all, available := hooktrigger.AllPlaceholders(), trigger.Placeholders()
+	for _, s := range append([]string{hook.Command}, hook.Argv...) {
+		for _, m := range staticPlaceholderRegex.FindAllStringSubmatch(s, -1) {
+			if slices.Contains(all, m[1]) && !slices.Contains(available, m[1]) {
+				return m[1]
+			}
+		}
+	}
+	return ""
+}
+
+// ValidateHookOptions validates the timeout, retries and on_failure values
+// of all hooks, and that no hook sets both command and argv.
+func ValidateHookOptions(hooksMap map[string]Hook) error {
+	for _, name := range slices.Sorted(maps.Keys(hooksMap)) {
+		hook := hooksMap[name]
+		if hook.Command != "" && len(hook.Argv) > 0 {
+			return fmt.Errorf("hook %q: command and argv are mutually exclusive", name)
+		}
+		if err := validateDuration(hook.Timeout, "timeout"); err != nil {
+			return fmt.Errorf("hook %q: %w", name, err)
+		}
+		if hook.Retries < 0 {
+			return fmt.Errorf("hook %q: invalid retries %d: must not be negative", name, hook.Retries)
+		}
+		if err := validateEnum(hook.OnFailure, "on_failure", ValidHookFailures); err != nil {
+			return fmt.Errorf("hook %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// ValidateHookDependencies checks that no hook lists itself in "after" and
+// that the "after" references don't form a cycle. References to hooks that
+// aren't defined are allowed: a repo's local config may order its hooks
+// after global ones, and "after" only orders hooks that run anyway.
+func ValidateHookDependencies(hooksMap map[string]Hook) error {
+	names := slices.Sorted(maps.Keys(hooksMap))
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(hooksMap))
+	var path []string
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visiting:
+			start := slices.Index(path, name)
+			return fmt.Errorf("hook dependency cycle: %s", strings.Join(append(path[start:], name), " -> "))
+		case visited:
+			return nil
+		}
+		state[name] = visiting
+		path = append(path, name)
+		for _, dep := range hooksMap[name].After {
+			if dep == name {
+				return fmt.Errorf("hook %q can't run after itself", name)
+			}
+			if _, ok := hooksMap[dep]; !ok {
+				continue
+			}
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = visited
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return err
 		}
 	}
 	return nil
@@ -0,0 +1,255 @@
+package forge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/raphi011/wt/internal/config"
+)
+
+// apiTimeout bounds a single HTTP request to a forge API.
+const apiTimeout = 30 * time.Second
+
+// APIError is returned when a forge API responds with a non-2xx status.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("HTTP %d", e.StatusCode)
+	}
+	return fmt.Sprintf("HTTP %d: %s", e.StatusCode, e.Message)
+}
+
+// apiClient performs authenticated JSON requests against a forge API.
+type apiClient struct {
+	http    *http.Client
+	token   string
+	headers map[string]string // extra headers sent with every request
+}
+
+// do sends a request with an optional JSON body and decodes the JSON response into out
+// (if non-nil); a *string out receives the raw response body instead.
+// Non-2xx responses are returned as *APIError.
+func (c *apiClient) do(ctx context.Context, method, url string, body, out any) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+
+	httpClient := c.http
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: apiTimeout}
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &APIError{StatusCode: resp.StatusCode, Message: apiErrorMessage(data)}
+	}
+
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	if s, ok := out.(*string); ok {
+		*s = string(data)
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// apiErrorMessage extracts a human-readable message from an error response body.
+// GitHub uses {"message": ...}, GitLab uses {"message": ...} or {"error": ...},
+// Bitbucket Cloud {"error": {"message": ...}} and Data Center
+// {"errors": [{"message": ...}]}.
+func apiErrorMessage(data []byte) string {
+	var body struct {
+		Message any `json:"message"`
+		Error   any `json:"error"`
+		Errors  []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &body); err == nil {
+		switch m := body.Message.(type) {
+		case string:
+			if m != "" {
+				return m
+			}
+		case nil:
+		default:
+			// GitLab validation errors: {"message": {"field": ["..."]}}
+			if b, err := json.Marshal(m); err == nil {
+				return string(b)
+			}
+		}
+		switch e := body.Error.(type) {
+		case string:
+			if e != "" {
+				return e
+			}
+		case map[string]any:
+			if m, ok := e["message"].(string); ok && m != "" {
+				return m
+			}
+		}
+		if len(body.Errors) > 0 && body.Errors[0].Message != "" {
+			return body.Errors[0].Message
+		}
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// cliTokens caches tokens read from CLI credential stores, keyed by host and user,
+// so that repeated forge lookups don't spawn gh/glab for every request.
+var cliTokens sync.Map
+
+// resolveToken finds an API token for host. Sources are tried in order:
+// the [forge.tokens] config key, the given environment variables, and the
+// CLI's stored credentials via fromCLI (may be nil).
+func resolveToken(ctx context.Context, forgeConfig *config.ForgeConfig, host, user string, envVars []string, fromCLI func(ctx context.Context, host, user string) (string, error)) (string, error) {
+	if forgeConfig != nil {
+		if token := forgeConfig.Tokens[host]; token != "" {
+			return token, nil
+		}
+	}
+
+	for _, name := range envVars {
+		if token := os.Getenv(name); token != "" {
+			return token, nil
+		}
+	}
+
+	if fromCLI != nil {
+		key := host + "\x00" + user
+		if token, ok := cliTokens.Load(key); ok {
+			return token.(string), nil
+		}
+		token, err := fromCLI(ctx, host, user)
+		if err == nil && token != "" {
+			cliTokens.Store(key, token)
+			return token, nil
+		}
+	}
+
+	return "", fmt.Errorf("no API token for %s: set [forge.tokens] %q, or one of %s", host, host, strings.Join(envVars, ", "))
+}
+
+// escapePathSegments escapes each "/"-separated segment of p for use in a URL
+// path, keeping the separators (e.g. branch names like "feature/x").
+func escapePathSegments(p string) string {
+	segments := strings.Split(p, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
+
+// apiHost normalizes a host parsed from a remote URL for API access.
+// SSH host aliases like "github.com-work" map back to the public host.
+func apiHost(host, public string) string {
+	if host == "" || strings.HasPrefix(host, public+"-") {
+		return public
+	}
+	return host
+}
+
+// remoteWebURL returns the instance URL for an HTTP(S) remote, keeping its
+// scheme and port (self-hosted instances often run on plain HTTP or a
+// custom port). Returns "" for SSH remotes.
+func remoteWebURL(remoteURL string) string {
+	if !strings.HasPrefix(remoteURL, "http://") && !strings.HasPrefix(remoteURL, "https://") {
+		return ""
+	}
+	u, err := url.Parse(remoteURL)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// cloneWithGit clones cloneURL with plain git. For bare clones, the repo is
+// cloned into <clonePath>/.git and configured for worktree support.
+func cloneWithGit(ctx context.Context, cloneURL, clonePath string, bare bool) error {
+	if !bare {
+		c := exec.CommandContext(ctx, "git", "clone", cloneURL, clonePath)
+		c.Stderr = os.Stderr
+		if err := c.Run(); err != nil {
+			return fmt.Errorf("git clone failed: %v", err)
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(clonePath, 0o755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	gitDir := filepath.Join(clonePath, ".git")
+	c := exec.CommandContext(ctx, "git", "clone", "--bare", cloneURL, gitDir)
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		os.RemoveAll(clonePath)
+		return fmt.Errorf("git clone failed: %v", err)
+	}
+
+	if err := configureBareRepo(ctx, gitDir); err != nil {
+		os.RemoveAll(clonePath)
+		return err
+	}
+	return nil
+}
+
+// printPRDetails writes a terminal summary of a PR, used by the API
+// backends' ViewPR (the CLI backends delegate to gh/glab).
+func printPRDetails(w io.Writer, title, ref, state, author, url, body string) {
+	fmt.Fprintf(w, "%s %s\n", title, ref)
+	fmt.Fprintf(w, "%s • %s\n", state, author)
+	if body = strings.TrimSpace(body); body != "" {
+		fmt.Fprintf(w, "\n%s\n", body)
+	}
+	fmt.Fprintf(w, "\n%s\n", url)
+}
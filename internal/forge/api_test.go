@@ -0,0 +1,90 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/raphi011/wt/internal/config"
+)
+
+func TestResolveToken(t *testing.T) {
+	// Not parallel: uses t.Setenv
+
+	ctx := context.Background()
+	cliCalls := 0
+	fromCLI := func(_ context.Context, host, user string) (string, error) {
+		cliCalls++
+		if host == "cli.test" && user == "alice" {
+			return "cli-token", nil
+		}
+		return "", errors.New("not logged in")
+	}
+
+	t.Setenv("WT_TEST_TOKEN_A", "")
+	t.Setenv("WT_TEST_TOKEN_B", "env-token")
+	envVars := []string{"WT_TEST_TOKEN_A", "WT_TEST_TOKEN_B"}
+
+	cfg := &config.ForgeConfig{Tokens: map[string]string{"config.test": "config-token"}}
+
+	tests := []struct {
+		name    string
+		host    string
+		user    string
+		envVars []string
+		want    string
+		wantErr bool
+	}{
+		{"config key wins over env", "config.test", "", envVars, "config-token", false},
+		{"first non-empty env var", "other.test", "", envVars, "env-token", false},
+		{"CLI credentials as fallback", "cli.test", "alice", nil, "cli-token", false},
+		{"no source", "none.test", "", nil, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveToken(ctx, cfg, tt.host, tt.user, tt.envVars, fromCLI)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("resolveToken() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// CLI tokens are cached per host and user
+	before := cliCalls
+	if _, err := resolveToken(ctx, cfg, "cli.test", "alice", nil, fromCLI); err != nil {
+		t.Fatalf("resolveToken() error = %v", err)
+	}
+	if cliCalls != before {
+		t.Errorf("expected cached CLI token, got %d extra CLI calls", cliCalls-before)
+	}
+}
+
+func TestAPIErrorMessage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"github message", `{"message":"Not Found"}`, "Not Found"},
+		{"gitlab error", `{"error":"insufficient_scope"}`, "insufficient_scope"},
+		{"gitlab validation", `{"message":{"title":["can't be blank"]}}`, `{"title":["can't be blank"]}`},
+		{"bitbucket cloud error", `{"type":"error","error":{"message":"Resource not found"}}`, "Resource not found"},
+		{"bitbucket data center errors", `{"errors":[{"context":null,"message":"Authentication failed"}]}`, "Authentication failed"},
+		{"plain text", "Bad Gateway\n", "Bad Gateway"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := apiErrorMessage([]byte(tt.body)); got != tt.want {
+				t.Errorf("apiErrorMessage(%q) = %q, want %q", tt.body, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// maxBranchesPerQuery caps the number of aliased pullRequests lookups in a
+// single GitHub GraphQL query to stay well below the API's node limits.
+const maxBranchesPerQuery = 50
+
+// listLimit is the page size used when listing all PRs/MRs of a repository.
+const listLimit = 100
+
+// branchPR pairs a PR with its head branch, as returned by list queries.
+type branchPR struct {
+	Branch string
+	Info   *PRInfo
+}
+
+// noPR returns a marker indicating that the forge was queried but no PR exists.
+func noPR() *PRInfo {
+	return &PRInfo{
+		Fetched:  true,
+		CachedAt: time.Now(),
+	}
+}
+
+// newOpenPR builds an OpenPR entry from a PR's info and head branch.
+func newOpenPR(info *PRInfo, branch string) OpenPR {
+	return OpenPR{
+		Number:          info.Number,
+		Title:           info.Title,
+		Author:          info.Author,
+		Branch:          branch,
+		IsDraft:         info.IsDraft,
+		URL:             info.URL,
+		Labels:          info.Labels,
+		ReviewRequested: info.ReviewRequested,
+		UpdatedAt:       info.UpdatedAt,
+	}
+}
+
+// matchBranchPRs maps each requested branch to the newest PR whose head is that
+// branch. prs must be ordered newest first. If complete is false (the listing was
+// truncated), branches without a match are looked up individually with lookup;
+// otherwise they get a "fetched, no PR" marker.
+func matchBranchPRs(ctx context.Context, branches []string, prs []branchPR, complete bool, lookup func(ctx context.Context, branch string) (*PRInfo, error)) (map[string]*PRInfo, error) {
+	wanted := make(map[string]bool, len(branches))
+	for _, b := range branches {
+		wanted[b] = true
+	}
+
+	result := make(map[string]*PRInfo, len(branches))
+	for _, pr := range prs {
+		if !wanted[pr.Branch] {
+			continue
+		}
+		if _, ok := result[pr.Branch]; !ok {
+			result[pr.Branch] = pr.Info
+		}
+	}
+
+	for _, b := range branches {
+		if _, ok := result[b]; ok {
+			continue
+		}
+		if complete {
+			result[b] = noPR()
+			continue
+		}
+		info, err := lookup(ctx, b)
+		if err != nil {
+			return nil, err
+		}
+		result[b] = info
+	}
+
+	return result, nil
+}
+
+// githubBatchQuery builds a GraphQL query that looks up the newest PR for each
+// branch, using one aliased pullRequests field per branch (b0, b1, ...).
+// The returned variables contain the branch names; owner and name must be added.
+func githubBatchQuery(branches []string) (string, map[string]any) {
+	vars := make(map[string]any, len(branches)+2)
+	var params, fields strings.Builder
+	params.WriteString("$owner: String!, $name: String!")
+	for i, b := range branches {
+		alias := fmt.Sprintf("b%d", i)
+		vars[alias] = b
+		fmt.Fprintf(&params, ", $%s: String!", alias)
+		fmt.Fprintf(&fields, "    %s: pullRequests(headRefName: $%s, first: 1, orderBy: {field: CREATED_AT, direction: DESC}) {\n      nodes { %s }\n    }\n", alias, alias, githubPRFields)
+	}
+
+	query := "query(" + params.String() + ") {\n  repository(owner: $owner, name: $name) {\n" + fields.String() + "  }\n}"
+	return query, vars
+}
+
+// githubPRsForBranches resolves PRs for branches with batched GraphQL queries.
+// run executes a query with the given variables and decodes "data" into out.
+func githubPRsForBranches(owner, name string, branches []string, run func(query string, vars map[string]any, out any) error) (map[string]*PRInfo, error) {
+	result := make(map[string]*PRInfo, len(branches))
+
+	for start := 0; start < len(branches); start += maxBranchesPerQuery {
+		chunk := branches[start:min(start+maxBranchesPerQuery, len(branches))]
+		query, vars := githubBatchQuery(chunk)
+		vars["owner"] = owner
+		vars["name"] = name
+
+		var data struct {
+			Repository map[string]struct {
+				Nodes []githubPRNode `json:"nodes"`
+			} `json:"repository"`
+		}
+		if err := run(query, vars, &data); err != nil {
+			return nil, err
+		}
+
+		for i, b := range chunk {
+			nodes := data.Repository[fmt.Sprintf("b%d", i)].Nodes
+			if len(nodes) == 0 {
+				result[b] = noPR()
+				continue
+			}
+			result[b] = nodes[0].toPRInfo()
+		}
+	}
+
+	return result, nil
+}
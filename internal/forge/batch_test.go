@@ -0,0 +1,124 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestMatchBranchPRs(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	prs := []branchPR{
+		{Branch: "feat-a", Info: &PRInfo{Number: 3, State: PRStateOpen, Fetched: true}},
+		{Branch: "other", Info: &PRInfo{Number: 2, State: PRStateOpen, Fetched: true}},
+		{Branch: "feat-a", Info: &PRInfo{Number: 1, State: PRStateClosed, Fetched: true}},
+	}
+
+	t.Run("complete listing", func(t *testing.T) {
+		t.Parallel()
+
+		lookup := func(context.Context, string) (*PRInfo, error) {
+			t.Error("lookup should not be called for a complete listing")
+			return nil, nil
+		}
+		got, err := matchBranchPRs(ctx, []string{"feat-a", "feat-b"}, prs, true, lookup)
+		if err != nil {
+			t.Fatalf("matchBranchPRs() error = %v", err)
+		}
+		if len(got) != 2 {
+			t.Fatalf("expected 2 entries, got %d", len(got))
+		}
+		if got["feat-a"].Number != 3 {
+			t.Errorf("feat-a should map to newest PR #3, got #%d", got["feat-a"].Number)
+		}
+		if got["feat-b"].Number != 0 || !got["feat-b"].Fetched {
+			t.Errorf("feat-b should get a fetched marker, got %+v", *got["feat-b"])
+		}
+		if _, ok := got["other"]; ok {
+			t.Error("unrequested branch should not be in result")
+		}
+	})
+
+	t.Run("truncated listing falls back to lookup", func(t *testing.T) {
+		t.Parallel()
+
+		var looked []string
+		lookup := func(_ context.Context, branch string) (*PRInfo, error) {
+			looked = append(looked, branch)
+			return &PRInfo{Number: 99, Fetched: true}, nil
+		}
+		got, err := matchBranchPRs(ctx, []string{"feat-a", "old"}, prs, false, lookup)
+		if err != nil {
+			t.Fatalf("matchBranchPRs() error = %v", err)
+		}
+		if len(looked) != 1 || looked[0] != "old" {
+			t.Errorf("expected lookup of [old], got %v", looked)
+		}
+		if got["old"].Number != 99 {
+			t.Errorf("old should come from lookup, got #%d", got["old"].Number)
+		}
+	})
+
+	t.Run("lookup error", func(t *testing.T) {
+		t.Parallel()
+
+		lookup := func(context.Context, string) (*PRInfo, error) {
+			return nil, errors.New("rate limited")
+		}
+		if _, err := matchBranchPRs(ctx, []string{"old"}, prs, false, lookup); err == nil {
+			t.Error("expected lookup error to be returned")
+		}
+	})
+}
+
+func TestGithubPRsForBranches_Chunks(t *testing.T) {
+	t.Parallel()
+
+	branches := make([]string, maxBranchesPerQuery+5)
+	for i := range branches {
+		branches[i] = fmt.Sprintf("branch-%d", i)
+	}
+
+	var queries int
+	run := func(query string, vars map[string]any, out any) error {
+		queries++
+		if vars["owner"] != "org" || vars["name"] != "repo" {
+			t.Errorf("unexpected owner/name vars: %v", vars)
+		}
+		if !strings.Contains(query, "b0: pullRequests(headRefName: $b0") {
+			t.Errorf("query is missing aliased lookup:\n%s", query)
+		}
+
+		// Report a PR only for "branch-3"
+		repo := make(map[string]any)
+		for alias, branch := range vars {
+			if branch == "branch-3" {
+				repo[alias] = map[string]any{"nodes": []any{map[string]any{"number": 3, "state": PRStateOpen}}}
+			}
+		}
+		data, _ := json.Marshal(map[string]any{"repository": repo})
+		return json.Unmarshal(data, out)
+	}
+
+	got, err := githubPRsForBranches("org", "repo", branches, run)
+	if err != nil {
+		t.Fatalf("githubPRsForBranches() error = %v", err)
+	}
+	if queries != 2 {
+		t.Errorf("expected 2 queries for %d branches, got %d", len(branches), queries)
+	}
+	if len(got) != len(branches) {
+		t.Fatalf("expected %d entries, got %d", len(branches), len(got))
+	}
+	if got["branch-3"].Number != 3 {
+		t.Errorf("branch-3 = #%d, want #3", got["branch-3"].Number)
+	}
+	if last := got[branches[len(branches)-1]]; last.Number != 0 || !last.Fetched {
+		t.Errorf("branch in second chunk should get a fetched marker, got %+v", *last)
+	}
+}
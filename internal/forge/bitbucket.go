@@ -0,0 +1,597 @@
+package forge
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/raphi011/wt/internal/browser"
+	"github.com/raphi011/wt/internal/config"
+)
+
+// bitbucketCloudHost is the host of Bitbucket Cloud.
+const bitbucketCloudHost = "bitbucket.org"
+
+// bitbucketPageSize is the page size for Bitbucket list requests
+// (the maximum Bitbucket Cloud allows for pull requests).
+const bitbucketPageSize = 50
+
+// bitbucketTokenEnv are the environment variables checked for a Bitbucket token.
+var bitbucketTokenEnv = []string{"BITBUCKET_TOKEN"}
+
+// bitbucketClient returns an authenticated API client for a Bitbucket host.
+// Tokens of the form "user:secret" (Cloud API tokens and app passwords, Data
+// Center username and password) are sent with Basic auth, anything else
+// (Cloud and Data Center access tokens) as a Bearer token.
+func bitbucketClient(ctx context.Context, forgeConfig *config.ForgeConfig, host string, httpClient *http.Client) (*apiClient, error) {
+	token, err := resolveToken(ctx, forgeConfig, host, "", bitbucketTokenEnv, nil)
+	if err != nil {
+		return nil, err
+	}
+	if strings.Contains(token, ":") {
+		basic := base64.StdEncoding.EncodeToString([]byte(token))
+		return &apiClient{http: httpClient, headers: map[string]string{"Authorization": "Basic " + basic}}, nil
+	}
+	return &apiClient{http: httpClient, token: token}, nil
+}
+
+// BitbucketCloud implements Forge for Bitbucket Cloud (bitbucket.org)
+// repositories using the REST API. Like [Gitea] there is no CLI backend.
+type BitbucketCloud struct {
+	ForgeConfig *config.ForgeConfig
+	BaseURL     string       // API base URL override (tests); defaults to https://api.bitbucket.org/2.0
+	HTTPClient  *http.Client // optional; defaults to a client with apiTimeout
+}
+
+// Name returns "bitbucket"
+func (b *BitbucketCloud) Name() string {
+	return "bitbucket"
+}
+
+// apiURL returns the API URL for path (which must start with "/").
+func (b *BitbucketCloud) apiURL(path string) string {
+	if b.BaseURL != "" {
+		return strings.TrimSuffix(b.BaseURL, "/") + path
+	}
+	return "https://api.bitbucket.org/2.0" + path
+}
+
+// repoURL returns the API URL for a path below /repositories/{workspace}/{repo}.
+func (b *BitbucketCloud) repoURL(repoPath, path string) string {
+	return b.apiURL("/repositories/" + escapePathSegments(repoPath) + path)
+}
+
+// client returns an authenticated API client.
+func (b *BitbucketCloud) client(ctx context.Context) (*apiClient, error) {
+	return bitbucketClient(ctx, b.ForgeConfig, bitbucketCloudHost, b.HTTPClient)
+}
+
+// Check verifies that an API token is available. The token isn't validated
+// here: repository and workspace access tokens can't read the current user,
+// so invalid tokens are reported by the first actual request.
+func (b *BitbucketCloud) Check(ctx context.Context) error {
+	_, err := b.client(ctx)
+	return err
+}
+
+// bitbucketCloudEndpoint is the REST shape of a pull request's source or destination.
+type bitbucketCloudEndpoint struct {
+	Branch struct {
+		Name string `json:"name"`
+	} `json:"branch"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+// bitbucketCloudPR is the REST shape of a pull request.
+type bitbucketCloudPR struct {
+	ID           int    `json:"id"`
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	State        string `json:"state"` // OPEN, MERGED, DECLINED, SUPERSEDED
+	Draft        bool   `json:"draft"`
+	CommentCount int    `json:"comment_count"`
+	Author       struct {
+		Nickname    string `json:"nickname"`
+		DisplayName string `json:"display_name"`
+	} `json:"author"`
+	Links struct {
+		HTML struct {
+			Href string `json:"href"`
+		} `json:"html"`
+	} `json:"links"`
+	Source       bitbucketCloudEndpoint `json:"source"`
+	Destination  bitbucketCloudEndpoint `json:"destination"`
+	UpdatedOn    time.Time              `json:"updated_on"`
+	Participants []struct {
+		User struct {
+			Nickname string `json:"nickname"`
+		} `json:"user"`
+		Role     string `json:"role"` // PARTICIPANT, REVIEWER
+		Approved bool   `json:"approved"`
+		State    string `json:"state"` // approved, changes_requested or null
+	} `json:"participants"` // only included when fetching a single PR
+}
+
+// author returns the PR author's nickname, falling back to the display name.
+func (pr bitbucketCloudPR) author() string {
+	if pr.Author.Nickname != "" {
+		return pr.Author.Nickname
+	}
+	return pr.Author.DisplayName
+}
+
+// fork returns the source repository (workspace/repo) and whether the PR is
+// from a fork.
+func (pr bitbucketCloudPR) fork() (repoPath string, isFork bool) {
+	src, dst := pr.Source.Repository.FullName, pr.Destination.Repository.FullName
+	if src == "" || dst == "" || strings.EqualFold(src, dst) {
+		return "", false
+	}
+	return src, true
+}
+
+// toPRInfo converts a REST pull request to PRInfo. Bitbucket has neither
+// labels nor CI status on pull requests, so those stay empty.
+func (pr bitbucketCloudPR) toPRInfo() *PRInfo {
+	info := &PRInfo{
+		Number:       pr.ID,
+		State:        normalizeBitbucketState(pr.State),
+		IsDraft:      pr.Draft,
+		URL:          pr.Links.HTML.Href,
+		Author:       pr.author(),
+		CommentCount: pr.CommentCount,
+		CachedAt:     time.Now(),
+		Fetched:      true,
+		Title:        pr.Title,
+		BaseBranch:   pr.Destination.Branch.Name,
+		UpdatedAt:    pr.UpdatedOn,
+	}
+	for _, p := range pr.Participants {
+		if p.Approved || p.State != "" {
+			info.HasReviews = true
+		} else if p.Role == "REVIEWER" {
+			info.ReviewRequested = append(info.ReviewRequested, p.User.Nickname)
+		}
+		if p.Approved {
+			info.IsApproved = true
+		}
+	}
+	return info
+}
+
+// bitbucketCloudStates are all pull request states; the API only returns
+// open PRs unless states are requested explicitly.
+var bitbucketCloudStates = []string{"OPEN", "MERGED", "DECLINED", "SUPERSEDED"}
+
+// listPRs lists pull requests matching query, newest first, following "next"
+// links for up to maxPages pages. complete reports whether all PRs were read.
+func (b *BitbucketCloud) listPRs(ctx context.Context, c *apiClient, repoPath string, query url.Values, maxPages int) (prs []bitbucketCloudPR, complete bool, err error) {
+	query.Set("sort", "-created_on")
+	if query.Get("pagelen") == "" {
+		query.Set("pagelen", strconv.Itoa(bitbucketPageSize))
+	}
+
+	next := b.repoURL(repoPath, "/pullrequests?"+query.Encode())
+	for page := 0; page < maxPages && next != ""; page++ {
+		var resp struct {
+			Values []bitbucketCloudPR `json:"values"`
+			Next   string             `json:"next"`
+		}
+		if err := c.do(ctx, http.MethodGet, next, nil, &resp); err != nil {
+			return nil, false, err
+		}
+		prs = append(prs, resp.Values...)
+		next = resp.Next
+	}
+
+	sort.SliceStable(prs, func(i, j int) bool { return prs[i].ID > prs[j].ID })
+	return prs, next == "", nil
+}
+
+// GetPRForBranch fetches PR info for a branch
+func (b *BitbucketCloud) GetPRForBranch(ctx context.Context, repoURL, branch string) (*PRInfo, error) {
+	repoPath := ExtractRepoPath(repoURL)
+	c, err := b.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{
+		"q":       {"source.branch.name = " + strconv.Quote(branch)},
+		"state":   bitbucketCloudStates,
+		"pagelen": {"1"},
+	}
+	prs, _, err := b.listPRs(ctx, c, repoPath, query, 1)
+	if err != nil {
+		return nil, fmt.Errorf("bitbucket api request failed: %w", err)
+	}
+
+	if len(prs) == 0 {
+		// No PR found - return marker indicating we checked
+		return noPR(), nil
+	}
+	return prs[0].toPRInfo(), nil
+}
+
+// GetPRsForBranches lists the repository's most recent PRs (all states) and
+// matches them to branches locally. Branches not covered by a truncated
+// listing fall back to GetPRForBranch.
+func (b *BitbucketCloud) GetPRsForBranches(ctx context.Context, repoURL string, branches []string) (map[string]*PRInfo, error) {
+	repoPath := ExtractRepoPath(repoURL)
+	c, err := b.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	list, complete, err := b.listPRs(ctx, c, repoPath, url.Values{"state": bitbucketCloudStates}, listLimit/bitbucketPageSize)
+	if err != nil {
+		return nil, fmt.Errorf("bitbucket api request failed: %w", err)
+	}
+
+	prs := make([]branchPR, len(list))
+	for i, pr := range list {
+		prs[i] = branchPR{Branch: pr.Source.Branch.Name, Info: pr.toPRInfo()}
+	}
+
+	return matchBranchPRs(ctx, branches, prs, complete, func(ctx context.Context, branch string) (*PRInfo, error) {
+		return b.GetPRForBranch(ctx, repoURL, branch)
+	})
+}
+
+// getPR fetches a single pull request.
+func (b *BitbucketCloud) getPR(ctx context.Context, c *apiClient, repoPath string, number int) (*bitbucketCloudPR, error) {
+	var pr bitbucketCloudPR
+	if err := c.do(ctx, http.MethodGet, b.repoURL(repoPath, fmt.Sprintf("/pullrequests/%d", number)), nil, &pr); err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+// GetPRHead fetches the head branch and repository for a PR number.
+// Bitbucket Cloud doesn't let maintainers push to forks.
+func (b *BitbucketCloud) GetPRHead(ctx context.Context, repoURL string, number int) (*PRHead, error) {
+	repoPath := ExtractRepoPath(repoURL)
+	c, err := b.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, err := b.getPR(ctx, c, repoPath, number)
+	if err != nil {
+		return nil, fmt.Errorf("bitbucket api request failed: %w", err)
+	}
+
+	branch := pr.Source.Branch.Name
+	if branch == "" {
+		return nil, fmt.Errorf("PR #%d has no head branch", number)
+	}
+
+	if src, isFork := pr.fork(); isFork {
+		return newForkHead(repoURL, branch, src, "https://"+bitbucketCloudHost+"/"+src+".git", false), nil
+	}
+
+	return &PRHead{Branch: branch}, nil
+}
+
+// validateBitbucketSpec validates a workspace/repo (Cloud) or PROJECT/repo
+// (Data Center) spec and returns the repo name.
+func validateBitbucketSpec(repoSpec string) (string, error) {
+	owner, name, ok := strings.Cut(repoSpec, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return "", fmt.Errorf("invalid repo spec %q: expected workspace/repo or PROJECT/repo format", repoSpec)
+	}
+	return name, nil
+}
+
+// CloneRepo clones a Bitbucket Cloud repo over HTTPS using git
+func (b *BitbucketCloud) CloneRepo(ctx context.Context, repoSpec, destPath string) (string, error) {
+	repoName, err := validateBitbucketSpec(repoSpec)
+	if err != nil {
+		return "", err
+	}
+	clonePath := filepath.Join(destPath, repoName)
+
+	if err := cloneWithGit(ctx, "https://"+bitbucketCloudHost+"/"+repoSpec+".git", clonePath, false); err != nil {
+		return "", err
+	}
+	return clonePath, nil
+}
+
+// CloneBareRepo clones a Bitbucket Cloud repo as a bare repo inside .git directory
+func (b *BitbucketCloud) CloneBareRepo(ctx context.Context, repoSpec, destPath string) (string, error) {
+	repoName, err := validateBitbucketSpec(repoSpec)
+	if err != nil {
+		return "", err
+	}
+	repoDir := filepath.Join(destPath, repoName)
+
+	if err := cloneWithGit(ctx, "https://"+bitbucketCloudHost+"/"+repoSpec+".git", repoDir, true); err != nil {
+		return "", err
+	}
+	return repoDir, nil
+}
+
+// CreatePR creates a new PR. Without a base branch, Bitbucket targets the
+// repository's main branch.
+func (b *BitbucketCloud) CreatePR(ctx context.Context, repoURL string, params CreatePRParams) (*CreatePRResult, error) {
+	repoPath := ExtractRepoPath(repoURL)
+	if params.Head == "" {
+		return nil, fmt.Errorf("head branch is required")
+	}
+	c, err := b.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	body := map[string]any{
+		"title":       params.Title,
+		"description": params.Body,
+		"source":      map[string]any{"branch": map[string]string{"name": params.Head}},
+		"draft":       params.Draft,
+	}
+	if params.Base != "" {
+		body["destination"] = map[string]any{"branch": map[string]string{"name": params.Base}}
+	}
+
+	var pr bitbucketCloudPR
+	if err := c.do(ctx, http.MethodPost, b.repoURL(repoPath, "/pullrequests"), body, &pr); err != nil {
+		return nil, fmt.Errorf("create PR failed: %w", err)
+	}
+
+	return &CreatePRResult{
+		Number: pr.ID,
+		URL:    pr.Links.HTML.Href,
+	}, nil
+}
+
+// bitbucketCloudMergeStrategies maps wt's merge strategies to Bitbucket Cloud's.
+var bitbucketCloudMergeStrategies = map[string]string{
+	"merge":  "merge_commit",
+	"squash": "squash",
+	"rebase": "rebase_fast_forward",
+}
+
+// MergePR merges a PR by number with the given strategy and closes the source branch
+func (b *BitbucketCloud) MergePR(ctx context.Context, repoURL string, number int, strategy string) error {
+	repoPath := ExtractRepoPath(repoURL)
+	mergeStrategy, ok := bitbucketCloudMergeStrategies[strategy]
+	if !ok {
+		return fmt.Errorf("unsupported merge strategy %q for bitbucket: use squash, rebase or merge", strategy)
+	}
+	c, err := b.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := c.do(ctx, http.MethodPost, b.repoURL(repoPath, fmt.Sprintf("/pullrequests/%d/merge", number)), map[string]any{
+		"merge_strategy":      mergeStrategy,
+		"close_source_branch": true,
+	}, nil); err != nil {
+		return fmt.Errorf("merge failed: %w", err)
+	}
+	return nil
+}
+
+// UpdatePRBase changes the destination branch of a PR
+func (b *BitbucketCloud) UpdatePRBase(ctx context.Context, repoURL string, number int, base string) error {
+	repoPath := ExtractRepoPath(repoURL)
+	c, err := b.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := c.do(ctx, http.MethodPut, b.repoURL(repoPath, fmt.Sprintf("/pullrequests/%d", number)), map[string]any{
+		"destination": map[string]any{"branch": map[string]any{"name": base}},
+	}, nil); err != nil {
+		return fmt.Errorf("update destination failed: %w", err)
+	}
+	return nil
+}
+
+// bitbucketCloudComment is the REST shape of a pull request comment.
+type bitbucketCloudComment struct {
+	ID      int  `json:"id"`
+	Deleted bool `json:"deleted"`
+	Content struct {
+		Raw string `json:"raw"`
+	} `json:"content"`
+	User struct {
+		Nickname    string `json:"nickname"`
+		DisplayName string `json:"display_name"`
+	} `json:"user"`
+	CreatedOn time.Time `json:"created_on"`
+	Inline    *struct {
+		Path     string `json:"path"`
+		From     int    `json:"from"` // line in the old version
+		To       int    `json:"to"`   // line in the new version
+		Outdated bool   `json:"outdated"`
+	} `json:"inline"` // only set on comments attached to a file
+	Parent *struct {
+		ID int `json:"id"`
+	} `json:"parent"`
+	Resolution *struct{} `json:"resolution"` // set once the thread is resolved
+	Links      struct {
+		HTML struct {
+			Href string `json:"href"`
+		} `json:"html"`
+	} `json:"links"`
+}
+
+// ListReviewThreads lists the inline comment threads of a PR. Replies are
+// attached to the thread of their top-level comment.
+func (b *BitbucketCloud) ListReviewThreads(ctx context.Context, repoURL string, number int) ([]ReviewThread, error) {
+	repoPath := ExtractRepoPath(repoURL)
+	c, err := b.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var comments []bitbucketCloudComment
+	next := b.repoURL(repoPath, fmt.Sprintf("/pullrequests/%d/comments?pagelen=%d", number, bitbucketPageSize))
+	for page := 0; page < maxReviewPages && next != ""; page++ {
+		var resp struct {
+			Values []bitbucketCloudComment `json:"values"`
+			Next   string                  `json:"next"`
+		}
+		if err := c.do(ctx, http.MethodGet, next, nil, &resp); err != nil {
+			return nil, fmt.Errorf("bitbucket api request failed: %w", err)
+		}
+		comments = append(comments, resp.Values...)
+		next = resp.Next
+	}
+	sort.SliceStable(comments, func(i, j int) bool { return comments[i].CreatedOn.Before(comments[j].CreatedOn) })
+
+	parents := make(map[int]int, len(comments))
+	for _, bc := range comments {
+		if bc.Parent != nil {
+			parents[bc.ID] = bc.Parent.ID
+		}
+	}
+	root := func(id int) int {
+		for range len(parents) {
+			parent, ok := parents[id]
+			if !ok {
+				break
+			}
+			id = parent
+		}
+		return id
+	}
+
+	index := make(map[int]int)
+	var threads []ReviewThread
+	for _, bc := range comments {
+		if bc.Parent == nil && bc.Inline != nil {
+			line := bc.Inline.To
+			if line == 0 {
+				line = bc.Inline.From
+			}
+			index[bc.ID] = len(threads)
+			threads = append(threads, ReviewThread{
+				Path:     bc.Inline.Path,
+				Line:     line,
+				Resolved: bc.Resolution != nil,
+				Outdated: bc.Inline.Outdated,
+				URL:      bc.Links.HTML.Href,
+			})
+		}
+		i, ok := index[root(bc.ID)]
+		if !ok || bc.Deleted {
+			continue
+		}
+		author := bc.User.Nickname
+		if author == "" {
+			author = bc.User.DisplayName
+		}
+		threads[i].Comments = append(threads[i].Comments, ReviewComment{
+			Author:    author,
+			Body:      bc.Content.Raw,
+			CreatedAt: bc.CreatedOn,
+		})
+	}
+
+	sortReviewThreads(threads)
+	return threads, nil
+}
+
+// ViewPR shows PR details or opens in browser
+func (b *BitbucketCloud) ViewPR(ctx context.Context, repoURL string, number int, web bool) error {
+	repoPath := ExtractRepoPath(repoURL)
+	c, err := b.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	pr, err := b.getPR(ctx, c, repoPath, number)
+	if err != nil {
+		return fmt.Errorf("bitbucket api request failed: %w", err)
+	}
+
+	if web {
+		return browser.Open(ctx, pr.Links.HTML.Href)
+	}
+
+	state := normalizeBitbucketState(pr.State)
+	if pr.Draft && state == PRStateOpen {
+		state = PRStateDraft
+	}
+	printPRDetails(os.Stdout, pr.Title, fmt.Sprintf("#%d", pr.ID), b.FormatState(state), pr.author(), pr.Links.HTML.Href, pr.Description)
+	return nil
+}
+
+// ListOpenPRs lists all open PRs for a repository
+func (b *BitbucketCloud) ListOpenPRs(ctx context.Context, repoURL string) ([]OpenPR, error) {
+	repoPath := ExtractRepoPath(repoURL)
+	c, err := b.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	prs, _, err := b.listPRs(ctx, c, repoPath, url.Values{"state": {"OPEN"}}, listLimit/bitbucketPageSize)
+	if err != nil {
+		return nil, fmt.Errorf("bitbucket api request failed: %w", err)
+	}
+
+	result := make([]OpenPR, len(prs))
+	for i, pr := range prs {
+		result[i] = newOpenPR(pr.toPRInfo(), pr.Source.Branch.Name)
+		result[i].HeadRepo, result[i].IsFork = pr.fork()
+	}
+
+	return result, nil
+}
+
+// CurrentUser returns the nickname of the token's user. Repository and
+// workspace access tokens have no user and fail here.
+func (b *BitbucketCloud) CurrentUser(ctx context.Context, repoURL string) (string, error) {
+	c, err := b.client(ctx)
+	if err != nil {
+		return "", err
+	}
+	var user struct {
+		Nickname    string `json:"nickname"`
+		DisplayName string `json:"display_name"`
+	}
+	if err := c.do(ctx, http.MethodGet, b.apiURL("/user"), nil, &user); err != nil {
+		return "", fmt.Errorf("bitbucket api request failed: %w", err)
+	}
+	if user.Nickname != "" {
+		return user.Nickname, nil
+	}
+	return user.DisplayName, nil
+}
+
+// PRHeadRef returns "": Bitbucket Cloud doesn't expose PR heads as refs
+func (b *BitbucketCloud) PRHeadRef(number int) string {
+	return ""
+}
+
+// FormatState returns a human-readable PR state
+func (b *BitbucketCloud) FormatState(state string) string {
+	return (&GitLab{}).FormatState(state)
+}
+
+// normalizeBitbucketState converts a Bitbucket Cloud or Data Center PR state
+// to the normalized format. Declined and superseded PRs count as closed.
+func normalizeBitbucketState(state string) string {
+	switch strings.ToUpper(state) {
+	case "OPEN":
+		return PRStateOpen
+	case "MERGED":
+		return PRStateMerged
+	case "DECLINED", "SUPERSEDED":
+		return PRStateClosed
+	default:
+		return strings.ToUpper(state)
+	}
+}
@@ -0,0 +1,617 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/raphi011/wt/internal/browser"
+	"github.com/raphi011/wt/internal/config"
+)
+
+// BitbucketDataCenter implements Forge for self-hosted Bitbucket Data Center
+// (formerly Bitbucket Server) repositories using the REST API. Repositories
+// are addressed as PROJECT/repo; personal repositories as ~USER/repo.
+type BitbucketDataCenter struct {
+	ForgeConfig *config.ForgeConfig
+	Host        string       // Bitbucket host (required unless WebURL or BaseURL is set)
+	WebURL      string       // instance URL (scheme://host[:port][/context]); derived from Host when empty
+	BaseURL     string       // REST root override (tests), e.g. https://host/rest; derived from WebURL when empty
+	HTTPClient  *http.Client // optional; defaults to a client with apiTimeout
+}
+
+// Name returns "bitbucket"
+func (b *BitbucketDataCenter) Name() string {
+	return "bitbucket"
+}
+
+// webURL returns the instance URL without trailing slash.
+func (b *BitbucketDataCenter) webURL() string {
+	if b.WebURL != "" {
+		return strings.TrimSuffix(b.WebURL, "/")
+	}
+	return "https://" + b.Host
+}
+
+// restURL returns the URL of path (which must start with "/") in the REST
+// API named api, e.g. "api" or "branch-utils".
+func (b *BitbucketDataCenter) restURL(api, path string) string {
+	base := b.webURL() + "/rest"
+	if b.BaseURL != "" {
+		base = strings.TrimSuffix(b.BaseURL, "/")
+	}
+	return base + "/" + api + "/1.0" + path
+}
+
+// repoURL returns the URL in the REST API named api for a path below
+// /projects/{project}/repos/{repo}.
+func (b *BitbucketDataCenter) repoURL(api, repoPath, path string) string {
+	project, repo, _ := strings.Cut(repoPath, "/")
+	return b.restURL(api, "/projects/"+url.PathEscape(project)+"/repos/"+url.PathEscape(repo)+path)
+}
+
+// client returns an authenticated API client.
+func (b *BitbucketDataCenter) client(ctx context.Context) (*apiClient, error) {
+	if b.Host == "" && b.WebURL == "" && b.BaseURL == "" {
+		return nil, fmt.Errorf("no host configured for bitbucket: add it to [hosts] or set host in the matching [[forge.rules]]")
+	}
+	return bitbucketClient(ctx, b.ForgeConfig, b.Host, b.HTTPClient)
+}
+
+// Check verifies that an API token is available. Like [BitbucketCloud.Check]
+// the token is validated by the first actual request, since project and
+// repository tokens have no user.
+func (b *BitbucketDataCenter) Check(ctx context.Context) error {
+	_, err := b.client(ctx)
+	return err
+}
+
+// bitbucketDCRef is the REST shape of a pull request's fromRef or toRef.
+type bitbucketDCRef struct {
+	ID         string `json:"id"`        // refs/heads/feature
+	DisplayID  string `json:"displayId"` // feature
+	Repository struct {
+		ID      int64  `json:"id"`
+		Slug    string `json:"slug"`
+		Project struct {
+			Key string `json:"key"` // ~USER for personal forks
+		} `json:"project"`
+	} `json:"repository"`
+}
+
+// bitbucketDCUser is the REST shape of a user.
+type bitbucketDCUser struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+// bitbucketDCPR is the REST shape of a pull request.
+type bitbucketDCPR struct {
+	ID          int    `json:"id"`
+	Version     int    `json:"version"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	State       string `json:"state"` // OPEN, MERGED, DECLINED
+	Draft       bool   `json:"draft"` // Data Center 8.18+
+	Author      struct {
+		User bitbucketDCUser `json:"user"`
+	} `json:"author"`
+	UpdatedDate int64 `json:"updatedDate"` // milliseconds since the epoch
+	Reviewers   []struct {
+		User     bitbucketDCUser `json:"user"`
+		Approved bool            `json:"approved"`
+		Status   string          `json:"status"` // APPROVED, NEEDS_WORK, UNAPPROVED
+	} `json:"reviewers"`
+	FromRef    bitbucketDCRef `json:"fromRef"`
+	ToRef      bitbucketDCRef `json:"toRef"`
+	Properties struct {
+		CommentCount int `json:"commentCount"`
+	} `json:"properties"`
+	Links struct {
+		Self []struct {
+			Href string `json:"href"`
+		} `json:"self"`
+	} `json:"links"`
+}
+
+// url returns the PR's web URL.
+func (pr bitbucketDCPR) url() string {
+	if len(pr.Links.Self) == 0 {
+		return ""
+	}
+	return pr.Links.Self[0].Href
+}
+
+// branch returns the PR's source branch name.
+func (pr bitbucketDCPR) branch() string {
+	if pr.FromRef.DisplayID != "" {
+		return pr.FromRef.DisplayID
+	}
+	return strings.TrimPrefix(pr.FromRef.ID, "refs/heads/")
+}
+
+// fork returns the source repository (PROJECT/repo, empty if unknown) and
+// whether the PR is from a fork.
+func (pr bitbucketDCPR) fork() (repoPath string, isFork bool) {
+	from := pr.FromRef.Repository
+	if from.ID == 0 || pr.ToRef.Repository.ID == 0 || from.ID == pr.ToRef.Repository.ID {
+		return "", false
+	}
+	if from.Project.Key != "" && from.Slug != "" {
+		repoPath = from.Project.Key + "/" + from.Slug
+	}
+	return repoPath, true
+}
+
+// toPRInfo converts a REST pull request to PRInfo. Labels and build status
+// aren't part of the pull request resource, so those stay empty.
+func (pr bitbucketDCPR) toPRInfo() *PRInfo {
+	info := &PRInfo{
+		Number:       pr.ID,
+		State:        normalizeBitbucketState(pr.State),
+		IsDraft:      pr.Draft,
+		URL:          pr.url(),
+		Author:       pr.Author.User.Name,
+		CommentCount: pr.Properties.CommentCount,
+		CachedAt:     time.Now(),
+		Fetched:      true,
+		Title:        pr.Title,
+		BaseBranch:   pr.ToRef.DisplayID,
+	}
+	if pr.UpdatedDate > 0 {
+		info.UpdatedAt = time.UnixMilli(pr.UpdatedDate)
+	}
+	for _, r := range pr.Reviewers {
+		if r.Status == "APPROVED" || r.Status == "NEEDS_WORK" {
+			info.HasReviews = true
+		} else {
+			info.ReviewRequested = append(info.ReviewRequested, r.User.Name)
+		}
+		if r.Approved {
+			info.IsApproved = true
+		}
+	}
+	return info
+}
+
+// listPRs lists pull requests matching query, newest first, for up to
+// maxPages pages. complete reports whether all PRs were read.
+func (b *BitbucketDataCenter) listPRs(ctx context.Context, c *apiClient, repoPath string, query url.Values, maxPages int) (prs []bitbucketDCPR, complete bool, err error) {
+	query.Set("order", "NEWEST")
+	if query.Get("limit") == "" {
+		query.Set("limit", strconv.Itoa(bitbucketPageSize))
+	}
+
+	start := 0
+	for page := 0; page < maxPages; page++ {
+		query.Set("start", strconv.Itoa(start))
+		var resp struct {
+			Values        []bitbucketDCPR `json:"values"`
+			IsLastPage    bool            `json:"isLastPage"`
+			NextPageStart int             `json:"nextPageStart"`
+		}
+		if err := c.do(ctx, http.MethodGet, b.repoURL("api", repoPath, "/pull-requests?"+query.Encode()), nil, &resp); err != nil {
+			return nil, false, err
+		}
+		prs = append(prs, resp.Values...)
+		if resp.IsLastPage {
+			return prs, true, nil
+		}
+		start = resp.NextPageStart
+	}
+	return prs, false, nil
+}
+
+// GetPRForBranch fetches PR info for a branch
+func (b *BitbucketDataCenter) GetPRForBranch(ctx context.Context, repoURL, branch string) (*PRInfo, error) {
+	repoPath := bitbucketDCRepoPath(repoURL)
+	c, err := b.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{
+		"state":     {"ALL"},
+		"at":        {"refs/heads/" + branch},
+		"direction": {"OUTGOING"},
+		"limit":     {"1"},
+	}
+	prs, _, err := b.listPRs(ctx, c, repoPath, query, 1)
+	if err != nil {
+		return nil, fmt.Errorf("bitbucket api request failed: %w", err)
+	}
+
+	if len(prs) == 0 {
+		// No PR found - return marker indicating we checked
+		return noPR(), nil
+	}
+	return prs[0].toPRInfo(), nil
+}
+
+// GetPRsForBranches lists the repository's most recent PRs (all states) and
+// matches them to branches locally. Branches not covered by a truncated
+// listing fall back to GetPRForBranch.
+func (b *BitbucketDataCenter) GetPRsForBranches(ctx context.Context, repoURL string, branches []string) (map[string]*PRInfo, error) {
+	repoPath := bitbucketDCRepoPath(repoURL)
+	c, err := b.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	list, complete, err := b.listPRs(ctx, c, repoPath, url.Values{"state": {"ALL"}}, listLimit/bitbucketPageSize)
+	if err != nil {
+		return nil, fmt.Errorf("bitbucket api request failed: %w", err)
+	}
+
+	prs := make([]branchPR, len(list))
+	for i, pr := range list {
+		prs[i] = branchPR{Branch: pr.branch(), Info: pr.toPRInfo()}
+	}
+
+	return matchBranchPRs(ctx, branches, prs, complete, func(ctx context.Context, branch string) (*PRInfo, error) {
+		return b.GetPRForBranch(ctx, repoURL, branch)
+	})
+}
+
+// getPR fetches a single pull request.
+func (b *BitbucketDataCenter) getPR(ctx context.Context, c *apiClient, repoPath string, number int) (*bitbucketDCPR, error) {
+	var pr bitbucketDCPR
+	if err := c.do(ctx, http.MethodGet, b.repoURL("api", repoPath, fmt.Sprintf("/pull-requests/%d", number)), nil, &pr); err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+// GetPRHead fetches the head branch and repository for a PR number.
+// Bitbucket Data Center doesn't let maintainers push to forks.
+func (b *BitbucketDataCenter) GetPRHead(ctx context.Context, repoURL string, number int) (*PRHead, error) {
+	repoPath := bitbucketDCRepoPath(repoURL)
+	c, err := b.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, err := b.getPR(ctx, c, repoPath, number)
+	if err != nil {
+		return nil, fmt.Errorf("bitbucket api request failed: %w", err)
+	}
+
+	branch := pr.branch()
+	if branch == "" {
+		return nil, fmt.Errorf("PR #%d has no head branch", number)
+	}
+
+	if forkPath, isFork := pr.fork(); isFork {
+		head := newForkHead(repoURL, branch, forkPath, b.cloneURL(forkPath), false)
+		// Keep the scm/ prefix of HTTP clone URLs on hosts not named bitbucket.*
+		if cloneURL := replaceRepoPath(repoURL, bitbucketDCRepoPath(repoURL), forkPath); cloneURL != "" {
+			head.CloneURL = cloneURL
+		}
+		return head, nil
+	}
+
+	return &PRHead{Branch: branch}, nil
+}
+
+// cloneURL returns the HTTPS clone URL for a PROJECT/repo spec.
+func (b *BitbucketDataCenter) cloneURL(repoSpec string) string {
+	return b.webURL() + "/scm/" + repoSpec + ".git"
+}
+
+// CloneRepo clones a Bitbucket Data Center repo over HTTPS using git
+func (b *BitbucketDataCenter) CloneRepo(ctx context.Context, repoSpec, destPath string) (string, error) {
+	repoName, err := validateBitbucketSpec(repoSpec)
+	if err != nil {
+		return "", err
+	}
+	clonePath := filepath.Join(destPath, repoName)
+
+	if err := cloneWithGit(ctx, b.cloneURL(repoSpec), clonePath, false); err != nil {
+		return "", err
+	}
+	return clonePath, nil
+}
+
+// CloneBareRepo clones a Bitbucket Data Center repo as a bare repo inside .git directory
+func (b *BitbucketDataCenter) CloneBareRepo(ctx context.Context, repoSpec, destPath string) (string, error) {
+	repoName, err := validateBitbucketSpec(repoSpec)
+	if err != nil {
+		return "", err
+	}
+	repoDir := filepath.Join(destPath, repoName)
+
+	if err := cloneWithGit(ctx, b.cloneURL(repoSpec), repoDir, true); err != nil {
+		return "", err
+	}
+	return repoDir, nil
+}
+
+// CreatePR creates a new PR
+func (b *BitbucketDataCenter) CreatePR(ctx context.Context, repoURL string, params CreatePRParams) (*CreatePRResult, error) {
+	repoPath := bitbucketDCRepoPath(repoURL)
+	if params.Head == "" {
+		return nil, fmt.Errorf("head branch is required")
+	}
+	c, err := b.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	base := params.Base
+	if base == "" {
+		var ref bitbucketDCRef
+		if err := c.do(ctx, http.MethodGet, b.repoURL("api", repoPath, "/default-branch"), nil, &ref); err != nil {
+			return nil, fmt.Errorf("failed to determine default branch: %w", err)
+		}
+		base = ref.DisplayID
+	}
+
+	body := map[string]any{
+		"title":       params.Title,
+		"description": params.Body,
+		"fromRef":     map[string]string{"id": "refs/heads/" + params.Head},
+		"toRef":       map[string]string{"id": "refs/heads/" + base},
+	}
+	if params.Draft {
+		body["draft"] = true
+	}
+
+	var pr bitbucketDCPR
+	if err := c.do(ctx, http.MethodPost, b.repoURL("api", repoPath, "/pull-requests"), body, &pr); err != nil {
+		return nil, fmt.Errorf("create PR failed: %w", err)
+	}
+
+	return &CreatePRResult{
+		Number: pr.ID,
+		URL:    pr.url(),
+	}, nil
+}
+
+// bitbucketDCMergeStrategies maps wt's merge strategies to Bitbucket Data
+// Center strategy IDs. The strategy must be enabled for the repository.
+var bitbucketDCMergeStrategies = map[string]string{
+	"merge":  "no-ff",
+	"squash": "squash",
+	"rebase": "rebase-ff-only",
+}
+
+// MergePR merges a PR by number with the given strategy and deletes the
+// source branch. Data Center has no delete-on-merge option, so the branch is
+// deleted with a separate request; failing to delete it is not an error.
+func (b *BitbucketDataCenter) MergePR(ctx context.Context, repoURL string, number int, strategy string) error {
+	repoPath := bitbucketDCRepoPath(repoURL)
+	strategyID, ok := bitbucketDCMergeStrategies[strategy]
+	if !ok {
+		return fmt.Errorf("unsupported merge strategy %q for bitbucket: use squash, rebase or merge", strategy)
+	}
+	c, err := b.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Merging requires the PR's current version (optimistic locking)
+	pr, err := b.getPR(ctx, c, repoPath, number)
+	if err != nil {
+		return fmt.Errorf("bitbucket api request failed: %w", err)
+	}
+
+	mergeURL := b.repoURL("api", repoPath, fmt.Sprintf("/pull-requests/%d/merge?version=%d", number, pr.Version))
+	if err := c.do(ctx, http.MethodPost, mergeURL, map[string]any{"strategyId": strategyID}, nil); err != nil {
+		return fmt.Errorf("merge failed: %w", err)
+	}
+
+	if pr.FromRef.ID != "" {
+		body := map[string]any{"name": pr.FromRef.ID, "dryRun": false}
+		_ = c.do(ctx, http.MethodDelete, b.repoURL("branch-utils", repoPath, "/branches"), body, nil) //nolint:errcheck // branch may be protected or already gone
+	}
+	return nil
+}
+
+// UpdatePRBase changes the target branch of a PR
+func (b *BitbucketDataCenter) UpdatePRBase(ctx context.Context, repoURL string, number int, base string) error {
+	repoPath := bitbucketDCRepoPath(repoURL)
+	c, err := b.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Updating requires the PR's current version (optimistic locking)
+	pr, err := b.getPR(ctx, c, repoPath, number)
+	if err != nil {
+		return fmt.Errorf("bitbucket api request failed: %w", err)
+	}
+
+	if err := c.do(ctx, http.MethodPut, b.repoURL("api", repoPath, fmt.Sprintf("/pull-requests/%d", number)), map[string]any{
+		"version": pr.Version,
+		"toRef":   map[string]any{"id": "refs/heads/" + base},
+	}, nil); err != nil {
+		return fmt.Errorf("update target branch failed: %w", err)
+	}
+	return nil
+}
+
+// bitbucketDCComment is the REST shape of a comment with its nested replies.
+type bitbucketDCComment struct {
+	Text           string               `json:"text"`
+	Author         bitbucketDCUser      `json:"author"`
+	CreatedDate    int64                `json:"createdDate"` // milliseconds since the epoch
+	State          string               `json:"state"`       // OPEN, RESOLVED (8.x)
+	ThreadResolved bool                 `json:"threadResolved"`
+	Comments       []bitbucketDCComment `json:"comments"`
+}
+
+// flatten appends the comment and its replies, depth first, to comments.
+func (c bitbucketDCComment) flatten(comments []ReviewComment) []ReviewComment {
+	comment := ReviewComment{Author: c.Author.Name, Body: c.Text}
+	if c.CreatedDate > 0 {
+		comment.CreatedAt = time.UnixMilli(c.CreatedDate)
+	}
+	comments = append(comments, comment)
+	for _, reply := range c.Comments {
+		comments = reply.flatten(comments)
+	}
+	return comments
+}
+
+// ListReviewThreads lists the file comment threads of a PR, taken from the
+// comments added in its activity stream.
+func (b *BitbucketDataCenter) ListReviewThreads(ctx context.Context, repoURL string, number int) ([]ReviewThread, error) {
+	repoPath := bitbucketDCRepoPath(repoURL)
+	c, err := b.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var threads []ReviewThread
+	start := 0
+	for page := 0; page < maxReviewPages; page++ {
+		query := url.Values{"start": {strconv.Itoa(start)}, "limit": {strconv.Itoa(bitbucketPageSize)}}
+		var resp struct {
+			Values []struct {
+				Action        string              `json:"action"`
+				CommentAction string              `json:"commentAction"`
+				Comment       *bitbucketDCComment `json:"comment"`
+				CommentAnchor *struct {
+					Path     string `json:"path"`
+					Line     int    `json:"line"`
+					Orphaned bool   `json:"orphaned"` // the commented line no longer exists
+				} `json:"commentAnchor"`
+			} `json:"values"`
+			IsLastPage    bool `json:"isLastPage"`
+			NextPageStart int  `json:"nextPageStart"`
+		}
+		if err := c.do(ctx, http.MethodGet, b.repoURL("api", repoPath, fmt.Sprintf("/pull-requests/%d/activities?%s", number, query.Encode())), nil, &resp); err != nil {
+			return nil, fmt.Errorf("bitbucket api request failed: %w", err)
+		}
+
+		for _, a := range resp.Values {
+			if a.Action != "COMMENTED" || a.CommentAction != "ADDED" || a.Comment == nil || a.CommentAnchor == nil || a.CommentAnchor.Path == "" {
+				continue
+			}
+			threads = append(threads, ReviewThread{
+				Path:     a.CommentAnchor.Path,
+				Line:     a.CommentAnchor.Line,
+				Resolved: a.Comment.ThreadResolved || a.Comment.State == "RESOLVED",
+				Outdated: a.CommentAnchor.Orphaned,
+				Comments: a.Comment.flatten(nil),
+			})
+		}
+		if resp.IsLastPage {
+			break
+		}
+		start = resp.NextPageStart
+	}
+
+	sortReviewThreads(threads)
+	return threads, nil
+}
+
+// ViewPR shows PR details or opens in browser
+func (b *BitbucketDataCenter) ViewPR(ctx context.Context, repoURL string, number int, web bool) error {
+	repoPath := bitbucketDCRepoPath(repoURL)
+	c, err := b.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	pr, err := b.getPR(ctx, c, repoPath, number)
+	if err != nil {
+		return fmt.Errorf("bitbucket api request failed: %w", err)
+	}
+
+	if web {
+		return browser.Open(ctx, pr.url())
+	}
+
+	state := normalizeBitbucketState(pr.State)
+	if pr.Draft && state == PRStateOpen {
+		state = PRStateDraft
+	}
+	printPRDetails(os.Stdout, pr.Title, fmt.Sprintf("#%d", pr.ID), b.FormatState(state), pr.Author.User.Name, pr.url(), pr.Description)
+	return nil
+}
+
+// ListOpenPRs lists all open PRs for a repository
+func (b *BitbucketDataCenter) ListOpenPRs(ctx context.Context, repoURL string) ([]OpenPR, error) {
+	repoPath := bitbucketDCRepoPath(repoURL)
+	c, err := b.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	prs, _, err := b.listPRs(ctx, c, repoPath, url.Values{"state": {"OPEN"}}, listLimit/bitbucketPageSize)
+	if err != nil {
+		return nil, fmt.Errorf("bitbucket api request failed: %w", err)
+	}
+
+	result := make([]OpenPR, len(prs))
+	for i, pr := range prs {
+		result[i] = newOpenPR(pr.toPRInfo(), pr.branch())
+		result[i].HeadRepo, result[i].IsFork = pr.fork()
+	}
+
+	return result, nil
+}
+
+// CurrentUser returns the name of the token's user. Data Center has no REST
+// resource for this; the applinks whoami servlet returns it as plain text.
+func (b *BitbucketDataCenter) CurrentUser(ctx context.Context, repoURL string) (string, error) {
+	c, err := b.client(ctx)
+	if err != nil {
+		return "", err
+	}
+	base := b.webURL()
+	if b.BaseURL != "" {
+		base = strings.TrimSuffix(strings.TrimSuffix(b.BaseURL, "/"), "/rest")
+	}
+	var name string
+	if err := c.do(ctx, http.MethodGet, base+"/plugins/servlet/applinks/whoami", nil, &name); err != nil {
+		return "", fmt.Errorf("bitbucket api request failed: %w", err)
+	}
+	if name = strings.TrimSpace(name); name == "" {
+		return "", fmt.Errorf("bitbucket: token has no user")
+	}
+	return name, nil
+}
+
+// PRHeadRef returns the ref Bitbucket Data Center keeps the head of a PR under
+func (b *BitbucketDataCenter) PRHeadRef(number int) string {
+	return fmt.Sprintf("refs/pull-requests/%d/from", number)
+}
+
+// FormatState returns a human-readable PR state
+func (b *BitbucketDataCenter) FormatState(state string) string {
+	return (&GitLab{}).FormatState(state)
+}
+
+// bitbucketDCRepoPath returns the PROJECT/repo path of a Data Center remote
+// URL. Unlike [ExtractRepoPath], it strips the "scm/" prefix of HTTP clone
+// URLs on any host, as instances may be mapped to bitbucket in [hosts].
+func bitbucketDCRepoPath(url string) string {
+	return trimBitbucketSCM(extractRepoPath(url))
+}
+
+// bitbucketDCWebURL returns the instance URL for an HTTP(S) remote, keeping
+// its scheme, port and any context path before /scm/
+// (https://host/bitbucket/scm/PROJ/repo.git -> https://host/bitbucket).
+// Returns "" for SSH remotes, which use a different port than the web UI.
+func bitbucketDCWebURL(remoteURL string) string {
+	web := remoteWebURL(remoteURL)
+	if web == "" {
+		return ""
+	}
+	u, err := url.Parse(remoteURL)
+	if err != nil {
+		return web
+	}
+	if i := strings.Index(u.Path, "/scm/"); i > 0 {
+		return web + u.Path[:i]
+	}
+	return web
+}
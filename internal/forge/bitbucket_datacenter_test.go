@@ -0,0 +1,374 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/raphi011/wt/internal/config"
+)
+
+// newTestBitbucketDC returns a BitbucketDataCenter forge pointed at an
+// httptest server running handler (mounted at the REST root). Requests
+// without the configured token are rejected with 401.
+func newTestBitbucketDC(t *testing.T, handler http.HandlerFunc) *BitbucketDataCenter {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			io.WriteString(w, `{"errors":[{"message":"Authentication failed. Please check your credentials and try again."}]}`)
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	return &BitbucketDataCenter{
+		ForgeConfig: &config.ForgeConfig{Tokens: map[string]string{"bitbucket.test": "test-token"}},
+		Host:        "bitbucket.test",
+		BaseURL:     srv.URL,
+	}
+}
+
+func TestBitbucketDC_GetPRForBranch(t *testing.T) {
+	t.Parallel()
+
+	b := newTestBitbucketDC(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/1.0/projects/PROJ/repos/repo/pull-requests" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		q := r.URL.Query()
+		if q.Get("state") != "ALL" || q.Get("direction") != "OUTGOING" {
+			t.Errorf("unexpected query %v", q)
+		}
+		switch q.Get("at") {
+		case "refs/heads/feature":
+			io.WriteString(w, `{"isLastPage":true,"values":[{"id":7,"state":"OPEN","draft":true,
+				"author":{"user":{"name":"bob","displayName":"Bob"}},
+				"reviewers":[{"approved":true,"status":"APPROVED"}],
+				"properties":{"commentCount":4},
+				"fromRef":{"id":"refs/heads/feature","displayId":"feature"},
+				"links":{"self":[{"href":"https://bitbucket.test/projects/PROJ/repos/repo/pull-requests/7"}]}}]}`)
+		default:
+			io.WriteString(w, `{"isLastPage":true,"values":[]}`)
+		}
+	})
+
+	ctx := context.Background()
+
+	pr, err := b.GetPRForBranch(ctx, "https://bitbucket.test/scm/PROJ/repo.git", "feature")
+	if err != nil {
+		t.Fatalf("GetPRForBranch() error = %v", err)
+	}
+	want := PRInfo{Number: 7, State: PRStateOpen, IsDraft: true, URL: "https://bitbucket.test/projects/PROJ/repos/repo/pull-requests/7",
+		Author: "bob", CommentCount: 4, HasReviews: true, IsApproved: true, Fetched: true}
+	pr.CachedAt = want.CachedAt
+	if !reflect.DeepEqual(*pr, want) {
+		t.Errorf("GetPRForBranch() = %+v, want %+v", *pr, want)
+	}
+
+	pr, err = b.GetPRForBranch(ctx, "https://bitbucket.test/scm/PROJ/repo.git", "no-pr")
+	if err != nil {
+		t.Fatalf("GetPRForBranch() error = %v", err)
+	}
+	if pr.Number != 0 || !pr.Fetched {
+		t.Errorf("expected fetched marker without PR, got %+v", *pr)
+	}
+}
+
+func TestBitbucketDC_GetPRsForBranches_Paginates(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var starts []string
+	b := newTestBitbucketDC(t, func(w http.ResponseWriter, r *http.Request) {
+		start := r.URL.Query().Get("start")
+		mu.Lock()
+		starts = append(starts, start)
+		mu.Unlock()
+		switch start {
+		case "0":
+			io.WriteString(w, `{"isLastPage":false,"nextPageStart":50,"values":[
+				{"id":12,"state":"DECLINED","fromRef":{"displayId":"feat-a"}}]}`)
+		default:
+			io.WriteString(w, `{"isLastPage":true,"values":[
+				{"id":3,"state":"MERGED","fromRef":{"id":"refs/heads/feat-b"}}]}`)
+		}
+	})
+
+	prs, err := b.GetPRsForBranches(context.Background(), "ssh://git@bitbucket.test:7999/PROJ/repo.git", []string{"feat-a", "feat-b", "feat-c"})
+	if err != nil {
+		t.Fatalf("GetPRsForBranches() error = %v", err)
+	}
+	if strings.Join(starts, ",") != "0,50" {
+		t.Errorf("requested starts %v, want 0,50", starts)
+	}
+	if prs["feat-a"].Number != 12 || prs["feat-a"].State != PRStateClosed {
+		t.Errorf("feat-a = %+v, want closed PR #12", *prs["feat-a"])
+	}
+	if prs["feat-b"].State != PRStateMerged {
+		t.Errorf("feat-b state = %q, want %q", prs["feat-b"].State, PRStateMerged)
+	}
+	if pr := prs["feat-c"]; pr.Number != 0 || !pr.Fetched {
+		t.Errorf("feat-c = %+v, want fetched marker", *pr)
+	}
+}
+
+func TestBitbucketDC_GetPRHead(t *testing.T) {
+	t.Parallel()
+
+	b := newTestBitbucketDC(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/1.0/projects/PROJ/repos/repo/pull-requests/1":
+			io.WriteString(w, `{"id":1,"fromRef":{"displayId":"feature","repository":{"id":10}},"toRef":{"displayId":"main","repository":{"id":10}}}`)
+		case "/api/1.0/projects/PROJ/repos/repo/pull-requests/2":
+			io.WriteString(w, `{"id":2,"fromRef":{"displayId":"patch","repository":{"id":99,"slug":"repo","project":{"key":"~JDOE"}}},
+				"toRef":{"displayId":"main","repository":{"id":10}}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `{"errors":[{"message":"Pull request 3 does not exist in PROJ/repo."}]}`)
+		}
+	})
+
+	ctx := context.Background()
+
+	head, err := b.GetPRHead(ctx, "https://bitbucket.test/scm/PROJ/repo.git", 1)
+	if err != nil || !reflect.DeepEqual(*head, PRHead{Branch: "feature"}) {
+		t.Errorf("GetPRHead(1) = %+v, %v; want branch feature", head, err)
+	}
+
+	head, err = b.GetPRHead(ctx, "https://bitbucket.test/scm/PROJ/repo.git", 2)
+	want := PRHead{Branch: "patch", IsFork: true, RepoPath: "~JDOE/repo", CloneURL: "https://bitbucket.test/scm/~JDOE/repo.git"}
+	if err != nil || !reflect.DeepEqual(*head, want) {
+		t.Errorf("GetPRHead(2) = %+v, %v; want %+v", head, err, want)
+	}
+
+	// Instances mapped in [hosts] keep scm/ in the fork's clone URL
+	head, err = b.GetPRHead(ctx, "https://jdoe@git.legacy.corp/scm/PROJ/repo.git", 2)
+	want.CloneURL = "https://jdoe@git.legacy.corp/scm/~JDOE/repo.git"
+	if err != nil || !reflect.DeepEqual(*head, want) {
+		t.Errorf("GetPRHead(2) on mapped host = %+v, %v; want %+v", head, err, want)
+	}
+
+	if _, err := b.GetPRHead(ctx, "https://bitbucket.test/scm/PROJ/repo.git", 3); err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Errorf("GetPRHead(3) should fail with the API message, got %v", err)
+	}
+}
+
+func TestBitbucketDC_CreatePR(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	b := newTestBitbucketDC(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/1.0/projects/PROJ/repos/repo/default-branch":
+			io.WriteString(w, `{"id":"refs/heads/develop","displayId":"develop"}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/api/1.0/projects/PROJ/repos/repo/pull-requests":
+			json.NewDecoder(r.Body).Decode(&got)
+			w.WriteHeader(http.StatusCreated)
+			io.WriteString(w, `{"id":8,"links":{"self":[{"href":"https://bitbucket.test/projects/PROJ/repos/repo/pull-requests/8"}]}}`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	})
+
+	result, err := b.CreatePR(context.Background(), "https://bitbucket.test/scm/PROJ/repo.git", CreatePRParams{
+		Title: "Add feature",
+		Body:  "Details",
+		Head:  "feature",
+	})
+	if err != nil {
+		t.Fatalf("CreatePR() error = %v", err)
+	}
+	if result.Number != 8 || result.URL != "https://bitbucket.test/projects/PROJ/repos/repo/pull-requests/8" {
+		t.Errorf("CreatePR() = %+v, want #8", *result)
+	}
+	fromRef, _ := got["fromRef"].(map[string]any)
+	toRef, _ := got["toRef"].(map[string]any)
+	if fromRef["id"] != "refs/heads/feature" || toRef["id"] != "refs/heads/develop" || got["title"] != "Add feature" {
+		t.Errorf("unexpected request body: %v", got)
+	}
+	if _, ok := got["draft"]; ok {
+		t.Errorf("draft should be omitted for non-draft PRs: %v", got)
+	}
+}
+
+func TestBitbucketDC_MergePR(t *testing.T) {
+	t.Parallel()
+
+	var merged map[string]any
+	var mergeQuery, deleted string
+	b := newTestBitbucketDC(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/1.0/projects/PROJ/repos/repo/pull-requests/4":
+			io.WriteString(w, `{"id":4,"version":3,"fromRef":{"id":"refs/heads/feature","displayId":"feature"}}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/api/1.0/projects/PROJ/repos/repo/pull-requests/4/merge":
+			mergeQuery = r.URL.RawQuery
+			json.NewDecoder(r.Body).Decode(&merged)
+		case r.Method == http.MethodDelete && r.URL.Path == "/branch-utils/1.0/projects/PROJ/repos/repo/branches":
+			var body map[string]any
+			json.NewDecoder(r.Body).Decode(&body)
+			deleted = fmt.Sprint(body["name"])
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	})
+
+	if err := b.MergePR(context.Background(), "https://bitbucket.test/scm/PROJ/repo.git", 4, "squash"); err != nil {
+		t.Fatalf("MergePR() error = %v", err)
+	}
+	if mergeQuery != "version=3" {
+		t.Errorf("merge query = %q, want version=3", mergeQuery)
+	}
+	if merged["strategyId"] != "squash" {
+		t.Errorf("unexpected merge body: %v", merged)
+	}
+	if deleted != "refs/heads/feature" {
+		t.Errorf("deleted branch = %q, want refs/heads/feature", deleted)
+	}
+}
+
+func TestBitbucketDC_UpdatePRBase(t *testing.T) {
+	t.Parallel()
+
+	var got struct {
+		Version int `json:"version"`
+		ToRef   struct {
+			ID string `json:"id"`
+		} `json:"toRef"`
+	}
+	b := newTestBitbucketDC(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/1.0/projects/PROJ/repos/repo/pull-requests/4":
+			io.WriteString(w, `{"id":4,"version":3}`)
+		case r.Method == http.MethodPut && r.URL.Path == "/api/1.0/projects/PROJ/repos/repo/pull-requests/4":
+			json.NewDecoder(r.Body).Decode(&got)
+			io.WriteString(w, `{"id":4,"version":4}`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	})
+
+	if err := b.UpdatePRBase(context.Background(), "https://bitbucket.test/scm/PROJ/repo.git", 4, "main"); err != nil {
+		t.Fatalf("UpdatePRBase() error = %v", err)
+	}
+	if got.Version != 3 || got.ToRef.ID != "refs/heads/main" {
+		t.Errorf("unexpected request body: %+v", got)
+	}
+}
+
+func TestBitbucketDC_ListReviewThreads(t *testing.T) {
+	t.Parallel()
+
+	b := newTestBitbucketDC(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/1.0/projects/PROJ/repos/repo/pull-requests/4/activities" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		if r.URL.Query().Get("start") == "0" {
+			io.WriteString(w, `{"isLastPage": false, "nextPageStart": 2, "values": [
+				{"action": "APPROVED"},
+				{"action": "COMMENTED", "commentAction": "ADDED", "comment": {"text": "General remark", "author": {"name": "bob"}, "createdDate": 1767225600000}}
+			]}`)
+			return
+		}
+		io.WriteString(w, `{"isLastPage": true, "values": [
+			{"action": "COMMENTED", "commentAction": "ADDED",
+				"commentAnchor": {"path": "main.go", "line": 8},
+				"comment": {"text": "Rename this", "author": {"name": "bob"}, "createdDate": 1767312000000, "threadResolved": true,
+					"comments": [{"text": "Done", "author": {"name": "alice"}, "createdDate": 1767398400000}]}},
+			{"action": "COMMENTED", "commentAction": "ADDED",
+				"commentAnchor": {"path": "old.go", "line": 3, "orphaned": true},
+				"comment": {"text": "Why remove?", "author": {"name": "carol"}, "createdDate": 1767484800000, "state": "OPEN"}}
+		]}`)
+	})
+
+	threads, err := b.ListReviewThreads(context.Background(), "https://bitbucket.test/scm/PROJ/repo.git", 4)
+	if err != nil {
+		t.Fatalf("ListReviewThreads() error = %v", err)
+	}
+
+	want := []ReviewThread{
+		{Path: "main.go", Line: 8, Resolved: true, Comments: []ReviewComment{
+			{Author: "bob", Body: "Rename this", CreatedAt: time.UnixMilli(1767312000000)},
+			{Author: "alice", Body: "Done", CreatedAt: time.UnixMilli(1767398400000)},
+		}},
+		{Path: "old.go", Line: 3, Outdated: true, Comments: []ReviewComment{
+			{Author: "carol", Body: "Why remove?", CreatedAt: time.UnixMilli(1767484800000)},
+		}},
+	}
+	if !reflect.DeepEqual(threads, want) {
+		t.Errorf("ListReviewThreads() = %+v, want %+v", threads, want)
+	}
+}
+
+func TestBitbucketDC_ListOpenPRs(t *testing.T) {
+	t.Parallel()
+
+	b := newTestBitbucketDC(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("state") != "OPEN" {
+			t.Errorf("state = %q, want OPEN", r.URL.Query().Get("state"))
+		}
+		io.WriteString(w, `{"isLastPage":true,"values":[
+			{"id":2,"title":"Two","draft":true,"author":{"user":{"name":"bob"}},"fromRef":{"displayId":"two"}},
+			{"id":1,"title":"One","author":{"user":{"name":"alice"}},"fromRef":{"displayId":"one"}}
+		]}`)
+	})
+
+	prs, err := b.ListOpenPRs(context.Background(), "https://bitbucket.test/scm/PROJ/repo.git")
+	if err != nil {
+		t.Fatalf("ListOpenPRs() error = %v", err)
+	}
+	want := []OpenPR{
+		{Number: 2, Title: "Two", Author: "bob", Branch: "two", IsDraft: true},
+		{Number: 1, Title: "One", Author: "alice", Branch: "one"},
+	}
+	if !reflect.DeepEqual(prs, want) {
+		t.Errorf("ListOpenPRs() = %+v, want %+v", prs, want)
+	}
+}
+
+func TestBitbucketDC_CurrentUser(t *testing.T) {
+	t.Parallel()
+
+	b := newTestBitbucketDC(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/plugins/servlet/applinks/whoami" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		io.WriteString(w, "jdoe\n")
+	})
+
+	user, err := b.CurrentUser(context.Background(), "https://bitbucket.test/scm/PROJ/repo.git")
+	if err != nil || user != "jdoe" {
+		t.Errorf("CurrentUser() = %q, %v; want jdoe", user, err)
+	}
+}
+
+func TestBitbucketDC_Unauthorized(t *testing.T) {
+	t.Parallel()
+
+	b := newTestBitbucketDC(t, func(w http.ResponseWriter, r *http.Request) {})
+	b.ForgeConfig.Tokens["bitbucket.test"] = "wrong-token"
+
+	_, err := b.ListOpenPRs(context.Background(), "https://bitbucket.test/scm/PROJ/repo.git")
+	if err == nil || !strings.Contains(err.Error(), "HTTP 401") || !strings.Contains(err.Error(), "Authentication failed") {
+		t.Errorf("ListOpenPRs() should fail with 401, got %v", err)
+	}
+}
+
+func TestBitbucketDC_NoHost(t *testing.T) {
+	t.Parallel()
+
+	b := &BitbucketDataCenter{ForgeConfig: &config.ForgeConfig{}}
+	if err := b.Check(context.Background()); err == nil || !strings.Contains(err.Error(), "no host configured") {
+		t.Errorf("Check() without host should fail, got %v", err)
+	}
+}
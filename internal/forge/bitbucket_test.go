@@ -0,0 +1,336 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/raphi011/wt/internal/config"
+)
+
+// newTestBitbucketCloud returns a BitbucketCloud forge pointed at an httptest
+// server running handler. Requests without the configured token are rejected
+// with 401.
+func newTestBitbucketCloud(t *testing.T, handler http.HandlerFunc) *BitbucketCloud {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	return &BitbucketCloud{
+		ForgeConfig: &config.ForgeConfig{Tokens: map[string]string{"bitbucket.org": "test-token"}},
+		BaseURL:     srv.URL,
+	}
+}
+
+func TestNormalizeBitbucketState(t *testing.T) {
+	tests := map[string]string{
+		"OPEN":       PRStateOpen,
+		"MERGED":     PRStateMerged,
+		"DECLINED":   PRStateClosed,
+		"SUPERSEDED": PRStateClosed,
+		"open":       PRStateOpen,
+	}
+	for state, want := range tests {
+		if got := normalizeBitbucketState(state); got != want {
+			t.Errorf("normalizeBitbucketState(%q) = %q, want %q", state, got, want)
+		}
+	}
+}
+
+func TestBitbucketCloud_GetPRForBranch(t *testing.T) {
+	t.Parallel()
+
+	b := newTestBitbucketCloud(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repositories/ws/repo/pullrequests" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		q := r.URL.Query()
+		if got := strings.Join(q["state"], ","); got != "OPEN,MERGED,DECLINED,SUPERSEDED" {
+			t.Errorf("state = %q, want all states", got)
+		}
+		switch q.Get("q") {
+		case `source.branch.name = "feature"`:
+			io.WriteString(w, `{"values":[{"id":7,"state":"DECLINED","draft":false,"comment_count":2,
+				"author":{"nickname":"bob","display_name":"Bob"},
+				"links":{"html":{"href":"https://bitbucket.org/ws/repo/pull-requests/7"}},
+				"source":{"branch":{"name":"feature"}}}]}`)
+		default:
+			io.WriteString(w, `{"values":[]}`)
+		}
+	})
+
+	ctx := context.Background()
+
+	pr, err := b.GetPRForBranch(ctx, "git@bitbucket.org:ws/repo.git", "feature")
+	if err != nil {
+		t.Fatalf("GetPRForBranch() error = %v", err)
+	}
+	want := PRInfo{Number: 7, State: PRStateClosed, URL: "https://bitbucket.org/ws/repo/pull-requests/7",
+		Author: "bob", CommentCount: 2, Fetched: true}
+	pr.CachedAt = want.CachedAt
+	if !reflect.DeepEqual(*pr, want) {
+		t.Errorf("GetPRForBranch() = %+v, want %+v", *pr, want)
+	}
+
+	pr, err = b.GetPRForBranch(ctx, "git@bitbucket.org:ws/repo.git", "no-pr")
+	if err != nil {
+		t.Fatalf("GetPRForBranch() error = %v", err)
+	}
+	if pr.Number != 0 || !pr.Fetched {
+		t.Errorf("expected fetched marker without PR, got %+v", *pr)
+	}
+}
+
+func TestBitbucketCloud_GetPRsForBranches_FollowsNext(t *testing.T) {
+	t.Parallel()
+
+	var srvURL string
+	b := newTestBitbucketCloud(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			io.WriteString(w, `{"values":[{"id":3,"state":"MERGED","source":{"branch":{"name":"feat-b"}}}]}`)
+			return
+		}
+		io.WriteString(w, `{"values":[{"id":9,"state":"OPEN","draft":true,"source":{"branch":{"name":"feat-a"}}}],
+			"next":"`+srvURL+`/repositories/ws/repo/pullrequests?page=2"}`)
+	})
+	srvURL = b.BaseURL
+
+	prs, err := b.GetPRsForBranches(context.Background(), "https://bitbucket.org/ws/repo.git", []string{"feat-a", "feat-b", "feat-c"})
+	if err != nil {
+		t.Fatalf("GetPRsForBranches() error = %v", err)
+	}
+	if pr := prs["feat-a"]; pr.Number != 9 || pr.State != PRStateOpen || !pr.IsDraft {
+		t.Errorf("feat-a = %+v, want open draft PR #9", *pr)
+	}
+	if prs["feat-b"].State != PRStateMerged {
+		t.Errorf("feat-b state = %q, want %q", prs["feat-b"].State, PRStateMerged)
+	}
+	if pr := prs["feat-c"]; pr.Number != 0 || !pr.Fetched {
+		t.Errorf("feat-c = %+v, want fetched marker", *pr)
+	}
+}
+
+func TestBitbucketCloud_GetPRHead(t *testing.T) {
+	t.Parallel()
+
+	b := newTestBitbucketCloud(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repositories/ws/repo/pullrequests/1":
+			io.WriteString(w, `{"id":1,"source":{"branch":{"name":"feature"},"repository":{"full_name":"ws/repo"}},
+				"destination":{"branch":{"name":"main"},"repository":{"full_name":"ws/repo"}}}`)
+		case "/repositories/ws/repo/pullrequests/2":
+			io.WriteString(w, `{"id":2,"source":{"branch":{"name":"patch"},"repository":{"full_name":"someone/repo"}},
+				"destination":{"branch":{"name":"main"},"repository":{"full_name":"ws/repo"}}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `{"type":"error","error":{"message":"Resource not found"}}`)
+		}
+	})
+
+	ctx := context.Background()
+
+	head, err := b.GetPRHead(ctx, "git@bitbucket.org:ws/repo.git", 1)
+	if err != nil || !reflect.DeepEqual(*head, PRHead{Branch: "feature"}) {
+		t.Errorf("GetPRHead(1) = %+v, %v; want branch feature", head, err)
+	}
+
+	head, err = b.GetPRHead(ctx, "git@bitbucket.org:ws/repo.git", 2)
+	want := PRHead{Branch: "patch", IsFork: true, RepoPath: "someone/repo", CloneURL: "git@bitbucket.org:someone/repo.git"}
+	if err != nil || !reflect.DeepEqual(*head, want) {
+		t.Errorf("GetPRHead(2) = %+v, %v; want %+v", head, err, want)
+	}
+
+	if _, err := b.GetPRHead(ctx, "git@bitbucket.org:ws/repo.git", 3); err == nil || !strings.Contains(err.Error(), "Resource not found") {
+		t.Errorf("GetPRHead(3) should fail with the API message, got %v", err)
+	}
+}
+
+func TestBitbucketCloud_CreatePR(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	b := newTestBitbucketCloud(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/repositories/ws/repo/pullrequests" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusCreated)
+		io.WriteString(w, `{"id":8,"links":{"html":{"href":"https://bitbucket.org/ws/repo/pull-requests/8"}}}`)
+	})
+
+	result, err := b.CreatePR(context.Background(), "git@bitbucket.org:ws/repo.git", CreatePRParams{
+		Title: "Add feature",
+		Body:  "Details",
+		Head:  "feature",
+		Draft: true,
+	})
+	if err != nil {
+		t.Fatalf("CreatePR() error = %v", err)
+	}
+	if result.Number != 8 || result.URL != "https://bitbucket.org/ws/repo/pull-requests/8" {
+		t.Errorf("CreatePR() = %+v, want #8", *result)
+	}
+	if got["title"] != "Add feature" || got["description"] != "Details" || got["draft"] != true {
+		t.Errorf("unexpected request body: %v", got)
+	}
+	if _, ok := got["destination"]; ok {
+		t.Errorf("destination should be omitted without a base branch: %v", got)
+	}
+}
+
+func TestBitbucketCloud_MergePR(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	b := newTestBitbucketCloud(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/repositories/ws/repo/pullrequests/4/merge" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+	})
+
+	ctx := context.Background()
+	if err := b.MergePR(ctx, "git@bitbucket.org:ws/repo.git", 4, "merge"); err != nil {
+		t.Fatalf("MergePR() error = %v", err)
+	}
+	if got["merge_strategy"] != "merge_commit" || got["close_source_branch"] != true {
+		t.Errorf("unexpected request body: %v", got)
+	}
+
+	if err := b.MergePR(ctx, "git@bitbucket.org:ws/repo.git", 4, "octopus"); err == nil {
+		t.Error("MergePR() should reject unknown strategies")
+	}
+}
+
+func TestBitbucketCloud_UpdatePRBase(t *testing.T) {
+	t.Parallel()
+
+	var got struct {
+		Destination struct {
+			Branch struct {
+				Name string `json:"name"`
+			} `json:"branch"`
+		} `json:"destination"`
+	}
+	b := newTestBitbucketCloud(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/repositories/ws/repo/pullrequests/4" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		io.WriteString(w, `{"id":4}`)
+	})
+
+	if err := b.UpdatePRBase(context.Background(), "git@bitbucket.org:ws/repo.git", 4, "main"); err != nil {
+		t.Fatalf("UpdatePRBase() error = %v", err)
+	}
+	if got.Destination.Branch.Name != "main" {
+		t.Errorf("destination branch = %q, want main", got.Destination.Branch.Name)
+	}
+}
+
+func TestBitbucketCloud_ListReviewThreads(t *testing.T) {
+	t.Parallel()
+
+	var srvURL string
+	b := newTestBitbucketCloud(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repositories/ws/repo/pullrequests/4/comments" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		if r.URL.Query().Get("page") == "" {
+			io.WriteString(w, `{"values": [
+				{"id": 1, "content": {"raw": "General remark"}, "user": {"nickname": "bob"}, "created_on": "2026-01-01T00:00:00Z"},
+				{"id": 2, "content": {"raw": "Rename this"}, "user": {"nickname": "bob"}, "created_on": "2026-01-02T00:00:00Z",
+					"inline": {"path": "main.go", "to": 8}, "resolution": {"type": "comment_resolution"},
+					"links": {"html": {"href": "https://bitbucket.org/ws/repo/pull-requests/4#comment-2"}}}
+			], "next": "`+srvURL+`/repositories/ws/repo/pullrequests/4/comments?page=2"}`)
+			return
+		}
+		io.WriteString(w, `{"values": [
+			{"id": 3, "content": {"raw": "Done"}, "user": {"display_name": "Alice"}, "created_on": "2026-01-03T00:00:00Z",
+				"inline": {"path": "main.go", "to": 8}, "parent": {"id": 2}},
+			{"id": 4, "content": {"raw": ""}, "deleted": true, "user": {"nickname": "bob"}, "created_on": "2026-01-04T00:00:00Z", "parent": {"id": 3}},
+			{"id": 5, "content": {"raw": "Why remove?"}, "user": {"nickname": "carol"}, "created_on": "2026-01-05T00:00:00Z",
+				"inline": {"path": "old.go", "from": 3, "to": null, "outdated": true}}
+		]}`)
+	})
+	srvURL = b.BaseURL
+
+	threads, err := b.ListReviewThreads(context.Background(), "git@bitbucket.org:ws/repo.git", 4)
+	if err != nil {
+		t.Fatalf("ListReviewThreads() error = %v", err)
+	}
+
+	want := []ReviewThread{
+		{Path: "main.go", Line: 8, Resolved: true, URL: "https://bitbucket.org/ws/repo/pull-requests/4#comment-2", Comments: []ReviewComment{
+			{Author: "bob", Body: "Rename this", CreatedAt: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)},
+			{Author: "Alice", Body: "Done", CreatedAt: time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)},
+		}},
+		{Path: "old.go", Line: 3, Outdated: true, Comments: []ReviewComment{
+			{Author: "carol", Body: "Why remove?", CreatedAt: time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)},
+		}},
+	}
+	if !reflect.DeepEqual(threads, want) {
+		t.Errorf("ListReviewThreads() = %+v, want %+v", threads, want)
+	}
+}
+
+func TestBitbucketCloud_ListOpenPRs(t *testing.T) {
+	t.Parallel()
+
+	b := newTestBitbucketCloud(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query()["state"]; len(got) != 1 || got[0] != "OPEN" {
+			t.Errorf("state = %v, want [OPEN]", got)
+		}
+		io.WriteString(w, `{"values":[
+			{"id":1,"title":"One","author":{"display_name":"Alice"},"source":{"branch":{"name":"one"}}},
+			{"id":2,"title":"Two","draft":true,"author":{"nickname":"bob"},"source":{"branch":{"name":"two"}}}
+		]}`)
+	})
+
+	prs, err := b.ListOpenPRs(context.Background(), "git@bitbucket.org:ws/repo.git")
+	if err != nil {
+		t.Fatalf("ListOpenPRs() error = %v", err)
+	}
+	want := []OpenPR{
+		{Number: 2, Title: "Two", Author: "bob", Branch: "two", IsDraft: true},
+		{Number: 1, Title: "One", Author: "Alice", Branch: "one"},
+	}
+	if !reflect.DeepEqual(prs, want) {
+		t.Errorf("ListOpenPRs() = %+v, want %+v", prs, want)
+	}
+}
+
+func TestBitbucketClient_BasicAuth(t *testing.T) {
+	t.Parallel()
+
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		io.WriteString(w, `{"values":[]}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	b := &BitbucketCloud{
+		ForgeConfig: &config.ForgeConfig{Tokens: map[string]string{"bitbucket.org": "jdoe@example.com:secret"}},
+		BaseURL:     srv.URL,
+	}
+	if _, err := b.ListOpenPRs(context.Background(), "git@bitbucket.org:ws/repo.git"); err != nil {
+		t.Fatalf("ListOpenPRs() error = %v", err)
+	}
+	// base64("jdoe@example.com:secret")
+	if auth != "Basic amRvZUBleGFtcGxlLmNvbTpzZWNyZXQ=" {
+		t.Errorf("Authorization = %q, want Basic auth", auth)
+	}
+}
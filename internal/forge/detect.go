@@ -10,22 +10,34 @@ import (
 // Detect returns the appropriate Forge implementation based on the remote URL.
 // If hostMap is provided, checks for exact domain matches first.
 // Falls back to pattern matching, then defaults to GitHub.
-// If forgeConfig is provided, it's passed to the forge for user lookup.
+// If forgeConfig is provided, it's passed to the forge for user lookup and
+// selects the backend (gh/glab CLI or direct API access).
 func Detect(remoteURL string, hostMap map[string]string, forgeConfig *config.ForgeConfig) Forge {
-	// Check hostMap first for exact domain match
-	if len(hostMap) > 0 {
-		host := extractHost(remoteURL)
-		if forgeType, ok := hostMap[host]; ok {
-			return ByNameWithConfig(forgeType, forgeConfig)
-		}
+	host := extractHost(remoteURL)
+
+	forgeType, ok := hostMap[host]
+	switch {
+	case ok:
+		// hostMap exact domain match
+	case isGitLab(remoteURL):
+		forgeType = "gitlab"
+	case isGitea(host):
+		forgeType = "gitea"
+	case isBitbucket(host):
+		forgeType = "bitbucket"
+	default:
+		// Default to GitHub (most common, backwards compatible)
+		forgeType = "github"
 	}
 
-	// Fall back to pattern matching
-	if isGitLab(remoteURL) {
-		return &GitLab{ForgeConfig: forgeConfig}
+	f := newForge(forgeType, host, forgeConfig)
+	switch f := f.(type) {
+	case *Gitea:
+		f.WebURL = remoteWebURL(remoteURL)
+	case *BitbucketDataCenter:
+		f.WebURL = bitbucketDCWebURL(remoteURL)
 	}
-	// Default to GitHub (most common, backwards compatible)
-	return &GitHub{ForgeConfig: forgeConfig}
+	return f
 }
 
 // extractHost parses the hostname from a git remote URL.
@@ -56,15 +68,45 @@ func extractHost(remoteURL string) string {
 }
 
 // ByNameWithConfig returns a Forge implementation by name with config.
-// Supported names: "github", "gitlab"
+// Supported names: "github", "gitlab", "gitea", "bitbucket"
 // Returns GitHub as default for unknown names.
 func ByNameWithConfig(name string, forgeConfig *config.ForgeConfig) Forge {
+	return newForge(name, "", forgeConfig)
+}
+
+// ByNameForHost returns a Forge implementation by name for a specific host,
+// e.g. the host of the [[forge.rules]] entry matching a repo to be cloned.
+// An empty host means the public instance.
+func ByNameForHost(name, host string, forgeConfig *config.ForgeConfig) Forge {
+	return newForge(name, host, forgeConfig)
+}
+
+// newForge returns the Forge implementation for a forge type and host.
+// With [forge] backend = "api" the HTTP API implementations are used,
+// otherwise the gh/glab CLI ones. Gitea and Bitbucket are always accessed
+// over their APIs. host is only used by the API backends (empty = the
+// public instance; Gitea has none and requires a host). For Bitbucket,
+// any host other than bitbucket.org is a Data Center instance.
+func newForge(name, host string, forgeConfig *config.ForgeConfig) Forge {
+	useAPI := forgeConfig != nil && forgeConfig.Backend == "api"
+
 	switch strings.ToLower(name) {
+	case "gitea":
+		return &Gitea{ForgeConfig: forgeConfig, Host: host}
+	case "bitbucket":
+		if apiHost(host, bitbucketCloudHost) == bitbucketCloudHost {
+			return &BitbucketCloud{ForgeConfig: forgeConfig}
+		}
+		return &BitbucketDataCenter{ForgeConfig: forgeConfig, Host: host}
 	case "gitlab":
+		if useAPI {
+			return &GitLabAPI{ForgeConfig: forgeConfig, Host: host}
+		}
 		return &GitLab{ForgeConfig: forgeConfig}
-	case "github":
-		return &GitHub{ForgeConfig: forgeConfig}
 	default:
+		if useAPI {
+			return &GitHubAPI{ForgeConfig: forgeConfig, Host: host}
+		}
 		return &GitHub{ForgeConfig: forgeConfig}
 	}
 }
@@ -91,13 +133,46 @@ func isGitLab(url string) bool {
 	return false
 }
 
+// isGitea checks if a remote host is a Gitea or Forgejo instance: codeberg.org
+// or a gitea.* / forgejo.* host. Self-hosted instances on other domains need a
+// [hosts] mapping.
+func isGitea(host string) bool {
+	host = strings.ToLower(host)
+	return host == "codeberg.org" ||
+		strings.HasPrefix(host, "gitea.") ||
+		strings.HasPrefix(host, "forgejo.")
+}
+
+// isBitbucket checks if a remote host is Bitbucket Cloud (bitbucket.org) or a
+// Data Center instance on a bitbucket.* host. Other Data Center hosts need a
+// [hosts] mapping.
+func isBitbucket(host string) bool {
+	host = strings.ToLower(host)
+	return host == "bitbucket.org" || strings.HasPrefix(host, "bitbucket.")
+}
+
 // ExtractRepoPath extracts the repository path from a git URL.
 // Handles SSH aliases: git@github.com-personal:user/repo.git -> user/repo
 // Handles SSH: git@github.com:user/repo.git -> user/repo
 // Handles HTTPS: https://github.com/user/repo.git -> user/repo
 // Handles SSH protocol: ssh://git@github.com/user/repo.git -> user/repo
 // Handles GitLab subgroups: git@gitlab.com:group/sub/repo.git -> group/sub/repo
+// Handles Bitbucket Cloud: https://user@bitbucket.org/workspace/repo.git -> workspace/repo
+// Handles Bitbucket Data Center: https://bitbucket.host/scm/PROJ/repo.git -> PROJ/repo,
+// ssh://git@host:7999/PROJ/repo.git -> PROJ/repo
+// The "scm/" prefix is only stripped on bitbucket.* hosts (see [isBitbucket]):
+// elsewhere, like in GitLab groups, "scm" is part of the path. The Data
+// Center backend strips it on any host (see [bitbucketDCRepoPath]).
 func ExtractRepoPath(url string) string {
+	path := extractRepoPath(url)
+	if isBitbucket(extractHost(url)) {
+		return trimBitbucketSCM(path)
+	}
+	return path
+}
+
+// extractRepoPath returns the path of a git URL without host and ".git".
+func extractRepoPath(url string) string {
 	url = strings.TrimSuffix(url, ".git")
 
 	// SSH format: git@host:path or git@host-alias:path
@@ -122,3 +197,28 @@ func ExtractRepoPath(url string) string {
 
 	return url
 }
+
+// replaceRepoPath returns url with its repository path old (usually
+// [ExtractRepoPath] of url) replaced by repoPath, keeping scheme, host and
+// any ".git" suffix. Returns "" if url has no host part to keep (e.g. a
+// local path).
+func replaceRepoPath(url, old, repoPath string) string {
+	trimmed := strings.TrimSuffix(url, ".git")
+	prefix, ok := strings.CutSuffix(trimmed, old)
+	if !ok || old == "" || !strings.Contains(prefix, ":") {
+		return ""
+	}
+	return prefix + repoPath + url[len(trimmed):]
+}
+
+// trimBitbucketSCM strips the "scm/" prefix of Bitbucket Data Center HTTP
+// clone URLs, including an optional context path (bitbucket/scm/PROJ/repo).
+// Only paths ending in exactly PROJECT/repo after "scm" are affected.
+func trimBitbucketSCM(path string) string {
+	segments := strings.Split(path, "/")
+	n := len(segments)
+	if n >= 3 && segments[n-3] == "scm" {
+		return segments[n-2] + "/" + segments[n-1]
+	}
+	return path
+}
@@ -2,6 +2,8 @@ package forge
 
 import (
 	"testing"
+
+	"github.com/raphi011/wt/internal/config"
 )
 
 func TestExtractHost(t *testing.T) {
@@ -149,6 +151,78 @@ func TestDetect(t *testing.T) {
 			hostMap:  map[string]string{"github.enterprise.corp": "github"},
 			wantType: "*forge.GitHub",
 		},
+		{
+			name:     "custom host matched to gitea",
+			url:      "git@git.corp:org/repo.git",
+			hostMap:  map[string]string{"git.corp": "gitea"},
+			wantType: "*forge.Gitea",
+		},
+		{
+			name:     "pattern fallback codeberg.org",
+			url:      "https://codeberg.org/org/repo.git",
+			hostMap:  nil,
+			wantType: "*forge.Gitea",
+		},
+		{
+			name:     "pattern fallback gitea. prefix",
+			url:      "https://gitea.corp.com/org/repo.git",
+			hostMap:  nil,
+			wantType: "*forge.Gitea",
+		},
+		{
+			name:     "pattern fallback forgejo. prefix",
+			url:      "git@forgejo.example.org:org/repo.git",
+			hostMap:  nil,
+			wantType: "*forge.Gitea",
+		},
+		{
+			name:     "github repo named gitea",
+			url:      "https://github.com/go-gitea/gitea.git",
+			hostMap:  nil,
+			wantType: "*forge.GitHub",
+		},
+		{
+			name:     "github repo named forgejo",
+			url:      "git@github.com:foo/forgejo.git",
+			hostMap:  nil,
+			wantType: "*forge.GitHub",
+		},
+		{
+			name:     "github repo mentioning codeberg.org",
+			url:      "https://github.com/mirrors/codeberg.org.git",
+			hostMap:  nil,
+			wantType: "*forge.GitHub",
+		},
+		{
+			name:     "pattern fallback bitbucket.org",
+			url:      "git@bitbucket.org:workspace/repo.git",
+			hostMap:  nil,
+			wantType: "*forge.BitbucketCloud",
+		},
+		{
+			name:     "pattern fallback bitbucket. prefix",
+			url:      "ssh://git@bitbucket.corp.com:7999/proj/repo.git",
+			hostMap:  nil,
+			wantType: "*forge.BitbucketDataCenter",
+		},
+		{
+			name:     "github repo named bitbucket",
+			url:      "git@github.com:atlassian/bitbucket.nvim.git",
+			hostMap:  nil,
+			wantType: "*forge.GitHub",
+		},
+		{
+			name:     "bitbucket.org in path of other host",
+			url:      "https://github.com/mirror/bitbucket.org-tools.git",
+			hostMap:  nil,
+			wantType: "*forge.GitHub",
+		},
+		{
+			name:     "custom host matched to bitbucket",
+			url:      "https://git.legacy.corp/scm/proj/repo.git",
+			hostMap:  map[string]string{"git.legacy.corp": "bitbucket"},
+			wantType: "*forge.BitbucketDataCenter",
+		},
 	}
 
 	for _, tt := range tests {
@@ -162,12 +236,135 @@ func TestDetect(t *testing.T) {
 	}
 }
 
+func TestDetect_APIBackend(t *testing.T) {
+	cfg := &config.ForgeConfig{Backend: "api"}
+
+	tests := []struct {
+		name     string
+		url      string
+		hostMap  map[string]string
+		wantType string
+		wantHost string
+	}{
+		{"github.com", "git@github.com:user/repo.git", nil, "*forge.GitHubAPI", "github.com"},
+		{"gitlab.com", "https://gitlab.com/group/sub/repo.git", nil, "*forge.GitLabAPI", "gitlab.com"},
+		{"custom gitlab host", "git@code.internal.corp:org/repo.git", map[string]string{"code.internal.corp": "gitlab"}, "*forge.GitLabAPI", "code.internal.corp"},
+		{"github enterprise", "https://github.enterprise.corp/org/repo.git", map[string]string{"github.enterprise.corp": "github"}, "*forge.GitHubAPI", "github.enterprise.corp"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Detect(tt.url, tt.hostMap, cfg)
+			if gotType := getForgeType(got); gotType != tt.wantType {
+				t.Fatalf("Detect(%q) = %s, want %s", tt.url, gotType, tt.wantType)
+			}
+			var host string
+			switch f := got.(type) {
+			case *GitHubAPI:
+				host = f.host()
+			case *GitLabAPI:
+				host = f.host()
+			}
+			if host != tt.wantHost {
+				t.Errorf("Detect(%q) host = %q, want %q", tt.url, host, tt.wantHost)
+			}
+		})
+	}
+
+	// ByNameWithConfig honors the backend too (used for clone)
+	if gotType := getForgeType(ByNameWithConfig("gitlab", cfg)); gotType != "*forge.GitLabAPI" {
+		t.Errorf("ByNameWithConfig(gitlab) = %s, want *forge.GitLabAPI", gotType)
+	}
+	if gotType := getForgeType(ByNameWithConfig("github", &config.ForgeConfig{Backend: "cli"})); gotType != "*forge.GitHub" {
+		t.Errorf("ByNameWithConfig(github, cli) = %s, want *forge.GitHub", gotType)
+	}
+}
+
+func TestDetect_GiteaInstanceURL(t *testing.T) {
+	hostMap := map[string]string{"git.corp": "gitea"}
+
+	tests := []struct {
+		url     string
+		wantAPI string
+	}{
+		{"git@git.corp:org/repo.git", "https://git.corp/api/v1/user"},
+		{"https://git.corp/org/repo.git", "https://git.corp/api/v1/user"},
+		{"http://git.corp:3000/org/repo.git", "http://git.corp:3000/api/v1/user"},
+	}
+
+	for _, tt := range tests {
+		g, ok := Detect(tt.url, hostMap, nil).(*Gitea)
+		if !ok {
+			t.Fatalf("Detect(%q) is not Gitea", tt.url)
+		}
+		if got := g.apiURL("/user"); got != tt.wantAPI {
+			t.Errorf("Detect(%q) api URL = %q, want %q", tt.url, got, tt.wantAPI)
+		}
+	}
+
+	// Clone targets use the host of the matching forge rule
+	cfg := &config.ForgeConfig{Rules: []config.ForgeRule{{Pattern: "infra/*", Type: "gitea", Host: "git.corp"}}}
+	g, ok := ByNameForHost(cfg.GetForgeTypeForRepo("infra/tools"), cfg.GetHostForRepo("infra/tools"), cfg).(*Gitea)
+	if !ok {
+		t.Fatal("ByNameForHost(gitea) is not Gitea")
+	}
+	if got := g.cloneURL("infra/tools"); got != "https://git.corp/infra/tools.git" {
+		t.Errorf("clone URL = %q, want https://git.corp/infra/tools.git", got)
+	}
+}
+
+func TestDetect_BitbucketInstanceURL(t *testing.T) {
+	hostMap := map[string]string{"git.legacy.corp": "bitbucket"}
+
+	tests := []struct {
+		url     string
+		wantAPI string
+	}{
+		{"ssh://git@git.legacy.corp:7999/proj/repo.git", "https://git.legacy.corp/rest/api/1.0/projects/proj/repos/repo"},
+		{"https://git.legacy.corp/scm/proj/repo.git", "https://git.legacy.corp/rest/api/1.0/projects/proj/repos/repo"},
+		{"http://git.legacy.corp:7990/bitbucket/scm/proj/repo.git", "http://git.legacy.corp:7990/bitbucket/rest/api/1.0/projects/proj/repos/repo"},
+	}
+
+	for _, tt := range tests {
+		b, ok := Detect(tt.url, hostMap, nil).(*BitbucketDataCenter)
+		if !ok {
+			t.Fatalf("Detect(%q) is not BitbucketDataCenter", tt.url)
+		}
+		if got := b.repoURL("api", bitbucketDCRepoPath(tt.url), ""); got != tt.wantAPI {
+			t.Errorf("Detect(%q) api URL = %q, want %q", tt.url, got, tt.wantAPI)
+		}
+	}
+
+	// Rules without a host (or with bitbucket.org) select Bitbucket Cloud
+	if _, ok := ByNameWithConfig("bitbucket", nil).(*BitbucketCloud); !ok {
+		t.Error("ByNameWithConfig(bitbucket) is not BitbucketCloud")
+	}
+	cfg := &config.ForgeConfig{Rules: []config.ForgeRule{{Pattern: "LEGACY/*", Type: "bitbucket", Host: "git.legacy.corp"}}}
+	b, ok := ByNameForHost(cfg.GetForgeTypeForRepo("LEGACY/app"), cfg.GetHostForRepo("LEGACY/app"), cfg).(*BitbucketDataCenter)
+	if !ok {
+		t.Fatal("ByNameForHost(bitbucket, git.legacy.corp) is not BitbucketDataCenter")
+	}
+	if got := b.cloneURL("LEGACY/app"); got != "https://git.legacy.corp/scm/LEGACY/app.git" {
+		t.Errorf("clone URL = %q, want https://git.legacy.corp/scm/LEGACY/app.git", got)
+	}
+}
+
 func getForgeType(f Forge) string {
 	switch f.(type) {
 	case *GitHub:
 		return "*forge.GitHub"
 	case *GitLab:
 		return "*forge.GitLab"
+	case *GitHubAPI:
+		return "*forge.GitHubAPI"
+	case *GitLabAPI:
+		return "*forge.GitLabAPI"
+	case *Gitea:
+		return "*forge.Gitea"
+	case *BitbucketCloud:
+		return "*forge.BitbucketCloud"
+	case *BitbucketDataCenter:
+		return "*forge.BitbucketDataCenter"
 	default:
 		return "unknown"
 	}
@@ -234,6 +431,51 @@ func TestExtractRepoPath(t *testing.T) {
 			url:  "https://github.com/user/repo",
 			want: "user/repo",
 		},
+		{
+			name: "Bitbucket Cloud HTTPS with user",
+			url:  "https://jdoe@bitbucket.org/workspace/repo.git",
+			want: "workspace/repo",
+		},
+		{
+			name: "Bitbucket Data Center HTTPS",
+			url:  "https://bitbucket.corp/scm/PROJ/repo.git",
+			want: "PROJ/repo",
+		},
+		{
+			name: "Bitbucket Data Center HTTPS with context path",
+			url:  "https://bitbucket.corp/bitbucket/scm/proj/repo.git",
+			want: "proj/repo",
+		},
+		{
+			name: "Bitbucket Data Center personal repo",
+			url:  "https://jdoe@bitbucket.corp/scm/~jdoe/repo.git",
+			want: "~jdoe/repo",
+		},
+		{
+			name: "Bitbucket Data Center SSH",
+			url:  "ssh://git@bitbucket.corp:7999/proj/repo.git",
+			want: "proj/repo",
+		},
+		{
+			name: "scm as project name",
+			url:  "git@github.com:scm/repo.git",
+			want: "scm/repo",
+		},
+		{
+			name: "GitLab subgroup named scm",
+			url:  "git@gitlab.com:group/scm/proj/repo.git",
+			want: "group/scm/proj/repo",
+		},
+		{
+			name: "GitLab HTTPS subgroup named scm",
+			url:  "https://gitlab.corp/group/scm/proj/repo.git",
+			want: "group/scm/proj/repo",
+		},
+		{
+			name: "Gitea org repo under scm path",
+			url:  "https://gitea.corp/scm/proj/repo.git",
+			want: "scm/proj/repo",
+		},
 	}
 
 	for _, tt := range tests {
@@ -245,3 +487,26 @@ func TestExtractRepoPath(t *testing.T) {
 		})
 	}
 }
+
+func TestReplaceRepoPath(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"git@github.com:org/repo.git", "git@github.com:someone/fork.git"},
+		{"git@github.com-work:org/repo.git", "git@github.com-work:someone/fork.git"},
+		{"https://github.com/org/repo", "https://github.com/someone/fork"},
+		{"ssh://git@gitlab.corp:2222/group/sub/repo.git", "ssh://git@gitlab.corp:2222/someone/fork.git"},
+		{"https://bitbucket.corp/scm/PROJ/repo.git", "https://bitbucket.corp/scm/someone/fork.git"},
+		{"https://gitlab.corp/group/scm/proj/repo.git", "https://gitlab.corp/someone/fork.git"},
+		{"/tmp/repos/org/repo.git", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			if got := replaceRepoPath(tt.url, ExtractRepoPath(tt.url), "someone/fork"); got != tt.want {
+				t.Errorf("replaceRepoPath(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,13 +1,31 @@
 // Package forge provides an abstraction layer for git hosting services.
 //
-// The package supports GitHub (via gh CLI) and GitLab (via glab CLI), enabling
-// wt commands to work seamlessly with both platforms without duplicating logic.
+// The package supports GitHub (via gh CLI), GitLab (via glab CLI),
+// Gitea/Forgejo and Bitbucket Cloud/Data Center (via their REST APIs),
+// enabling wt commands to work seamlessly with all platforms without
+// duplicating logic.
+//
+// # Backends
+//
+// Each platform has two implementations, selected by [forge] backend:
+//
+//   - "cli" (default): [GitHub] and [GitLab] shell out to gh/glab
+//   - "api": [GitHubAPI] and [GitLabAPI] call the REST/GraphQL APIs over HTTP
+//
+// [Gitea], [BitbucketCloud] and [BitbucketDataCenter] have no CLI backend and
+// always use the HTTP API. Both Bitbucket flavors are forge type "bitbucket":
+// bitbucket.org is Cloud, any other host is Data Center.
+//
+// The API backends take their token from [forge.tokens], environment variables
+// (GH_TOKEN, GITLAB_TOKEN, ...) or the CLI's stored credentials, and accept a
+// BaseURL override so they can be tested against an httptest server.
 //
 // # Forge Interface
 //
 // The [Forge] interface defines operations for:
 //
-//   - Fetching PR/MR information for a branch
+//   - Fetching PR/MR information for a branch, or batched for many branches
+//     of one repository ([Forge.GetPRsForBranches])
 //   - Getting the source branch for a PR number
 //   - Cloning repositories
 //   - Creating, viewing, and merging PRs
@@ -18,7 +36,8 @@
 // Detection checks:
 //
 //  1. Custom host mappings from config (for self-hosted instances)
-//  2. URL patterns (gitlab.com, gitlab.* domains)
+//  2. URL patterns (gitlab.com, gitlab.* domains; codeberg.org, gitea.*,
+//     forgejo.* domains; bitbucket.org, bitbucket.* domains)
 //  3. Falls back to GitHub (most common)
 //
 // # Usage
@@ -31,9 +50,16 @@
 // Some features have platform-specific limitations:
 //
 //   - GitLab does not support rebase merge via CLI (only squash and merge)
+//   - Gitea can't filter PRs by head branch, so lookups scan recent PRs;
+//     drafts are PRs with a "WIP:" title prefix
+//   - Bitbucket has declined and superseded PRs, both reported as CLOSED;
+//     Data Center HTTP clone URLs have an extra "scm/" path segment
 //   - PR state names differ (OPEN/MERGED/CLOSED vs open/merged/closed)
 //   - Draft PR handling varies between platforms
+//   - Batched lookups use aliased GraphQL queries on GitHub; GitLab lists the
+//     project's recent MRs and matches source branches locally
 //
-// Any feature involving forge operations must implement both GitHub and GitLab.
+// Any feature involving forge operations must implement GitHub, GitLab, Gitea
+// and Bitbucket.
 // Never call gh or glab directly outside this package.
 package forge
@@ -18,6 +18,21 @@ const (
 	PRStateDraft  = "DRAFT"
 )
 
+// CI check rollup constants (normalized across forges). An empty value means
+// the forge reported no checks or the status is unknown.
+const (
+	CheckStatePending = "PENDING"
+	CheckStatePassing = "PASSING"
+	CheckStateFailing = "FAILING"
+)
+
+// Mergeability constants (normalized across forges). An empty value means the
+// forge hasn't computed mergeability yet or the PR is no longer open.
+const (
+	MergeStateMergeable   = "MERGEABLE"
+	MergeStateConflicting = "CONFLICTING"
+)
+
 // PRInfo represents pull request information
 type PRInfo struct {
 	Number       int       `json:"number"`
@@ -30,6 +45,14 @@ type PRInfo struct {
 	IsApproved   bool      `json:"is_approved"`   // approved status
 	CachedAt     time.Time `json:"cached_at"`
 	Fetched      bool      `json:"fetched"` // true = API was queried (distinguishes "not fetched" from "no PR")
+
+	Title           string    `json:"title,omitempty"`
+	BaseBranch      string    `json:"base_branch,omitempty"`      // target branch
+	Checks          string    `json:"checks,omitempty"`           // CI rollup: PENDING, PASSING, FAILING; empty = unknown
+	Mergeable       string    `json:"mergeable,omitempty"`        // MERGEABLE, CONFLICTING; empty = unknown
+	ReviewRequested []string  `json:"review_requested,omitempty"` // pending reviewer logins/teams
+	Labels          []string  `json:"labels,omitempty"`
+	UpdatedAt       time.Time `json:"updated_at,omitzero"`
 }
 
 // CreatePRParams contains parameters for creating a PR/MR
@@ -49,11 +72,41 @@ type CreatePRResult struct {
 
 // OpenPR represents a PR in a list of open PRs
 type OpenPR struct {
-	Number  int
-	Title   string
-	Author  string
-	Branch  string
-	IsDraft bool
+	Number          int
+	Title           string
+	Author          string
+	Branch          string // head branch name
+	IsFork          bool   // head branch is in another repository
+	HeadRepo        string // head repository (owner/repo) of forks; empty if unknown
+	IsDraft         bool
+	URL             string
+	Labels          []string
+	ReviewRequested []string // pending reviewer logins/teams
+	UpdatedAt       time.Time
+}
+
+// PRHead describes the source branch of a PR.
+type PRHead struct {
+	Branch              string // source branch name in the head repository
+	IsFork              bool   // head repository differs from the base repository
+	RepoPath            string // head repository (owner/repo) of forks; empty if the fork was deleted
+	CloneURL            string // clone URL of the fork, same protocol as the base repo's remote when possible
+	MaintainerCanModify bool   // maintainers of the base repository may push to the fork's branch
+}
+
+// newForkHead returns the head of a PR from the fork forkPath. The clone URL
+// is derived from the base repository's repoURL so that fetching and pushing
+// use the same protocol and credentials; cloneURL (as reported by the forge)
+// is the fallback.
+func newForkHead(repoURL, branch, forkPath, cloneURL string, maintainerCanModify bool) *PRHead {
+	head := &PRHead{Branch: branch, IsFork: true, RepoPath: forkPath, MaintainerCanModify: maintainerCanModify}
+	if forkPath != "" {
+		head.CloneURL = replaceRepoPath(repoURL, ExtractRepoPath(repoURL), forkPath)
+		if head.CloneURL == "" {
+			head.CloneURL = cloneURL
+		}
+	}
+	return head
 }
 
 // Forge represents a git hosting service (GitHub, GitLab, etc.)
@@ -67,8 +120,14 @@ type Forge interface {
 	// GetPRForBranch fetches PR info for a branch
 	GetPRForBranch(ctx context.Context, repoURL, branch string) (*PRInfo, error)
 
-	// GetPRBranch gets the source branch name for a PR number
-	GetPRBranch(ctx context.Context, repoURL string, number int) (string, error)
+	// GetPRsForBranches fetches PR info for several branches of one repository
+	// in as few requests as possible. The result has an entry for every branch;
+	// branches without a PR map to a "fetched, no PR" marker.
+	GetPRsForBranches(ctx context.Context, repoURL string, branches []string) (map[string]*PRInfo, error)
+
+	// GetPRHead gets the source branch of a PR number and, for PRs from
+	// forks, the repository it lives in
+	GetPRHead(ctx context.Context, repoURL string, number int) (*PRHead, error)
 
 	// CloneRepo clones a repository to destPath, returns the full clone path
 	CloneRepo(ctx context.Context, repoSpec, destPath string) (string, error)
@@ -88,6 +147,14 @@ type Forge interface {
 	// Returns error if repo doesn't allow the requested merge strategy
 	MergePR(ctx context.Context, repoURL string, number int, strategy string) error
 
+	// UpdatePRBase changes the base (target) branch of PR number, e.g. to
+	// retarget a stacked PR after its parent was merged
+	UpdatePRBase(ctx context.Context, repoURL string, number int, base string) error
+
+	// ListReviewThreads lists the review threads attached to files of PR
+	// number, sorted by file and line. General PR comments are not included.
+	ListReviewThreads(ctx context.Context, repoURL string, number int) ([]ReviewThread, error)
+
 	// ViewPR shows PR details or opens in browser
 	// If web is true, opens in browser; otherwise shows details in terminal
 	ViewPR(ctx context.Context, repoURL string, number int, web bool) error
@@ -95,6 +162,15 @@ type Forge interface {
 	// ListOpenPRs lists all open PRs for a repository
 	ListOpenPRs(ctx context.Context, repoURL string) ([]OpenPR, error)
 
+	// CurrentUser returns the login of the authenticated user on the forge
+	// hosting repoURL (used to resolve "@me" in filters)
+	CurrentUser(ctx context.Context, repoURL string) (string, error)
+
+	// PRHeadRef returns the ref under which the base repository exposes the
+	// head commit of PR number (also for PRs from forks), or "" if the forge
+	// doesn't publish one
+	PRHeadRef(number int) string
+
 	// FormatState returns a human-readable PR state
 	FormatState(state string) string
 }
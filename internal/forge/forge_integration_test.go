@@ -490,17 +490,17 @@ func TestForge_PRWorkflow(t *testing.T) {
 				}
 			})
 
-			t.Run("GetPRBranch", func(t *testing.T) {
+			t.Run("GetPRHead", func(t *testing.T) {
 				if prNumber == 0 {
 					t.Skip("No PR created")
 				}
 
-				branch, err := fc.forge.GetPRBranch(ctx, fc.repoURL, prNumber)
+				head, err := fc.forge.GetPRHead(ctx, fc.repoURL, prNumber)
 				if err != nil {
-					t.Fatalf("GetPRBranch() error = %v", err)
+					t.Fatalf("GetPRHead() error = %v", err)
 				}
-				if branch != testBranch {
-					t.Errorf("GetPRBranch() = %q, want %q", branch, testBranch)
+				if head.Branch != testBranch || head.IsFork {
+					t.Errorf("GetPRHead() = %+v, want branch %q, not a fork", *head, testBranch)
 				}
 			})
 
@@ -39,6 +39,67 @@ func TestNormalizeGitLabState(t *testing.T) {
 	}
 }
 
+func TestGithubChecksRollup(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		contexts []githubCheckContext
+		want     string
+	}{
+		{"no checks", nil, ""},
+		{"all passed", []githubCheckContext{
+			{Status: "COMPLETED", Conclusion: "SUCCESS"},
+			{Status: "COMPLETED", Conclusion: "SKIPPED"},
+			{State: "SUCCESS"},
+		}, CheckStatePassing},
+		{"check run in progress", []githubCheckContext{
+			{Status: "COMPLETED", Conclusion: "SUCCESS"},
+			{Status: "IN_PROGRESS"},
+		}, CheckStatePending},
+		{"status context pending", []githubCheckContext{{State: "PENDING"}}, CheckStatePending},
+		{"failure wins over pending", []githubCheckContext{
+			{Status: "QUEUED"},
+			{Status: "COMPLETED", Conclusion: "TIMED_OUT"},
+		}, CheckStateFailing},
+		{"status context error", []githubCheckContext{{State: "ERROR"}}, CheckStateFailing},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := githubChecksRollup(tt.contexts); got != tt.want {
+				t.Errorf("githubChecksRollup() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGitLabMergeable(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		hasConflicts bool
+		status       string
+		want         string
+	}{
+		{false, "mergeable", MergeStateMergeable},
+		{false, "not_approved", MergeStateMergeable},
+		{false, "ci_must_pass", MergeStateMergeable},
+		{true, "mergeable", MergeStateConflicting},
+		{false, "conflict", MergeStateConflicting},
+		{false, "need_rebase", MergeStateConflicting},
+		{false, "checking", ""},
+		{false, "", ""},
+	}
+
+	for _, tt := range tests {
+		if got := gitlabMergeable(tt.hasConflicts, tt.status); got != tt.want {
+			t.Errorf("gitlabMergeable(%v, %q) = %q, want %q", tt.hasConflicts, tt.status, got, tt.want)
+		}
+	}
+}
+
 func TestGitHub_FormatState(t *testing.T) {
 	t.Parallel()
 
@@ -129,6 +190,28 @@ func TestGitLab_Name(t *testing.T) {
 	}
 }
 
+func TestPRHeadRef(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		forge Forge
+		want  string
+	}{
+		{&GitHub{}, "refs/pull/7/head"},
+		{&GitHubAPI{}, "refs/pull/7/head"},
+		{&GitLab{}, "refs/merge-requests/7/head"},
+		{&GitLabAPI{}, "refs/merge-requests/7/head"},
+		{&Gitea{}, "refs/pull/7/head"},
+		{&BitbucketCloud{}, ""},
+		{&BitbucketDataCenter{}, "refs/pull-requests/7/from"},
+	}
+	for _, tt := range tests {
+		if got := tt.forge.PRHeadRef(7); got != tt.want {
+			t.Errorf("%T.PRHeadRef(7) = %q, want %q", tt.forge, got, tt.want)
+		}
+	}
+}
+
 func TestConfigureBareRepo(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,604 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/raphi011/wt/internal/browser"
+	"github.com/raphi011/wt/internal/config"
+)
+
+// giteaPageSize is the page size for Gitea list requests. Gitea caps pages
+// at MAX_RESPONSE_ITEMS (50 by default).
+const giteaPageSize = 50
+
+// giteaMaxPages bounds how many pages GetPRForBranch scans for a branch.
+const giteaMaxPages = 10
+
+// Gitea implements Forge for Gitea and Forgejo (e.g. Codeberg) repositories
+// using the REST API. There is no CLI backend: Gitea is always accessed over
+// HTTP, regardless of [forge] backend.
+type Gitea struct {
+	ForgeConfig *config.ForgeConfig
+	Host        string       // Gitea host (required unless WebURL or BaseURL is set)
+	WebURL      string       // instance URL (scheme://host[:port]); derived from Host when empty
+	BaseURL     string       // API base URL override (tests); derived from WebURL when empty
+	HTTPClient  *http.Client // optional; defaults to a client with apiTimeout
+}
+
+// Name returns "gitea"
+func (g *Gitea) Name() string {
+	return "gitea"
+}
+
+// webURL returns the instance URL without trailing slash.
+func (g *Gitea) webURL() string {
+	if g.WebURL != "" {
+		return strings.TrimSuffix(g.WebURL, "/")
+	}
+	return "https://" + g.Host
+}
+
+// apiURL returns the API URL for path (which must start with "/").
+func (g *Gitea) apiURL(path string) string {
+	if g.BaseURL != "" {
+		return strings.TrimSuffix(g.BaseURL, "/") + path
+	}
+	return g.webURL() + "/api/v1" + path
+}
+
+// repoURL returns the API URL for a path below /repos/{owner}/{repo}.
+func (g *Gitea) repoURL(repoPath, path string) string {
+	return g.apiURL("/repos/" + escapePathSegments(repoPath) + path)
+}
+
+// client returns an authenticated API client.
+func (g *Gitea) client(ctx context.Context) (*apiClient, error) {
+	if g.Host == "" && g.WebURL == "" && g.BaseURL == "" {
+		return nil, fmt.Errorf("no host configured for gitea: add it to [hosts] or set host in the matching [[forge.rules]]")
+	}
+	token, err := resolveToken(ctx, g.ForgeConfig, g.Host, "", []string{"GITEA_TOKEN", "FORGEJO_TOKEN"}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &apiClient{http: g.HTTPClient, token: token}, nil
+}
+
+// Check verifies that an API token is available and valid
+func (g *Gitea) Check(ctx context.Context) error {
+	c, err := g.client(ctx)
+	if err != nil {
+		return err
+	}
+	var user struct {
+		Login string `json:"login"`
+	}
+	if err := c.do(ctx, http.MethodGet, g.apiURL("/user"), nil, &user); err != nil {
+		return fmt.Errorf("gitea api auth check failed: %w", err)
+	}
+	return nil
+}
+
+// giteaBranch is the REST shape of a pull request's head or base.
+type giteaBranch struct {
+	Ref    string `json:"ref"`
+	RepoID int64  `json:"repo_id"`
+	Repo   *struct {
+		FullName string `json:"full_name"`
+		CloneURL string `json:"clone_url"`
+	} `json:"repo"` // null if the repository was deleted
+}
+
+// giteaPR is the REST shape of a pull request.
+type giteaPR struct {
+	Number   int    `json:"number"`
+	Title    string `json:"title"`
+	Body     string `json:"body"`
+	State    string `json:"state"` // open, closed
+	Merged   bool   `json:"merged"`
+	Draft    bool   `json:"draft"` // Forgejo / newer Gitea; older versions only use a WIP title prefix
+	HTMLURL  string `json:"html_url"`
+	Comments int    `json:"comments"`
+	User     struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	Head      giteaBranch `json:"head"`
+	Base      giteaBranch `json:"base"`
+	Mergeable bool        `json:"mergeable"`
+	UpdatedAt time.Time   `json:"updated_at"`
+	Labels    []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	RequestedReviewers []struct {
+		Login string `json:"login"`
+	} `json:"requested_reviewers"`
+	AllowMaintainerEdit bool `json:"allow_maintainer_edit"`
+}
+
+// fork returns the head repository (owner/repo, empty if deleted) and
+// whether the PR is from a fork.
+func (pr giteaPR) fork() (repoPath string, isFork bool) {
+	if pr.Head.RepoID == 0 || pr.Base.RepoID == 0 || pr.Head.RepoID == pr.Base.RepoID {
+		return "", false
+	}
+	if pr.Head.Repo != nil {
+		repoPath = pr.Head.Repo.FullName
+	}
+	return repoPath, true
+}
+
+// giteaWIPPrefixes are the default title prefixes Gitea treats as work in progress.
+var giteaWIPPrefixes = []string{"WIP:", "[WIP]"}
+
+// isDraft reports whether the PR is a draft (work in progress).
+func (pr giteaPR) isDraft() bool {
+	if pr.Draft {
+		return true
+	}
+	title := strings.ToUpper(strings.TrimSpace(pr.Title))
+	for _, prefix := range giteaWIPPrefixes {
+		if strings.HasPrefix(title, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// toPRInfo converts a REST pull request to PRInfo.
+// Gitea doesn't include CI status in PR listings, so Checks stays unknown.
+func (pr giteaPR) toPRInfo() *PRInfo {
+	info := &PRInfo{
+		Number:       pr.Number,
+		State:        normalizeGiteaState(pr.State, pr.Merged),
+		IsDraft:      pr.isDraft(),
+		URL:          pr.HTMLURL,
+		Author:       pr.User.Login,
+		CommentCount: pr.Comments,
+		CachedAt:     time.Now(),
+		Fetched:      true,
+		Title:        pr.Title,
+		BaseBranch:   pr.Base.Ref,
+		UpdatedAt:    pr.UpdatedAt,
+	}
+	for _, l := range pr.Labels {
+		info.Labels = append(info.Labels, l.Name)
+	}
+	for _, r := range pr.RequestedReviewers {
+		info.ReviewRequested = append(info.ReviewRequested, r.Login)
+	}
+	if info.State == PRStateOpen {
+		info.Mergeable = MergeStateConflicting
+		if pr.Mergeable {
+			info.Mergeable = MergeStateMergeable
+		}
+	}
+	return info
+}
+
+// listPRs lists pull requests in the given state ("open", "closed", "all"),
+// newest first, reading pages until maxPages is reached, a page comes back
+// short, or stop returns true for a page. complete reports whether all PRs
+// were read.
+func (g *Gitea) listPRs(ctx context.Context, c *apiClient, repoPath, state string, maxPages int, stop func([]giteaPR) bool) (prs []giteaPR, complete bool, err error) {
+	for page := 1; page <= maxPages; page++ {
+		query := url.Values{
+			"state": {state},
+			"sort":  {"newest"},
+			"page":  {fmt.Sprint(page)},
+			"limit": {fmt.Sprint(giteaPageSize)},
+		}
+		var batch []giteaPR
+		if err := c.do(ctx, http.MethodGet, g.repoURL(repoPath, "/pulls?"+query.Encode()), nil, &batch); err != nil {
+			return nil, false, err
+		}
+		prs = append(prs, batch...)
+		if len(batch) < giteaPageSize {
+			complete = true
+			break
+		}
+		if stop != nil && stop(batch) {
+			break
+		}
+	}
+
+	// Don't rely on the server's sort support: order newest (highest number) first
+	sort.SliceStable(prs, func(i, j int) bool { return prs[i].Number > prs[j].Number })
+	return prs, complete, nil
+}
+
+// GetPRForBranch fetches PR info for a branch. Gitea's API can't filter
+// pull requests by head branch, so recent PRs are scanned.
+func (g *Gitea) GetPRForBranch(ctx context.Context, repoURL, branch string) (*PRInfo, error) {
+	repoPath := ExtractRepoPath(repoURL)
+	c, err := g.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	hasBranch := func(batch []giteaPR) bool {
+		for _, pr := range batch {
+			if pr.Head.Ref == branch {
+				return true
+			}
+		}
+		return false
+	}
+	prs, _, err := g.listPRs(ctx, c, repoPath, "all", giteaMaxPages, hasBranch)
+	if err != nil {
+		return nil, fmt.Errorf("gitea api request failed: %w", err)
+	}
+
+	for _, pr := range prs {
+		if pr.Head.Ref == branch {
+			return pr.toPRInfo(), nil
+		}
+	}
+	// No PR found - return marker indicating we checked
+	return noPR(), nil
+}
+
+// GetPRsForBranches lists the repository's most recent PRs (all states) and
+// matches them to branches locally. Branches not covered by a truncated
+// listing fall back to GetPRForBranch.
+func (g *Gitea) GetPRsForBranches(ctx context.Context, repoURL string, branches []string) (map[string]*PRInfo, error) {
+	repoPath := ExtractRepoPath(repoURL)
+	c, err := g.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	list, complete, err := g.listPRs(ctx, c, repoPath, "all", listLimit/giteaPageSize, nil)
+	if err != nil {
+		return nil, fmt.Errorf("gitea api request failed: %w", err)
+	}
+
+	prs := make([]branchPR, len(list))
+	for i, pr := range list {
+		prs[i] = branchPR{Branch: pr.Head.Ref, Info: pr.toPRInfo()}
+	}
+
+	return matchBranchPRs(ctx, branches, prs, complete, func(ctx context.Context, branch string) (*PRInfo, error) {
+		return g.GetPRForBranch(ctx, repoURL, branch)
+	})
+}
+
+// getPR fetches a single pull request.
+func (g *Gitea) getPR(ctx context.Context, c *apiClient, repoPath string, number int) (*giteaPR, error) {
+	var pr giteaPR
+	if err := c.do(ctx, http.MethodGet, g.repoURL(repoPath, fmt.Sprintf("/pulls/%d", number)), nil, &pr); err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+// GetPRHead fetches the head branch and repository for a PR number
+func (g *Gitea) GetPRHead(ctx context.Context, repoURL string, number int) (*PRHead, error) {
+	repoPath := ExtractRepoPath(repoURL)
+	c, err := g.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, err := g.getPR(ctx, c, repoPath, number)
+	if err != nil {
+		return nil, fmt.Errorf("gitea api request failed: %w", err)
+	}
+
+	if pr.Head.Ref == "" {
+		return nil, fmt.Errorf("PR #%d has no head branch", number)
+	}
+
+	if forkPath, isFork := pr.fork(); isFork {
+		var cloneURL string
+		if pr.Head.Repo != nil {
+			cloneURL = pr.Head.Repo.CloneURL
+		}
+		return newForkHead(repoURL, pr.Head.Ref, forkPath, cloneURL, pr.AllowMaintainerEdit), nil
+	}
+
+	return &PRHead{Branch: pr.Head.Ref}, nil
+}
+
+// validateGiteaSpec validates an owner/repo spec and returns the repo name.
+func validateGiteaSpec(repoSpec string) (string, error) {
+	owner, name, ok := strings.Cut(repoSpec, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return "", fmt.Errorf("invalid repo spec %q: expected owner/repo format", repoSpec)
+	}
+	return name, nil
+}
+
+// cloneURL returns the HTTPS clone URL for an owner/repo spec.
+func (g *Gitea) cloneURL(repoSpec string) string {
+	return g.webURL() + "/" + repoSpec + ".git"
+}
+
+// CloneRepo clones a Gitea repo over HTTPS using git
+func (g *Gitea) CloneRepo(ctx context.Context, repoSpec, destPath string) (string, error) {
+	repoName, err := validateGiteaSpec(repoSpec)
+	if err != nil {
+		return "", err
+	}
+	clonePath := filepath.Join(destPath, repoName)
+
+	if err := cloneWithGit(ctx, g.cloneURL(repoSpec), clonePath, false); err != nil {
+		return "", err
+	}
+	return clonePath, nil
+}
+
+// CloneBareRepo clones a Gitea repo as a bare repo inside .git directory
+func (g *Gitea) CloneBareRepo(ctx context.Context, repoSpec, destPath string) (string, error) {
+	repoName, err := validateGiteaSpec(repoSpec)
+	if err != nil {
+		return "", err
+	}
+	repoDir := filepath.Join(destPath, repoName)
+
+	if err := cloneWithGit(ctx, g.cloneURL(repoSpec), repoDir, true); err != nil {
+		return "", err
+	}
+	return repoDir, nil
+}
+
+// CreatePR creates a new PR. Drafts are created with Gitea's "WIP:" title prefix.
+func (g *Gitea) CreatePR(ctx context.Context, repoURL string, params CreatePRParams) (*CreatePRResult, error) {
+	repoPath := ExtractRepoPath(repoURL)
+	if params.Head == "" {
+		return nil, fmt.Errorf("head branch is required")
+	}
+	c, err := g.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	base := params.Base
+	if base == "" {
+		var repo struct {
+			DefaultBranch string `json:"default_branch"`
+		}
+		if err := c.do(ctx, http.MethodGet, g.repoURL(repoPath, ""), nil, &repo); err != nil {
+			return nil, fmt.Errorf("failed to determine default branch: %w", err)
+		}
+		base = repo.DefaultBranch
+	}
+
+	title := params.Title
+	if params.Draft {
+		title = "WIP: " + title
+	}
+
+	var pr giteaPR
+	if err := c.do(ctx, http.MethodPost, g.repoURL(repoPath, "/pulls"), map[string]any{
+		"head":  params.Head,
+		"base":  base,
+		"title": title,
+		"body":  params.Body,
+	}, &pr); err != nil {
+		return nil, fmt.Errorf("create PR failed: %w", err)
+	}
+
+	return &CreatePRResult{
+		Number: pr.Number,
+		URL:    pr.HTMLURL,
+	}, nil
+}
+
+// MergePR merges a PR by number with the given strategy and deletes the head branch
+func (g *Gitea) MergePR(ctx context.Context, repoURL string, number int, strategy string) error {
+	repoPath := ExtractRepoPath(repoURL)
+	c, err := g.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Gitea's merge styles: merge, rebase, rebase-merge, squash
+	if err := c.do(ctx, http.MethodPost, g.repoURL(repoPath, fmt.Sprintf("/pulls/%d/merge", number)), map[string]any{
+		"Do":                        strategy,
+		"delete_branch_after_merge": true,
+	}, nil); err != nil {
+		return fmt.Errorf("merge failed: %w", err)
+	}
+	return nil
+}
+
+// UpdatePRBase changes the base branch of a PR
+func (g *Gitea) UpdatePRBase(ctx context.Context, repoURL string, number int, base string) error {
+	repoPath := ExtractRepoPath(repoURL)
+	c, err := g.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := c.do(ctx, http.MethodPatch, g.repoURL(repoPath, fmt.Sprintf("/pulls/%d", number)), map[string]any{
+		"base": base,
+	}, nil); err != nil {
+		return fmt.Errorf("update base failed: %w", err)
+	}
+	return nil
+}
+
+// giteaReview is the subset of a REST pull request review used to find its comments.
+type giteaReview struct {
+	ID            int `json:"id"`
+	CommentsCount int `json:"comments_count"`
+}
+
+// giteaReviewComment is the REST shape of a review comment.
+type giteaReviewComment struct {
+	Body string `json:"body"`
+	User struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	Resolver *struct {
+		Login string `json:"login"`
+	} `json:"resolver"` // set once the conversation is resolved
+	Path             string    `json:"path"`
+	Position         int       `json:"position"`          // line in the new version, 0 for old-side comments
+	OriginalPosition int       `json:"original_position"` // line in the old version
+	HTMLURL          string    `json:"html_url"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// ListReviewThreads lists the review conversations of a PR. Gitea has no
+// thread resource; replies are comments on the same line in later reviews,
+// so comments are grouped by file and line.
+func (g *Gitea) ListReviewThreads(ctx context.Context, repoURL string, number int) ([]ReviewThread, error) {
+	repoPath := ExtractRepoPath(repoURL)
+	c, err := g.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var reviews []giteaReview
+	for page := 1; page <= maxReviewPages; page++ {
+		query := url.Values{"page": {fmt.Sprint(page)}, "limit": {fmt.Sprint(giteaPageSize)}}
+		var batch []giteaReview
+		if err := c.do(ctx, http.MethodGet, g.repoURL(repoPath, fmt.Sprintf("/pulls/%d/reviews?%s", number, query.Encode())), nil, &batch); err != nil {
+			return nil, fmt.Errorf("gitea api request failed: %w", err)
+		}
+		reviews = append(reviews, batch...)
+		if len(batch) < giteaPageSize {
+			break
+		}
+	}
+
+	var comments []giteaReviewComment
+	for _, r := range reviews {
+		if r.CommentsCount == 0 {
+			continue
+		}
+		var batch []giteaReviewComment
+		if err := c.do(ctx, http.MethodGet, g.repoURL(repoPath, fmt.Sprintf("/pulls/%d/reviews/%d/comments", number, r.ID)), nil, &batch); err != nil {
+			return nil, fmt.Errorf("gitea api request failed: %w", err)
+		}
+		comments = append(comments, batch...)
+	}
+	sort.SliceStable(comments, func(i, j int) bool { return comments[i].CreatedAt.Before(comments[j].CreatedAt) })
+
+	type location struct {
+		path string
+		line int
+	}
+	index := make(map[location]int)
+	var threads []ReviewThread
+	for _, rc := range comments {
+		line := rc.Position
+		if line == 0 {
+			line = rc.OriginalPosition
+		}
+		loc := location{rc.Path, line}
+		i, ok := index[loc]
+		if !ok {
+			i = len(threads)
+			index[loc] = i
+			threads = append(threads, ReviewThread{Path: rc.Path, Line: line, URL: rc.HTMLURL})
+		}
+		if rc.Resolver != nil {
+			threads[i].Resolved = true
+		}
+		threads[i].Comments = append(threads[i].Comments, ReviewComment{
+			Author:    rc.User.Login,
+			Body:      rc.Body,
+			CreatedAt: rc.CreatedAt,
+		})
+	}
+
+	sortReviewThreads(threads)
+	return threads, nil
+}
+
+// ViewPR shows PR details or opens in browser
+func (g *Gitea) ViewPR(ctx context.Context, repoURL string, number int, web bool) error {
+	repoPath := ExtractRepoPath(repoURL)
+	c, err := g.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	pr, err := g.getPR(ctx, c, repoPath, number)
+	if err != nil {
+		return fmt.Errorf("gitea api request failed: %w", err)
+	}
+
+	if web {
+		return browser.Open(ctx, pr.HTMLURL)
+	}
+
+	state := normalizeGiteaState(pr.State, pr.Merged)
+	if pr.isDraft() && state == PRStateOpen {
+		state = PRStateDraft
+	}
+	printPRDetails(os.Stdout, pr.Title, fmt.Sprintf("#%d", pr.Number), g.FormatState(state), pr.User.Login, pr.HTMLURL, pr.Body)
+	return nil
+}
+
+// ListOpenPRs lists all open PRs for a repository
+func (g *Gitea) ListOpenPRs(ctx context.Context, repoURL string) ([]OpenPR, error) {
+	repoPath := ExtractRepoPath(repoURL)
+	c, err := g.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	prs, _, err := g.listPRs(ctx, c, repoPath, "open", listLimit/giteaPageSize, nil)
+	if err != nil {
+		return nil, fmt.Errorf("gitea api request failed: %w", err)
+	}
+
+	result := make([]OpenPR, len(prs))
+	for i, pr := range prs {
+		result[i] = newOpenPR(pr.toPRInfo(), pr.Head.Ref)
+		result[i].HeadRepo, result[i].IsFork = pr.fork()
+	}
+
+	return result, nil
+}
+
+// CurrentUser returns the login of the token's user
+func (g *Gitea) CurrentUser(ctx context.Context, repoURL string) (string, error) {
+	c, err := g.client(ctx)
+	if err != nil {
+		return "", err
+	}
+	var user struct {
+		Login string `json:"login"`
+	}
+	if err := c.do(ctx, http.MethodGet, g.apiURL("/user"), nil, &user); err != nil {
+		return "", fmt.Errorf("gitea api request failed: %w", err)
+	}
+	return user.Login, nil
+}
+
+// PRHeadRef returns the ref Gitea/Forgejo keeps the head of a PR under
+func (g *Gitea) PRHeadRef(number int) string {
+	return fmt.Sprintf("refs/pull/%d/head", number)
+}
+
+// FormatState returns a human-readable PR state
+func (g *Gitea) FormatState(state string) string {
+	return (&GitLab{}).FormatState(state)
+}
+
+// normalizeGiteaState converts Gitea's state (open/closed plus a merged
+// flag) to the normalized format
+func normalizeGiteaState(state string, merged bool) string {
+	if merged {
+		return PRStateMerged
+	}
+	switch strings.ToLower(state) {
+	case "open":
+		return PRStateOpen
+	case "closed":
+		return PRStateClosed
+	default:
+		return strings.ToUpper(state)
+	}
+}
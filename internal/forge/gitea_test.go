@@ -0,0 +1,329 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/raphi011/wt/internal/config"
+)
+
+// newTestGitea returns a Gitea forge pointed at an httptest server running handler.
+// Requests without the configured token are rejected with 401.
+func newTestGitea(t *testing.T, handler http.HandlerFunc) *Gitea {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			io.WriteString(w, `{"message":"token is required"}`)
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	return &Gitea{
+		ForgeConfig: &config.ForgeConfig{Tokens: map[string]string{"git.test": "test-token"}},
+		Host:        "git.test",
+		BaseURL:     srv.URL,
+	}
+}
+
+func TestGitea_GetPRForBranch(t *testing.T) {
+	t.Parallel()
+
+	g := newTestGitea(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/org/repo/pulls" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		if r.URL.Query().Get("state") != "all" {
+			t.Errorf("state = %q, want all", r.URL.Query().Get("state"))
+		}
+		io.WriteString(w, `[
+			{"number":7,"title":"WIP: Add feature","state":"open","merged":false,
+			 "html_url":"https://git.test/org/repo/pulls/7","comments":3,
+			 "user":{"login":"bob"},"head":{"ref":"feature"},"base":{"ref":"main"},
+			 "mergeable":true,"updated_at":"2026-01-02T03:04:05Z",
+			 "labels":[{"name":"bug"}],"requested_reviewers":[{"login":"carol"}]},
+			{"number":5,"title":"Fix","state":"closed","merged":true,
+			 "html_url":"https://git.test/org/repo/pulls/5","user":{"login":"alice"},"head":{"ref":"fix"}}
+		]`)
+	})
+
+	ctx := context.Background()
+
+	pr, err := g.GetPRForBranch(ctx, "git@git.test:org/repo.git", "feature")
+	if err != nil {
+		t.Fatalf("GetPRForBranch() error = %v", err)
+	}
+	want := PRInfo{Number: 7, State: PRStateOpen, IsDraft: true,
+		URL: "https://git.test/org/repo/pulls/7", Author: "bob", CommentCount: 3, Fetched: true,
+		Title: "WIP: Add feature", BaseBranch: "main", Mergeable: MergeStateMergeable,
+		ReviewRequested: []string{"carol"}, Labels: []string{"bug"}, UpdatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}
+	pr.CachedAt = want.CachedAt
+	if !reflect.DeepEqual(*pr, want) {
+		t.Errorf("GetPRForBranch() = %+v, want %+v", *pr, want)
+	}
+
+	pr, err = g.GetPRForBranch(ctx, "git@git.test:org/repo.git", "fix")
+	if err != nil {
+		t.Fatalf("GetPRForBranch() error = %v", err)
+	}
+	if pr.State != PRStateMerged {
+		t.Errorf("merged PR state = %q, want %q", pr.State, PRStateMerged)
+	}
+
+	pr, err = g.GetPRForBranch(ctx, "git@git.test:org/repo.git", "no-pr")
+	if err != nil {
+		t.Fatalf("GetPRForBranch() error = %v", err)
+	}
+	if pr.Number != 0 || !pr.Fetched {
+		t.Errorf("expected fetched marker without PR, got %+v", *pr)
+	}
+}
+
+func TestGitea_GetPRsForBranches_Paginates(t *testing.T) {
+	t.Parallel()
+
+	var pages []string
+	g := newTestGitea(t, func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		pages = append(pages, page)
+		var prs []string
+		switch page {
+		case "1":
+			// A full page: numbers 100..51, one of them for feat-a
+			for n := 100; n > 100-giteaPageSize; n-- {
+				branch := fmt.Sprintf("other-%d", n)
+				if n == 60 {
+					branch = "feat-a"
+				}
+				prs = append(prs, fmt.Sprintf(`{"number":%d,"state":"open","head":{"ref":%q}}`, n, branch))
+			}
+		case "2":
+			prs = append(prs, `{"number":3,"state":"closed","merged":true,"head":{"ref":"feat-b"}}`)
+		}
+		io.WriteString(w, "["+strings.Join(prs, ",")+"]")
+	})
+
+	prs, err := g.GetPRsForBranches(context.Background(), "https://git.test/org/repo.git", []string{"feat-a", "feat-b", "feat-c"})
+	if err != nil {
+		t.Fatalf("GetPRsForBranches() error = %v", err)
+	}
+	if strings.Join(pages, ",") != "1,2" {
+		t.Errorf("requested pages %v, want 1,2", pages)
+	}
+	if prs["feat-a"].Number != 60 || prs["feat-a"].State != PRStateOpen {
+		t.Errorf("feat-a = %+v, want open PR #60", *prs["feat-a"])
+	}
+	if prs["feat-b"].State != PRStateMerged {
+		t.Errorf("feat-b state = %q, want %q", prs["feat-b"].State, PRStateMerged)
+	}
+	if pr := prs["feat-c"]; pr.Number != 0 || !pr.Fetched {
+		t.Errorf("feat-c = %+v, want fetched marker", *pr)
+	}
+}
+
+func TestGitea_GetPRHead(t *testing.T) {
+	t.Parallel()
+
+	g := newTestGitea(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/org/repo/pulls/1":
+			io.WriteString(w, `{"number":1,"head":{"ref":"feature","repo_id":10},"base":{"ref":"main","repo_id":10}}`)
+		case "/repos/org/repo/pulls/2":
+			io.WriteString(w, `{"number":2,"allow_maintainer_edit":true,
+				"head":{"ref":"patch","repo_id":99,"repo":{"full_name":"someone/repo","clone_url":"https://git.test/someone/repo.git"}},
+				"base":{"ref":"main","repo_id":10}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `{"message":"not found"}`)
+		}
+	})
+
+	ctx := context.Background()
+
+	head, err := g.GetPRHead(ctx, "https://git.test/org/repo.git", 1)
+	if err != nil || !reflect.DeepEqual(*head, PRHead{Branch: "feature"}) {
+		t.Errorf("GetPRHead(1) = %+v, %v; want branch feature", head, err)
+	}
+
+	head, err = g.GetPRHead(ctx, "https://git.test/org/repo.git", 2)
+	want := PRHead{Branch: "patch", IsFork: true, RepoPath: "someone/repo",
+		CloneURL: "https://git.test/someone/repo.git", MaintainerCanModify: true}
+	if err != nil || !reflect.DeepEqual(*head, want) {
+		t.Errorf("GetPRHead(2) = %+v, %v; want %+v", head, err, want)
+	}
+
+	if _, err := g.GetPRHead(ctx, "https://git.test/org/repo.git", 3); err == nil || !strings.Contains(err.Error(), "HTTP 404") {
+		t.Errorf("GetPRHead(3) should fail with 404, got %v", err)
+	}
+}
+
+func TestGitea_CreatePR(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	g := newTestGitea(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/org/repo":
+			io.WriteString(w, `{"default_branch":"develop"}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/org/repo/pulls":
+			json.NewDecoder(r.Body).Decode(&got)
+			w.WriteHeader(http.StatusCreated)
+			io.WriteString(w, `{"number":8,"html_url":"https://git.test/org/repo/pulls/8"}`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	})
+
+	result, err := g.CreatePR(context.Background(), "git@git.test:org/repo.git", CreatePRParams{
+		Title: "Add feature",
+		Body:  "Details",
+		Head:  "feature",
+		Draft: true,
+	})
+	if err != nil {
+		t.Fatalf("CreatePR() error = %v", err)
+	}
+	if result.Number != 8 || result.URL != "https://git.test/org/repo/pulls/8" {
+		t.Errorf("CreatePR() = %+v, want #8", *result)
+	}
+	if got["base"] != "develop" || got["head"] != "feature" || got["title"] != "WIP: Add feature" || got["body"] != "Details" {
+		t.Errorf("unexpected request body: %v", got)
+	}
+}
+
+func TestGitea_MergePR(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	g := newTestGitea(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/repos/org/repo/pulls/4/merge" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+	})
+
+	if err := g.MergePR(context.Background(), "git@git.test:org/repo.git", 4, "rebase"); err != nil {
+		t.Fatalf("MergePR() error = %v", err)
+	}
+	if got["Do"] != "rebase" || got["delete_branch_after_merge"] != true {
+		t.Errorf("unexpected request body: %v", got)
+	}
+}
+
+func TestGitea_UpdatePRBase(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	g := newTestGitea(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != "/repos/org/repo/pulls/4" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		io.WriteString(w, `{"number":4}`)
+	})
+
+	if err := g.UpdatePRBase(context.Background(), "git@git.test:org/repo.git", 4, "main"); err != nil {
+		t.Fatalf("UpdatePRBase() error = %v", err)
+	}
+	if got["base"] != "main" {
+		t.Errorf("unexpected request body: %v", got)
+	}
+}
+
+func TestGitea_ListReviewThreads(t *testing.T) {
+	t.Parallel()
+
+	g := newTestGitea(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/org/repo/pulls/4/reviews":
+			io.WriteString(w, `[{"id": 1, "comments_count": 2}, {"id": 2, "comments_count": 0}, {"id": 3, "comments_count": 1}]`)
+		case "/repos/org/repo/pulls/4/reviews/1/comments":
+			io.WriteString(w, `[
+				{"body": "Rename this", "user": {"login": "bob"}, "resolver": {"login": "alice"}, "path": "main.go", "position": 8,
+					"html_url": "https://git.test/org/repo/pulls/4#issuecomment-1", "created_at": "2026-01-02T00:00:00Z"},
+				{"body": "Why remove?", "user": {"login": "bob"}, "path": "old.go", "position": 0, "original_position": 3,
+					"html_url": "https://git.test/org/repo/pulls/4#issuecomment-2", "created_at": "2026-01-02T00:01:00Z"}]`)
+		case "/repos/org/repo/pulls/4/reviews/3/comments":
+			io.WriteString(w, `[{"body": "Done", "user": {"login": "alice"}, "resolver": {"login": "alice"}, "path": "main.go", "position": 8,
+				"html_url": "https://git.test/org/repo/pulls/4#issuecomment-3", "created_at": "2026-01-03T00:00:00Z"}]`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	})
+
+	threads, err := g.ListReviewThreads(context.Background(), "git@git.test:org/repo.git", 4)
+	if err != nil {
+		t.Fatalf("ListReviewThreads() error = %v", err)
+	}
+
+	want := []ReviewThread{
+		{Path: "main.go", Line: 8, Resolved: true, URL: "https://git.test/org/repo/pulls/4#issuecomment-1", Comments: []ReviewComment{
+			{Author: "bob", Body: "Rename this", CreatedAt: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)},
+			{Author: "alice", Body: "Done", CreatedAt: time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)},
+		}},
+		{Path: "old.go", Line: 3, URL: "https://git.test/org/repo/pulls/4#issuecomment-2", Comments: []ReviewComment{
+			{Author: "bob", Body: "Why remove?", CreatedAt: time.Date(2026, 1, 2, 0, 1, 0, 0, time.UTC)},
+		}},
+	}
+	if !reflect.DeepEqual(threads, want) {
+		t.Errorf("ListReviewThreads() = %+v, want %+v", threads, want)
+	}
+}
+
+func TestGitea_ListOpenPRs(t *testing.T) {
+	t.Parallel()
+
+	g := newTestGitea(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("state") != "open" {
+			t.Errorf("state = %q, want open", r.URL.Query().Get("state"))
+		}
+		io.WriteString(w, `[
+			{"number":1,"title":"One","state":"open","user":{"login":"alice"},"head":{"ref":"one"}},
+			{"number":2,"title":"[WIP] Two","state":"open","user":{"login":"bob"},
+			 "head":{"ref":"main","repo_id":2,"repo":{"full_name":"bob/repo"}},"base":{"repo_id":1}}
+		]`)
+	})
+
+	prs, err := g.ListOpenPRs(context.Background(), "git@git.test:org/repo.git")
+	if err != nil {
+		t.Fatalf("ListOpenPRs() error = %v", err)
+	}
+	want := []OpenPR{
+		{Number: 2, Title: "[WIP] Two", Author: "bob", Branch: "main", IsFork: true, HeadRepo: "bob/repo", IsDraft: true},
+		{Number: 1, Title: "One", Author: "alice", Branch: "one"},
+	}
+	if !reflect.DeepEqual(prs, want) {
+		t.Errorf("ListOpenPRs() = %+v, want %+v", prs, want)
+	}
+}
+
+func TestGitea_Unauthorized(t *testing.T) {
+	t.Parallel()
+
+	g := newTestGitea(t, func(w http.ResponseWriter, r *http.Request) {})
+	g.ForgeConfig.Tokens["git.test"] = "wrong-token"
+
+	err := g.Check(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "HTTP 401") {
+		t.Errorf("Check() should fail with 401, got %v", err)
+	}
+}
+
+func TestGitea_NoHost(t *testing.T) {
+	t.Parallel()
+
+	g := ByNameWithConfig("gitea", &config.ForgeConfig{})
+	if err := g.Check(context.Background()); err == nil || !strings.Contains(err.Error(), "no host configured") {
+		t.Errorf("Check() without host should fail, got %v", err)
+	}
+}
@@ -49,22 +49,31 @@ func (g *GitHub) GetPRForBranch(ctx context.Context, repoURL, branch string) (*P
 		"-R", repoPath,
 		"--head", branch,
 		"--state", "all",
-		"--json", "number,state,isDraft,url,author,comments,reviewDecision",
+		"--json", "number,title,state,isDraft,url,author,comments,reviewDecision,baseRefName,mergeable,updatedAt,reviewRequests,labels,statusCheckRollup",
 		"--limit", "1")
 	if err != nil {
 		return nil, fmt.Errorf("gh command failed: %v", err)
 	}
 
 	var prs []struct {
-		Number  int    `json:"number"`
-		State   string `json:"state"`
-		IsDraft bool   `json:"isDraft"`
-		URL     string `json:"url"`
-		Author  struct {
+		Number      int       `json:"number"`
+		Title       string    `json:"title"`
+		State       string    `json:"state"`
+		IsDraft     bool      `json:"isDraft"`
+		URL         string    `json:"url"`
+		BaseRefName string    `json:"baseRefName"`
+		Mergeable   string    `json:"mergeable"`
+		UpdatedAt   time.Time `json:"updatedAt"`
+		Author      struct {
 			Login string `json:"login"`
 		} `json:"author"`
-		Comments       []any  `json:"comments"` // just need the count
-		ReviewDecision string `json:"reviewDecision"`
+		Comments          []any                `json:"comments"` // just need the count
+		ReviewDecision    string               `json:"reviewDecision"`
+		ReviewRequests    []githubReviewer     `json:"reviewRequests"`
+		StatusCheckRollup []githubCheckContext `json:"statusCheckRollup"`
+		Labels            []struct {
+			Name string `json:"name"`
+		} `json:"labels"`
 	}
 	if err := json.Unmarshal(output, &prs); err != nil {
 		return nil, fmt.Errorf("failed to parse gh output: %w", err)
@@ -79,7 +88,7 @@ func (g *GitHub) GetPRForBranch(ctx context.Context, repoURL, branch string) (*P
 	}
 
 	pr := prs[0]
-	return &PRInfo{
+	info := &PRInfo{
 		Number:       pr.Number,
 		State:        pr.State, // GitHub already uses OPEN, MERGED, CLOSED
 		IsDraft:      pr.IsDraft,
@@ -90,37 +99,141 @@ func (g *GitHub) GetPRForBranch(ctx context.Context, repoURL, branch string) (*P
 		IsApproved:   pr.ReviewDecision == "APPROVED",
 		CachedAt:     time.Now(),
 		Fetched:      true,
-	}, nil
+		Title:        pr.Title,
+		BaseBranch:   pr.BaseRefName,
+		Checks:       githubChecksRollup(pr.StatusCheckRollup),
+		Mergeable:    normalizeGitHubMergeable(pr.Mergeable),
+		UpdatedAt:    pr.UpdatedAt,
+	}
+	for _, r := range pr.ReviewRequests {
+		if name := r.String(); name != "" {
+			info.ReviewRequested = append(info.ReviewRequested, name)
+		}
+	}
+	for _, l := range pr.Labels {
+		info.Labels = append(info.Labels, l.Name)
+	}
+	return info, nil
+}
+
+// githubCheckContext is one entry of gh's statusCheckRollup: either a check
+// run (status + conclusion) or a commit status context (state).
+type githubCheckContext struct {
+	Status     string `json:"status"`     // CheckRun: QUEUED, IN_PROGRESS, COMPLETED, ...
+	Conclusion string `json:"conclusion"` // CheckRun: SUCCESS, FAILURE, NEUTRAL, SKIPPED, ...
+	State      string `json:"state"`      // StatusContext: SUCCESS, FAILURE, ERROR, PENDING, EXPECTED
+}
+
+// githubChecksRollup combines individual checks into a single CheckState the
+// way GitHub does: any failure fails, otherwise anything unfinished is pending.
+func githubChecksRollup(contexts []githubCheckContext) string {
+	if len(contexts) == 0 {
+		return ""
+	}
+	pending := false
+	for _, c := range contexts {
+		if c.State != "" {
+			switch normalizeGitHubChecks(c.State) {
+			case CheckStateFailing:
+				return CheckStateFailing
+			case CheckStatePending:
+				pending = true
+			}
+			continue
+		}
+		if !strings.EqualFold(c.Status, "COMPLETED") {
+			pending = true
+			continue
+		}
+		switch strings.ToUpper(c.Conclusion) {
+		case "FAILURE", "TIMED_OUT", "CANCELLED", "ACTION_REQUIRED", "STARTUP_FAILURE":
+			return CheckStateFailing
+		}
+	}
+	if pending {
+		return CheckStatePending
+	}
+	return CheckStatePassing
 }
 
-// GetPRBranch fetches the head branch name for a PR number using gh CLI
-func (g *GitHub) GetPRBranch(ctx context.Context, repoURL string, number int) (string, error) {
+// GetPRsForBranches fetches PR info for several branches using batched
+// GraphQL queries via gh api (one aliased lookup per branch)
+func (g *GitHub) GetPRsForBranches(ctx context.Context, repoURL string, branches []string) (map[string]*PRInfo, error) {
+	repoPath := ExtractRepoPath(repoURL)
+	owner, name, err := splitGitHubRepo(repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return githubPRsForBranches(owner, name, branches, func(query string, vars map[string]any, out any) error {
+		return g.graphql(ctx, repoPath, query, vars, out)
+	})
+}
+
+// graphql runs a GraphQL query via gh api and decodes the "data" field into
+// out. String variables are passed as-is, others (e.g. numbers) are typed.
+func (g *GitHub) graphql(ctx context.Context, repoPath, query string, vars map[string]any, out any) error {
+	args := []string{"api", "graphql", "-f", "query=" + query}
+	for k, v := range vars {
+		flag := "-F"
+		if _, ok := v.(string); ok {
+			flag = "-f"
+		}
+		args = append(args, flag, fmt.Sprintf("%s=%v", k, v))
+	}
+	output, err := g.outputWithUser(ctx, repoPath, args...)
+	if err != nil {
+		return fmt.Errorf("gh command failed: %v", err)
+	}
+
+	resp := struct {
+		Data any `json:"data"`
+	}{Data: out}
+	if err := json.Unmarshal(output, &resp); err != nil {
+		return fmt.Errorf("failed to parse gh output: %w", err)
+	}
+	return nil
+}
+
+// GetPRHead fetches the head branch and repository for a PR number using gh CLI
+func (g *GitHub) GetPRHead(ctx context.Context, repoURL string, number int) (*PRHead, error) {
 	repoPath := ExtractRepoPath(repoURL)
 	output, err := g.outputWithUser(ctx, repoPath, "pr", "view",
 		fmt.Sprintf("%d", number),
 		"-R", repoPath,
-		"--json", "headRefName,isCrossRepository")
+		"--json", "headRefName,isCrossRepository,headRepository,headRepositoryOwner,maintainerCanModify")
 	if err != nil {
-		return "", fmt.Errorf("gh command failed: %v", err)
+		return nil, fmt.Errorf("gh command failed: %v", err)
 	}
 
 	var result struct {
 		HeadRefName       string `json:"headRefName"`
 		IsCrossRepository bool   `json:"isCrossRepository"`
+		HeadRepository    *struct {
+			Name string `json:"name"`
+		} `json:"headRepository"`
+		HeadRepositoryOwner struct {
+			Login string `json:"login"`
+		} `json:"headRepositoryOwner"`
+		MaintainerCanModify bool `json:"maintainerCanModify"`
 	}
 	if err := json.Unmarshal(output, &result); err != nil {
-		return "", fmt.Errorf("failed to parse gh output: %w", err)
+		return nil, fmt.Errorf("failed to parse gh output: %w", err)
 	}
 
-	if result.IsCrossRepository {
-		return "", fmt.Errorf("PR #%d is from a fork - cross-repository PRs are not supported", number)
+	if result.HeadRefName == "" {
+		return nil, fmt.Errorf("PR #%d has no head branch", number)
 	}
 
-	if result.HeadRefName == "" {
-		return "", fmt.Errorf("PR #%d has no head branch", number)
+	if result.IsCrossRepository {
+		var forkPath string
+		if result.HeadRepository != nil && result.HeadRepositoryOwner.Login != "" {
+			forkPath = result.HeadRepositoryOwner.Login + "/" + result.HeadRepository.Name
+		}
+		return newForkHead(repoURL, result.HeadRefName, forkPath, "", result.MaintainerCanModify), nil
 	}
 
-	return result.HeadRefName, nil
+	return &PRHead{Branch: result.HeadRefName}, nil
 }
 
 // CloneRepo clones a GitHub repo using gh CLI
@@ -242,6 +355,30 @@ func (g *GitHub) MergePR(ctx context.Context, repoURL string, number int, strate
 	return nil
 }
 
+// UpdatePRBase changes the base branch of a PR
+func (g *GitHub) UpdatePRBase(ctx context.Context, repoURL string, number int, base string) error {
+	repoPath := ExtractRepoPath(repoURL)
+	if err := g.runWithUser(ctx, repoPath, "pr", "edit", fmt.Sprintf("%d", number),
+		"-R", repoPath,
+		"--base", base); err != nil {
+		return fmt.Errorf("update base failed: %v", err)
+	}
+	return nil
+}
+
+// ListReviewThreads lists the review threads of a PR using GraphQL via gh api
+func (g *GitHub) ListReviewThreads(ctx context.Context, repoURL string, number int) ([]ReviewThread, error) {
+	repoPath := ExtractRepoPath(repoURL)
+	owner, name, err := splitGitHubRepo(repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return githubReviewThreads(owner, name, number, func(query string, vars map[string]any, out any) error {
+		return g.graphql(ctx, repoPath, query, vars, out)
+	})
+}
+
 // ViewPR shows PR details or opens in browser
 func (g *GitHub) ViewPR(ctx context.Context, repoURL string, number int, web bool) error {
 	repoPath := ExtractRepoPath(repoURL)
@@ -271,20 +408,33 @@ func (g *GitHub) ListOpenPRs(ctx context.Context, repoURL string) ([]OpenPR, err
 	output, err := g.outputWithUser(ctx, repoPath, "pr", "list",
 		"-R", repoPath,
 		"--state", "open",
-		"--json", "number,title,headRefName,author,isDraft",
+		"--json", "number,title,headRefName,isCrossRepository,headRepository,headRepositoryOwner,author,isDraft,url,labels,reviewRequests,updatedAt",
 		"--limit", "100")
 	if err != nil {
 		return nil, fmt.Errorf("gh command failed: %v", err)
 	}
 
 	var prs []struct {
-		Number      int    `json:"number"`
-		Title       string `json:"title"`
-		HeadRefName string `json:"headRefName"`
-		IsDraft     bool   `json:"isDraft"`
-		Author      struct {
+		Number            int    `json:"number"`
+		Title             string `json:"title"`
+		HeadRefName       string `json:"headRefName"`
+		IsCrossRepository bool   `json:"isCrossRepository"`
+		HeadRepository    *struct {
+			Name string `json:"name"`
+		} `json:"headRepository"`
+		HeadRepositoryOwner struct {
+			Login string `json:"login"`
+		} `json:"headRepositoryOwner"`
+		IsDraft   bool      `json:"isDraft"`
+		URL       string    `json:"url"`
+		UpdatedAt time.Time `json:"updatedAt"`
+		Author    struct {
 			Login string `json:"login"`
 		} `json:"author"`
+		ReviewRequests []githubReviewer `json:"reviewRequests"`
+		Labels         []struct {
+			Name string `json:"name"`
+		} `json:"labels"`
 	}
 	if err := json.Unmarshal(output, &prs); err != nil {
 		return nil, fmt.Errorf("failed to parse gh output: %w", err)
@@ -293,17 +443,45 @@ func (g *GitHub) ListOpenPRs(ctx context.Context, repoURL string) ([]OpenPR, err
 	result := make([]OpenPR, len(prs))
 	for i, pr := range prs {
 		result[i] = OpenPR{
-			Number:  pr.Number,
-			Title:   pr.Title,
-			Author:  pr.Author.Login,
-			Branch:  pr.HeadRefName,
-			IsDraft: pr.IsDraft,
+			Number:    pr.Number,
+			Title:     pr.Title,
+			Author:    pr.Author.Login,
+			Branch:    pr.HeadRefName,
+			IsFork:    pr.IsCrossRepository,
+			IsDraft:   pr.IsDraft,
+			URL:       pr.URL,
+			UpdatedAt: pr.UpdatedAt,
+		}
+		if pr.IsCrossRepository && pr.HeadRepository != nil && pr.HeadRepositoryOwner.Login != "" {
+			result[i].HeadRepo = pr.HeadRepositoryOwner.Login + "/" + pr.HeadRepository.Name
+		}
+		for _, r := range pr.ReviewRequests {
+			if name := r.String(); name != "" {
+				result[i].ReviewRequested = append(result[i].ReviewRequested, name)
+			}
+		}
+		for _, l := range pr.Labels {
+			result[i].Labels = append(result[i].Labels, l.Name)
 		}
 	}
 
 	return result, nil
 }
 
+// CurrentUser returns the login of the gh account used for the repo
+func (g *GitHub) CurrentUser(ctx context.Context, repoURL string) (string, error) {
+	output, err := g.outputWithUser(ctx, ExtractRepoPath(repoURL), "api", "user", "--jq", ".login")
+	if err != nil {
+		return "", fmt.Errorf("gh command failed: %v", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// PRHeadRef returns the ref GitHub keeps the head of a PR under
+func (g *GitHub) PRHeadRef(number int) string {
+	return fmt.Sprintf("refs/pull/%d/head", number)
+}
+
 // FormatState returns a human-readable PR state
 func (g *GitHub) FormatState(state string) string {
 	switch state {
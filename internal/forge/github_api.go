@@ -0,0 +1,677 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/raphi011/wt/internal/browser"
+	"github.com/raphi011/wt/internal/config"
+)
+
+// githubPublicHost is the host of the public GitHub instance.
+const githubPublicHost = "github.com"
+
+// GitHubAPI implements Forge for GitHub repositories using the REST and
+// GraphQL APIs directly (selected with [forge] backend = "api").
+type GitHubAPI struct {
+	ForgeConfig *config.ForgeConfig
+	Host        string       // GitHub host ("github.com" or an Enterprise host); empty = github.com
+	BaseURL     string       // REST API base URL override (tests); derived from Host when empty
+	HTTPClient  *http.Client // optional; defaults to a client with apiTimeout
+}
+
+// Name returns "github"
+func (g *GitHubAPI) Name() string {
+	return "github"
+}
+
+// host returns the normalized GitHub host.
+func (g *GitHubAPI) host() string {
+	return apiHost(g.Host, githubPublicHost)
+}
+
+// restURL returns the REST API URL for path (which must start with "/").
+func (g *GitHubAPI) restURL(path string) string {
+	switch {
+	case g.BaseURL != "":
+		return strings.TrimSuffix(g.BaseURL, "/") + path
+	case g.host() == githubPublicHost:
+		return "https://api.github.com" + path
+	default:
+		return "https://" + g.host() + "/api/v3" + path
+	}
+}
+
+// graphqlURL returns the GraphQL endpoint.
+func (g *GitHubAPI) graphqlURL() string {
+	switch {
+	case g.BaseURL != "":
+		return strings.TrimSuffix(g.BaseURL, "/") + "/graphql"
+	case g.host() == githubPublicHost:
+		return "https://api.github.com/graphql"
+	default:
+		return "https://" + g.host() + "/api/graphql"
+	}
+}
+
+// client returns an authenticated API client for a repo path (org/repo).
+// The repo path selects the gh user from forge rules for CLI credential lookup.
+func (g *GitHubAPI) client(ctx context.Context, repoPath string) (*apiClient, error) {
+	envVars := []string{"GH_TOKEN", "GITHUB_TOKEN"}
+	if g.host() != githubPublicHost {
+		envVars = []string{"GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN"}
+	}
+
+	var user string
+	if g.ForgeConfig != nil {
+		user = g.ForgeConfig.GetUserForRepo(repoPath)
+	}
+
+	token, err := resolveToken(ctx, g.ForgeConfig, g.host(), user, envVars, ghCLIToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return &apiClient{
+		http:  g.HTTPClient,
+		token: token,
+		headers: map[string]string{
+			"Accept":               "application/vnd.github+json",
+			"X-GitHub-Api-Version": "2022-11-28",
+		},
+	}, nil
+}
+
+// ghCLIToken reads a token from gh's credential store, if gh is installed.
+func ghCLIToken(ctx context.Context, host, user string) (string, error) {
+	args := []string{"auth", "token", "--hostname", host}
+	if user != "" {
+		args = append(args, "--user", user)
+	}
+	out, err := exec.CommandContext(ctx, "gh", args...).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// splitGitHubRepo splits "org/repo" into owner and name.
+func splitGitHubRepo(repoPath string) (owner, name string, err error) {
+	owner, name, ok := strings.Cut(repoPath, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return "", "", fmt.Errorf("invalid GitHub repo %q: expected owner/repo", repoPath)
+	}
+	return owner, name, nil
+}
+
+// Check verifies that an API token is available and valid
+func (g *GitHubAPI) Check(ctx context.Context) error {
+	c, err := g.client(ctx, "")
+	if err != nil {
+		return err
+	}
+	var user struct {
+		Login string `json:"login"`
+	}
+	if err := c.do(ctx, http.MethodGet, g.restURL("/user"), nil, &user); err != nil {
+		return fmt.Errorf("github api auth check failed: %w", err)
+	}
+	return nil
+}
+
+// githubPRNode is the GraphQL shape of a pull request.
+type githubPRNode struct {
+	Number      int       `json:"number"`
+	Title       string    `json:"title"`
+	State       string    `json:"state"`
+	IsDraft     bool      `json:"isDraft"`
+	URL         string    `json:"url"`
+	HeadRefName string    `json:"headRefName"`
+	BaseRefName string    `json:"baseRefName"`
+	Mergeable   string    `json:"mergeable"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+	Author      struct {
+		Login string `json:"login"`
+	} `json:"author"`
+	Comments struct {
+		TotalCount int `json:"totalCount"`
+	} `json:"comments"`
+	ReviewDecision string `json:"reviewDecision"`
+	ReviewRequests struct {
+		Nodes []struct {
+			RequestedReviewer githubReviewer `json:"requestedReviewer"`
+		} `json:"nodes"`
+	} `json:"reviewRequests"`
+	Labels struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"labels"`
+	Commits struct {
+		Nodes []struct {
+			Commit struct {
+				StatusCheckRollup *struct {
+					State string `json:"state"`
+				} `json:"statusCheckRollup"`
+			} `json:"commit"`
+		} `json:"nodes"`
+	} `json:"commits"`
+}
+
+// githubPRFields are the GraphQL fields selected for githubPRNode.
+// The check rollup is read from the head commit (the last one).
+const githubPRFields = `number title state isDraft url headRefName baseRefName mergeable updatedAt author { login } comments { totalCount } reviewDecision ` +
+	`reviewRequests(first: 20) { nodes { requestedReviewer { ... on User { login } ... on Team { slug } } } } ` +
+	`labels(first: 20) { nodes { name } } ` +
+	`commits(last: 1) { nodes { commit { statusCheckRollup { state } } } }`
+
+// githubReviewer is a requested reviewer: a user (login) or a team (slug).
+type githubReviewer struct {
+	Login string `json:"login"`
+	Slug  string `json:"slug"`
+}
+
+// String returns the reviewer's login or team slug.
+func (r githubReviewer) String() string {
+	if r.Login != "" {
+		return r.Login
+	}
+	return r.Slug
+}
+
+// toPRInfo converts a GraphQL PR node to PRInfo.
+func (n githubPRNode) toPRInfo() *PRInfo {
+	info := &PRInfo{
+		Number:       n.Number,
+		State:        n.State, // GitHub already uses OPEN, MERGED, CLOSED
+		IsDraft:      n.IsDraft,
+		URL:          n.URL,
+		Author:       n.Author.Login,
+		CommentCount: n.Comments.TotalCount,
+		HasReviews:   n.ReviewDecision != "",
+		IsApproved:   n.ReviewDecision == "APPROVED",
+		CachedAt:     time.Now(),
+		Fetched:      true,
+		Title:        n.Title,
+		BaseBranch:   n.BaseRefName,
+		Mergeable:    normalizeGitHubMergeable(n.Mergeable),
+		UpdatedAt:    n.UpdatedAt,
+	}
+	for _, r := range n.ReviewRequests.Nodes {
+		if name := r.RequestedReviewer.String(); name != "" {
+			info.ReviewRequested = append(info.ReviewRequested, name)
+		}
+	}
+	for _, l := range n.Labels.Nodes {
+		info.Labels = append(info.Labels, l.Name)
+	}
+	if len(n.Commits.Nodes) > 0 && n.Commits.Nodes[0].Commit.StatusCheckRollup != nil {
+		info.Checks = normalizeGitHubChecks(n.Commits.Nodes[0].Commit.StatusCheckRollup.State)
+	}
+	return info
+}
+
+// normalizeGitHubChecks converts a GitHub status rollup state
+// (SUCCESS, FAILURE, ERROR, PENDING, EXPECTED) to a CheckState constant.
+func normalizeGitHubChecks(state string) string {
+	switch strings.ToUpper(state) {
+	case "SUCCESS":
+		return CheckStatePassing
+	case "FAILURE", "ERROR":
+		return CheckStateFailing
+	case "PENDING", "EXPECTED":
+		return CheckStatePending
+	default:
+		return ""
+	}
+}
+
+// normalizeGitHubMergeable converts GitHub's mergeable field
+// (MERGEABLE, CONFLICTING, UNKNOWN) to a MergeState constant.
+func normalizeGitHubMergeable(mergeable string) string {
+	switch strings.ToUpper(mergeable) {
+	case "MERGEABLE":
+		return MergeStateMergeable
+	case "CONFLICTING":
+		return MergeStateConflicting
+	default:
+		return ""
+	}
+}
+
+// graphql runs a GraphQL query and decodes the "data" field into out.
+func (g *GitHubAPI) graphql(ctx context.Context, c *apiClient, query string, variables map[string]any, out any) error {
+	var resp struct {
+		Data   any `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	resp.Data = out
+	if err := c.do(ctx, http.MethodPost, g.graphqlURL(), map[string]any{
+		"query":     query,
+		"variables": variables,
+	}, &resp); err != nil {
+		return err
+	}
+	if len(resp.Errors) > 0 {
+		msgs := make([]string, len(resp.Errors))
+		for i, e := range resp.Errors {
+			msgs[i] = e.Message
+		}
+		return fmt.Errorf("graphql: %s", strings.Join(msgs, "; "))
+	}
+	return nil
+}
+
+// GetPRForBranch fetches PR info for a branch using the GraphQL API
+func (g *GitHubAPI) GetPRForBranch(ctx context.Context, repoURL, branch string) (*PRInfo, error) {
+	repoPath := ExtractRepoPath(repoURL)
+	owner, name, err := splitGitHubRepo(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	c, err := g.client(ctx, repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `query($owner: String!, $name: String!, $branch: String!) {
+  repository(owner: $owner, name: $name) {
+    pullRequests(headRefName: $branch, first: 1, orderBy: {field: CREATED_AT, direction: DESC}) {
+      nodes { ` + githubPRFields + ` }
+    }
+  }
+}`
+	var data struct {
+		Repository struct {
+			PullRequests struct {
+				Nodes []githubPRNode `json:"nodes"`
+			} `json:"pullRequests"`
+		} `json:"repository"`
+	}
+	if err := g.graphql(ctx, c, query, map[string]any{"owner": owner, "name": name, "branch": branch}, &data); err != nil {
+		return nil, fmt.Errorf("github api request failed: %w", err)
+	}
+
+	nodes := data.Repository.PullRequests.Nodes
+	if len(nodes) == 0 {
+		// No PR found - return marker indicating we checked
+		return noPR(), nil
+	}
+	return nodes[0].toPRInfo(), nil
+}
+
+// GetPRsForBranches fetches PR info for several branches using batched
+// GraphQL queries (one aliased lookup per branch)
+func (g *GitHubAPI) GetPRsForBranches(ctx context.Context, repoURL string, branches []string) (map[string]*PRInfo, error) {
+	repoPath := ExtractRepoPath(repoURL)
+	owner, name, err := splitGitHubRepo(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	c, err := g.client(ctx, repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	prs, err := githubPRsForBranches(owner, name, branches, func(query string, vars map[string]any, out any) error {
+		return g.graphql(ctx, c, query, vars, out)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("github api request failed: %w", err)
+	}
+	return prs, nil
+}
+
+// githubPull is the REST shape of a pull request.
+type githubPull struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	Body    string `json:"body"`
+	State   string `json:"state"`
+	Draft   bool   `json:"draft"`
+	Merged  bool   `json:"merged"`
+	HTMLURL string `json:"html_url"`
+	User    struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Labels    []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	RequestedReviewers  []githubReviewer `json:"requested_reviewers"`
+	RequestedTeams      []githubReviewer `json:"requested_teams"`
+	MaintainerCanModify bool             `json:"maintainer_can_modify"`
+	Head                struct {
+		Ref  string `json:"ref"`
+		Repo *struct {
+			FullName string `json:"full_name"`
+			CloneURL string `json:"clone_url"`
+		} `json:"repo"`
+	} `json:"head"`
+	Base struct {
+		Repo struct {
+			FullName string `json:"full_name"`
+		} `json:"repo"`
+	} `json:"base"`
+}
+
+// isCrossRepository reports whether the PR's head lives in a fork.
+// A deleted fork (nil head repo) is treated as cross-repository.
+func (p *githubPull) isCrossRepository() bool {
+	return p.Head.Repo == nil || !strings.EqualFold(p.Head.Repo.FullName, p.Base.Repo.FullName)
+}
+
+// getPull fetches a single pull request via REST.
+func (g *GitHubAPI) getPull(ctx context.Context, c *apiClient, owner, name string, number int) (*githubPull, error) {
+	var pr githubPull
+	if err := c.do(ctx, http.MethodGet, g.restURL(fmt.Sprintf("/repos/%s/%s/pulls/%d", owner, name, number)), nil, &pr); err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+// GetPRHead fetches the head branch and repository for a PR number
+func (g *GitHubAPI) GetPRHead(ctx context.Context, repoURL string, number int) (*PRHead, error) {
+	repoPath := ExtractRepoPath(repoURL)
+	owner, name, err := splitGitHubRepo(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	c, err := g.client(ctx, repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, err := g.getPull(ctx, c, owner, name, number)
+	if err != nil {
+		return nil, fmt.Errorf("github api request failed: %w", err)
+	}
+
+	if pr.Head.Ref == "" {
+		return nil, fmt.Errorf("PR #%d has no head branch", number)
+	}
+
+	if pr.isCrossRepository() {
+		var forkPath, cloneURL string
+		if pr.Head.Repo != nil {
+			forkPath, cloneURL = pr.Head.Repo.FullName, pr.Head.Repo.CloneURL
+		}
+		return newForkHead(repoURL, pr.Head.Ref, forkPath, cloneURL, pr.MaintainerCanModify), nil
+	}
+
+	return &PRHead{Branch: pr.Head.Ref}, nil
+}
+
+// cloneURL returns the HTTPS clone URL for an org/repo spec.
+func (g *GitHubAPI) cloneURL(repoSpec string) string {
+	return "https://" + g.host() + "/" + repoSpec + ".git"
+}
+
+// CloneRepo clones a GitHub repo over HTTPS using git
+func (g *GitHubAPI) CloneRepo(ctx context.Context, repoSpec, destPath string) (string, error) {
+	_, repoName, err := splitGitHubRepo(repoSpec)
+	if err != nil {
+		return "", fmt.Errorf("invalid repo spec %q: expected org/repo format", repoSpec)
+	}
+	clonePath := filepath.Join(destPath, repoName)
+
+	if err := cloneWithGit(ctx, g.cloneURL(repoSpec), clonePath, false); err != nil {
+		return "", err
+	}
+	return clonePath, nil
+}
+
+// CloneBareRepo clones a GitHub repo as a bare repo inside .git directory
+func (g *GitHubAPI) CloneBareRepo(ctx context.Context, repoSpec, destPath string) (string, error) {
+	_, repoName, err := splitGitHubRepo(repoSpec)
+	if err != nil {
+		return "", fmt.Errorf("invalid repo spec %q: expected org/repo format", repoSpec)
+	}
+	repoDir := filepath.Join(destPath, repoName)
+
+	if err := cloneWithGit(ctx, g.cloneURL(repoSpec), repoDir, true); err != nil {
+		return "", err
+	}
+	return repoDir, nil
+}
+
+// defaultBranch fetches the repository's default branch.
+func (g *GitHubAPI) defaultBranch(ctx context.Context, c *apiClient, owner, name string) (string, error) {
+	var repo struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	if err := c.do(ctx, http.MethodGet, g.restURL(fmt.Sprintf("/repos/%s/%s", owner, name)), nil, &repo); err != nil {
+		return "", err
+	}
+	return repo.DefaultBranch, nil
+}
+
+// CreatePR creates a new PR via the REST API
+func (g *GitHubAPI) CreatePR(ctx context.Context, repoURL string, params CreatePRParams) (*CreatePRResult, error) {
+	repoPath := ExtractRepoPath(repoURL)
+	owner, name, err := splitGitHubRepo(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	if params.Head == "" {
+		return nil, fmt.Errorf("head branch is required")
+	}
+	c, err := g.client(ctx, repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	base := params.Base
+	if base == "" {
+		if base, err = g.defaultBranch(ctx, c, owner, name); err != nil {
+			return nil, fmt.Errorf("failed to determine default branch: %w", err)
+		}
+	}
+
+	var pr githubPull
+	if err := c.do(ctx, http.MethodPost, g.restURL(fmt.Sprintf("/repos/%s/%s/pulls", owner, name)), map[string]any{
+		"title": params.Title,
+		"body":  params.Body,
+		"head":  params.Head,
+		"base":  base,
+		"draft": params.Draft,
+	}, &pr); err != nil {
+		return nil, fmt.Errorf("create PR failed: %w", err)
+	}
+
+	return &CreatePRResult{
+		Number: pr.Number,
+		URL:    pr.HTMLURL,
+	}, nil
+}
+
+// MergePR merges a PR by number with the given strategy and deletes the head branch
+func (g *GitHubAPI) MergePR(ctx context.Context, repoURL string, number int, strategy string) error {
+	repoPath := ExtractRepoPath(repoURL)
+	owner, name, err := splitGitHubRepo(repoPath)
+	if err != nil {
+		return err
+	}
+	c, err := g.client(ctx, repoPath)
+	if err != nil {
+		return err
+	}
+
+	method := "squash" // default
+	switch strategy {
+	case "rebase", "merge":
+		method = strategy
+	}
+
+	pr, err := g.getPull(ctx, c, owner, name, number)
+	if err != nil {
+		return fmt.Errorf("merge failed: %w", err)
+	}
+
+	if err := c.do(ctx, http.MethodPut, g.restURL(fmt.Sprintf("/repos/%s/%s/pulls/%d/merge", owner, name, number)), map[string]any{
+		"merge_method": method,
+	}, nil); err != nil {
+		return fmt.Errorf("merge failed: %w", err)
+	}
+
+	// Delete the head branch like gh's --delete-branch. Fork branches belong to
+	// someone else; a branch that's already gone (422) is fine.
+	if !pr.isCrossRepository() && pr.Head.Ref != "" {
+		err := c.do(ctx, http.MethodDelete, g.restURL(fmt.Sprintf("/repos/%s/%s/git/refs/heads/%s", owner, name, escapePathSegments(pr.Head.Ref))), nil, nil)
+		var apiErr *APIError
+		if err != nil && !(errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnprocessableEntity) {
+			return fmt.Errorf("PR merged, but failed to delete branch %s: %w", pr.Head.Ref, err)
+		}
+	}
+	return nil
+}
+
+// UpdatePRBase changes the base branch of a PR
+func (g *GitHubAPI) UpdatePRBase(ctx context.Context, repoURL string, number int, base string) error {
+	repoPath := ExtractRepoPath(repoURL)
+	owner, name, err := splitGitHubRepo(repoPath)
+	if err != nil {
+		return err
+	}
+	c, err := g.client(ctx, repoPath)
+	if err != nil {
+		return err
+	}
+
+	if err := c.do(ctx, http.MethodPatch, g.restURL(fmt.Sprintf("/repos/%s/%s/pulls/%d", owner, name, number)), map[string]any{
+		"base": base,
+	}, nil); err != nil {
+		return fmt.Errorf("update base failed: %w", err)
+	}
+	return nil
+}
+
+// ListReviewThreads lists the review threads of a PR using the GraphQL API
+func (g *GitHubAPI) ListReviewThreads(ctx context.Context, repoURL string, number int) ([]ReviewThread, error) {
+	repoPath := ExtractRepoPath(repoURL)
+	owner, name, err := splitGitHubRepo(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	c, err := g.client(ctx, repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	threads, err := githubReviewThreads(owner, name, number, func(query string, vars map[string]any, out any) error {
+		return g.graphql(ctx, c, query, vars, out)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("github api request failed: %w", err)
+	}
+	return threads, nil
+}
+
+// ViewPR shows PR details or opens in browser
+func (g *GitHubAPI) ViewPR(ctx context.Context, repoURL string, number int, web bool) error {
+	repoPath := ExtractRepoPath(repoURL)
+	owner, name, err := splitGitHubRepo(repoPath)
+	if err != nil {
+		return err
+	}
+	c, err := g.client(ctx, repoPath)
+	if err != nil {
+		return err
+	}
+
+	pr, err := g.getPull(ctx, c, owner, name, number)
+	if err != nil {
+		return fmt.Errorf("github api request failed: %w", err)
+	}
+
+	if web {
+		return browser.Open(ctx, pr.HTMLURL)
+	}
+
+	state := strings.ToUpper(pr.State)
+	if pr.Merged {
+		state = PRStateMerged
+	} else if pr.Draft && state == PRStateOpen {
+		state = PRStateDraft
+	}
+	printPRDetails(os.Stdout, pr.Title, fmt.Sprintf("#%d", pr.Number), g.FormatState(state), pr.User.Login, pr.HTMLURL, pr.Body)
+	return nil
+}
+
+// ListOpenPRs lists all open PRs for a repository
+func (g *GitHubAPI) ListOpenPRs(ctx context.Context, repoURL string) ([]OpenPR, error) {
+	repoPath := ExtractRepoPath(repoURL)
+	owner, name, err := splitGitHubRepo(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	c, err := g.client(ctx, repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var prs []githubPull
+	if err := c.do(ctx, http.MethodGet, g.restURL(fmt.Sprintf("/repos/%s/%s/pulls?state=open&per_page=100", owner, name)), nil, &prs); err != nil {
+		return nil, fmt.Errorf("github api request failed: %w", err)
+	}
+
+	result := make([]OpenPR, len(prs))
+	for i, pr := range prs {
+		result[i] = OpenPR{
+			Number:    pr.Number,
+			Title:     pr.Title,
+			Author:    pr.User.Login,
+			Branch:    pr.Head.Ref,
+			IsFork:    pr.isCrossRepository(),
+			IsDraft:   pr.Draft,
+			URL:       pr.HTMLURL,
+			UpdatedAt: pr.UpdatedAt,
+		}
+		if result[i].IsFork && pr.Head.Repo != nil {
+			result[i].HeadRepo = pr.Head.Repo.FullName
+		}
+		for _, r := range slices.Concat(pr.RequestedReviewers, pr.RequestedTeams) {
+			result[i].ReviewRequested = append(result[i].ReviewRequested, r.String())
+		}
+		for _, l := range pr.Labels {
+			result[i].Labels = append(result[i].Labels, l.Name)
+		}
+	}
+
+	return result, nil
+}
+
+// CurrentUser returns the login of the token's user
+func (g *GitHubAPI) CurrentUser(ctx context.Context, repoURL string) (string, error) {
+	c, err := g.client(ctx, ExtractRepoPath(repoURL))
+	if err != nil {
+		return "", err
+	}
+	var user struct {
+		Login string `json:"login"`
+	}
+	if err := c.do(ctx, http.MethodGet, g.restURL("/user"), nil, &user); err != nil {
+		return "", fmt.Errorf("github api request failed: %w", err)
+	}
+	return user.Login, nil
+}
+
+// PRHeadRef returns the ref GitHub keeps the head of a PR under
+func (g *GitHubAPI) PRHeadRef(number int) string {
+	return (&GitHub{}).PRHeadRef(number)
+}
+
+// FormatState returns a human-readable PR state
+func (g *GitHubAPI) FormatState(state string) string {
+	return (&GitHub{}).FormatState(state)
+}
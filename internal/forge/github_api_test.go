@@ -0,0 +1,429 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/raphi011/wt/internal/config"
+)
+
+// newTestGitHubAPI returns a GitHubAPI pointed at an httptest server running handler.
+// Requests without the configured token are rejected with 401.
+func newTestGitHubAPI(t *testing.T, handler http.HandlerFunc) *GitHubAPI {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			io.WriteString(w, `{"message":"Bad credentials"}`)
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	return &GitHubAPI{
+		ForgeConfig: &config.ForgeConfig{Tokens: map[string]string{"github.test": "test-token"}},
+		Host:        "github.test",
+		BaseURL:     srv.URL,
+	}
+}
+
+func TestGitHubAPI_GetPRForBranch(t *testing.T) {
+	t.Parallel()
+
+	g := newTestGitHubAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/graphql" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var req struct {
+			Variables map[string]string `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Variables["owner"] != "org" || req.Variables["name"] != "repo" {
+			t.Errorf("unexpected variables: %v", req.Variables)
+		}
+
+		if req.Variables["branch"] != "feature" {
+			io.WriteString(w, `{"data":{"repository":{"pullRequests":{"nodes":[]}}}}`)
+			return
+		}
+		io.WriteString(w, `{"data":{"repository":{"pullRequests":{"nodes":[{
+			"number": 42, "title": "Add feature", "state": "MERGED", "isDraft": false,
+			"url": "https://github.test/org/repo/pull/42", "baseRefName": "main",
+			"mergeable": "UNKNOWN", "updatedAt": "2026-01-02T03:04:05Z",
+			"author": {"login": "alice"}, "comments": {"totalCount": 3},
+			"reviewDecision": "APPROVED",
+			"reviewRequests": {"nodes": [{"requestedReviewer": {"login": "bob"}}, {"requestedReviewer": {"slug": "core"}}]},
+			"labels": {"nodes": [{"name": "bug"}]},
+			"commits": {"nodes": [{"commit": {"statusCheckRollup": {"state": "SUCCESS"}}}]}}]}}}}`)
+	})
+
+	ctx := context.Background()
+
+	pr, err := g.GetPRForBranch(ctx, "git@github.test:org/repo.git", "feature")
+	if err != nil {
+		t.Fatalf("GetPRForBranch() error = %v", err)
+	}
+	want := PRInfo{Number: 42, State: PRStateMerged, URL: "https://github.test/org/repo/pull/42",
+		Author: "alice", CommentCount: 3, HasReviews: true, IsApproved: true, Fetched: true,
+		Title: "Add feature", BaseBranch: "main", Checks: CheckStatePassing,
+		ReviewRequested: []string{"bob", "core"}, Labels: []string{"bug"}, UpdatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}
+	pr.CachedAt = want.CachedAt
+	if !reflect.DeepEqual(*pr, want) {
+		t.Errorf("GetPRForBranch() = %+v, want %+v", *pr, want)
+	}
+
+	pr, err = g.GetPRForBranch(ctx, "git@github.test:org/repo.git", "no-pr")
+	if err != nil {
+		t.Fatalf("GetPRForBranch() error = %v", err)
+	}
+	if pr.Number != 0 || !pr.Fetched {
+		t.Errorf("expected fetched marker without PR, got %+v", *pr)
+	}
+}
+
+func TestGitHubAPI_GetPRForBranch_GraphQLError(t *testing.T) {
+	t.Parallel()
+
+	g := newTestGitHubAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"data":null,"errors":[{"message":"Could not resolve to a Repository"}]}`)
+	})
+
+	_, err := g.GetPRForBranch(context.Background(), "https://github.test/org/missing", "feature")
+	if err == nil || !strings.Contains(err.Error(), "Could not resolve to a Repository") {
+		t.Errorf("expected graphql error, got %v", err)
+	}
+}
+
+func TestGitHubAPI_GetPRHead(t *testing.T) {
+	t.Parallel()
+
+	g := newTestGitHubAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/org/repo/pulls/1":
+			io.WriteString(w, `{"number":1,"head":{"ref":"feature","repo":{"full_name":"org/repo"}},"base":{"repo":{"full_name":"org/repo"}}}`)
+		case "/repos/org/repo/pulls/2":
+			io.WriteString(w, `{"number":2,"maintainer_can_modify":true,
+				"head":{"ref":"patch","repo":{"full_name":"someone/repo","clone_url":"https://github.test/someone/repo.git"}},
+				"base":{"repo":{"full_name":"org/repo"}}}`)
+		case "/repos/org/repo/pulls/4":
+			io.WriteString(w, `{"number":4,"head":{"ref":"gone","repo":null},"base":{"repo":{"full_name":"org/repo"}}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `{"message":"Not Found"}`)
+		}
+	})
+
+	ctx := context.Background()
+
+	head, err := g.GetPRHead(ctx, "git@github.test:org/repo.git", 1)
+	if err != nil || !reflect.DeepEqual(*head, PRHead{Branch: "feature"}) {
+		t.Errorf("GetPRHead(1) = %+v, %v; want branch feature", head, err)
+	}
+
+	head, err = g.GetPRHead(ctx, "git@github.test:org/repo.git", 2)
+	want := PRHead{Branch: "patch", IsFork: true, RepoPath: "someone/repo",
+		CloneURL: "git@github.test:someone/repo.git", MaintainerCanModify: true}
+	if err != nil || !reflect.DeepEqual(*head, want) {
+		t.Errorf("GetPRHead(2) = %+v, %v; want %+v", head, err, want)
+	}
+
+	head, err = g.GetPRHead(ctx, "git@github.test:org/repo.git", 4)
+	want = PRHead{Branch: "gone", IsFork: true}
+	if err != nil || !reflect.DeepEqual(*head, want) {
+		t.Errorf("GetPRHead(4) = %+v, %v; want %+v", head, err, want)
+	}
+
+	_, err = g.GetPRHead(ctx, "git@github.test:org/repo.git", 3)
+	if err == nil || !strings.Contains(err.Error(), "HTTP 404: Not Found") {
+		t.Errorf("GetPRHead(3) should surface API error, got %v", err)
+	}
+}
+
+func TestGitHubAPI_CreatePR(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	g := newTestGitHubAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/org/repo":
+			io.WriteString(w, `{"default_branch":"trunk"}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/org/repo/pulls":
+			json.NewDecoder(r.Body).Decode(&got)
+			w.WriteHeader(http.StatusCreated)
+			io.WriteString(w, `{"number":7,"html_url":"https://github.test/org/repo/pull/7"}`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	result, err := g.CreatePR(context.Background(), "git@github.test:org/repo.git", CreatePRParams{
+		Title: "Add feature",
+		Body:  "body",
+		Head:  "feature",
+		Draft: true,
+	})
+	if err != nil {
+		t.Fatalf("CreatePR() error = %v", err)
+	}
+	if result.Number != 7 || result.URL != "https://github.test/org/repo/pull/7" {
+		t.Errorf("CreatePR() = %+v", *result)
+	}
+	if got["base"] != "trunk" || got["head"] != "feature" || got["draft"] != true {
+		t.Errorf("unexpected request body: %v", got)
+	}
+}
+
+func TestGitHubAPI_MergePR(t *testing.T) {
+	t.Parallel()
+
+	var mergeMethod string
+	var deleted bool
+	g := newTestGitHubAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/org/repo/pulls/5":
+			io.WriteString(w, `{"number":5,"head":{"ref":"feat/x","repo":{"full_name":"org/repo"}},"base":{"repo":{"full_name":"org/repo"}}}`)
+		case r.Method == http.MethodPut && r.URL.Path == "/repos/org/repo/pulls/5/merge":
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			mergeMethod = body["merge_method"]
+			io.WriteString(w, `{"merged":true}`)
+		case r.Method == http.MethodDelete && r.URL.Path == "/repos/org/repo/git/refs/heads/feat/x":
+			deleted = true
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	if err := g.MergePR(context.Background(), "git@github.test:org/repo.git", 5, "rebase"); err != nil {
+		t.Fatalf("MergePR() error = %v", err)
+	}
+	if mergeMethod != "rebase" {
+		t.Errorf("merge_method = %q, want rebase", mergeMethod)
+	}
+	if !deleted {
+		t.Error("head branch should be deleted after merge")
+	}
+}
+
+func TestGitHubAPI_UpdatePRBase(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	g := newTestGitHubAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != "/repos/org/repo/pulls/5" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		io.WriteString(w, `{"number":5}`)
+	})
+
+	if err := g.UpdatePRBase(context.Background(), "git@github.test:org/repo.git", 5, "main"); err != nil {
+		t.Fatalf("UpdatePRBase() error = %v", err)
+	}
+	if got["base"] != "main" {
+		t.Errorf("unexpected request body: %v", got)
+	}
+}
+
+func TestGitHubAPI_ListReviewThreads(t *testing.T) {
+	t.Parallel()
+
+	var cursors []any
+	g := newTestGitHubAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables map[string]any `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Variables["number"] != float64(7) {
+			t.Errorf("unexpected variables: %v", req.Variables)
+		}
+		cursors = append(cursors, req.Variables["after"])
+
+		if req.Variables["after"] == nil {
+			io.WriteString(w, `{"data":{"repository":{"pullRequest":{"reviewThreads":{
+				"pageInfo": {"hasNextPage": true, "endCursor": "c1"},
+				"nodes": [{"path": "main.go", "line": 12, "isResolved": false, "isOutdated": false,
+					"comments": {"nodes": [
+						{"author": {"login": "bob"}, "body": "Handle the error", "createdAt": "2026-01-02T03:04:05Z", "url": "https://github.test/org/repo/pull/7#discussion_r1"},
+						{"author": null, "body": "Done", "createdAt": "2026-01-03T03:04:05Z", "url": "https://github.test/org/repo/pull/7#discussion_r2"}]}}]}}}}}`)
+			return
+		}
+		io.WriteString(w, `{"data":{"repository":{"pullRequest":{"reviewThreads":{
+			"pageInfo": {"hasNextPage": false, "endCursor": "c2"},
+			"nodes": [{"path": "cmd/app.go", "line": null, "originalLine": 3, "isResolved": true, "isOutdated": true,
+				"comments": {"nodes": [{"author": {"login": "carol"}, "body": "Typo", "createdAt": "2026-01-01T00:00:00Z", "url": "u"}]}}]}}}}}`)
+	})
+
+	threads, err := g.ListReviewThreads(context.Background(), "git@github.test:org/repo.git", 7)
+	if err != nil {
+		t.Fatalf("ListReviewThreads() error = %v", err)
+	}
+	if !reflect.DeepEqual(cursors, []any{nil, "c1"}) {
+		t.Errorf("cursors = %v, want [nil c1]", cursors)
+	}
+
+	want := []ReviewThread{
+		{Path: "cmd/app.go", Line: 3, Resolved: true, Outdated: true, URL: "u",
+			Comments: []ReviewComment{{Author: "carol", Body: "Typo", CreatedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}}},
+		{Path: "main.go", Line: 12, URL: "https://github.test/org/repo/pull/7#discussion_r1",
+			Comments: []ReviewComment{
+				{Author: "bob", Body: "Handle the error", CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)},
+				{Body: "Done", CreatedAt: time.Date(2026, 1, 3, 3, 4, 5, 0, time.UTC)},
+			}},
+	}
+	if !reflect.DeepEqual(threads, want) {
+		t.Errorf("ListReviewThreads() = %+v, want %+v", threads, want)
+	}
+}
+
+func TestGitHubAPI_ListReviewThreads_NotFound(t *testing.T) {
+	t.Parallel()
+
+	g := newTestGitHubAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"data":{"repository":{"pullRequest":null}}}`)
+	})
+
+	_, err := g.ListReviewThreads(context.Background(), "git@github.test:org/repo.git", 7)
+	if err == nil || !strings.Contains(err.Error(), "PR #7 not found") {
+		t.Errorf("ListReviewThreads() error = %v, want not found", err)
+	}
+}
+
+func TestGitHubAPI_ListOpenPRs(t *testing.T) {
+	t.Parallel()
+
+	g := newTestGitHubAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/org/repo/pulls" || r.URL.Query().Get("state") != "open" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		io.WriteString(w, `[
+			{"number":1,"title":"One","draft":false,"user":{"login":"alice"},
+			 "head":{"ref":"one","repo":{"full_name":"org/repo"}},"base":{"repo":{"full_name":"org/repo"}},
+			 "html_url":"https://github.test/org/repo/pull/1","labels":[{"name":"bug"}],
+			 "requested_reviewers":[{"login":"carol"}],"requested_teams":[{"slug":"core"}]},
+			{"number":2,"title":"Two","draft":true,"user":{"login":"bob"},
+			 "head":{"ref":"main","repo":{"full_name":"bob/repo"}},"base":{"repo":{"full_name":"org/repo"}}}
+		]`)
+	})
+
+	prs, err := g.ListOpenPRs(context.Background(), "git@github.test:org/repo.git")
+	if err != nil {
+		t.Fatalf("ListOpenPRs() error = %v", err)
+	}
+	want := []OpenPR{
+		{Number: 1, Title: "One", Author: "alice", Branch: "one", URL: "https://github.test/org/repo/pull/1",
+			Labels: []string{"bug"}, ReviewRequested: []string{"carol", "core"}},
+		{Number: 2, Title: "Two", Author: "bob", Branch: "main", IsFork: true, HeadRepo: "bob/repo", IsDraft: true},
+	}
+	if len(prs) != len(want) {
+		t.Fatalf("ListOpenPRs() returned %d PRs, want %d", len(prs), len(want))
+	}
+	for i := range want {
+		if !reflect.DeepEqual(prs[i], want[i]) {
+			t.Errorf("prs[%d] = %+v, want %+v", i, prs[i], want[i])
+		}
+	}
+}
+
+func TestGitHubAPI_CurrentUser(t *testing.T) {
+	t.Parallel()
+
+	g := newTestGitHubAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/user" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		io.WriteString(w, `{"login":"octocat"}`)
+	})
+
+	user, err := g.CurrentUser(context.Background(), "git@github.com:org/repo.git")
+	if err != nil || user != "octocat" {
+		t.Errorf("CurrentUser() = %q, %v; want octocat", user, err)
+	}
+}
+
+func TestGitHubAPI_Check_BadCredentials(t *testing.T) {
+	t.Parallel()
+
+	g := newTestGitHubAPI(t, func(w http.ResponseWriter, r *http.Request) {})
+	g.ForgeConfig.Tokens["github.test"] = "wrong-token"
+
+	err := g.Check(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "Bad credentials") {
+		t.Errorf("Check() should fail with bad credentials, got %v", err)
+	}
+}
+
+func TestGitHubAPI_URLs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		host        string
+		wantREST    string
+		wantGraphQL string
+	}{
+		{"", "https://api.github.com/user", "https://api.github.com/graphql"},
+		{"github.com", "https://api.github.com/user", "https://api.github.com/graphql"},
+		{"github.com-work", "https://api.github.com/user", "https://api.github.com/graphql"},
+		{"github.corp", "https://github.corp/api/v3/user", "https://github.corp/api/graphql"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			t.Parallel()
+			g := &GitHubAPI{Host: tt.host}
+			if got := g.restURL("/user"); got != tt.wantREST {
+				t.Errorf("restURL() = %q, want %q", got, tt.wantREST)
+			}
+			if got := g.graphqlURL(); got != tt.wantGraphQL {
+				t.Errorf("graphqlURL() = %q, want %q", got, tt.wantGraphQL)
+			}
+		})
+	}
+}
+
+func TestGitHubAPI_GetPRsForBranches(t *testing.T) {
+	t.Parallel()
+
+	var requests int
+	g := newTestGitHubAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		var req struct {
+			Query     string            `json:"query"`
+			Variables map[string]string `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Variables["b0"] != "feat-a" || req.Variables["b1"] != "feat-b" {
+			t.Errorf("unexpected variables: %v", req.Variables)
+		}
+		io.WriteString(w, `{"data":{"repository":{
+			"b0":{"nodes":[{"number":1,"state":"OPEN","isDraft":true,"url":"u1","author":{"login":"alice"},"comments":{"totalCount":0},"reviewDecision":"",
+				"mergeable":"CONFLICTING","commits":{"nodes":[{"commit":{"statusCheckRollup":{"state":"FAILURE"}}}]}}]},
+			"b1":{"nodes":[]}}}}`)
+	})
+
+	prs, err := g.GetPRsForBranches(context.Background(), "git@github.test:org/repo.git", []string{"feat-a", "feat-b"})
+	if err != nil {
+		t.Fatalf("GetPRsForBranches() error = %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("expected a single request, got %d", requests)
+	}
+	if pr := prs["feat-a"]; pr == nil || pr.Number != 1 || !pr.IsDraft || pr.Author != "alice" {
+		t.Errorf("feat-a = %+v, want PR #1 by alice", pr)
+	}
+	if pr := prs["feat-a"]; pr.Checks != CheckStateFailing || pr.Mergeable != MergeStateConflicting {
+		t.Errorf("feat-a checks/mergeable = %q/%q, want failing/conflicting", pr.Checks, pr.Mergeable)
+	}
+	if pr := prs["feat-b"]; pr == nil || pr.Number != 0 || !pr.Fetched {
+		t.Errorf("feat-b = %+v, want fetched marker", pr)
+	}
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -63,6 +65,162 @@ func (g *GitLab) outputGlab(ctx context.Context, args ...string) ([]byte, error)
 	return out, nil
 }
 
+// glabMR is the JSON shape of a merge request in glab's list output.
+type glabMR struct {
+	IID          int    `json:"iid"`
+	State        string `json:"state"` // opened, merged, closed
+	Draft        bool   `json:"draft"`
+	WebURL       string `json:"web_url"`
+	SourceBranch string `json:"source_branch"`
+	Author       struct {
+		Username string `json:"username"`
+	} `json:"author"`
+	UserNotesCount int   `json:"user_notes_count"`
+	ApprovedBy     []any `json:"approved_by"` // just need to check if non-empty
+	Approved       bool  `json:"approved"`
+	gitlabMRDetails
+}
+
+// gitlabMRDetails are the merge request fields shared by glab's JSON output
+// and the REST API that feed PRInfo's review, mergeability and CI metadata.
+type gitlabMRDetails struct {
+	Title        string    `json:"title"`
+	TargetBranch string    `json:"target_branch"`
+	Labels       []string  `json:"labels"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	SHA          string    `json:"sha"` // head commit
+	Reviewers    []struct {
+		Username string `json:"username"`
+	} `json:"reviewers"`
+	HasConflicts        bool   `json:"has_conflicts"`
+	DetailedMergeStatus string `json:"detailed_merge_status"`
+	HeadPipeline        *struct {
+		Status string `json:"status"`
+	} `json:"head_pipeline"` // only included when fetching a single MR
+	SourceProjectID int `json:"source_project_id"`
+	TargetProjectID int `json:"target_project_id"`
+}
+
+// isFork reports whether the MR's source branch lives in another project.
+func (d gitlabMRDetails) isFork() bool {
+	return d.SourceProjectID != 0 && d.TargetProjectID != 0 && d.SourceProjectID != d.TargetProjectID
+}
+
+// fill copies the details into info, which must already have its state set.
+func (d gitlabMRDetails) fill(info *PRInfo) {
+	info.Title = d.Title
+	info.BaseBranch = d.TargetBranch
+	info.Labels = d.Labels
+	info.UpdatedAt = d.UpdatedAt
+	for _, r := range d.Reviewers {
+		info.ReviewRequested = append(info.ReviewRequested, r.Username)
+	}
+	if d.HeadPipeline != nil {
+		info.Checks = normalizeGitLabPipeline(d.HeadPipeline.Status)
+	}
+	if info.State == PRStateOpen {
+		info.Mergeable = gitlabMergeable(d.HasConflicts, d.DetailedMergeStatus)
+	}
+}
+
+// gitlabMergeable derives a MergeState from an open MR's conflict flag and
+// detailed merge status. Statuses that are still being computed are unknown;
+// other blockers (approvals, discussions, CI) don't make the MR conflicting.
+func gitlabMergeable(hasConflicts bool, detailedStatus string) string {
+	if hasConflicts {
+		return MergeStateConflicting
+	}
+	switch detailedStatus {
+	case "conflict", "broken_status", "need_rebase":
+		return MergeStateConflicting
+	case "", "unchecked", "checking", "preparing", "approvals_syncing":
+		return ""
+	default:
+		return MergeStateMergeable
+	}
+}
+
+// gitlabPipeline is the REST shape of a pipeline in a project's pipeline list.
+type gitlabPipeline struct {
+	SHA    string `json:"sha"`
+	Status string `json:"status"`
+}
+
+// gitlabPipelinesPath returns the project-relative pipelines listing path,
+// newest first. A non-empty sha restricts the listing to that commit.
+func gitlabPipelinesPath(sha string, perPage int) string {
+	query := url.Values{
+		"order_by": {"id"},
+		"sort":     {"desc"},
+		"per_page": {strconv.Itoa(perPage)},
+	}
+	if sha != "" {
+		query.Set("sha", sha)
+	}
+	return "/pipelines?" + query.Encode()
+}
+
+// applyGitLabPipelines sets Checks on each PR without a CI status from the
+// newest pipeline of its head commit. shas maps PRs to their head commit;
+// pipelines must be ordered newest first.
+func applyGitLabPipelines(shas map[*PRInfo]string, pipelines []gitlabPipeline) {
+	newest := make(map[string]string, len(pipelines))
+	for _, p := range pipelines {
+		if _, ok := newest[p.SHA]; !ok {
+			newest[p.SHA] = p.Status
+		}
+	}
+	for info, sha := range shas {
+		if info.Checks == "" {
+			info.Checks = normalizeGitLabPipeline(newest[sha])
+		}
+	}
+}
+
+// openMRHeads returns the head commits of the open MRs in prs, keyed by PRInfo.
+// Only open MRs are worth a pipeline lookup.
+func openMRHeads(prs map[string]*PRInfo, heads map[*PRInfo]string) map[*PRInfo]string {
+	shas := make(map[*PRInfo]string)
+	for _, info := range prs {
+		if sha := heads[info]; sha != "" && info.State == PRStateOpen && info.Checks == "" {
+			shas[info] = sha
+		}
+	}
+	return shas
+}
+
+// normalizeGitLabPipeline converts a GitLab pipeline status to a CheckState constant.
+func normalizeGitLabPipeline(status string) string {
+	switch status {
+	case "success":
+		return CheckStatePassing
+	case "failed", "canceled", "canceling":
+		return CheckStateFailing
+	case "created", "waiting_for_resource", "preparing", "waiting_for_callback", "pending", "running", "scheduled", "manual":
+		return CheckStatePending
+	default:
+		return ""
+	}
+}
+
+// toPRInfo converts a glab MR to PRInfo.
+func (mr glabMR) toPRInfo() *PRInfo {
+	info := &PRInfo{
+		Number:       mr.IID,
+		State:        normalizeGitLabState(mr.State),
+		IsDraft:      mr.Draft,
+		URL:          mr.WebURL,
+		Author:       mr.Author.Username,
+		CommentCount: mr.UserNotesCount,
+		HasReviews:   len(mr.ApprovedBy) > 0,
+		IsApproved:   mr.Approved,
+		CachedAt:     time.Now(),
+		Fetched:      true,
+	}
+	mr.fill(info)
+	return info
+}
+
 // GetPRForBranch fetches PR info for a branch using glab CLI
 func (g *GitLab) GetPRForBranch(ctx context.Context, repoURL, branch string) (*PRInfo, error) {
 	projectPath := ExtractRepoPath(repoURL)
@@ -77,47 +235,84 @@ func (g *GitLab) GetPRForBranch(ctx context.Context, repoURL, branch string) (*P
 		return nil, fmt.Errorf("glab command failed: %v", err)
 	}
 
-	var prs []struct {
-		IID    int    `json:"iid"`
-		State  string `json:"state"` // opened, merged, closed
-		Draft  bool   `json:"draft"`
-		WebURL string `json:"web_url"`
-		Author struct {
-			Username string `json:"username"`
-		} `json:"author"`
-		UserNotesCount int   `json:"user_notes_count"`
-		ApprovedBy     []any `json:"approved_by"` // just need to check if non-empty
-		Approved       bool  `json:"approved"`
-	}
+	var prs []glabMR
 	if err := json.Unmarshal(output, &prs); err != nil {
 		return nil, fmt.Errorf("failed to parse glab output: %w", err)
 	}
 
 	if len(prs) == 0 {
 		// No MR found - return marker indicating we checked
-		return &PRInfo{
-			Fetched:  true,
-			CachedAt: time.Now(),
-		}, nil
-	}
-
-	pr := prs[0]
-	return &PRInfo{
-		Number:       pr.IID,
-		State:        normalizeGitLabState(pr.State),
-		IsDraft:      pr.Draft,
-		URL:          pr.WebURL,
-		Author:       pr.Author.Username,
-		CommentCount: pr.UserNotesCount,
-		HasReviews:   len(pr.ApprovedBy) > 0,
-		IsApproved:   pr.Approved,
-		CachedAt:     time.Now(),
-		Fetched:      true,
-	}, nil
+		return noPR(), nil
+	}
+
+	info := prs[0].toPRInfo()
+	if info.State == PRStateOpen && prs[0].SHA != "" {
+		g.addPipelineChecks(ctx, projectPath, map[*PRInfo]string{info: prs[0].SHA}, prs[0].SHA)
+	}
+	return info, nil
+}
+
+// addPipelineChecks fills in CI status for the given open MRs from the
+// project's pipelines. A non-empty sha limits the lookup to that commit.
+// This is best-effort: without pipeline access the MRs are kept as they are.
+func (g *GitLab) addPipelineChecks(ctx context.Context, projectPath string, shas map[*PRInfo]string, sha string) {
+	if len(shas) == 0 {
+		return
+	}
+	perPage := listLimit
+	if sha != "" {
+		perPage = 1
+	}
+	output, err := g.outputGlab(ctx, "api", "projects/"+url.PathEscape(projectPath)+gitlabPipelinesPath(sha, perPage))
+	if err != nil {
+		return
+	}
+	var pipelines []gitlabPipeline
+	if err := json.Unmarshal(output, &pipelines); err != nil {
+		return
+	}
+	applyGitLabPipelines(shas, pipelines)
 }
 
-// GetPRBranch fetches the source branch name for a PR number using glab CLI
-func (g *GitLab) GetPRBranch(ctx context.Context, repoURL string, number int) (string, error) {
+// GetPRsForBranches lists the project's most recent MRs (all states) in one
+// call and matches them to branches locally. Branches not covered by a
+// truncated listing fall back to GetPRForBranch.
+func (g *GitLab) GetPRsForBranches(ctx context.Context, repoURL string, branches []string) (map[string]*PRInfo, error) {
+	projectPath := ExtractRepoPath(repoURL)
+
+	output, err := g.outputGlab(ctx, "mr", "list",
+		"-R", projectPath,
+		"--all",
+		"-F", "json",
+		"-P", strconv.Itoa(listLimit))
+	if err != nil {
+		return nil, fmt.Errorf("glab command failed: %v", err)
+	}
+
+	var mrs []glabMR
+	if err := json.Unmarshal(output, &mrs); err != nil {
+		return nil, fmt.Errorf("failed to parse glab output: %w", err)
+	}
+
+	prs := make([]branchPR, len(mrs))
+	heads := make(map[*PRInfo]string, len(mrs))
+	for i, mr := range mrs {
+		prs[i] = branchPR{Branch: mr.SourceBranch, Info: mr.toPRInfo()}
+		heads[prs[i].Info] = mr.SHA
+	}
+
+	result, err := matchBranchPRs(ctx, branches, prs, len(mrs) < listLimit, func(ctx context.Context, branch string) (*PRInfo, error) {
+		return g.GetPRForBranch(ctx, repoURL, branch)
+	})
+	if err != nil {
+		return nil, err
+	}
+	g.addPipelineChecks(ctx, projectPath, openMRHeads(result, heads), "")
+	return result, nil
+}
+
+// GetPRHead fetches the source branch and project for a PR number using glab CLI
+func (g *GitLab) GetPRHead(ctx context.Context, repoURL string, number int) (*PRHead, error) {
 	projectPath := ExtractRepoPath(repoURL)
 
 	output, err := g.outputGlab(ctx, "mr", "view",
@@ -125,29 +320,32 @@ func (g *GitLab) GetPRBranch(ctx context.Context, repoURL string, number int) (s
 		"-R", projectPath,
 		"-F", "json")
 	if err != nil {
-		return "", fmt.Errorf("glab command failed: %v", err)
+		return nil, fmt.Errorf("glab command failed: %v", err)
 	}
 
-	var result struct {
-		SourceBranch    string `json:"source_branch"`
-		SourceProjectID int    `json:"source_project_id"`
-		TargetProjectID int    `json:"target_project_id"`
-	}
-	if err := json.Unmarshal(output, &result); err != nil {
-		return "", fmt.Errorf("failed to parse glab output: %w", err)
+	var mr gitlabMR
+	if err := json.Unmarshal(output, &mr); err != nil {
+		return nil, fmt.Errorf("failed to parse glab output: %w", err)
 	}
 
-	// Check for cross-project PR (fork)
-	if result.SourceProjectID != 0 && result.TargetProjectID != 0 &&
-		result.SourceProjectID != result.TargetProjectID {
-		return "", fmt.Errorf("PR !%d is from a fork - cross-project PRs are not supported", number)
+	if mr.SourceBranch == "" {
+		return nil, fmt.Errorf("PR !%d has no source branch", number)
 	}
 
-	if result.SourceBranch == "" {
-		return "", fmt.Errorf("PR !%d has no source branch", number)
+	if !mr.isFork() {
+		return &PRHead{Branch: mr.SourceBranch}, nil
 	}
 
-	return result.SourceBranch, nil
+	// A deleted fork is not an error: its branch is still reachable via
+	// the MR ref on the target project
+	var source *gitlabProject
+	if output, err := g.outputGlab(ctx, "api", fmt.Sprintf("projects/%d", mr.SourceProjectID)); err == nil {
+		var project gitlabProject
+		if json.Unmarshal(output, &project) == nil {
+			source = &project
+		}
+	}
+	return mr.forkHead(repoURL, source), nil
 }
 
 // CloneRepo clones a GitLab repo using glab CLI
@@ -309,6 +507,38 @@ func (g *GitLab) MergePR(ctx context.Context, repoURL string, number int, strate
 	return nil
 }
 
+// UpdatePRBase changes the target branch of a MR
+func (g *GitLab) UpdatePRBase(ctx context.Context, repoURL string, number int, base string) error {
+	projectPath := ExtractRepoPath(repoURL)
+	if err := g.runGlab(ctx, "mr", "update", fmt.Sprintf("%d", number),
+		"-R", projectPath,
+		"--target-branch", base); err != nil {
+		return fmt.Errorf("update target branch failed: %v", err)
+	}
+	return nil
+}
+
+// ListReviewThreads lists the diff discussions of a MR via glab api
+func (g *GitLab) ListReviewThreads(ctx context.Context, repoURL string, number int) ([]ReviewThread, error) {
+	projectPath := ExtractRepoPath(repoURL)
+	discussions, err := gitlabListDiscussions(func(page int) ([]gitlabDiscussion, error) {
+		output, err := g.outputGlab(ctx, "api", fmt.Sprintf("projects/%s/merge_requests/%d/discussions?per_page=%d&page=%d",
+			url.PathEscape(projectPath), number, listLimit, page))
+		if err != nil {
+			return nil, fmt.Errorf("glab command failed: %v", err)
+		}
+		var batch []gitlabDiscussion
+		if err := json.Unmarshal(output, &batch); err != nil {
+			return nil, fmt.Errorf("failed to parse glab output: %w", err)
+		}
+		return batch, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return gitlabReviewThreads(discussions), nil
+}
+
 // ViewPR shows MR details or opens in browser
 func (g *GitLab) ViewPR(ctx context.Context, repoURL string, number int, web bool) error {
 	projectPath := ExtractRepoPath(repoURL)
@@ -334,33 +564,40 @@ func (g *GitLab) ListOpenPRs(ctx context.Context, repoURL string) ([]OpenPR, err
 		return nil, fmt.Errorf("glab command failed: %v", err)
 	}
 
-	var prs []struct {
-		IID          int    `json:"iid"`
-		Title        string `json:"title"`
-		SourceBranch string `json:"source_branch"`
-		Draft        bool   `json:"draft"`
-		Author       struct {
-			Username string `json:"username"`
-		} `json:"author"`
-	}
-	if err := json.Unmarshal(output, &prs); err != nil {
+	var mrs []glabMR
+	if err := json.Unmarshal(output, &mrs); err != nil {
 		return nil, fmt.Errorf("failed to parse glab output: %w", err)
 	}
 
-	result := make([]OpenPR, len(prs))
-	for i, pr := range prs {
-		result[i] = OpenPR{
-			Number:  pr.IID,
-			Title:   pr.Title,
-			Author:  pr.Author.Username,
-			Branch:  pr.SourceBranch,
-			IsDraft: pr.Draft,
-		}
+	result := make([]OpenPR, len(mrs))
+	for i, mr := range mrs {
+		result[i] = newOpenPR(mr.toPRInfo(), mr.SourceBranch)
+		result[i].IsFork = mr.isFork()
 	}
 
 	return result, nil
 }
 
+// CurrentUser returns the username glab is logged in as
+func (g *GitLab) CurrentUser(ctx context.Context, repoURL string) (string, error) {
+	output, err := g.outputGlab(ctx, "api", "user")
+	if err != nil {
+		return "", fmt.Errorf("glab command failed: %v", err)
+	}
+	var user struct {
+		Username string `json:"username"`
+	}
+	if err := json.Unmarshal(output, &user); err != nil {
+		return "", fmt.Errorf("failed to parse glab output: %w", err)
+	}
+	return user.Username, nil
+}
+
+// PRHeadRef returns the ref GitLab keeps the head of an MR under
+func (g *GitLab) PRHeadRef(number int) string {
+	return fmt.Sprintf("refs/merge-requests/%d/head", number)
+}
+
 // FormatState returns a human-readable PR state
 func (g *GitLab) FormatState(state string) string {
 	switch state {
@@ -0,0 +1,485 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/raphi011/wt/internal/browser"
+	"github.com/raphi011/wt/internal/config"
+)
+
+// gitlabPublicHost is the host of the public GitLab instance.
+const gitlabPublicHost = "gitlab.com"
+
+// GitLabAPI implements Forge for GitLab repositories using the REST API
+// directly (selected with [forge] backend = "api").
+type GitLabAPI struct {
+	ForgeConfig *config.ForgeConfig
+	Host        string       // GitLab host; empty = gitlab.com
+	BaseURL     string       // API base URL override (tests); derived from Host when empty
+	HTTPClient  *http.Client // optional; defaults to a client with apiTimeout
+}
+
+// Name returns "gitlab"
+func (g *GitLabAPI) Name() string {
+	return "gitlab"
+}
+
+// host returns the normalized GitLab host.
+func (g *GitLabAPI) host() string {
+	return apiHost(g.Host, gitlabPublicHost)
+}
+
+// apiURL returns the API URL for path (which must start with "/").
+func (g *GitLabAPI) apiURL(path string) string {
+	if g.BaseURL != "" {
+		return strings.TrimSuffix(g.BaseURL, "/") + path
+	}
+	return "https://" + g.host() + "/api/v4" + path
+}
+
+// projectURL returns the API URL for a path below /projects/:id.
+// The project path (group/sub/repo) is URL-encoded as the project ID.
+func (g *GitLabAPI) projectURL(projectPath, path string) string {
+	return g.apiURL("/projects/" + url.PathEscape(projectPath) + path)
+}
+
+// client returns an authenticated API client.
+func (g *GitLabAPI) client(ctx context.Context) (*apiClient, error) {
+	token, err := resolveToken(ctx, g.ForgeConfig, g.host(), "", []string{"GITLAB_TOKEN", "GITLAB_ACCESS_TOKEN"}, glabCLIToken)
+	if err != nil {
+		return nil, err
+	}
+	return &apiClient{http: g.HTTPClient, token: token}, nil
+}
+
+// glabCLIToken reads a token from glab's config, if glab is installed.
+func glabCLIToken(ctx context.Context, host, _ string) (string, error) {
+	out, err := exec.CommandContext(ctx, "glab", "config", "get", "token", "--host", host).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// Check verifies that an API token is available and valid
+func (g *GitLabAPI) Check(ctx context.Context) error {
+	c, err := g.client(ctx)
+	if err != nil {
+		return err
+	}
+	var user struct {
+		Username string `json:"username"`
+	}
+	if err := c.do(ctx, http.MethodGet, g.apiURL("/user"), nil, &user); err != nil {
+		return fmt.Errorf("gitlab api auth check failed: %w", err)
+	}
+	return nil
+}
+
+// gitlabMR is the REST shape of a merge request.
+type gitlabMR struct {
+	IID         int    `json:"iid"`
+	Description string `json:"description"`
+	State       string `json:"state"` // opened, merged, closed
+	Draft       bool   `json:"draft"`
+	WebURL      string `json:"web_url"`
+	Author      struct {
+		Username string `json:"username"`
+	} `json:"author"`
+	UserNotesCount int    `json:"user_notes_count"`
+	SourceBranch   string `json:"source_branch"`
+	// AllowCollaboration is set when members of the target project may push
+	// to the source branch of a fork
+	AllowCollaboration bool `json:"allow_collaboration"`
+	gitlabMRDetails
+}
+
+// gitlabProject is the subset of a REST project used to locate forks.
+type gitlabProject struct {
+	PathWithNamespace string `json:"path_with_namespace"`
+	HTTPURLToRepo     string `json:"http_url_to_repo"`
+}
+
+// forkHead returns the head of a fork MR given its source project, which
+// is nil if the fork no longer exists.
+func (mr gitlabMR) forkHead(repoURL string, source *gitlabProject) *PRHead {
+	var forkPath, cloneURL string
+	if source != nil {
+		forkPath, cloneURL = source.PathWithNamespace, source.HTTPURLToRepo
+	}
+	return newForkHead(repoURL, mr.SourceBranch, forkPath, cloneURL, mr.AllowCollaboration)
+}
+
+// toPRInfo converts a REST merge request to PRInfo.
+func (mr gitlabMR) toPRInfo() *PRInfo {
+	info := &PRInfo{
+		Number:       mr.IID,
+		State:        normalizeGitLabState(mr.State),
+		IsDraft:      mr.Draft,
+		URL:          mr.WebURL,
+		Author:       mr.Author.Username,
+		CommentCount: mr.UserNotesCount,
+		CachedAt:     time.Now(),
+		Fetched:      true,
+	}
+	mr.fill(info)
+	return info
+}
+
+// GetPRForBranch fetches MR info for a branch
+func (g *GitLabAPI) GetPRForBranch(ctx context.Context, repoURL, branch string) (*PRInfo, error) {
+	projectPath := ExtractRepoPath(repoURL)
+	c, err := g.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{
+		"source_branch": {branch},
+		"state":         {"all"},
+		"per_page":      {"1"},
+	}
+	var mrs []gitlabMR
+	if err := c.do(ctx, http.MethodGet, g.projectURL(projectPath, "/merge_requests?"+query.Encode()), nil, &mrs); err != nil {
+		return nil, fmt.Errorf("gitlab api request failed: %w", err)
+	}
+
+	if len(mrs) == 0 {
+		// No MR found - return marker indicating we checked
+		return noPR(), nil
+	}
+
+	info := mrs[0].toPRInfo()
+	if info.State == PRStateOpen && mrs[0].SHA != "" {
+		g.addPipelineChecks(ctx, c, projectPath, map[*PRInfo]string{info: mrs[0].SHA}, mrs[0].SHA)
+	}
+	return info, nil
+}
+
+// addPipelineChecks fills in CI status for the given open MRs from the
+// project's pipelines. A non-empty sha limits the lookup to that commit.
+// This is best-effort: without pipeline access the MRs are kept as they are.
+func (g *GitLabAPI) addPipelineChecks(ctx context.Context, c *apiClient, projectPath string, shas map[*PRInfo]string, sha string) {
+	if len(shas) == 0 {
+		return
+	}
+	perPage := listLimit
+	if sha != "" {
+		perPage = 1
+	}
+	var pipelines []gitlabPipeline
+	if err := c.do(ctx, http.MethodGet, g.projectURL(projectPath, gitlabPipelinesPath(sha, perPage)), nil, &pipelines); err != nil {
+		return
+	}
+	applyGitLabPipelines(shas, pipelines)
+}
+
+// GetPRsForBranches lists the project's most recent MRs (all states) in one
+// request and matches them to branches locally. Branches not covered by a
+// truncated listing fall back to GetPRForBranch.
+func (g *GitLabAPI) GetPRsForBranches(ctx context.Context, repoURL string, branches []string) (map[string]*PRInfo, error) {
+	projectPath := ExtractRepoPath(repoURL)
+	c, err := g.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{
+		"state":    {"all"},
+		"order_by": {"created_at"},
+		"sort":     {"desc"},
+		"per_page": {strconv.Itoa(listLimit)},
+	}
+	var mrs []gitlabMR
+	if err := c.do(ctx, http.MethodGet, g.projectURL(projectPath, "/merge_requests?"+query.Encode()), nil, &mrs); err != nil {
+		return nil, fmt.Errorf("gitlab api request failed: %w", err)
+	}
+
+	prs := make([]branchPR, len(mrs))
+	heads := make(map[*PRInfo]string, len(mrs))
+	for i, mr := range mrs {
+		prs[i] = branchPR{Branch: mr.SourceBranch, Info: mr.toPRInfo()}
+		heads[prs[i].Info] = mr.SHA
+	}
+
+	result, err := matchBranchPRs(ctx, branches, prs, len(mrs) < listLimit, func(ctx context.Context, branch string) (*PRInfo, error) {
+		return g.GetPRForBranch(ctx, repoURL, branch)
+	})
+	if err != nil {
+		return nil, err
+	}
+	g.addPipelineChecks(ctx, c, projectPath, openMRHeads(result, heads), "")
+	return result, nil
+}
+
+// getMR fetches a single merge request.
+func (g *GitLabAPI) getMR(ctx context.Context, c *apiClient, projectPath string, number int) (*gitlabMR, error) {
+	var mr gitlabMR
+	if err := c.do(ctx, http.MethodGet, g.projectURL(projectPath, fmt.Sprintf("/merge_requests/%d", number)), nil, &mr); err != nil {
+		return nil, err
+	}
+	return &mr, nil
+}
+
+// GetPRHead fetches the source branch and project for a MR number
+func (g *GitLabAPI) GetPRHead(ctx context.Context, repoURL string, number int) (*PRHead, error) {
+	projectPath := ExtractRepoPath(repoURL)
+	c, err := g.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mr, err := g.getMR(ctx, c, projectPath, number)
+	if err != nil {
+		return nil, fmt.Errorf("gitlab api request failed: %w", err)
+	}
+
+	if mr.SourceBranch == "" {
+		return nil, fmt.Errorf("PR !%d has no source branch", number)
+	}
+
+	if !mr.isFork() {
+		return &PRHead{Branch: mr.SourceBranch}, nil
+	}
+
+	// A deleted fork is not an error: its branch is still reachable via
+	// the MR ref on the target project
+	var source *gitlabProject
+	var project gitlabProject
+	if err := c.do(ctx, http.MethodGet, g.apiURL(fmt.Sprintf("/projects/%d", mr.SourceProjectID)), nil, &project); err == nil {
+		source = &project
+	}
+	return mr.forkHead(repoURL, source), nil
+}
+
+// validateGitLabSpec validates a group/repo spec and returns the repo name.
+func validateGitLabSpec(repoSpec string) (string, error) {
+	parts := strings.Split(repoSpec, "/")
+	if len(parts) < 2 {
+		return "", fmt.Errorf("invalid repo spec %q: expected group/repo format", repoSpec)
+	}
+	repoName := parts[len(parts)-1]
+	if repoName == "" {
+		return "", fmt.Errorf("invalid repo spec %q: repo name must not be empty", repoSpec)
+	}
+	for _, p := range parts[:len(parts)-1] {
+		if p != "" {
+			return repoName, nil
+		}
+	}
+	return "", fmt.Errorf("invalid repo spec %q: group must not be empty", repoSpec)
+}
+
+// cloneURL returns the HTTPS clone URL for a group/repo spec.
+func (g *GitLabAPI) cloneURL(repoSpec string) string {
+	return "https://" + g.host() + "/" + repoSpec + ".git"
+}
+
+// CloneRepo clones a GitLab repo over HTTPS using git
+func (g *GitLabAPI) CloneRepo(ctx context.Context, repoSpec, destPath string) (string, error) {
+	repoName, err := validateGitLabSpec(repoSpec)
+	if err != nil {
+		return "", err
+	}
+	clonePath := filepath.Join(destPath, repoName)
+
+	if err := cloneWithGit(ctx, g.cloneURL(repoSpec), clonePath, false); err != nil {
+		return "", err
+	}
+	return clonePath, nil
+}
+
+// CloneBareRepo clones a GitLab repo as a bare repo inside .git directory
+func (g *GitLabAPI) CloneBareRepo(ctx context.Context, repoSpec, destPath string) (string, error) {
+	repoName, err := validateGitLabSpec(repoSpec)
+	if err != nil {
+		return "", err
+	}
+	repoDir := filepath.Join(destPath, repoName)
+
+	if err := cloneWithGit(ctx, g.cloneURL(repoSpec), repoDir, true); err != nil {
+		return "", err
+	}
+	return repoDir, nil
+}
+
+// CreatePR creates a new MR
+func (g *GitLabAPI) CreatePR(ctx context.Context, repoURL string, params CreatePRParams) (*CreatePRResult, error) {
+	projectPath := ExtractRepoPath(repoURL)
+	if params.Head == "" {
+		return nil, fmt.Errorf("source branch is required")
+	}
+	c, err := g.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	target := params.Base
+	if target == "" {
+		var project struct {
+			DefaultBranch string `json:"default_branch"`
+		}
+		if err := c.do(ctx, http.MethodGet, g.projectURL(projectPath, ""), nil, &project); err != nil {
+			return nil, fmt.Errorf("failed to determine default branch: %w", err)
+		}
+		target = project.DefaultBranch
+	}
+
+	title := params.Title
+	if params.Draft {
+		title = "Draft: " + title
+	}
+
+	var mr gitlabMR
+	if err := c.do(ctx, http.MethodPost, g.projectURL(projectPath, "/merge_requests"), map[string]any{
+		"source_branch": params.Head,
+		"target_branch": target,
+		"title":         title,
+		"description":   params.Body,
+	}, &mr); err != nil {
+		return nil, fmt.Errorf("create MR failed: %w", err)
+	}
+
+	return &CreatePRResult{
+		Number: mr.IID,
+		URL:    mr.WebURL,
+	}, nil
+}
+
+// MergePR merges a MR by number with the given strategy
+func (g *GitLabAPI) MergePR(ctx context.Context, repoURL string, number int, strategy string) error {
+	// Consistent with the glab backend: GitLab's rebase is a separate action
+	if strategy == "rebase" {
+		return fmt.Errorf("rebase merge strategy is not supported on GitLab (use squash or merge)")
+	}
+
+	projectPath := ExtractRepoPath(repoURL)
+	c, err := g.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := c.do(ctx, http.MethodPut, g.projectURL(projectPath, fmt.Sprintf("/merge_requests/%d/merge", number)), map[string]any{
+		"squash":                      strategy == "squash",
+		"should_remove_source_branch": true,
+	}, nil); err != nil {
+		return fmt.Errorf("merge failed: %w", err)
+	}
+	return nil
+}
+
+// UpdatePRBase changes the target branch of a MR
+func (g *GitLabAPI) UpdatePRBase(ctx context.Context, repoURL string, number int, base string) error {
+	projectPath := ExtractRepoPath(repoURL)
+	c, err := g.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := c.do(ctx, http.MethodPut, g.projectURL(projectPath, fmt.Sprintf("/merge_requests/%d", number)), map[string]any{
+		"target_branch": base,
+	}, nil); err != nil {
+		return fmt.Errorf("update target branch failed: %w", err)
+	}
+	return nil
+}
+
+// ListReviewThreads lists the diff discussions of a MR
+func (g *GitLabAPI) ListReviewThreads(ctx context.Context, repoURL string, number int) ([]ReviewThread, error) {
+	projectPath := ExtractRepoPath(repoURL)
+	c, err := g.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	discussions, err := gitlabListDiscussions(func(page int) ([]gitlabDiscussion, error) {
+		var batch []gitlabDiscussion
+		err := c.do(ctx, http.MethodGet, g.projectURL(projectPath, fmt.Sprintf("/merge_requests/%d/discussions?per_page=%d&page=%d", number, listLimit, page)), nil, &batch)
+		return batch, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("gitlab api request failed: %w", err)
+	}
+	return gitlabReviewThreads(discussions), nil
+}
+
+// ViewPR shows MR details or opens in browser
+func (g *GitLabAPI) ViewPR(ctx context.Context, repoURL string, number int, web bool) error {
+	projectPath := ExtractRepoPath(repoURL)
+	c, err := g.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	mr, err := g.getMR(ctx, c, projectPath, number)
+	if err != nil {
+		return fmt.Errorf("gitlab api request failed: %w", err)
+	}
+
+	if web {
+		return browser.Open(ctx, mr.WebURL)
+	}
+
+	state := normalizeGitLabState(mr.State)
+	if mr.Draft && state == PRStateOpen {
+		state = PRStateDraft
+	}
+	printPRDetails(os.Stdout, mr.Title, fmt.Sprintf("!%d", mr.IID), g.FormatState(state), mr.Author.Username, mr.WebURL, mr.Description)
+	return nil
+}
+
+// ListOpenPRs lists all open MRs for a repository
+func (g *GitLabAPI) ListOpenPRs(ctx context.Context, repoURL string) ([]OpenPR, error) {
+	projectPath := ExtractRepoPath(repoURL)
+	c, err := g.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var mrs []gitlabMR
+	if err := c.do(ctx, http.MethodGet, g.projectURL(projectPath, "/merge_requests?state=opened&per_page=100"), nil, &mrs); err != nil {
+		return nil, fmt.Errorf("gitlab api request failed: %w", err)
+	}
+
+	result := make([]OpenPR, len(mrs))
+	for i, mr := range mrs {
+		result[i] = newOpenPR(mr.toPRInfo(), mr.SourceBranch)
+		result[i].IsFork = mr.isFork()
+	}
+
+	return result, nil
+}
+
+// CurrentUser returns the username of the token's user
+func (g *GitLabAPI) CurrentUser(ctx context.Context, repoURL string) (string, error) {
+	c, err := g.client(ctx)
+	if err != nil {
+		return "", err
+	}
+	var user struct {
+		Username string `json:"username"`
+	}
+	if err := c.do(ctx, http.MethodGet, g.apiURL("/user"), nil, &user); err != nil {
+		return "", fmt.Errorf("gitlab api request failed: %w", err)
+	}
+	return user.Username, nil
+}
+
+// PRHeadRef returns the ref GitLab keeps the head of an MR under
+func (g *GitLabAPI) PRHeadRef(number int) string {
+	return (&GitLab{}).PRHeadRef(number)
+}
+
+// FormatState returns a human-readable PR state
+func (g *GitLabAPI) FormatState(state string) string {
+	return (&GitLab{}).FormatState(state)
+}
@@ -0,0 +1,338 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/raphi011/wt/internal/config"
+)
+
+// newTestGitLabAPI returns a GitLabAPI pointed at an httptest server running handler.
+// Requests without the configured token are rejected with 401.
+func newTestGitLabAPI(t *testing.T, handler http.HandlerFunc) *GitLabAPI {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			io.WriteString(w, `{"message":"401 Unauthorized"}`)
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	return &GitLabAPI{
+		ForgeConfig: &config.ForgeConfig{Tokens: map[string]string{"gitlab.test": "test-token"}},
+		Host:        "gitlab.test",
+		BaseURL:     srv.URL,
+	}
+}
+
+func TestGitLabAPI_GetPRForBranch(t *testing.T) {
+	t.Parallel()
+
+	g := newTestGitLabAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.RawPath == "/projects/group%2Fsub%2Frepo/pipelines" {
+			if q.Get("sha") != "abc123" {
+				t.Errorf("pipelines sha = %q, want abc123", q.Get("sha"))
+			}
+			io.WriteString(w, `[{"sha":"abc123","status":"running"}]`)
+			return
+		}
+		// Project path must be URL-encoded as a single path segment
+		if r.URL.RawPath != "/projects/group%2Fsub%2Frepo/merge_requests" {
+			t.Errorf("unexpected path %q", r.URL.RawPath)
+		}
+		if q.Get("state") != "all" {
+			t.Errorf("state = %q, want all", q.Get("state"))
+		}
+		if q.Get("source_branch") != "feature" {
+			io.WriteString(w, `[]`)
+			return
+		}
+		io.WriteString(w, `[{"iid":12,"state":"opened","draft":true,
+			"web_url":"https://gitlab.test/group/sub/repo/-/merge_requests/12",
+			"author":{"username":"bob"},"user_notes_count":2,
+			"title":"Add feature","target_branch":"main","labels":["backend"],
+			"reviewers":[{"username":"carol"}],"has_conflicts":false,"detailed_merge_status":"mergeable",
+			"sha":"abc123","updated_at":"2026-01-02T03:04:05Z"}]`)
+	})
+
+	ctx := context.Background()
+
+	pr, err := g.GetPRForBranch(ctx, "git@gitlab.test:group/sub/repo.git", "feature")
+	if err != nil {
+		t.Fatalf("GetPRForBranch() error = %v", err)
+	}
+	want := PRInfo{Number: 12, State: PRStateOpen, IsDraft: true,
+		URL: "https://gitlab.test/group/sub/repo/-/merge_requests/12", Author: "bob", CommentCount: 2, Fetched: true,
+		Title: "Add feature", BaseBranch: "main", Checks: CheckStatePending, Mergeable: MergeStateMergeable,
+		ReviewRequested: []string{"carol"}, Labels: []string{"backend"}, UpdatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}
+	pr.CachedAt = want.CachedAt
+	if !reflect.DeepEqual(*pr, want) {
+		t.Errorf("GetPRForBranch() = %+v, want %+v", *pr, want)
+	}
+
+	pr, err = g.GetPRForBranch(ctx, "git@gitlab.test:group/sub/repo.git", "no-mr")
+	if err != nil {
+		t.Fatalf("GetPRForBranch() error = %v", err)
+	}
+	if pr.Number != 0 || !pr.Fetched {
+		t.Errorf("expected fetched marker without MR, got %+v", *pr)
+	}
+}
+
+func TestGitLabAPI_GetPRHead(t *testing.T) {
+	t.Parallel()
+
+	g := newTestGitLabAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.RawPath
+		if path == "" {
+			path = r.URL.Path
+		}
+		switch path {
+		case "/projects/group%2Frepo/merge_requests/1":
+			io.WriteString(w, `{"iid":1,"source_branch":"feature","source_project_id":10,"target_project_id":10}`)
+		case "/projects/group%2Frepo/merge_requests/2":
+			io.WriteString(w, `{"iid":2,"source_branch":"patch","source_project_id":99,"target_project_id":10,"allow_collaboration":true}`)
+		case "/projects/99":
+			io.WriteString(w, `{"id":99,"path_with_namespace":"someone/repo","http_url_to_repo":"https://gitlab.test/someone/repo.git"}`)
+		case "/projects/group%2Frepo/merge_requests/3":
+			io.WriteString(w, `{"iid":3,"source_branch":"gone","source_project_id":98,"target_project_id":10}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `{"message":"404 Not found"}`)
+		}
+	})
+
+	ctx := context.Background()
+
+	head, err := g.GetPRHead(ctx, "https://gitlab.test/group/repo.git", 1)
+	if err != nil || !reflect.DeepEqual(*head, PRHead{Branch: "feature"}) {
+		t.Errorf("GetPRHead(1) = %+v, %v; want branch feature", head, err)
+	}
+
+	head, err = g.GetPRHead(ctx, "https://gitlab.test/group/repo.git", 2)
+	want := PRHead{Branch: "patch", IsFork: true, RepoPath: "someone/repo",
+		CloneURL: "https://gitlab.test/someone/repo.git", MaintainerCanModify: true}
+	if err != nil || !reflect.DeepEqual(*head, want) {
+		t.Errorf("GetPRHead(2) = %+v, %v; want %+v", head, err, want)
+	}
+
+	// Deleted fork: the MR ref still works, so it's not an error
+	head, err = g.GetPRHead(ctx, "https://gitlab.test/group/repo.git", 3)
+	want = PRHead{Branch: "gone", IsFork: true}
+	if err != nil || !reflect.DeepEqual(*head, want) {
+		t.Errorf("GetPRHead(3) = %+v, %v; want %+v", head, err, want)
+	}
+}
+
+func TestGitLabAPI_CreatePR(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	g := newTestGitLabAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.RawPath == "/projects/group%2Frepo":
+			io.WriteString(w, `{"default_branch":"develop"}`)
+		case r.Method == http.MethodPost && r.URL.RawPath == "/projects/group%2Frepo/merge_requests":
+			json.NewDecoder(r.Body).Decode(&got)
+			w.WriteHeader(http.StatusCreated)
+			io.WriteString(w, `{"iid":3,"web_url":"https://gitlab.test/group/repo/-/merge_requests/3"}`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	})
+
+	result, err := g.CreatePR(context.Background(), "git@gitlab.test:group/repo.git", CreatePRParams{
+		Title: "Add feature",
+		Head:  "feature",
+		Draft: true,
+	})
+	if err != nil {
+		t.Fatalf("CreatePR() error = %v", err)
+	}
+	if result.Number != 3 {
+		t.Errorf("CreatePR().Number = %d, want 3", result.Number)
+	}
+	if got["target_branch"] != "develop" || got["source_branch"] != "feature" || got["title"] != "Draft: Add feature" {
+		t.Errorf("unexpected request body: %v", got)
+	}
+}
+
+func TestGitLabAPI_MergePR(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	g := newTestGitLabAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.RawPath != "/projects/group%2Frepo/merge_requests/4/merge" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		io.WriteString(w, `{"iid":4,"state":"merged"}`)
+	})
+
+	ctx := context.Background()
+
+	if err := g.MergePR(ctx, "git@gitlab.test:group/repo.git", 4, "squash"); err != nil {
+		t.Fatalf("MergePR() error = %v", err)
+	}
+	if got["squash"] != true || got["should_remove_source_branch"] != true {
+		t.Errorf("unexpected request body: %v", got)
+	}
+
+	if err := g.MergePR(ctx, "git@gitlab.test:group/repo.git", 4, "rebase"); err == nil {
+		t.Error("MergePR() with rebase should fail")
+	}
+}
+
+func TestGitLabAPI_UpdatePRBase(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	g := newTestGitLabAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.RawPath != "/projects/group%2Frepo/merge_requests/4" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		io.WriteString(w, `{"iid":4}`)
+	})
+
+	if err := g.UpdatePRBase(context.Background(), "git@gitlab.test:group/repo.git", 4, "main"); err != nil {
+		t.Fatalf("UpdatePRBase() error = %v", err)
+	}
+	if got["target_branch"] != "main" {
+		t.Errorf("unexpected request body: %v", got)
+	}
+}
+
+func TestGitLabAPI_ListReviewThreads(t *testing.T) {
+	t.Parallel()
+
+	g := newTestGitLabAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.RawPath != "/projects/group%2Frepo/merge_requests/4/discussions" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		if r.URL.Query().Get("page") != "1" {
+			t.Errorf("unexpected page %q", r.URL.Query().Get("page"))
+		}
+		io.WriteString(w, `[
+			{"id": "a", "notes": [{"id": 1, "body": "General remark", "author": {"username": "bob"}, "created_at": "2026-01-01T00:00:00Z"}]},
+			{"id": "b", "notes": [
+				{"id": 2, "body": "Rename this", "author": {"username": "bob"}, "created_at": "2026-01-02T00:00:00Z",
+					"resolvable": true, "resolved": true, "position": {"new_path": "main.go", "old_path": "main.go", "new_line": 8}},
+				{"id": 3, "body": "changed this line in version 2", "system": true, "author": {"username": "alice"}, "created_at": "2026-01-02T01:00:00Z"},
+				{"id": 4, "body": "Done", "author": {"username": "alice"}, "created_at": "2026-01-02T02:00:00Z", "resolvable": true, "resolved": true}]},
+			{"id": "c", "notes": [{"id": 5, "body": "Why remove?", "author": {"username": "carol"}, "created_at": "2026-01-03T00:00:00Z",
+				"resolvable": true, "resolved": false, "position": {"new_path": "", "old_path": "old.go", "new_line": null, "old_line": 3}}]}
+		]`)
+	})
+
+	threads, err := g.ListReviewThreads(context.Background(), "git@gitlab.test:group/repo.git", 4)
+	if err != nil {
+		t.Fatalf("ListReviewThreads() error = %v", err)
+	}
+
+	want := []ReviewThread{
+		{Path: "main.go", Line: 8, Resolved: true, Comments: []ReviewComment{
+			{Author: "bob", Body: "Rename this", CreatedAt: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)},
+			{Author: "alice", Body: "Done", CreatedAt: time.Date(2026, 1, 2, 2, 0, 0, 0, time.UTC)},
+		}},
+		{Path: "old.go", Line: 3, Comments: []ReviewComment{
+			{Author: "carol", Body: "Why remove?", CreatedAt: time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)},
+		}},
+	}
+	if !reflect.DeepEqual(threads, want) {
+		t.Errorf("ListReviewThreads() = %+v, want %+v", threads, want)
+	}
+}
+
+func TestGitLabAPI_ListOpenPRs(t *testing.T) {
+	t.Parallel()
+
+	g := newTestGitLabAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("state") != "opened" {
+			t.Errorf("state = %q, want opened", r.URL.Query().Get("state"))
+		}
+		io.WriteString(w, `[{"iid":1,"title":"One","source_branch":"one","draft":false,"author":{"username":"alice"},
+			"web_url":"https://gitlab.test/group/repo/-/merge_requests/1","labels":["backend"],"reviewers":[{"username":"carol"}],
+			"source_project_id":7,"target_project_id":1}]`)
+	})
+
+	prs, err := g.ListOpenPRs(context.Background(), "git@gitlab.test:group/repo.git")
+	if err != nil {
+		t.Fatalf("ListOpenPRs() error = %v", err)
+	}
+	want := OpenPR{Number: 1, Title: "One", Author: "alice", Branch: "one", IsFork: true, URL: "https://gitlab.test/group/repo/-/merge_requests/1",
+		Labels: []string{"backend"}, ReviewRequested: []string{"carol"}}
+	if len(prs) != 1 || !reflect.DeepEqual(prs[0], want) {
+		t.Errorf("ListOpenPRs() = %+v, want [%+v]", prs, want)
+	}
+}
+
+func TestGitLabAPI_Unauthorized(t *testing.T) {
+	t.Parallel()
+
+	g := newTestGitLabAPI(t, func(w http.ResponseWriter, r *http.Request) {})
+	g.ForgeConfig.Tokens["gitlab.test"] = "wrong-token"
+
+	err := g.Check(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "HTTP 401") {
+		t.Errorf("Check() should fail with 401, got %v", err)
+	}
+}
+
+func TestGitLabAPI_GetPRsForBranches(t *testing.T) {
+	t.Parallel()
+
+	var requests int
+	g := newTestGitLabAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		q := r.URL.Query()
+		if strings.HasSuffix(r.URL.Path, "/pipelines") {
+			// Newest first: only the newest pipeline of a commit counts
+			io.WriteString(w, `[{"sha":"aaa","status":"failed"},{"sha":"bbb","status":"success"},{"sha":"aaa","status":"success"}]`)
+			return
+		}
+		if q.Get("state") != "all" || q.Get("source_branch") != "" {
+			t.Errorf("expected a single listing of all MRs, got %s", r.URL)
+		}
+		// Newest first: feat-a has a newer open MR and an older closed one
+		io.WriteString(w, `[
+			{"iid":5,"state":"opened","source_branch":"feat-a","web_url":"u5","sha":"aaa","has_conflicts":true},
+			{"iid":4,"state":"merged","source_branch":"feat-b","web_url":"u4","sha":"bbb"},
+			{"iid":2,"state":"closed","source_branch":"feat-a","web_url":"u2"}
+		]`)
+	})
+
+	prs, err := g.GetPRsForBranches(context.Background(), "git@gitlab.test:group/repo.git", []string{"feat-a", "feat-b", "feat-c"})
+	if err != nil {
+		t.Fatalf("GetPRsForBranches() error = %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("expected an MR and a pipeline listing, got %d requests", requests)
+	}
+	if prs["feat-a"].Number != 5 || prs["feat-a"].State != PRStateOpen {
+		t.Errorf("feat-a = %+v, want open MR !5", *prs["feat-a"])
+	}
+	if pr := prs["feat-a"]; pr.Checks != CheckStateFailing || pr.Mergeable != MergeStateConflicting {
+		t.Errorf("feat-a checks/mergeable = %q/%q, want failing/conflicting", pr.Checks, pr.Mergeable)
+	}
+	if pr := prs["feat-b"]; pr.Checks != "" || pr.Mergeable != "" {
+		t.Errorf("merged feat-b should have no checks/mergeable, got %q/%q", pr.Checks, pr.Mergeable)
+	}
+	if prs["feat-b"].State != PRStateMerged {
+		t.Errorf("feat-b state = %q, want %q", prs["feat-b"].State, PRStateMerged)
+	}
+	if pr := prs["feat-c"]; pr.Number != 0 || !pr.Fetched {
+		t.Errorf("feat-c = %+v, want fetched marker", *pr)
+	}
+}
@@ -0,0 +1,228 @@
+package forge
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// maxReviewPages bounds how many pages of review comments are read for a
+// single PR (listLimit per page).
+const maxReviewPages = 10
+
+// ReviewComment is a single comment in a review thread
+type ReviewComment struct {
+	Author    string    `json:"author"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at,omitzero"`
+}
+
+// ReviewThread is a review discussion attached to a file of a PR. The first
+// comment starts the thread, the rest are replies in chronological order.
+type ReviewThread struct {
+	Path     string          `json:"path"`
+	Line     int             `json:"line,omitempty"` // line in the PR's version of the file (0 = unknown)
+	Resolved bool            `json:"resolved"`
+	Outdated bool            `json:"outdated,omitempty"` // the code the thread refers to has changed since
+	URL      string          `json:"url,omitempty"`
+	Comments []ReviewComment `json:"comments"`
+}
+
+// sortReviewThreads orders threads by file, line and start of the discussion.
+func sortReviewThreads(threads []ReviewThread) {
+	sort.SliceStable(threads, func(i, j int) bool {
+		a, b := threads[i], threads[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if len(a.Comments) == 0 || len(b.Comments) == 0 {
+			return len(a.Comments) > len(b.Comments)
+		}
+		return a.Comments[0].CreatedAt.Before(b.Comments[0].CreatedAt)
+	})
+}
+
+// githubReviewThreadsQuery pages through the review threads of a PR.
+const githubReviewThreadsQuery = `query($owner: String!, $name: String!, $number: Int!, $after: String) {
+  repository(owner: $owner, name: $name) {
+    pullRequest(number: $number) {
+      reviewThreads(first: 100, after: $after) {
+        pageInfo { hasNextPage endCursor }
+        nodes {
+          path line originalLine isResolved isOutdated
+          comments(first: 100) { nodes { author { login } body createdAt url } }
+        }
+      }
+    }
+  }
+}`
+
+// githubReviewThread is the GraphQL shape of a PR review thread.
+type githubReviewThread struct {
+	Path         string `json:"path"`
+	Line         int    `json:"line"`         // null once outdated
+	OriginalLine int    `json:"originalLine"` // line in the commit the thread was started on
+	IsResolved   bool   `json:"isResolved"`
+	IsOutdated   bool   `json:"isOutdated"`
+	Comments     struct {
+		Nodes []struct {
+			Author *struct {
+				Login string `json:"login"`
+			} `json:"author"` // null for deleted accounts
+			Body      string    `json:"body"`
+			CreatedAt time.Time `json:"createdAt"`
+			URL       string    `json:"url"`
+		} `json:"nodes"`
+	} `json:"comments"`
+}
+
+// toReviewThread converts a GraphQL review thread to ReviewThread.
+func (t githubReviewThread) toReviewThread() ReviewThread {
+	thread := ReviewThread{
+		Path:     t.Path,
+		Line:     t.Line,
+		Resolved: t.IsResolved,
+		Outdated: t.IsOutdated,
+	}
+	if thread.Line == 0 {
+		thread.Line = t.OriginalLine
+	}
+	for i, c := range t.Comments.Nodes {
+		if i == 0 {
+			thread.URL = c.URL
+		}
+		comment := ReviewComment{Body: c.Body, CreatedAt: c.CreatedAt}
+		if c.Author != nil {
+			comment.Author = c.Author.Login
+		}
+		thread.Comments = append(thread.Comments, comment)
+	}
+	return thread
+}
+
+// githubReviewThreads lists the review threads of PR number, following the
+// GraphQL cursor. run executes a query with the given variables and decodes
+// "data" into out.
+func githubReviewThreads(owner, name string, number int, run func(query string, vars map[string]any, out any) error) ([]ReviewThread, error) {
+	var threads []ReviewThread
+	var after string
+
+	for page := 0; page < maxReviewPages; page++ {
+		vars := map[string]any{"owner": owner, "name": name, "number": number}
+		if after != "" {
+			vars["after"] = after
+		}
+
+		var data struct {
+			Repository struct {
+				PullRequest *struct {
+					ReviewThreads struct {
+						PageInfo struct {
+							HasNextPage bool   `json:"hasNextPage"`
+							EndCursor   string `json:"endCursor"`
+						} `json:"pageInfo"`
+						Nodes []githubReviewThread `json:"nodes"`
+					} `json:"reviewThreads"`
+				} `json:"pullRequest"`
+			} `json:"repository"`
+		}
+		if err := run(githubReviewThreadsQuery, vars, &data); err != nil {
+			return nil, err
+		}
+
+		pr := data.Repository.PullRequest
+		if pr == nil {
+			return nil, fmt.Errorf("PR #%d not found", number)
+		}
+		for _, t := range pr.ReviewThreads.Nodes {
+			threads = append(threads, t.toReviewThread())
+		}
+		if !pr.ReviewThreads.PageInfo.HasNextPage {
+			break
+		}
+		after = pr.ReviewThreads.PageInfo.EndCursor
+	}
+
+	sortReviewThreads(threads)
+	return threads, nil
+}
+
+// gitlabDiscussion is the REST shape of a merge request discussion.
+type gitlabDiscussion struct {
+	ID    string `json:"id"`
+	Notes []struct {
+		ID     int    `json:"id"`
+		Body   string `json:"body"`
+		System bool   `json:"system"`
+		Author struct {
+			Username string `json:"username"`
+		} `json:"author"`
+		CreatedAt  time.Time `json:"created_at"`
+		Resolvable bool      `json:"resolvable"`
+		Resolved   bool      `json:"resolved"`
+		Position   *struct {
+			NewPath string `json:"new_path"`
+			OldPath string `json:"old_path"`
+			NewLine int    `json:"new_line"`
+			OldLine int    `json:"old_line"`
+		} `json:"position"` // only set on diff notes
+	} `json:"notes"`
+}
+
+// gitlabReviewThreads converts the diff discussions of a MR to review
+// threads; general discussions and system notes are skipped.
+func gitlabReviewThreads(discussions []gitlabDiscussion) []ReviewThread {
+	var threads []ReviewThread
+	for _, d := range discussions {
+		if len(d.Notes) == 0 || d.Notes[0].System || d.Notes[0].Position == nil {
+			continue
+		}
+
+		first := d.Notes[0]
+		thread := ReviewThread{
+			Path:     first.Position.NewPath,
+			Line:     first.Position.NewLine,
+			Resolved: first.Resolvable && first.Resolved,
+		}
+		if thread.Path == "" {
+			thread.Path = first.Position.OldPath
+		}
+		if thread.Line == 0 {
+			thread.Line = first.Position.OldLine
+		}
+		for _, n := range d.Notes {
+			if n.System {
+				continue
+			}
+			thread.Comments = append(thread.Comments, ReviewComment{
+				Author:    n.Author.Username,
+				Body:      n.Body,
+				CreatedAt: n.CreatedAt,
+			})
+		}
+		threads = append(threads, thread)
+	}
+
+	sortReviewThreads(threads)
+	return threads
+}
+
+// gitlabListDiscussions reads all discussion pages of a MR. fetch returns
+// one page (starting at 1) of listLimit discussions.
+func gitlabListDiscussions(fetch func(page int) ([]gitlabDiscussion, error)) ([]gitlabDiscussion, error) {
+	var all []gitlabDiscussion
+	for page := 1; page <= maxReviewPages; page++ {
+		batch, err := fetch(page)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, batch...)
+		if len(batch) < listLimit {
+			break
+		}
+	}
+	return all, nil
+}
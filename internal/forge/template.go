@@ -0,0 +1,93 @@
+package forge
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// githubTemplatePaths are the locations GitHub (and Gitea/Forgejo, which
+// also reads .gitea/) look for a single PR template, in order.
+var githubTemplatePaths = []string{
+	".github/pull_request_template.md",
+	".gitea/pull_request_template.md",
+	"pull_request_template.md",
+	"docs/pull_request_template.md",
+}
+
+// gitlabTemplateDir holds GitLab merge request templates. Default.md is
+// GitLab's default; otherwise the first template (by name) is used.
+const gitlabTemplateDir = ".gitlab/merge_request_templates"
+
+// FindPRTemplate returns the PR/MR body template of the repository checked
+// out at dir, or "" if it has none. GitLab templates are preferred for
+// forgeName "gitlab", GitHub-style templates otherwise. File names are
+// matched case-insensitively.
+func FindPRTemplate(dir, forgeName string) string {
+	finders := []func(string) string{findGitHubTemplate, findGitLabTemplate}
+	if forgeName == "gitlab" {
+		slices.Reverse(finders)
+	}
+	for _, find := range finders {
+		if content := find(dir); content != "" {
+			return content
+		}
+	}
+	return ""
+}
+
+// findGitHubTemplate reads the first GitHub-style PR template in dir.
+func findGitHubTemplate(dir string) string {
+	for _, rel := range githubTemplatePaths {
+		folder, name := filepath.Split(filepath.FromSlash(rel))
+		if content := readFileFold(filepath.Join(dir, folder), name); content != "" {
+			return content
+		}
+	}
+	return ""
+}
+
+// findGitLabTemplate reads Default.md, or else the first template, from
+// the GitLab merge request templates directory in dir.
+func findGitLabTemplate(dir string) string {
+	templateDir := filepath.Join(dir, filepath.FromSlash(gitlabTemplateDir))
+	if content := readFileFold(templateDir, "default.md"); content != "" {
+		return content
+	}
+	entries, err := os.ReadDir(templateDir)
+	if err != nil {
+		return ""
+	}
+	for _, e := range entries { // sorted by name
+		if !e.IsDir() && strings.EqualFold(filepath.Ext(e.Name()), ".md") {
+			if content := readFile(filepath.Join(templateDir, e.Name())); content != "" {
+				return content
+			}
+		}
+	}
+	return ""
+}
+
+// readFileFold reads the file in dir whose name matches name ignoring case.
+func readFileFold(dir, name string) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+	for _, e := range entries {
+		if !e.IsDir() && strings.EqualFold(e.Name(), name) {
+			return readFile(filepath.Join(dir, e.Name()))
+		}
+	}
+	return ""
+}
+
+// readFile returns the trimmed content of path, or "" if it can't be read.
+func readFile(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,73 @@
+package forge
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindPRTemplate(t *testing.T) {
+	t.Parallel()
+
+	write := func(t *testing.T, dir, rel, content string) {
+		t.Helper()
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("none", func(t *testing.T) {
+		t.Parallel()
+		if got := FindPRTemplate(t.TempDir(), "github"); got != "" {
+			t.Errorf("FindPRTemplate() = %q, want empty", got)
+		}
+	})
+
+	t.Run("github upper case", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		write(t, dir, ".github/PULL_REQUEST_TEMPLATE.md", "## Summary\n")
+		write(t, dir, "docs/pull_request_template.md", "docs")
+		if got := FindPRTemplate(dir, "github"); got != "## Summary" {
+			t.Errorf("FindPRTemplate() = %q, want .github template", got)
+		}
+	})
+
+	t.Run("gitlab default", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		write(t, dir, ".gitlab/merge_request_templates/Bug.md", "bug")
+		write(t, dir, ".gitlab/merge_request_templates/Default.md", "default")
+		if got := FindPRTemplate(dir, "gitlab"); got != "default" {
+			t.Errorf("FindPRTemplate() = %q, want default", got)
+		}
+	})
+
+	t.Run("gitlab first by name", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		write(t, dir, ".gitlab/merge_request_templates/Feature.md", "feature")
+		write(t, dir, ".gitlab/merge_request_templates/Bug.md", "bug")
+		write(t, dir, ".gitlab/merge_request_templates/notes.txt", "ignored")
+		if got := FindPRTemplate(dir, "gitlab"); got != "bug" {
+			t.Errorf("FindPRTemplate() = %q, want bug", got)
+		}
+	})
+
+	t.Run("forge decides precedence", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		write(t, dir, ".github/pull_request_template.md", "github")
+		write(t, dir, ".gitlab/merge_request_templates/Default.md", "gitlab")
+		if got := FindPRTemplate(dir, "gitlab"); got != "gitlab" {
+			t.Errorf("FindPRTemplate(gitlab) = %q, want gitlab", got)
+		}
+		if got := FindPRTemplate(dir, "gitea"); got != "github" {
+			t.Errorf("FindPRTemplate(gitea) = %q, want github", got)
+		}
+	})
+}
@@ -1,5 +1,6 @@
-// Package fs provides filesystem utilities: atomic JSON storage, path
-// resolution (symlink canonicalization), and the ~/.wt/ data directory.
+// Package fs provides filesystem utilities: atomic JSON storage, file
+// locking for read-modify-write of shared state files, path resolution
+// (symlink canonicalization), and the ~/.wt/ data directory.
 package fs
 
 import (
@@ -24,26 +25,43 @@ func WtDir() (string, error) {
 }
 
 // SaveJSON atomically writes data as JSON to the specified path.
-// It ensures the parent directory exists, writes to a temp file,
-// then renames to the final path for atomic operation.
+// It ensures the parent directory exists, writes to a uniquely named temp
+// file in the same directory, then renames to the final path, so concurrent
+// writers never share (and corrupt) a temp file.
 func SaveJSON(path string, data any) error {
 	// Ensure directory exists
-	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
 		return err
 	}
 
-	tempPath := path + ".tmp"
-
 	jsonData, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
 		return err
 	}
 
-	if err := os.WriteFile(tempPath, jsonData, 0o600); err != nil {
+	// CreateTemp uses mode 0600, which is what state files should have
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tempPath := tmp.Name()
+
+	if _, err := tmp.Write(jsonData); err != nil {
+		tmp.Close()
+		os.Remove(tempPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tempPath)
 		return err
 	}
 
-	return os.Rename(tempPath, path)
+	if err := os.Rename(tempPath, path); err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+	return nil
 }
 
 // LoadJSON reads JSON from the specified path into dest.
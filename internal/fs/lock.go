@@ -0,0 +1,99 @@
+package fs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultLockTimeout is how long WithLock and UpdateJSON wait for another
+// wt process to release a state file before giving up.
+const DefaultLockTimeout = 10 * time.Second
+
+// ErrLockTimeout is returned when a lock could not be acquired in time.
+var ErrLockTimeout = errors.New("timed out waiting for lock")
+
+// lockPollInterval is the delay between attempts to acquire a held lock.
+const lockPollInterval = 10 * time.Millisecond
+
+// FileLock is an exclusive advisory lock guarding a state file.
+// The lock is held on a sibling "<path>.lock" file rather than the file
+// itself, because SaveJSON replaces the file (and its inode) on every write.
+type FileLock struct {
+	f *os.File
+}
+
+// Lock acquires an exclusive lock for path, waiting up to timeout for
+// other holders (in this or another process) to release it.
+// Returns an error wrapping ErrLockTimeout if the lock is still held
+// when the timeout expires.
+func Lock(path string, timeout time.Duration) (*FileLock, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+
+	lockPath := path + ".lock"
+	deadline := time.Now().Add(timeout)
+	for {
+		// Each attempt uses its own descriptor: flock(2) locks are per open
+		// file description, so goroutines in one process exclude each other too.
+		f, err := os.OpenFile(lockPath, os.O_RDWR|os.O_CREATE, 0o600)
+		if err != nil {
+			return nil, err
+		}
+
+		ok, err := tryLock(f)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("lock %s: %w", lockPath, err)
+		}
+		if ok {
+			return &FileLock{f: f}, nil
+		}
+		f.Close()
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%w: %s (another wt process may be running)", ErrLockTimeout, lockPath)
+		}
+		time.Sleep(lockPollInterval)
+	}
+}
+
+// Unlock releases the lock. The lock file is left in place so that
+// concurrent lockers always agree on the file they lock.
+func (l *FileLock) Unlock() error {
+	if err := unlock(l.f); err != nil {
+		l.f.Close()
+		return err
+	}
+	return l.f.Close()
+}
+
+// WithLock runs fn while holding the lock for path.
+func WithLock(path string, fn func() error) error {
+	l, err := Lock(path, DefaultLockTimeout)
+	if err != nil {
+		return err
+	}
+	defer l.Unlock()
+
+	return fn()
+}
+
+// UpdateJSON performs a locked read-modify-write of the JSON file at path.
+// dest is filled from the current file contents (left untouched if the file
+// doesn't exist), then fn mutates it, and the result is saved atomically.
+// Nothing is written if fn returns an error.
+func UpdateJSON(path string, dest any, fn func() error) error {
+	return WithLock(path, func() error {
+		if err := LoadJSON(path, dest); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if err := fn(); err != nil {
+			return err
+		}
+		return SaveJSON(path, dest)
+	})
+}
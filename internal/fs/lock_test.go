@@ -0,0 +1,119 @@
+package fs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestUpdateJSON_ConcurrentWriters(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "counter.json")
+
+	type counter struct {
+		N int `json:"n"`
+	}
+
+	const workers = 50
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var c counter
+			errs <- UpdateJSON(path, &c, func() error {
+				c.N++
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("UpdateJSON failed: %v", err)
+		}
+	}
+
+	var got counter
+	if err := LoadJSON(path, &got); err != nil {
+		t.Fatalf("LoadJSON failed: %v", err)
+	}
+	if got.N != workers {
+		t.Errorf("lost updates: n = %d, want %d", got.N, workers)
+	}
+
+	// No temp files are left behind
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if filepath.Ext(e.Name()) == ".tmp" {
+			t.Errorf("leftover temp file %s", e.Name())
+		}
+	}
+}
+
+func TestUpdateJSON_FnErrorSkipsSave(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "data.json")
+	if err := SaveJSON(path, map[string]int{"n": 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	wantErr := errors.New("boom")
+	var data map[string]int
+	err := UpdateJSON(path, &data, func() error {
+		data["n"] = 2
+		return wantErr
+	})
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("UpdateJSON error = %v, want %v", err, wantErr)
+	}
+
+	var loaded map[string]int
+	if err := LoadJSON(path, &loaded); err != nil {
+		t.Fatal(err)
+	}
+	if loaded["n"] != 1 {
+		t.Errorf("n = %d, want 1 (file must be unchanged)", loaded["n"])
+	}
+}
+
+func TestLock_Timeout(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "data.json")
+
+	held, err := Lock(path, time.Second)
+	if err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+
+	start := time.Now()
+	_, err = Lock(path, 50*time.Millisecond)
+	if !errors.Is(err, ErrLockTimeout) {
+		t.Fatalf("second Lock error = %v, want ErrLockTimeout", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("Lock gave up after %v, want at least the timeout", elapsed)
+	}
+
+	if err := held.Unlock(); err != nil {
+		t.Fatalf("Unlock failed: %v", err)
+	}
+
+	again, err := Lock(path, time.Second)
+	if err != nil {
+		t.Fatalf("Lock after Unlock failed: %v", err)
+	}
+	again.Unlock()
+}
@@ -0,0 +1,32 @@
+//go:build !windows
+
+package fs
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// tryLock attempts to take an exclusive flock on f without blocking.
+// Returns false if another descriptor holds the lock.
+func tryLock(f *os.File) (bool, error) {
+	for {
+		err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+		switch {
+		case err == nil:
+			return true, nil
+		case errors.Is(err, syscall.EWOULDBLOCK):
+			return false, nil
+		case errors.Is(err, syscall.EINTR):
+			continue
+		default:
+			return false, err
+		}
+	}
+}
+
+// unlock releases the flock on f.
+func unlock(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+}
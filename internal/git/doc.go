@@ -24,6 +24,13 @@
 //   - [GetDefaultBranch]: Detect main/master branch
 //   - [CloneRegular], [CloneBareWithWorktreeSupport]: Clone repositories
 //
+// # Merge Detection
+//
+// Detect merged branches from local git data (used by prune alongside PR state):
+//
+//   - [DetectLocalMerges]: Set [Worktree.MergeReason] for worktrees merged into origin/<default>
+//   - [IsAncestor], [IsSquashMerged], [GetGoneUpstreams]: Individual ancestry, squash/rebase and deleted-upstream checks
+//
 // # Migration
 //
 // Convert between regular and bare-in-.git repository formats:
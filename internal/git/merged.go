@@ -0,0 +1,272 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// MergeReason describes why a worktree's branch is considered merged.
+// Empty means the branch is not (known to be) merged.
+type MergeReason string
+
+// Merge reason constants, in order of precedence.
+const (
+	MergeReasonPR           MergeReason = "pr"            // forge reports the PR/MR as merged
+	MergeReasonAncestor     MergeReason = "ancestor"      // branch tip is reachable from origin/<default> (merge commit, or fast-forward of commits made on the branch)
+	MergeReasonSquash       MergeReason = "squash"        // branch changes exist on origin/<default> as different commits (squash or rebase merge)
+	MergeReasonUpstreamGone MergeReason = "upstream-gone" // upstream branch was deleted on the remote
+)
+
+// IsAncestor reports whether ancestor is reachable from ref.
+// Uses `git merge-base --is-ancestor`, which exits 1 for "no" and >1 on error.
+func IsAncestor(ctx context.Context, repoPath, ancestor, ref string) (bool, error) {
+	err := runGit(ctx, repoPath, "merge-base", "--is-ancestor", ancestor, ref)
+	if err == nil {
+		return true, nil
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return false, nil
+	}
+	return false, err
+}
+
+// IsSquashMerged reports whether the changes of branch are already contained in target
+// as different commits, as produced by squash or rebase merges.
+//
+// First checks every commit individually with `git cherry` (rebase merges keep
+// one patch per commit). If that fails, synthesizes a single dangling commit with
+// the branch's tree on top of the merge-base and checks that patch instead, which
+// detects multi-commit squash merges as long as target hasn't rewritten the same lines since.
+func IsSquashMerged(ctx context.Context, repoPath, branch, target string) (bool, error) {
+	unmerged, total, err := cherry(ctx, repoPath, target, branch)
+	if err != nil {
+		return false, err
+	}
+	if total == 0 {
+		// No commits of its own: nothing was squashed
+		return false, nil
+	}
+	if unmerged == 0 {
+		return true, nil
+	}
+
+	base, err := outputGit(ctx, repoPath, "merge-base", target, branch)
+	if err != nil {
+		return false, nil // unrelated histories
+	}
+	tree, err := outputGit(ctx, repoPath, "rev-parse", branch+"^{tree}")
+	if err != nil {
+		return false, err
+	}
+	squashed, err := outputGit(ctx, repoPath, "commit-tree", strings.TrimSpace(string(tree)),
+		"-p", strings.TrimSpace(string(base)), "-m", "wt squash-merge probe")
+	if err != nil {
+		return false, err
+	}
+
+	unmerged, _, err = cherry(ctx, repoPath, target, strings.TrimSpace(string(squashed)))
+	if err != nil {
+		return false, err
+	}
+	return unmerged == 0, nil
+}
+
+// cherry runs `git cherry upstream head` and returns the number of commits
+// without an equivalent patch in upstream, and the total number of commits listed.
+func cherry(ctx context.Context, repoPath, upstream, head string) (unmerged, total int, err error) {
+	output, err := outputGit(ctx, repoPath, "cherry", upstream, head)
+	if err != nil {
+		return 0, 0, err
+	}
+	for line := range strings.SplitSeq(strings.TrimSpace(string(output)), "\n") {
+		if line == "" {
+			continue
+		}
+		total++
+		if strings.HasPrefix(line, "+") {
+			unmerged++
+		}
+	}
+	return unmerged, total, nil
+}
+
+// GetGoneUpstreams returns the set of local branches whose configured upstream
+// no longer exists on the remote (shown as "[gone]" after `git fetch --prune`).
+func GetGoneUpstreams(ctx context.Context, repoPath string) map[string]bool {
+	gone := make(map[string]bool)
+	output, err := outputGit(ctx, repoPath, "for-each-ref", "--format=%(refname:short) %(upstream:track)", "refs/heads")
+	if err != nil {
+		return gone
+	}
+	for line := range strings.SplitSeq(string(output), "\n") {
+		branch, track, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if ok && track == "[gone]" {
+			gone[branch] = true
+		}
+	}
+	return gone
+}
+
+// mergedByMergeCommit reports whether branch, whose tip is reachable from
+// target, was brought in by a merge commit: its tip is not on the first-parent
+// history of target. A tip on that history is either fast-forwarded into
+// target or a branch created at a target commit that has no commits of its own.
+func mergedByMergeCommit(ctx context.Context, repoPath, branch, target string) (bool, error) {
+	tip, err := outputGit(ctx, repoPath, "rev-parse", "refs/heads/"+branch)
+	if err != nil {
+		return false, err
+	}
+	// Oldest first-parent commit of target not reachable from the tip, with its parents
+	output, err := outputGit(ctx, repoPath, "rev-list", "--first-parent", "--reverse", "--parents",
+		"^"+strings.TrimSpace(string(tip)), target)
+	if err != nil {
+		return false, err
+	}
+	first, _, _ := strings.Cut(string(output), "\n")
+	commits := strings.Fields(first)
+	if len(commits) < 2 {
+		return false, nil // tip is target (or a root commit of it): nothing merged
+	}
+	return commits[1] != strings.TrimSpace(string(tip)), nil
+}
+
+// tipIsOwnCommit reports whether the tip of branch was made on the branch,
+// according to its reflog: an entry that moved the branch to its current tip
+// must be a commit, cherry-pick or merge commit. Commits discarded again
+// (e.g. by `git reset --hard origin/main`) don't count. It only confirms a
+// fast-forward merge: without a reflog (e.g. in bare repos, which don't
+// write one) it returns false.
+func tipIsOwnCommit(ctx context.Context, repoPath, branch string) bool {
+	tip, err := outputGit(ctx, repoPath, "rev-parse", "refs/heads/"+branch)
+	if err != nil {
+		return false
+	}
+	output, err := outputGit(ctx, repoPath, "reflog", "show", "--format=%H %gs", "refs/heads/"+branch, "--")
+	if err != nil {
+		return false
+	}
+	for line := range strings.SplitSeq(string(output), "\n") {
+		sha, subject, ok := strings.Cut(line, " ")
+		if ok && sha == strings.TrimSpace(string(tip)) && isCommitReflogSubject(subject) {
+			return true
+		}
+	}
+	return false
+}
+
+// isCommitReflogSubject reports whether a reflog subject records a commit
+// made on the branch ("commit: ...", "commit (amend): ...", "cherry-pick: ...",
+// "merge x: Merge made by ..."), as opposed to a reset, fast-forward or
+// branch creation.
+func isCommitReflogSubject(subject string) bool {
+	switch {
+	case strings.HasPrefix(subject, "commit"), strings.HasPrefix(subject, "cherry-pick"):
+		return true
+	case strings.HasPrefix(subject, "merge "):
+		return !strings.HasSuffix(subject, ": Fast-forward")
+	default:
+		return false
+	}
+}
+
+// LocalMergeReason detects whether branch has been merged into target using
+// only local git data. Checks, in order: ancestry, squash/rebase patch
+// equivalence, and a deleted upstream (goneUpstream, see [GetGoneUpstreams]).
+// A branch reachable from target only counts as merged with evidence that it
+// had commits of its own: a merge commit, or a tip committed on the branch
+// according to its reflog. Otherwise it may just have been created at (or
+// reset to) a target commit.
+// Returns an empty reason if the branch is not merged.
+func LocalMergeReason(ctx context.Context, repoPath, branch, target string, goneUpstream bool) (MergeReason, error) {
+	isAncestor, err := IsAncestor(ctx, repoPath, "refs/heads/"+branch, target)
+	if err != nil {
+		return "", err
+	}
+	if isAncestor {
+		merged, err := mergedByMergeCommit(ctx, repoPath, branch, target)
+		if err != nil {
+			return "", err
+		}
+		if merged || tipIsOwnCommit(ctx, repoPath, branch) {
+			return MergeReasonAncestor, nil
+		}
+	} else {
+		squashed, err := IsSquashMerged(ctx, repoPath, "refs/heads/"+branch, target)
+		if err != nil {
+			return "", err
+		}
+		if squashed {
+			return MergeReasonSquash, nil
+		}
+	}
+
+	if goneUpstream {
+		return MergeReasonUpstreamGone, nil
+	}
+	return "", nil
+}
+
+// DetectLocalMerges sets MergeReason on worktrees whose branch has been merged
+// into origin/<default>, using [LocalMergeReason]. Worktrees that already have
+// a MergeReason (e.g. from the PR cache), are detached, check out the default
+// branch, or are the main worktree are left untouched.
+// Repos are processed in parallel. Per-worktree git failures are returned as
+// warnings; the affected worktree simply stays unmerged.
+func DetectLocalMerges(ctx context.Context, worktrees []Worktree) []LoadWarning {
+	byRepo := make(map[string][]int)
+	var repoOrder []string
+	for i, wt := range worktrees {
+		if _, ok := byRepo[wt.RepoPath]; !ok {
+			repoOrder = append(repoOrder, wt.RepoPath)
+		}
+		byRepo[wt.RepoPath] = append(byRepo[wt.RepoPath], i)
+	}
+
+	warnings := make([][]LoadWarning, len(repoOrder))
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(8) // Bound concurrent git operations
+
+	for r, repoPath := range repoOrder {
+		g.Go(func() error {
+			defaultBranch := GetDefaultBranch(ctx, repoPath)
+			target := "refs/remotes/origin/" + defaultBranch
+			hasTarget := RefExists(ctx, repoPath, target)
+			gone := GetGoneUpstreams(ctx, repoPath)
+
+			for _, i := range byRepo[repoPath] {
+				wt := &worktrees[i]
+				if wt.MergeReason != "" || wt.Branch == "" || wt.Branch == "(detached)" ||
+					wt.Branch == defaultBranch || wt.Path == repoPath {
+					continue
+				}
+				if !hasTarget {
+					if gone[wt.Branch] {
+						wt.MergeReason = MergeReasonUpstreamGone
+					}
+					continue
+				}
+				reason, err := LocalMergeReason(ctx, repoPath, wt.Branch, target, gone[wt.Branch])
+				if err != nil {
+					warnings[r] = append(warnings[r], LoadWarning{RepoName: wt.RepoName, Err: err})
+					continue
+				}
+				wt.MergeReason = reason
+			}
+			return nil // Never fail — warnings are non-fatal
+		})
+	}
+
+	_ = g.Wait()
+
+	var all []LoadWarning
+	for _, w := range warnings {
+		all = append(all, w...)
+	}
+	return all
+}
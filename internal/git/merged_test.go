@@ -0,0 +1,281 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// commitFile writes a file and commits it on the current branch.
+func commitFile(t *testing.T, repoPath, name, content, msg string) {
+	t.Helper()
+	ctx := context.Background()
+	if err := os.WriteFile(filepath.Join(repoPath, name), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if err := runGit(ctx, repoPath, "add", name); err != nil {
+		t.Fatalf("failed to add: %v", err)
+	}
+	if err := runGit(ctx, repoPath, "commit", "-m", msg); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+}
+
+// mustGit runs a git command and fails the test on error.
+func mustGit(t *testing.T, repoPath string, args ...string) {
+	t.Helper()
+	if err := runGit(context.Background(), repoPath, args...); err != nil {
+		t.Fatalf("git %v: %v", args, err)
+	}
+}
+
+// createFeatureBranch creates branch with two commits and switches back to main.
+func createFeatureBranch(t *testing.T, repoPath, branch string) {
+	t.Helper()
+	mustGit(t, repoPath, "checkout", "-b", branch)
+	commitFile(t, repoPath, branch+"-a.txt", "a\n", "first change")
+	commitFile(t, repoPath, branch+"-b.txt", "b\n", "second change")
+	mustGit(t, repoPath, "checkout", "main")
+}
+
+func TestLocalMergeReason(t *testing.T) {
+	t.Parallel()
+
+	const target = "refs/remotes/origin/main"
+
+	tests := []struct {
+		name  string
+		setup func(t *testing.T, repoPath string)
+		gone  bool
+		want  MergeReason
+	}{
+		{
+			name: "unmerged branch",
+			setup: func(t *testing.T, repoPath string) {
+				createFeatureBranch(t, repoPath, "feature")
+			},
+			want: "",
+		},
+		{
+			name: "fresh branch without commits is not merged",
+			setup: func(t *testing.T, repoPath string) {
+				mustGit(t, repoPath, "branch", "feature")
+			},
+			want: "",
+		},
+		{
+			name: "fresh branch behind target is not merged",
+			setup: func(t *testing.T, repoPath string) {
+				mustGit(t, repoPath, "branch", "feature")
+				commitFile(t, repoPath, "other.txt", "other\n", "change on main")
+				mustGit(t, repoPath, "push", "origin", "main")
+			},
+			want: "",
+		},
+		{
+			name: "commits discarded by reset are not merged",
+			setup: func(t *testing.T, repoPath string) {
+				createFeatureBranch(t, repoPath, "feature")
+				mustGit(t, repoPath, "branch", "-f", "feature", "origin/main")
+				mustGit(t, repoPath, "checkout", "feature")
+				commitFile(t, repoPath, "discarded.txt", "x\n", "discarded work")
+				mustGit(t, repoPath, "reset", "--hard", "origin/main")
+				mustGit(t, repoPath, "checkout", "main")
+			},
+			want: "",
+		},
+		{
+			name: "fast-forward with later reset to the merged tip",
+			setup: func(t *testing.T, repoPath string) {
+				createFeatureBranch(t, repoPath, "feature")
+				mustGit(t, repoPath, "merge", "--ff-only", "feature")
+				mustGit(t, repoPath, "push", "origin", "main")
+				mustGit(t, repoPath, "checkout", "feature")
+				mustGit(t, repoPath, "reset", "--hard", "HEAD~1")
+				mustGit(t, repoPath, "reset", "--hard", "origin/main")
+				mustGit(t, repoPath, "checkout", "main")
+			},
+			want: MergeReasonAncestor,
+		},
+		{
+			name: "fresh branch with upstream gone",
+			setup: func(t *testing.T, repoPath string) {
+				mustGit(t, repoPath, "branch", "feature")
+			},
+			gone: true,
+			want: MergeReasonUpstreamGone,
+		},
+		{
+			name: "merge commit",
+			setup: func(t *testing.T, repoPath string) {
+				createFeatureBranch(t, repoPath, "feature")
+				mustGit(t, repoPath, "merge", "--no-ff", "-m", "Merge feature", "feature")
+				mustGit(t, repoPath, "push", "origin", "main")
+			},
+			want: MergeReasonAncestor,
+		},
+		{
+			name: "fast-forward",
+			setup: func(t *testing.T, repoPath string) {
+				createFeatureBranch(t, repoPath, "feature")
+				mustGit(t, repoPath, "merge", "--ff-only", "feature")
+				mustGit(t, repoPath, "push", "origin", "main")
+			},
+			want: MergeReasonAncestor,
+		},
+		{
+			name: "squash merge",
+			setup: func(t *testing.T, repoPath string) {
+				createFeatureBranch(t, repoPath, "feature")
+				commitFile(t, repoPath, "other.txt", "other\n", "unrelated change on main")
+				mustGit(t, repoPath, "merge", "--squash", "feature")
+				mustGit(t, repoPath, "commit", "-m", "Squashed feature")
+				mustGit(t, repoPath, "push", "origin", "main")
+			},
+			want: MergeReasonSquash,
+		},
+		{
+			name: "rebase merge",
+			setup: func(t *testing.T, repoPath string) {
+				createFeatureBranch(t, repoPath, "feature")
+				commitFile(t, repoPath, "other.txt", "other\n", "unrelated change on main")
+				mustGit(t, repoPath, "cherry-pick", "main..feature")
+				mustGit(t, repoPath, "push", "origin", "main")
+			},
+			want: MergeReasonSquash,
+		},
+		{
+			name: "merged locally but not pushed",
+			setup: func(t *testing.T, repoPath string) {
+				createFeatureBranch(t, repoPath, "feature")
+				mustGit(t, repoPath, "merge", "--no-ff", "-m", "Merge feature", "feature")
+			},
+			want: "",
+		},
+		{
+			name: "upstream gone",
+			setup: func(t *testing.T, repoPath string) {
+				createFeatureBranch(t, repoPath, "feature")
+			},
+			gone: true,
+			want: MergeReasonUpstreamGone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			repoPath, _ := setupTestRepoWithOrigin(t)
+			tt.setup(t, repoPath)
+
+			got, err := LocalMergeReason(context.Background(), repoPath, "feature", target, tt.gone)
+			if err != nil {
+				t.Fatalf("LocalMergeReason() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("LocalMergeReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetGoneUpstreams(t *testing.T) {
+	t.Parallel()
+
+	repoPath, _ := setupTestRepoWithOrigin(t)
+
+	createFeatureBranch(t, repoPath, "deleted")
+	createFeatureBranch(t, repoPath, "kept")
+	mustGit(t, repoPath, "push", "-u", "origin", "deleted", "kept")
+	mustGit(t, repoPath, "push", "origin", "--delete", "deleted")
+	mustGit(t, repoPath, "fetch", "--prune")
+
+	gone := GetGoneUpstreams(context.Background(), repoPath)
+	if !gone["deleted"] {
+		t.Errorf("expected 'deleted' to have a gone upstream, got %v", gone)
+	}
+	if gone["kept"] || gone["main"] {
+		t.Errorf("expected only 'deleted' to be gone, got %v", gone)
+	}
+}
+
+func TestDetectLocalMerges(t *testing.T) {
+	t.Parallel()
+
+	repoPath, _ := setupTestRepoWithOrigin(t)
+	ctx := context.Background()
+
+	createFeatureBranch(t, repoPath, "merged")
+	createFeatureBranch(t, repoPath, "open")
+	mustGit(t, repoPath, "merge", "--no-ff", "-m", "Merge merged", "merged")
+	mustGit(t, repoPath, "push", "origin", "main")
+
+	worktrees := []Worktree{
+		{Path: repoPath, Branch: "main", RepoPath: repoPath},
+		{Path: "/wt/merged", Branch: "merged", RepoPath: repoPath},
+		{Path: "/wt/open", Branch: "open", RepoPath: repoPath},
+		{Path: "/wt/pr", Branch: "open", RepoPath: repoPath, MergeReason: MergeReasonPR},
+		{Path: "/wt/detached", Branch: "(detached)", RepoPath: repoPath},
+	}
+
+	if warnings := DetectLocalMerges(ctx, worktrees); len(warnings) > 0 {
+		t.Fatalf("unexpected warnings: %v", warnings)
+	}
+
+	want := []MergeReason{"", MergeReasonAncestor, "", MergeReasonPR, ""}
+	for i, wt := range worktrees {
+		if wt.MergeReason != want[i] {
+			t.Errorf("worktrees[%d] (%s) MergeReason = %q, want %q", i, wt.Path, wt.MergeReason, want[i])
+		}
+	}
+}
+
+// TestLocalMergeReason_BareRepo covers repos cloned bare, as wt does by
+// default, where git writes no reflog for branches.
+func TestLocalMergeReason_BareRepo(t *testing.T) {
+	t.Parallel()
+
+	workPath, originPath := setupTestRepoWithOrigin(t)
+	ctx := context.Background()
+	const target = "refs/remotes/origin/main"
+
+	createFeatureBranch(t, workPath, "merged")
+	createFeatureBranch(t, workPath, "open")
+	mustGit(t, workPath, "push", "origin", "merged", "open")
+	mustGit(t, workPath, "merge", "--no-ff", "-m", "Merge merged", "merged")
+	mustGit(t, workPath, "push", "origin", "main")
+
+	barePath := filepath.Join(resolveTempDir(t), "repo.git")
+	mustGit(t, "", "clone", "--bare", originPath, barePath)
+	configureTestRepo(t, barePath)
+	mustGit(t, barePath, "config", "remote.origin.fetch", "+refs/heads/*:refs/remotes/origin/*")
+	mustGit(t, barePath, "fetch", "origin")
+	mustGit(t, barePath, "branch", "fresh", target)
+	mustGit(t, barePath, "branch", "-f", "merged", "refs/remotes/origin/merged")
+
+	// Move origin/main past the fresh branch
+	commitFile(t, workPath, "other.txt", "other\n", "change on main")
+	mustGit(t, workPath, "push", "origin", "main")
+	mustGit(t, barePath, "fetch", "origin")
+
+	tests := []struct {
+		branch string
+		gone   bool
+		want   MergeReason
+	}{
+		{branch: "fresh", want: ""},
+		{branch: "fresh", gone: true, want: MergeReasonUpstreamGone},
+		{branch: "merged", want: MergeReasonAncestor},
+		{branch: "open", want: ""},
+	}
+	for _, tt := range tests {
+		got, err := LocalMergeReason(ctx, barePath, tt.branch, target, tt.gone)
+		if err != nil {
+			t.Fatalf("LocalMergeReason(%s) error = %v", tt.branch, err)
+		}
+		if got != tt.want {
+			t.Errorf("LocalMergeReason(%s, gone=%v) = %q, want %q", tt.branch, tt.gone, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package git
+
+import (
+	"context"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Branch config keys written by wt pr checkout and wt pr sync. Git drops
+// them together with the rest of the branch section when the branch is
+// deleted.
+const (
+	prConfigKey     = "wt-pr"      // PR a local branch was created for (branch.<name>.wt-pr)
+	prHeadConfigKey = "wt-pr-head" // PR head last fetched into the branch (branch.<name>.wt-pr-head)
+)
+
+// GetBranchPR returns the number of the PR a branch was checked out for
+// Returns 0 if the branch wasn't created for a PR
+func GetBranchPR(ctx context.Context, repoPath, branch string) (int, error) {
+	value, err := getBranchConfig(ctx, repoPath, branch, prConfigKey)
+	if err != nil || value == "" {
+		return 0, err
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, nil
+	}
+	return number, nil
+}
+
+// SetBranchPR records number as the PR a branch was checked out for
+func SetBranchPR(ctx context.Context, repoPath, branch string, number int) error {
+	return runGit(ctx, repoPath, "config", "branch."+branch+"."+prConfigKey, strconv.Itoa(number))
+}
+
+// GetBranchPRHead returns the PR head commit last fetched for a branch by
+// wt pr checkout or wt pr sync
+// Returns empty string if none was recorded
+func GetBranchPRHead(ctx context.Context, repoPath, branch string) (string, error) {
+	return getBranchConfig(ctx, repoPath, branch, prHeadConfigKey)
+}
+
+// SetBranchPRHead records commit as the PR head last fetched for a branch
+func SetBranchPRHead(ctx context.Context, repoPath, branch, commit string) error {
+	return runGit(ctx, repoPath, "config", "branch."+branch+"."+prHeadConfigKey, commit)
+}
+
+// getBranchConfig returns the value of branch.<branch>.<key>
+// Returns empty string if the key isn't set
+func getBranchConfig(ctx context.Context, repoPath, branch, key string) (string, error) {
+	output, err := outputGit(ctx, repoPath, "config", "branch."+branch+"."+key)
+	if err != nil {
+		// Exit code 1 means the config key doesn't exist - not an error
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
@@ -0,0 +1,44 @@
+package git
+
+import (
+	"context"
+	"testing"
+)
+
+func TestBranchPR(t *testing.T) {
+	t.Parallel()
+	repoPath := setupNotesTestRepo(t)
+	ctx := context.Background()
+
+	if number, err := GetBranchPR(ctx, repoPath, "feature"); err != nil || number != 0 {
+		t.Fatalf("GetBranchPR() before set = %d, %v; want 0", number, err)
+	}
+
+	if err := SetBranchPR(ctx, repoPath, "feature", 42); err != nil {
+		t.Fatalf("SetBranchPR failed: %v", err)
+	}
+	if number, err := GetBranchPR(ctx, repoPath, "feature"); err != nil || number != 42 {
+		t.Errorf("GetBranchPR() = %d, %v; want 42", number, err)
+	}
+	if number, err := GetBranchPR(ctx, repoPath, "main"); err != nil || number != 0 {
+		t.Errorf("GetBranchPR(main) = %d, %v; want 0", number, err)
+	}
+}
+
+func TestBranchPRHead(t *testing.T) {
+	t.Parallel()
+	repoPath := setupNotesTestRepo(t)
+	ctx := context.Background()
+
+	if head, err := GetBranchPRHead(ctx, repoPath, "feature"); err != nil || head != "" {
+		t.Fatalf("GetBranchPRHead() before set = %q, %v; want empty", head, err)
+	}
+
+	const sha = "0123456789abcdef0123456789abcdef01234567"
+	if err := SetBranchPRHead(ctx, repoPath, "feature", sha); err != nil {
+		t.Fatalf("SetBranchPRHead failed: %v", err)
+	}
+	if head, err := GetBranchPRHead(ctx, repoPath, "feature"); err != nil || head != sha {
+		t.Errorf("GetBranchPRHead() = %q, %v; want %s", head, err, sha)
+	}
+}
@@ -54,6 +54,32 @@ func GetCommitMeta(ctx context.Context, repoPath string, shas []string) (map[str
 	return metas, nil
 }
 
+// Commit is a commit message as listed by [ListCommits].
+type Commit struct {
+	SHA     string
+	Subject string
+	Body    string // message after the subject line, trimmed
+}
+
+// ListCommits returns the commits reachable from HEAD in path but not from
+// base (e.g. "origin/main"), oldest first.
+func ListCommits(ctx context.Context, path, base string) ([]Commit, error) {
+	output, err := outputGit(ctx, path, "log", "--reverse", "--format=%H%x00%s%x00%b%x1e", base+"..HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits since %s: %v", base, err)
+	}
+
+	var commits []Commit
+	for record := range strings.SplitSeq(string(output), "\x1e") {
+		fields := strings.SplitN(strings.TrimLeft(record, "\n"), "\x00", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		commits = append(commits, Commit{SHA: fields[0], Subject: fields[1], Body: strings.TrimSpace(fields[2])})
+	}
+	return commits, nil
+}
+
 // GetRepoDisplayName returns the folder name of the repository.
 func GetRepoDisplayName(repoPath string) string {
 	return filepath.Base(repoPath)
@@ -122,6 +148,19 @@ func FetchBranchFromRemote(ctx context.Context, repoPath, remote, branch string)
 	return nil
 }
 
+// FetchRef fetches ref (e.g. "refs/pull/1/head") from remote without storing
+// it and returns the fetched commit.
+func FetchRef(ctx context.Context, repoPath, remote, ref string) (string, error) {
+	if err := runGit(ctx, repoPath, "fetch", remote, ref, "--quiet"); err != nil {
+		return "", fmt.Errorf("failed to fetch %s from %s: %v", ref, remote, err)
+	}
+	out, err := outputGit(ctx, repoPath, "rev-parse", "FETCH_HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve fetched %s: %v", ref, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
 // ParseRemoteRef checks if ref has a valid remote prefix (e.g., "origin/main").
 // Returns the remote name, branch name, and whether it's a remote ref.
 func ParseRemoteRef(ctx context.Context, repoPath, ref string) (remote, branch string, isRemote bool) {
@@ -140,6 +179,14 @@ func HasRemote(ctx context.Context, repoPath, remoteName string) bool {
 	return runGit(ctx, repoPath, "remote", "get-url", remoteName) == nil
 }
 
+// AddRemote adds a remote named name with the given URL.
+func AddRemote(ctx context.Context, repoPath, name, url string) error {
+	if err := runGit(ctx, repoPath, "remote", "add", name, url); err != nil {
+		return fmt.Errorf("failed to add remote %s: %v", name, err)
+	}
+	return nil
+}
+
 // ListRemotes returns all remote names for a repository.
 func ListRemotes(ctx context.Context, repoPath string) ([]string, error) {
 	output, err := outputGit(ctx, repoPath, "remote")
@@ -194,6 +241,16 @@ func GetUpstreamBranch(ctx context.Context, repoPath, branch string) string {
 	return strings.TrimPrefix(ref, "refs/heads/")
 }
 
+// GetUpstreamRemote returns the remote a local branch tracks.
+// Returns empty string if no upstream is configured.
+func GetUpstreamRemote(ctx context.Context, repoPath, branch string) string {
+	output, err := outputGit(ctx, repoPath, "config", fmt.Sprintf("branch.%s.remote", branch))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
 // SetUpstreamBranch sets the upstream tracking branch for a local branch.
 // upstream should be "origin/<branch>" or just "<branch>" (will prepend origin/).
 func SetUpstreamBranch(ctx context.Context, repoPath, localBranch, upstream string) error {
@@ -203,6 +260,13 @@ func SetUpstreamBranch(ctx context.Context, repoPath, localBranch, upstream stri
 	return runGit(ctx, repoPath, "branch", "--set-upstream-to="+upstream, localBranch)
 }
 
+// SetUpstreamToRemote sets the upstream of localBranch to branch on remote,
+// which must have been fetched. Unlike [SetUpstreamBranch] the remote and
+// branch names may differ from origin and localBranch.
+func SetUpstreamToRemote(ctx context.Context, repoPath, localBranch, remote, branch string) error {
+	return runGit(ctx, repoPath, "branch", "--set-upstream-to="+remote+"/"+branch, localBranch)
+}
+
 // RefExists checks if a git ref resolves to a valid object.
 // Returns false for unborn HEAD (empty repos with no commits).
 // Works for any ref: HEAD, origin/main, refs/heads/branch, etc.
@@ -227,6 +291,21 @@ func PushBranch(ctx context.Context, repoPath, branch string) error {
 	return runGit(ctx, repoPath, "push", "-u", "origin", branch)
 }
 
+// PushBranchTo pushes the local branch to remoteBranch on remote and sets it
+// as upstream. If lease is set, a rewritten branch replaces the remote one as
+// long as that is still at commit lease (--force-with-lease).
+func PushBranchTo(ctx context.Context, repoPath, remote, branch, remoteBranch, lease string) error {
+	args := []string{"push", "--set-upstream", "--quiet"}
+	if lease != "" {
+		args = append(args, "--force-with-lease=refs/heads/"+remoteBranch+":"+lease)
+	}
+	args = append(args, remote, "refs/heads/"+branch+":refs/heads/"+remoteBranch)
+	if err := runGit(ctx, repoPath, args...); err != nil {
+		return fmt.Errorf("failed to push %s to %s: %v", branch, remote, err)
+	}
+	return nil
+}
+
 // GetCurrentRepoMainPathFrom returns the main repository path from the given path
 // Works whether you're in the main repo or a worktree
 // Returns empty string if not in a git repo
@@ -440,6 +519,67 @@ func DeleteLocalBranch(ctx context.Context, repoPath, branch string, force bool)
 	return nil
 }
 
+// CreateLocalBranch creates branch pointing at commit without checking it out.
+func CreateLocalBranch(ctx context.Context, repoPath, branch, commit string) error {
+	if err := runGit(ctx, repoPath, "branch", branch, commit); err != nil {
+		return fmt.Errorf("failed to create branch: %v", err)
+	}
+	return nil
+}
+
+// UpdateRef points ref (a full name like refs/wt-trash/<id>) at commit,
+// creating it if needed. A ref keeps the commit from being garbage collected.
+func UpdateRef(ctx context.Context, repoPath, ref, commit string) error {
+	if err := runGit(ctx, repoPath, "update-ref", ref, commit); err != nil {
+		return fmt.Errorf("failed to update %s: %v", ref, err)
+	}
+	return nil
+}
+
+// DeleteRef deletes ref. A missing ref is not an error.
+func DeleteRef(ctx context.Context, repoPath, ref string) error {
+	if err := runGit(ctx, repoPath, "update-ref", "-d", ref); err != nil {
+		return fmt.Errorf("failed to delete %s: %v", ref, err)
+	}
+	return nil
+}
+
+// ResolveCommit returns the full SHA of the commit rev points at.
+func ResolveCommit(ctx context.Context, repoPath, rev string) (string, error) {
+	out, err := outputGit(ctx, repoPath, "rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %v", rev, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// GetHeadCommit returns the full SHA of HEAD in the worktree at path.
+func GetHeadCommit(ctx context.Context, path string) (string, error) {
+	out, err := outputGit(ctx, path, "rev-parse", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// FastForward fast-forwards the branch checked out in the worktree at path
+// to commit. Fails if that isn't a fast-forward or would overwrite local changes.
+func FastForward(ctx context.Context, path, commit string) error {
+	if err := runGit(ctx, path, "merge", "--ff-only", "--quiet", commit); err != nil {
+		return fmt.Errorf("failed to fast-forward: %v", err)
+	}
+	return nil
+}
+
+// ResetHard resets the branch checked out in the worktree at path, its index
+// and working tree to commit.
+func ResetHard(ctx context.Context, path, commit string) error {
+	if err := runGit(ctx, path, "reset", "--hard", "--quiet", commit); err != nil {
+		return fmt.Errorf("failed to reset: %v", err)
+	}
+	return nil
+}
+
 // ListLocalBranches returns all local branch names for a repository.
 func ListLocalBranches(ctx context.Context, repoPath string) ([]string, error) {
 	output, err := outputGit(ctx, repoPath, "branch", "--format=%(refname:short)")
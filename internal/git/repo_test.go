@@ -359,8 +359,11 @@ func TestParseRemoteRef(t *testing.T) {
 	if err := runGit(ctx, repoPath, "remote", "add", "origin", "https://github.com/test/repo.git"); err != nil {
 		t.Fatalf("failed to add origin: %v", err)
 	}
-	if err := runGit(ctx, repoPath, "remote", "add", "upstream", "https://github.com/upstream/repo.git"); err != nil {
-		t.Fatalf("failed to add upstream: %v", err)
+	if err := AddRemote(ctx, repoPath, "upstream", "https://github.com/upstream/repo.git"); err != nil {
+		t.Fatalf("AddRemote failed: %v", err)
+	}
+	if err := AddRemote(ctx, repoPath, "upstream", "https://github.com/other/repo.git"); err == nil {
+		t.Error("AddRemote with an existing name should fail")
 	}
 
 	tests := []struct {
@@ -841,6 +844,9 @@ func TestUpstreamBranch(t *testing.T) {
 	if upstream != "feature-up" {
 		t.Errorf("GetUpstreamBranch = %q, want %q", upstream, "feature-up")
 	}
+	if remote := GetUpstreamRemote(ctx, repoPath, "feature-up"); remote != "origin" {
+		t.Errorf("GetUpstreamRemote = %q, want origin", remote)
+	}
 
 	// No upstream configured
 	if err := runGit(ctx, repoPath, "checkout", "-b", "no-upstream"); err != nil {
@@ -850,6 +856,9 @@ func TestUpstreamBranch(t *testing.T) {
 	if upstream != "" {
 		t.Errorf("GetUpstreamBranch for no-upstream = %q, want empty", upstream)
 	}
+	if remote := GetUpstreamRemote(ctx, repoPath, "no-upstream"); remote != "" {
+		t.Errorf("GetUpstreamRemote for no-upstream = %q, want empty", remote)
+	}
 
 	// Set upstream and verify
 	if err := SetUpstreamBranch(ctx, repoPath, "no-upstream", "feature-up"); err != nil {
@@ -859,6 +868,152 @@ func TestUpstreamBranch(t *testing.T) {
 	if upstream != "feature-up" {
 		t.Errorf("GetUpstreamBranch after set = %q, want %q", upstream, "feature-up")
 	}
+
+	// Track a branch of another remote under a different local name
+	originURL, err := GetOriginURL(ctx, repoPath)
+	if err != nil {
+		t.Fatalf("GetOriginURL failed: %v", err)
+	}
+	if err := AddRemote(ctx, repoPath, "fork", originURL); err != nil {
+		t.Fatalf("AddRemote failed: %v", err)
+	}
+	if err := FetchBranchFromRemote(ctx, repoPath, "fork", "feature-up"); err != nil {
+		t.Fatalf("FetchBranchFromRemote failed: %v", err)
+	}
+	if err := SetUpstreamToRemote(ctx, repoPath, "no-upstream", "fork", "feature-up"); err != nil {
+		t.Fatalf("SetUpstreamToRemote failed: %v", err)
+	}
+	if remote := GetUpstreamRemote(ctx, repoPath, "no-upstream"); remote != "fork" {
+		t.Errorf("GetUpstreamRemote after SetUpstreamToRemote = %q, want fork", remote)
+	}
+	if upstream := GetUpstreamBranch(ctx, repoPath, "no-upstream"); upstream != "feature-up" {
+		t.Errorf("GetUpstreamBranch after SetUpstreamToRemote = %q, want feature-up", upstream)
+	}
+}
+
+func TestFetchRefFastForwardAndReset(t *testing.T) {
+	t.Parallel()
+
+	repoPath, _ := setupTestRepoWithOrigin(t)
+	ctx := context.Background()
+
+	base, err := GetHeadCommit(ctx, repoPath)
+	if err != nil {
+		t.Fatalf("GetHeadCommit failed: %v", err)
+	}
+
+	// Publish a new commit under a PR-style ref that isn't a branch
+	if err := runGit(ctx, repoPath, "commit", "--allow-empty", "-m", "PR commit"); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+	prHead, _ := GetHeadCommit(ctx, repoPath)
+	if err := runGit(ctx, repoPath, "push", "origin", "HEAD:refs/pull/1/head"); err != nil {
+		t.Fatalf("failed to push: %v", err)
+	}
+	if err := ResetHard(ctx, repoPath, base); err != nil {
+		t.Fatalf("ResetHard failed: %v", err)
+	}
+	if head, _ := GetHeadCommit(ctx, repoPath); head != base {
+		t.Fatalf("HEAD after reset = %s, want %s", head, base)
+	}
+
+	got, err := FetchRef(ctx, repoPath, "origin", "refs/pull/1/head")
+	if err != nil {
+		t.Fatalf("FetchRef failed: %v", err)
+	}
+	if got != prHead {
+		t.Errorf("FetchRef = %s, want %s", got, prHead)
+	}
+
+	if err := FastForward(ctx, repoPath, got); err != nil {
+		t.Fatalf("FastForward failed: %v", err)
+	}
+	if head, _ := GetHeadCommit(ctx, repoPath); head != prHead {
+		t.Errorf("HEAD after fast-forward = %s, want %s", head, prHead)
+	}
+
+	if _, err := FetchRef(ctx, repoPath, "origin", "refs/pull/2/head"); err == nil {
+		t.Error("FetchRef of missing ref should fail")
+	}
+}
+
+func TestPushBranchTo(t *testing.T) {
+	t.Parallel()
+
+	repoPath, _ := setupTestRepoWithOrigin(t)
+	ctx := context.Background()
+
+	if err := runGit(ctx, repoPath, "checkout", "-b", "feature"); err != nil {
+		t.Fatalf("failed to create branch: %v", err)
+	}
+	if err := runGit(ctx, repoPath, "commit", "--allow-empty", "-m", "Feature"); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+
+	if err := PushBranchTo(ctx, repoPath, "origin", "feature", "feature", ""); err != nil {
+		t.Fatalf("PushBranchTo failed: %v", err)
+	}
+	pushed, err := GetHeadCommit(ctx, repoPath)
+	if err != nil {
+		t.Fatalf("GetHeadCommit failed: %v", err)
+	}
+	if remote, branch := GetUpstreamRemote(ctx, repoPath, "feature"), GetUpstreamBranch(ctx, repoPath, "feature"); remote != "origin" || branch != "feature" {
+		t.Errorf("upstream = %s/%s, want origin/feature", remote, branch)
+	}
+
+	// Rewrite the pushed commit
+	if err := runGit(ctx, repoPath, "commit", "--amend", "--allow-empty", "-m", "Feature v2"); err != nil {
+		t.Fatalf("failed to amend: %v", err)
+	}
+	if err := PushBranchTo(ctx, repoPath, "origin", "feature", "feature", ""); err == nil {
+		t.Error("PushBranchTo of rewritten branch should fail without lease")
+	}
+	if err := PushBranchTo(ctx, repoPath, "origin", "feature", "feature", "0000000000000000000000000000000000000000"); err == nil {
+		t.Error("PushBranchTo with stale lease should fail")
+	}
+	if err := PushBranchTo(ctx, repoPath, "origin", "feature", "feature", pushed); err != nil {
+		t.Fatalf("PushBranchTo with force-with-lease failed: %v", err)
+	}
+	if ahead, behind, err := GetAheadBehind(ctx, repoPath, "feature", "origin/feature"); err != nil || ahead != 0 || behind != 0 {
+		t.Errorf("GetAheadBehind after push = %d, %d, %v; want 0, 0", ahead, behind, err)
+	}
+}
+
+func TestListCommits(t *testing.T) {
+	t.Parallel()
+
+	repoPath, _ := setupTestRepoWithOrigin(t)
+	ctx := context.Background()
+
+	commits, err := ListCommits(ctx, repoPath, "origin/main")
+	if err != nil || len(commits) != 0 {
+		t.Fatalf("ListCommits() without new commits = %v, %v; want none", commits, err)
+	}
+
+	if err := runGit(ctx, repoPath, "commit", "--allow-empty", "-m", "Add login", "-m", "With OAuth.\n\nCloses #1."); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+	if err := runGit(ctx, repoPath, "commit", "--allow-empty", "-m", "Fix typo"); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+
+	commits, err = ListCommits(ctx, repoPath, "origin/main")
+	if err != nil {
+		t.Fatalf("ListCommits() error = %v", err)
+	}
+	if len(commits) != 2 {
+		t.Fatalf("ListCommits() returned %d commits, want 2", len(commits))
+	}
+	if commits[0].Subject != "Add login" || commits[0].Body != "With OAuth.\n\nCloses #1." {
+		t.Errorf("first commit = %+v, want oldest commit with body", commits[0])
+	}
+	if commits[1].Subject != "Fix typo" || commits[1].Body != "" || len(commits[1].SHA) != 40 {
+		t.Errorf("second commit = %+v", commits[1])
+	}
+
+	if _, err := ListCommits(ctx, repoPath, "origin/missing"); err == nil {
+		t.Error("ListCommits() with unknown base should fail")
+	}
 }
 
 func TestCloneRegular(t *testing.T) {
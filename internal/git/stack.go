@@ -0,0 +1,130 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Branch config keys of stacked branches. Git drops them together with the
+// rest of the branch section when the branch is deleted.
+const (
+	parentConfigKey     = "wt-parent"      // parent of a stacked branch (branch.<name>.wt-parent)
+	parentBaseConfigKey = "wt-parent-base" // tip of the parent before it was retargeted (branch.<name>.wt-parent-base)
+)
+
+// GetBranchParent returns the branch a stacked branch is built on
+// Returns empty string if the branch isn't stacked
+func GetBranchParent(ctx context.Context, repoPath, branch string) (string, error) {
+	output, err := outputGit(ctx, repoPath, "config", "branch."+branch+"."+parentConfigKey)
+	if err != nil {
+		// Exit code 1 means the config key doesn't exist - not an error
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// SetBranchParent records parent as the branch a stacked branch is built on
+func SetBranchParent(ctx context.Context, repoPath, branch, parent string) error {
+	return runGit(ctx, repoPath, "config", "branch."+branch+"."+parentConfigKey, parent)
+}
+
+// ClearBranchParent removes the parent of a stacked branch
+func ClearBranchParent(ctx context.Context, repoPath, branch string) error {
+	if err := runGit(ctx, repoPath, "config", "--unset", "branch."+branch+"."+parentConfigKey); err != nil {
+		// Exit code 5 means the key doesn't exist - not an error for clearing
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 5 {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// GetBranchParentBase returns the commit a stacked branch was built on
+// before its parent was retargeted, i.e. the tip of its merged parent
+// Returns empty string if the parent wasn't retargeted since the last rebase
+func GetBranchParentBase(ctx context.Context, repoPath, branch string) (string, error) {
+	output, err := outputGit(ctx, repoPath, "config", "branch."+branch+"."+parentBaseConfigKey)
+	if err != nil {
+		// Exit code 1 means the config key doesn't exist - not an error
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// SetBranchParentBase records commit as the tip of a stacked branch's
+// parent before it was retargeted
+func SetBranchParentBase(ctx context.Context, repoPath, branch, commit string) error {
+	return runGit(ctx, repoPath, "config", "branch."+branch+"."+parentBaseConfigKey, commit)
+}
+
+// ClearBranchParentBase removes the recorded parent tip of a stacked branch
+func ClearBranchParentBase(ctx context.Context, repoPath, branch string) error {
+	if err := runGit(ctx, repoPath, "config", "--unset", "branch."+branch+"."+parentBaseConfigKey); err != nil {
+		// Exit code 5 means the key doesn't exist - not an error for clearing
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 5 {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// GetBranchParents returns the parents of all stacked branches in the
+// repository (branch -> parent).
+// Uses: `git config --get-regexp 'branch\..*\.wt-parent$'`
+func GetBranchParents(ctx context.Context, repoPath string) (map[string]string, error) {
+	parents := make(map[string]string)
+
+	output, err := outputGit(ctx, repoPath, "config", "--get-regexp", `^branch\..*\.`+parentConfigKey+`$`)
+	if err != nil {
+		// Exit code 1 means no stacked branches - not an error
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return parents, nil
+		}
+		return nil, err
+	}
+
+	// Parse output lines like:
+	// branch.feature-b.wt-parent feature-a
+	for line := range strings.SplitSeq(string(output), "\n") {
+		key, parent, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok {
+			continue
+		}
+		branch := strings.TrimSuffix(strings.TrimPrefix(key, "branch."), "."+parentConfigKey)
+		if branch != "" && parent != "" {
+			parents[branch] = parent
+		}
+	}
+
+	return parents, nil
+}
+
+// RebaseOnto rebases the branch checked out at path onto upstream. With
+// oldBase, the commit the branch was built on (e.g. its parent's tip before
+// the parent was rebased or squash-merged), only the commits after oldBase
+// are replayed: `git rebase --onto <upstream> <oldBase>`. Without, commits
+// upstream had before it was rewritten (found via its reflog, see
+// `git merge-base --fork-point`) are not replayed. A conflicting rebase is
+// left in progress for the user to resolve.
+func RebaseOnto(ctx context.Context, path, upstream, oldBase string) error {
+	args := []string{"rebase", "--fork-point", upstream}
+	if oldBase != "" {
+		args = []string{"rebase", "--onto", upstream, oldBase}
+	}
+	if err := runGit(ctx, path, args...); err != nil {
+		return fmt.Errorf("rebase onto %s failed: %v", upstream, err)
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBranchParent(t *testing.T) {
+	t.Parallel()
+	repoPath := setupNotesTestRepo(t)
+	ctx := context.Background()
+
+	parent, err := GetBranchParent(ctx, repoPath, "feature")
+	if err != nil || parent != "" {
+		t.Fatalf("GetBranchParent() before set = %q, %v; want empty", parent, err)
+	}
+	parents, err := GetBranchParents(ctx, repoPath)
+	if err != nil || len(parents) != 0 {
+		t.Fatalf("GetBranchParents() without stacks = %v, %v; want empty", parents, err)
+	}
+
+	if err := SetBranchParent(ctx, repoPath, "feature", "main"); err != nil {
+		t.Fatalf("SetBranchParent failed: %v", err)
+	}
+	if err := SetBranchParent(ctx, repoPath, "feature/Child", "feature"); err != nil {
+		t.Fatalf("SetBranchParent failed: %v", err)
+	}
+	// Notes share the branch section and must not show up as parents
+	if err := SetBranchNote(ctx, repoPath, "feature", "WIP"); err != nil {
+		t.Fatalf("SetBranchNote failed: %v", err)
+	}
+
+	if parent, err := GetBranchParent(ctx, repoPath, "feature"); err != nil || parent != "main" {
+		t.Errorf("GetBranchParent() = %q, %v; want main", parent, err)
+	}
+	parents, err = GetBranchParents(ctx, repoPath)
+	if err != nil {
+		t.Fatalf("GetBranchParents failed: %v", err)
+	}
+	want := map[string]string{"feature": "main", "feature/Child": "feature"}
+	if !reflect.DeepEqual(parents, want) {
+		t.Errorf("GetBranchParents() = %v, want %v", parents, want)
+	}
+
+	if err := ClearBranchParent(ctx, repoPath, "feature"); err != nil {
+		t.Fatalf("ClearBranchParent failed: %v", err)
+	}
+	if err := ClearBranchParent(ctx, repoPath, "feature"); err != nil {
+		t.Fatalf("ClearBranchParent on unset parent should not fail: %v", err)
+	}
+	if parent, err := GetBranchParent(ctx, repoPath, "feature"); err != nil || parent != "" {
+		t.Errorf("GetBranchParent() after clear = %q, %v; want empty", parent, err)
+	}
+}
+
+func TestBranchParentBase(t *testing.T) {
+	t.Parallel()
+	repoPath := setupNotesTestRepo(t)
+	ctx := context.Background()
+
+	if base, err := GetBranchParentBase(ctx, repoPath, "feature"); err != nil || base != "" {
+		t.Fatalf("GetBranchParentBase() before set = %q, %v; want empty", base, err)
+	}
+
+	if err := SetBranchParent(ctx, repoPath, "feature", "main"); err != nil {
+		t.Fatalf("SetBranchParent failed: %v", err)
+	}
+	if err := SetBranchParentBase(ctx, repoPath, "feature", "abc123"); err != nil {
+		t.Fatalf("SetBranchParentBase failed: %v", err)
+	}
+	if base, err := GetBranchParentBase(ctx, repoPath, "feature"); err != nil || base != "abc123" {
+		t.Errorf("GetBranchParentBase() = %q, %v; want abc123", base, err)
+	}
+	// The parent base shares the wt-parent prefix and must not show up as a parent
+	parents, err := GetBranchParents(ctx, repoPath)
+	if err != nil {
+		t.Fatalf("GetBranchParents failed: %v", err)
+	}
+	if want := map[string]string{"feature": "main"}; !reflect.DeepEqual(parents, want) {
+		t.Errorf("GetBranchParents() = %v, want %v", parents, want)
+	}
+
+	if err := ClearBranchParentBase(ctx, repoPath, "feature"); err != nil {
+		t.Fatalf("ClearBranchParentBase failed: %v", err)
+	}
+	if err := ClearBranchParentBase(ctx, repoPath, "feature"); err != nil {
+		t.Fatalf("ClearBranchParentBase on unset base should not fail: %v", err)
+	}
+	if base, err := GetBranchParentBase(ctx, repoPath, "feature"); err != nil || base != "" {
+		t.Errorf("GetBranchParentBase() after clear = %q, %v; want empty", base, err)
+	}
+}
+
+func TestRebaseOnto(t *testing.T) {
+	t.Parallel()
+	repoPath := setupTestRepo(t)
+	ctx := context.Background()
+
+	commit := func(file, content, msg string, args ...string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repoPath, file), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := runGit(ctx, repoPath, "add", file); err != nil {
+			t.Fatalf("failed to add: %v", err)
+		}
+		if err := runGit(ctx, repoPath, append([]string{"commit", "-m", msg}, args...)...); err != nil {
+			t.Fatalf("failed to commit: %v", err)
+		}
+	}
+
+	// parent <- child, then rewrite the parent's commit
+	if err := runGit(ctx, repoPath, "checkout", "-b", "parent"); err != nil {
+		t.Fatal(err)
+	}
+	commit("parent.txt", "v1\n", "Parent")
+	if err := runGit(ctx, repoPath, "checkout", "-b", "child"); err != nil {
+		t.Fatal(err)
+	}
+	commit("child.txt", "child\n", "Child")
+	if err := runGit(ctx, repoPath, "checkout", "parent"); err != nil {
+		t.Fatal(err)
+	}
+	commit("parent.txt", "v2\n", "Parent v2", "--amend")
+	if err := runGit(ctx, repoPath, "checkout", "child"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RebaseOnto(ctx, repoPath, "parent", ""); err != nil {
+		t.Fatalf("RebaseOnto failed: %v", err)
+	}
+
+	out, err := outputGit(ctx, repoPath, "log", "--format=%s", "parent..child")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(string(out)); got != "Child" {
+		t.Errorf("commits on top of parent = %q, want only Child", got)
+	}
+	if ok, err := IsAncestor(ctx, repoPath, "parent", "child"); err != nil || !ok {
+		t.Errorf("parent should be an ancestor of child after rebase (err %v)", err)
+	}
+}
+
+func TestRebaseOnto_SquashMergedParent(t *testing.T) {
+	t.Parallel()
+	repoPath := setupTestRepo(t)
+	ctx := context.Background()
+
+	commit := func(file, content, msg string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repoPath, file), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := runGit(ctx, repoPath, "add", file); err != nil {
+			t.Fatalf("failed to add: %v", err)
+		}
+		if err := runGit(ctx, repoPath, "commit", "-m", msg); err != nil {
+			t.Fatalf("failed to commit: %v", err)
+		}
+	}
+
+	base, err := GetCurrentBranch(ctx, repoPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// parent (two commits) <- child, then squash-merge parent into base
+	if err := runGit(ctx, repoPath, "checkout", "-b", "parent"); err != nil {
+		t.Fatal(err)
+	}
+	commit("parent.txt", "v1\n", "Parent")
+	commit("parent.txt", "v2\n", "Parent v2")
+	if err := runGit(ctx, repoPath, "checkout", "-b", "child"); err != nil {
+		t.Fatal(err)
+	}
+	commit("child.txt", "child\n", "Child")
+	if err := runGit(ctx, repoPath, "checkout", base); err != nil {
+		t.Fatal(err)
+	}
+	if err := runGit(ctx, repoPath, "merge", "--squash", "parent"); err != nil {
+		t.Fatal(err)
+	}
+	if err := runGit(ctx, repoPath, "commit", "-m", "Squashed parent"); err != nil {
+		t.Fatal(err)
+	}
+	oldParent, err := ResolveCommit(ctx, repoPath, "parent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := runGit(ctx, repoPath, "checkout", "child"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Replaying the parent's first commit would conflict with the squash
+	if err := RebaseOnto(ctx, repoPath, base, oldParent); err != nil {
+		t.Fatalf("RebaseOnto failed: %v", err)
+	}
+
+	out, err := outputGit(ctx, repoPath, "log", "--format=%s", base+"..child")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(string(out)); got != "Child" {
+		t.Errorf("commits on top of %s = %q, want only Child", base, got)
+	}
+}
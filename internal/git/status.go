@@ -0,0 +1,314 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Operation names reported by GetInProgressOperation.
+const (
+	OperationRebase     = "rebase"
+	OperationMerge      = "merge"
+	OperationCherryPick = "cherry-pick"
+	OperationRevert     = "revert"
+)
+
+// StatusInfo is the parsed output of `git status --porcelain=v2 --branch`.
+type StatusInfo struct {
+	Head       string // branch name, or "(detached)"
+	Upstream   string // upstream ref (e.g. "origin/feature"), empty if none
+	Gone       bool   // upstream is configured but its ref no longer exists
+	Ahead      int    // commits ahead of upstream
+	Behind     int    // commits behind upstream
+	Staged     int    // entries with changes in the index
+	Unstaged   int    // tracked entries with changes in the working tree
+	Untracked  int    // untracked files
+	Conflicted int    // unmerged entries
+}
+
+// IsDirty reports whether the working tree has any uncommitted changes,
+// including untracked files.
+func (s StatusInfo) IsDirty() bool {
+	return s.Staged > 0 || s.Unstaged > 0 || s.Untracked > 0 || s.Conflicted > 0
+}
+
+// ParseStatusPorcelainV2 parses the output of `git status --porcelain=v2 --branch`.
+// Ignored entries ("!") and unknown header lines are skipped.
+func ParseStatusPorcelainV2(out []byte) StatusInfo {
+	var s StatusInfo
+	hasAB := false
+	for line := range strings.SplitSeq(string(out), "\n") {
+		if line == "" {
+			continue
+		}
+
+		if header, ok := strings.CutPrefix(line, "# "); ok {
+			key, value, _ := strings.Cut(header, " ")
+			switch key {
+			case "branch.head":
+				s.Head = value
+			case "branch.upstream":
+				s.Upstream = value
+			case "branch.ab":
+				// Format: "+<ahead> -<behind>"; omitted when the upstream ref is gone
+				hasAB = true
+				ahead, behind, _ := strings.Cut(value, " ")
+				s.Ahead, _ = strconv.Atoi(strings.TrimPrefix(ahead, "+"))
+				s.Behind, _ = strconv.Atoi(strings.TrimPrefix(behind, "-"))
+			}
+			continue
+		}
+
+		switch line[0] {
+		case '1', '2':
+			// "1 XY ..." (changed) or "2 XY ..." (renamed/copied);
+			// X is the index status, Y the working tree status, "." = unmodified
+			if len(line) < 4 {
+				continue
+			}
+			if line[2] != '.' {
+				s.Staged++
+			}
+			if line[3] != '.' {
+				s.Unstaged++
+			}
+		case 'u':
+			s.Conflicted++
+		case '?':
+			s.Untracked++
+		}
+	}
+	s.Gone = s.Upstream != "" && !hasAB
+	return s
+}
+
+// GetStatus runs `git status --porcelain=v2 --branch` in the worktree at path.
+func GetStatus(ctx context.Context, path string) (StatusInfo, error) {
+	out, err := outputGit(ctx, path, "status", "--porcelain=v2", "--branch")
+	if err != nil {
+		return StatusInfo{}, fmt.Errorf("git status: %w", err)
+	}
+	return ParseStatusPorcelainV2(out), nil
+}
+
+// GetInProgressOperation returns the operation (rebase, merge, cherry-pick, revert)
+// currently in progress in the worktree at path, or "" if there is none.
+func GetInProgressOperation(ctx context.Context, path string) (string, error) {
+	gitDir, err := GetAbsoluteGitDir(ctx, path)
+	if err != nil {
+		return "", err
+	}
+
+	markers := []struct {
+		name      string
+		operation string
+	}{
+		{"rebase-merge", OperationRebase},
+		{"rebase-apply", OperationRebase},
+		{"MERGE_HEAD", OperationMerge},
+		{"CHERRY_PICK_HEAD", OperationCherryPick},
+		{"REVERT_HEAD", OperationRevert},
+	}
+	for _, m := range markers {
+		if _, err := os.Stat(filepath.Join(gitDir, m.name)); err == nil {
+			return m.operation, nil
+		}
+	}
+	return "", nil
+}
+
+// GetAheadBehind returns how many commits ref has that base doesn't (ahead)
+// and vice versa (behind), using `git rev-list --left-right --count`.
+func GetAheadBehind(ctx context.Context, dir, ref, base string) (ahead, behind int, err error) {
+	out, err := outputGit(ctx, dir, "rev-list", "--left-right", "--count", ref+"..."+base)
+	if err != nil {
+		return 0, 0, err
+	}
+	fields := strings.Fields(string(out))
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("unexpected rev-list output: %q", out)
+	}
+	ahead, _ = strconv.Atoi(fields[0])
+	behind, _ = strconv.Atoi(fields[1])
+	return ahead, behind, nil
+}
+
+// CountUnpushedCommits returns the number of commits on HEAD that are not
+// reachable from any remote-tracking branch or from the local defaultBranch
+// (commits already on the default branch are not considered unpushed work).
+func CountUnpushedCommits(ctx context.Context, path, defaultBranch string) (int, error) {
+	args := []string{"rev-list", "--count", "HEAD", "--not", "--remotes"}
+	if defaultBranch != "" && RefExists(ctx, path, "refs/heads/"+defaultBranch) {
+		args = append(args, "refs/heads/"+defaultBranch)
+	}
+	out, err := outputGit(ctx, path, args...)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(out)))
+}
+
+// CountCommitsNotIn counts the commits on HEAD of the worktree at path that
+// are not reachable from any of bases.
+func CountCommitsNotIn(ctx context.Context, path string, bases ...string) (int, error) {
+	args := append([]string{"rev-list", "--count", "HEAD", "--not"}, bases...)
+	out, err := outputGit(ctx, path, args...)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(out)))
+}
+
+// GetStashCounts returns the number of stash entries per branch.
+// Stashes are shared by all worktrees of a repo; the branch is taken from the
+// stash subject ("WIP on <branch>: ..." or "On <branch>: ...").
+func GetStashCounts(ctx context.Context, repoPath string) (map[string]int, error) {
+	out, err := outputGit(ctx, repoPath, "stash", "list", "--format=%gs")
+	if err != nil {
+		return nil, err
+	}
+	return parseStashCounts(string(out)), nil
+}
+
+// parseStashCounts counts stash subjects per branch.
+func parseStashCounts(out string) map[string]int {
+	counts := make(map[string]int)
+	for line := range strings.SplitSeq(out, "\n") {
+		rest, ok := strings.CutPrefix(line, "WIP on ")
+		if !ok {
+			rest, ok = strings.CutPrefix(line, "On ")
+		}
+		if !ok {
+			continue
+		}
+		if branch, _, found := strings.Cut(rest, ": "); found {
+			counts[branch]++
+		}
+	}
+	return counts
+}
+
+// WorktreeStatus is a worktree together with the state of its working tree,
+// as shown by `wt status`.
+type WorktreeStatus struct {
+	Worktree
+
+	Dirty         bool   `json:"dirty"`
+	Staged        int    `json:"staged"`
+	Unstaged      int    `json:"unstaged"`
+	Untracked     int    `json:"untracked"`
+	Conflicted    int    `json:"conflicted"`
+	Upstream      string `json:"upstream,omitempty"`
+	UpstreamGone  bool   `json:"upstream_gone,omitempty"`
+	Ahead         int    `json:"ahead"`
+	Behind        int    `json:"behind"`
+	Unpushed      int    `json:"unpushed"` // commits not on the upstream, or on no remote/default branch if there is none
+	DefaultBranch string `json:"default_branch,omitempty"`
+	DefaultAhead  int    `json:"default_ahead"`  // commits not on origin/<default>
+	DefaultBehind int    `json:"default_behind"` // commits on origin/<default> missing from HEAD
+	Stashes       int    `json:"stashes"`
+	Operation     string `json:"operation,omitempty"` // in-progress rebase, merge, cherry-pick or revert
+}
+
+// LoadWorktreeStatuses collects the working tree status of each worktree in parallel.
+// Per repo: GetDefaultBranch + GetStashCounts. Per worktree: GetStatus +
+// GetInProgressOperation + GetAheadBehind against origin/<default>, plus
+// CountUnpushedCommits when the branch has no (existing) upstream.
+// Results keep the order of worktrees. Worktrees whose status cannot be read
+// are omitted and reported as warnings (non-fatal).
+func LoadWorktreeStatuses(ctx context.Context, worktrees []Worktree) ([]WorktreeStatus, []LoadWarning) {
+	type repoInfo struct {
+		defaultBranch string
+		stashes       map[string]int
+	}
+
+	// Per-repo data, shared by all worktrees of a repo
+	repos := make(map[string]*repoInfo)
+	for _, wt := range worktrees {
+		if _, ok := repos[wt.RepoPath]; !ok {
+			repos[wt.RepoPath] = &repoInfo{}
+		}
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(8) // Bound concurrent git operations
+
+	for repoPath, info := range repos {
+		g.Go(func() error {
+			info.defaultBranch = GetDefaultBranch(gctx, repoPath)
+			// Non-fatal: without stash info the counts are simply 0
+			info.stashes, _ = GetStashCounts(gctx, repoPath) //nolint:errcheck
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	type result struct {
+		status  WorktreeStatus
+		warning *LoadWarning
+	}
+	results := make([]result, len(worktrees))
+
+	g, gctx = errgroup.WithContext(ctx)
+	g.SetLimit(8)
+
+	for i, wt := range worktrees {
+		info := repos[wt.RepoPath]
+		g.Go(func() error {
+			st, err := GetStatus(gctx, wt.Path)
+			if err != nil {
+				results[i] = result{warning: &LoadWarning{RepoName: wt.RepoName, Err: fmt.Errorf("%s: %w", wt.Path, err)}}
+				return nil
+			}
+
+			ws := WorktreeStatus{
+				Worktree:      wt,
+				Dirty:         st.IsDirty(),
+				Staged:        st.Staged,
+				Unstaged:      st.Unstaged,
+				Untracked:     st.Untracked,
+				Conflicted:    st.Conflicted,
+				Upstream:      st.Upstream,
+				UpstreamGone:  st.Gone,
+				Ahead:         st.Ahead,
+				Behind:        st.Behind,
+				DefaultBranch: info.defaultBranch,
+				Stashes:       info.stashes[wt.Branch],
+			}
+
+			// Without a live upstream, count commits that exist on no remote
+			if st.Upstream == "" || st.Gone {
+				ws.Unpushed, _ = CountUnpushedCommits(gctx, wt.Path, info.defaultBranch) //nolint:errcheck // unborn HEAD
+			} else {
+				ws.Unpushed = st.Ahead
+			}
+
+			// Non-fatal: operation and default-branch comparison are best effort
+			ws.Operation, _ = GetInProgressOperation(gctx, wt.Path) //nolint:errcheck
+			if RefExists(gctx, wt.Path, "refs/remotes/origin/"+info.defaultBranch) {
+				ws.DefaultAhead, ws.DefaultBehind, _ = GetAheadBehind(gctx, wt.Path, "HEAD", "origin/"+info.defaultBranch) //nolint:errcheck
+			}
+
+			results[i] = result{status: ws}
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	var statuses []WorktreeStatus
+	var warnings []LoadWarning
+	for _, r := range results {
+		if r.warning != nil {
+			warnings = append(warnings, *r.warning)
+			continue
+		}
+		statuses = append(statuses, r.status)
+	}
+	return statuses, warnings
+}
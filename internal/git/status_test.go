@@ -0,0 +1,196 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseStatusPorcelainV2(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+		want  StatusInfo
+	}{
+		{
+			name:  "clean with upstream",
+			input: "# branch.oid 1234567890abcdef\n# branch.head main\n# branch.upstream origin/main\n# branch.ab +0 -0\n",
+			want:  StatusInfo{Head: "main", Upstream: "origin/main"},
+		},
+		{
+			name:  "no upstream",
+			input: "# branch.oid 1234567890abcdef\n# branch.head feature\n",
+			want:  StatusInfo{Head: "feature"},
+		},
+		{
+			name: "mixed changes",
+			input: "# branch.head feature\n# branch.upstream origin/feature\n# branch.ab +2 -3\n" +
+				"1 M. N... 100644 100644 100644 abc abc staged.go\n" +
+				"1 .M N... 100644 100644 100644 abc abc unstaged.go\n" +
+				"1 MM N... 100644 100644 100644 abc abc both.go\n" +
+				"2 R. N... 100644 100644 100644 abc abc R100 new.go\told.go\n" +
+				"u UU N... 100644 100644 100644 100644 abc abc abc conflict.go\n" +
+				"? untracked.txt\n" +
+				"? other dir/file.txt\n" +
+				"! ignored.log\n",
+			want: StatusInfo{Head: "feature", Upstream: "origin/feature", Ahead: 2, Behind: 3,
+				Staged: 3, Unstaged: 2, Untracked: 2, Conflicted: 1},
+		},
+		{
+			name:  "upstream gone",
+			input: "# branch.oid 1234567890abcdef\n# branch.head feature\n# branch.upstream origin/feature\n",
+			want:  StatusInfo{Head: "feature", Upstream: "origin/feature", Gone: true},
+		},
+		{
+			name:  "detached",
+			input: "# branch.oid 1234567890abcdef\n# branch.head (detached)\n",
+			want:  StatusInfo{Head: "(detached)"},
+		},
+		{
+			name:  "empty",
+			input: "",
+			want:  StatusInfo{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := ParseStatusPorcelainV2([]byte(tt.input)); got != tt.want {
+				t.Errorf("ParseStatusPorcelainV2() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatusInfo_IsDirty(t *testing.T) {
+	t.Parallel()
+
+	if (StatusInfo{Ahead: 3}).IsDirty() {
+		t.Error("unpushed commits alone should not be dirty")
+	}
+	if !(StatusInfo{Untracked: 1}).IsDirty() {
+		t.Error("untracked files should be dirty")
+	}
+}
+
+func TestParseStashCounts(t *testing.T) {
+	t.Parallel()
+
+	got := parseStashCounts("WIP on main: abc123 Initial commit\nOn feature: wt autostash\nOn feature: other\nWIP on (no branch): abc fix\nunknown\n")
+	want := map[string]int{"main": 1, "feature": 2, "(no branch)": 1}
+	if len(got) != len(want) {
+		t.Fatalf("parseStashCounts() = %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("parseStashCounts()[%q] = %d, want %d", k, got[k], v)
+		}
+	}
+}
+
+func TestGetInProgressOperation(t *testing.T) {
+	t.Parallel()
+
+	repoPath := setupTestRepo(t)
+	ctx := context.Background()
+
+	op, err := GetInProgressOperation(ctx, repoPath)
+	if err != nil {
+		t.Fatalf("GetInProgressOperation failed: %v", err)
+	}
+	if op != "" {
+		t.Errorf("expected no operation, got %q", op)
+	}
+
+	// Create a conflicting merge
+	mustGit(t, repoPath, "checkout", "-b", "other")
+	commitFile(t, repoPath, "README.md", "other\n", "other change")
+	mustGit(t, repoPath, "checkout", "main")
+	commitFile(t, repoPath, "README.md", "main\n", "main change")
+	if err := runGit(ctx, repoPath, "merge", "other"); err == nil {
+		t.Fatal("expected merge conflict")
+	}
+
+	op, err = GetInProgressOperation(ctx, repoPath)
+	if err != nil {
+		t.Fatalf("GetInProgressOperation failed: %v", err)
+	}
+	if op != OperationMerge {
+		t.Errorf("expected %q, got %q", OperationMerge, op)
+	}
+
+	st, err := GetStatus(ctx, repoPath)
+	if err != nil {
+		t.Fatalf("GetStatus failed: %v", err)
+	}
+	if st.Conflicted != 1 {
+		t.Errorf("expected 1 conflicted entry, got %+v", st)
+	}
+}
+
+func TestLoadWorktreeStatuses(t *testing.T) {
+	t.Parallel()
+
+	repoPath, _ := setupTestRepoWithOrigin(t)
+	ctx := context.Background()
+
+	// feature: one local commit, one staged file, one untracked file, one stash
+	wtPath := filepath.Join(filepath.Dir(repoPath), "feature-wt")
+	mustGit(t, repoPath, "worktree", "add", "-b", "feature", wtPath)
+	commitFile(t, wtPath, "feature.txt", "feature\n", "feature commit")
+	if err := os.WriteFile(filepath.Join(wtPath, "stashed.txt"), []byte("x\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	mustGit(t, wtPath, "stash", "push", "-u", "-m", "wip")
+	if err := os.WriteFile(filepath.Join(wtPath, "staged.txt"), []byte("x\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	mustGit(t, wtPath, "add", "staged.txt")
+	if err := os.WriteFile(filepath.Join(wtPath, "new.txt"), []byte("x\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	worktrees := []Worktree{
+		{Path: repoPath, Branch: "main", RepoName: "repo", RepoPath: repoPath},
+		{Path: wtPath, Branch: "feature", RepoName: "repo", RepoPath: repoPath},
+		{Path: filepath.Join(repoPath, "missing"), Branch: "gone", RepoName: "repo", RepoPath: repoPath},
+	}
+
+	statuses, warnings := LoadWorktreeStatuses(ctx, worktrees)
+	if len(warnings) != 1 {
+		t.Errorf("expected 1 warning for missing worktree, got %v", warnings)
+	}
+	if len(statuses) != 2 {
+		t.Fatalf("expected 2 statuses, got %d", len(statuses))
+	}
+
+	mainStatus := statuses[0]
+	if mainStatus.Dirty || mainStatus.Upstream != "origin/main" || mainStatus.Stashes != 0 || mainStatus.Unpushed != 0 {
+		t.Errorf("main: unexpected status %+v", mainStatus)
+	}
+
+	feature := statuses[1]
+	if feature.Branch != "feature" {
+		t.Fatalf("expected feature second, got %q", feature.Branch)
+	}
+	if !feature.Dirty || feature.Staged != 1 || feature.Untracked != 1 {
+		t.Errorf("feature: expected 1 staged and 1 untracked, got %+v", feature)
+	}
+	if feature.DefaultBranch != "main" || feature.DefaultAhead != 1 || feature.DefaultBehind != 0 {
+		t.Errorf("feature: expected 1 ahead of origin/main, got ahead=%d behind=%d (default %q)",
+			feature.DefaultAhead, feature.DefaultBehind, feature.DefaultBranch)
+	}
+	if feature.Unpushed != 1 {
+		t.Errorf("feature: expected 1 unpushed commit, got %d", feature.Unpushed)
+	}
+	if feature.Stashes != 1 {
+		t.Errorf("feature: expected 1 stash, got %d", feature.Stashes)
+	}
+	if feature.Upstream != "" {
+		t.Errorf("feature: expected no upstream, got %q", feature.Upstream)
+	}
+}
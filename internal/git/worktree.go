@@ -2,6 +2,7 @@ package git
 
 import (
 	"context"
+	"strings"
 	"time"
 )
 
@@ -12,20 +13,22 @@ const zeroHash = "0000000000000000000000000000000000000000"
 // Used as the unified struct across all commands (list, prune, cd, exec).
 // Fields tagged json:"-" are internal and excluded from user-facing JSON output.
 type Worktree struct {
-	Path        string    `json:"path"`
-	Branch      string    `json:"branch"`
-	CommitHash  string    `json:"commit"`
-	CommitAge   string    `json:"commit_age,omitempty"`
-	RepoName    string    `json:"repo"`
-	RepoPath    string    `json:"-"`
-	OriginURL   string    `json:"-"`
-	Note        string    `json:"note,omitempty"`
-	HasUpstream bool      `json:"-"`
-	CommitDate  time.Time `json:"commit_date"`
-	PRNumber    int       `json:"pr_number,omitempty"`
-	PRState     string    `json:"pr_state,omitempty"`
-	PRURL       string    `json:"pr_url,omitempty"`
-	PRDraft     bool      `json:"pr_draft,omitempty"`
+	Path        string      `json:"path"`
+	Branch      string      `json:"branch"`
+	CommitHash  string      `json:"commit"`
+	CommitAge   string      `json:"commit_age,omitempty"`
+	RepoName    string      `json:"repo"`
+	RepoPath    string      `json:"-"`
+	OriginURL   string      `json:"-"`
+	Note        string      `json:"note,omitempty"`
+	HasUpstream bool        `json:"-"`
+	CommitDate  time.Time   `json:"commit_date"`
+	PRNumber    int         `json:"pr_number,omitempty"`
+	PRState     string      `json:"pr_state,omitempty"`
+	PRURL       string      `json:"pr_url,omitempty"`
+	PRDraft     bool        `json:"pr_draft,omitempty"`
+	PRChecks    string      `json:"pr_checks,omitempty"`
+	MergeReason MergeReason `json:"merge_reason,omitempty"`
 }
 
 // CreateWorktreeResult contains the result of creating a worktree
@@ -75,3 +78,30 @@ func CreateWorktreeNewBranch(ctx context.Context, gitDir, wtPath, branch, baseRe
 func CreateWorktreeOrphan(ctx context.Context, gitDir, wtPath, branch string) error {
 	return runGit(ctx, gitDir, "worktree", "add", "--orphan", "-b", branch, wtPath)
 }
+
+// AttachWorktree registers wtPath as a worktree of branch without checking out
+// any files, for re-attaching an existing working tree (e.g. restored from trash).
+// With an empty branch, HEAD is detached at commit.
+// The index is left empty; call [ResetIndex] or restore a saved index afterwards.
+func AttachWorktree(ctx context.Context, repoPath, wtPath, branch, commit string) error {
+	if branch == "" {
+		return runGit(ctx, repoPath, "worktree", "add", "--no-checkout", "--detach", wtPath, commit)
+	}
+	return runGit(ctx, repoPath, "worktree", "add", "--no-checkout", wtPath, branch)
+}
+
+// ResetIndex resets the index of the worktree at path to HEAD
+// without touching the working tree (`git reset --mixed`).
+func ResetIndex(ctx context.Context, path string) error {
+	return runGit(ctx, path, "reset", "--quiet", "--mixed")
+}
+
+// GetAbsoluteGitDir returns the git directory of the worktree at path
+// (for linked worktrees: <repo>/.git/worktrees/<name>).
+func GetAbsoluteGitDir(ctx context.Context, path string) (string, error) {
+	out, err := outputGit(ctx, path, "rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
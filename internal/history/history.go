@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/raphi011/wt/internal/fs"
+	"github.com/raphi011/wt/internal/migrate"
 )
 
 // maxEntries is the maximum number of history entries kept.
@@ -29,6 +30,7 @@ type Entry struct {
 
 // History stores worktree access entries.
 type History struct {
+	Version int     `json:"version"` // schema version, see Migrate
 	Entries []Entry `json:"entries"`
 }
 
@@ -39,10 +41,14 @@ func DefaultPath() string {
 }
 
 // Load reads the history from disk at the given path.
-// Returns empty history if the file doesn't exist. Unrecognized formats
-// (e.g. the old {"most_recent": "..."} schema) decode into an empty
-// History struct because unknown JSON keys are silently dropped.
+// Returns empty history if the file doesn't exist. Files with an older
+// schema version (e.g. the old {"most_recent": "..."} format) are
+// migrated first.
 func Load(path string) (*History, error) {
+	if _, err := migrate.Upgrade(path, SchemaKind); err != nil {
+		return nil, err
+	}
+
 	var h History
 	if err := fs.LoadJSON(path, &h); err != nil {
 		if os.IsNotExist(err) {
@@ -55,7 +61,23 @@ func Load(path string) (*History, error) {
 
 // Save writes the history to disk atomically at the given path.
 func (h *History) Save(path string) error {
-	return fs.SaveJSON(path, h)
+	h.Version = migrate.Latest(SchemaKind)
+	return fs.WithLock(path, func() error { return fs.SaveJSON(path, h) })
+}
+
+// Transact performs a locked read-modify-write of the history at path:
+// it reloads the history under the lock, applies fn and saves the result,
+// so concurrent wt processes don't drop each other's entries.
+func Transact(path string, fn func(*History) error) error {
+	if _, err := migrate.Upgrade(path, SchemaKind); err != nil {
+		return err
+	}
+
+	var h History
+	return fs.UpdateJSON(path, &h, func() error {
+		h.Version = migrate.Latest(SchemaKind)
+		return fn(&h)
+	})
 }
 
 // FindByPath returns the entry matching the given path, or nil if not found.
@@ -121,36 +143,36 @@ func RecordAccess(path, repoName, branch, historyPath string) error {
 	// Store canonical path so future lookups match regardless of symlink form.
 	path = fs.ResolvePath(path)
 
-	h, err := Load(historyPath)
-	if err != nil {
-		return fmt.Errorf("load history: %w", err)
-	}
+	err := Transact(historyPath, func(h *History) error {
+		now := time.Now()
 
-	now := time.Now()
-
-	if entry := h.FindByPath(path); entry != nil {
-		entry.AccessCount++
-		entry.LastAccess = now
-		// Update repo/branch in case they changed
-		entry.RepoName = repoName
-		entry.Branch = branch
-	} else {
-		h.Entries = append(h.Entries, Entry{
-			Path:        path,
-			RepoName:    repoName,
-			Branch:      branch,
-			AccessCount: 1,
-			LastAccess:  now,
-		})
-	}
+		if entry := h.FindByPath(path); entry != nil {
+			entry.AccessCount++
+			entry.LastAccess = now
+			// Update repo/branch in case they changed
+			entry.RepoName = repoName
+			entry.Branch = branch
+		} else {
+			h.Entries = append(h.Entries, Entry{
+				Path:        path,
+				RepoName:    repoName,
+				Branch:      branch,
+				AccessCount: 1,
+				LastAccess:  now,
+			})
+		}
 
-	// Evict oldest entries if over cap
-	if len(h.Entries) > maxEntries {
-		h.SortByRecency()
-		h.Entries = h.Entries[:maxEntries]
+		// Evict oldest entries if over cap
+		if len(h.Entries) > maxEntries {
+			h.SortByRecency()
+			h.Entries = h.Entries[:maxEntries]
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("record history: %w", err)
 	}
-
-	return h.Save(historyPath)
+	return nil
 }
 
 // GetMostRecent returns the path of the most recently accessed worktree.
@@ -1,10 +1,14 @@
 package history
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/raphi011/wt/internal/migrate"
 )
 
 func TestRecordAccess(t *testing.T) {
@@ -141,6 +145,42 @@ func TestRecordAccess_MaxCap(t *testing.T) {
 	}
 }
 
+func TestRecordAccess_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	historyFile := filepath.Join(tmpDir, "history.json")
+
+	const workers = 20
+	var wg sync.WaitGroup
+	for i := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Half the workers hit the same path, the rest distinct paths
+			path := "/path/to/shared"
+			if i%2 == 1 {
+				path = fmt.Sprintf("/path/to/wt%d", i)
+			}
+			if err := RecordAccess(path, "myrepo", "main", historyFile); err != nil {
+				t.Errorf("RecordAccess failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	h, err := Load(historyFile)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(h.Entries) != 1+workers/2 {
+		t.Fatalf("expected %d entries, got %d", 1+workers/2, len(h.Entries))
+	}
+	if e := h.FindByPath("/path/to/shared"); e == nil || e.AccessCount != workers/2 {
+		t.Errorf("shared entry = %+v, want AccessCount %d", e, workers/2)
+	}
+}
+
 func TestGetMostRecent(t *testing.T) {
 	t.Parallel()
 
@@ -384,9 +424,25 @@ func TestLoad_OldFormat(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Load failed: %v", err)
 	}
-	// Old format should result in empty entries (Go ignores unknown fields)
-	if len(h.Entries) != 0 {
-		t.Errorf("expected 0 entries for old format, got %d", len(h.Entries))
+	// Old format is migrated: the most recent path becomes an entry
+	if len(h.Entries) != 1 || h.Entries[0].Path != "/foo" || h.Entries[0].AccessCount != 1 {
+		t.Errorf("expected migrated entry for /foo, got %+v", h.Entries)
+	}
+	if h.Version != migrate.Latest(SchemaKind) {
+		t.Errorf("Version = %d, want %d", h.Version, migrate.Latest(SchemaKind))
+	}
+
+	// The original file is kept as a backup
+	backups, _ := filepath.Glob(historyFile + ".v0-*.bak")
+	if len(backups) != 1 {
+		t.Fatalf("expected 1 backup, got %v", backups)
+	}
+	data, err := os.ReadFile(backups[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"most_recent":"/foo"}` {
+		t.Errorf("backup = %s, want original contents", data)
 	}
 }
 
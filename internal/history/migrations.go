@@ -0,0 +1,42 @@
+package history
+
+import (
+	"time"
+
+	"github.com/raphi011/wt/internal/migrate"
+)
+
+// SchemaKind identifies history.json in the migration registry (see wt doctor).
+const SchemaKind = "history"
+
+func init() {
+	// v0 → v1: files written before versioning. The original format
+	// {"most_recent": "<path>"} is converted into a single entry so the
+	// last visited worktree survives the upgrade.
+	migrate.Register(migrate.Migration{
+		Kind:        SchemaKind,
+		From:        0,
+		Description: "add schema version, convert most_recent to entries",
+		Up: func(doc map[string]any) error {
+			recent, _ := doc["most_recent"].(string)
+			delete(doc, "most_recent")
+			if doc["entries"] == nil {
+				doc["entries"] = []any{}
+			}
+			if entries, ok := doc["entries"].([]any); ok && recent != "" && len(entries) == 0 {
+				doc["entries"] = []any{map[string]any{
+					"path":         recent,
+					"access_count": 1,
+					"last_access":  time.Now().Format(time.RFC3339),
+				}}
+			}
+			return nil
+		},
+	})
+}
+
+// Migrate upgrades the history at path to the current schema version,
+// keeping a backup of the old file.
+func Migrate(path string) (migrate.Result, error) {
+	return migrate.Upgrade(path, SchemaKind)
+}
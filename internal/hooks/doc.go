@@ -1,11 +1,18 @@
 // Package hooks provides hook execution with placeholder substitution.
 //
 // Hooks are shell commands defined in config that run before or after wt operations
-// like checkout, pr checkout, prune, or merge. They enable workflow automation such as
+// like checkout, prune, pr create/merge, repo add/clone, note and label changes,
+// or cd. They enable workflow automation such as
 // opening editors, installing dependencies, or sending notifications.
 //
 // # Hook Selection
 //
+// The "on" field has the form [before:|after:]trigger[:subtype], with the
+// triggers checkout (create, open, pr), prune, pr (create, merge), repo (add,
+// clone), note (set, clear), label (add, remove, clear), cd and all. "merge"
+// is an alias of "pr:merge". Hooks for repo come from the global config only,
+// so adding or cloning a repo never runs its own code.
+//
 // Hooks can run automatically or manually:
 //
 //   - Automatic: Hooks with "on" config matching the command type run automatically
@@ -21,22 +28,56 @@
 //	command = "echo 'Done with {branch}'"
 //	# no "on" - only runs via --hook=cleanup
 //
+// # Hook Scripts
+//
+// Executable files in ~/.wt/hooks/<trigger>.d/ and <repo>/.wt/hooks/<trigger>.d/
+// are hooks too, named "<trigger>/<file>" and matched like on = ["<trigger>"]
+// (see [config.LoadHookScripts]). A script runs without a shell and gets the
+// WT_ environment variables plus the hook context as a JSON object on stdin
+// (hook, worktree_dir, repo_dir, branch, repo, trigger, action, phase,
+// config_dir, pr_number, pr_repo, pr_url, note, label, args). A hooks entry with the script's name
+// and no command configures it, e.g. enabled = false or a timeout:
+//
+//	[hooks."checkout/10-setup.sh"]
+//	timeout = "5m"
+//
 // # Execution Order
 //
-// Hooks run in alphabetical order by name. Use naming prefixes to control ordering
-// (e.g., "01-install", "02-lint", "99-editor").
+// Matched hooks form a dependency graph: a hook with after = ["install"] starts
+// once the install hook has finished, if install runs too. Hooks that are ready
+// start in alphabetical order by name. Hooks with parallel = true run
+// concurrently with other parallel hooks, their output lines prefixed with the
+// hook name; other hooks run on their own with the terminal attached. Hooks
+// after a failed hook are skipped. Cycles are rejected by config validation.
+//
+//	[hooks.install]
+//	command = "npm install"
+//	on = ["checkout"]
+//	parallel = true
+//
+//	[hooks.editor]
+//	command = "code {worktree-dir}"
+//	on = ["checkout"]
+//	after = ["install"]
 //
 // # Placeholder Substitution
 //
-// Static placeholders available in all hooks:
+// Static placeholders:
 //
 //   - {worktree-dir}: Absolute worktree path
 //   - {repo-dir}: Absolute main repo path
 //   - {branch}: Branch name
 //   - {repo}: Registered repo name (as shown in wt repo list)
-//   - {trigger}: Command that triggered the hook (checkout, prune, merge, run)
-//   - {action}: Checkout subtype: create, open, pr, or manual (for wt hook)
+//   - {trigger}: Command that triggered the hook (checkout, prune, pr, repo,
+//     note, label, cd, run)
+//   - {action}: Trigger subtype, e.g. create, open, pr, merge, or manual (for wt hook)
 //   - {phase}: Hook timing: before or after
+//   - {pr-number}, {pr-repo}, {pr-url}: PR number, forge repo path and URL
+//   - {note}: New branch note (note:set)
+//   - {label}: Label added or removed (label:add, label:remove)
+//
+// Config validation rejects placeholders that have no value for any of the
+// hook's triggers, e.g. {branch} in a repo:clone hook.
 //
 // Custom variables via --arg key=value or --arg key (bare boolean):
 //
@@ -44,6 +85,16 @@
 //   - {key:-default}: Value with fallback if not provided
 //   - {key:+text}: Expands to text if key is set and non-empty, otherwise empty
 //
+// Values are substituted as raw text. Append :q to any placeholder ({branch:q},
+// {prompt:q}) to insert it quoted for a POSIX shell. Hooks also get every
+// value as an environment variable: WT_<NAME> for static placeholders
+// (WT_WORKTREE_DIR, WT_BRANCH, WT_PR_NUMBER, ...) and WT_ARG_<KEY> for --arg
+// variables. A hook with argv instead of command runs the program directly,
+// without a shell, substituting placeholders in each argument:
+//
+//	[hooks.claude]
+//	argv = ["claude", "-p", "{prompt}"]
+//
 // # Execution Context
 //
 // Hooks run with the working directory set to:
@@ -51,14 +102,36 @@
 //   - Repo root for pr checkout hooks (both before and after)
 //   - Worktree path for before:prune hooks (worktree still exists)
 //   - Main repo path for after:prune hooks (worktree is deleted)
-//   - Repo root for merge hooks (both before and after)
+//   - Repo root for pr merge hooks (both before and after)
+//   - Current directory for pr create hooks
+//   - Repo root for repo, note and label hooks; the current directory for
+//     before:repo:clone (the repo doesn't exist yet)
+//   - Worktree path for cd hooks, whose output goes to stderr
 //
-// Before hooks abort the current item on failure. For checkout and merge, this
+// Before hooks abort the current item on failure. For checkout and pr, this
 // stops the command. For prune batches, the individual worktree is skipped but
 // remaining worktrees are still processed. After hook failures are logged
 // but don't stop batch operations ([RunForEach]).
 // Use [RunSingle] for individual hook execution where errors are returned to the caller.
 //
+// # Failures, Timeouts and Retries
+//
+// on_failure overrides the phase default: "abort" stops the remaining hooks and
+// fails the operation (for after hooks, the operation has already happened, so
+// only the command's result and, for prune, the remaining worktrees are
+// affected), "warn" logs a warning and "ignore" continues as if the hook
+// succeeded. A hook is killed, with its child processes, when it runs longer
+// than timeout or wt is interrupted (Ctrl-C). Failed hooks are retried up to
+// retries times, interrupted ones aren't. Failures are returned as [HookError]
+// with the exit code and duration.
+//
+//	[hooks.install]
+//	command = "npm ci"
+//	on = ["before:checkout"]
+//	timeout = "5m"
+//	retries = 2
+//	on_failure = "warn"
+//
 // # Stdin Support
 //
 // Use --arg key=- to read stdin content into a variable:
@@ -0,0 +1,215 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+
+	"github.com/raphi011/wt/internal/log"
+)
+
+// hookState is the progress of a hook within a run of several hooks.
+type hookState int
+
+const (
+	hookPending hookState = iota
+	hookRunning
+	hookDone
+	hookFailed
+	hookSkipped
+)
+
+// errDependencyCycle is reported for hooks that can't run because their
+// "after" references form a cycle (only possible when global and local
+// hooks are merged; each config file is validated on load).
+var errDependencyCycle = errors.New("skipped because of a hook dependency cycle")
+
+// runGraph runs matches as a dependency graph: a hook starts once all hooks
+// it runs "after" have finished, if they are part of matches. Hooks that are
+// ready start in the order of matches. Parallel hooks run concurrently with
+// their output prefixed by the hook name; other hooks run on their own with
+// the terminal attached.
+//
+// What happens when a hook fails (or is skipped because a hook it runs after
+// didn't succeed) depends on its failure policy (see failurePolicy): "warn"
+// calls onError and skips the hooks running after it, "ignore" treats the
+// hook as successful, and "abort" calls onError with abort set and starts no
+// more hooks. Returns the first error of an aborting hook. Interrupted hooks
+// (goCtx canceled) always abort. onError is called from the calling goroutine.
+func runGraph(goCtx context.Context, matches []HookMatch, ctx Context, workDir string, onError func(name string, err error, abort bool)) error {
+	l := log.FromContext(goCtx)
+
+	index := make(map[string]int, len(matches))
+	for i, m := range matches {
+		index[m.Name] = i
+	}
+	deps := make([][]int, len(matches))
+	for i, m := range matches {
+		for _, name := range m.Hook.After {
+			if j, ok := index[name]; ok && j != i {
+				deps[i] = append(deps[i], j)
+			}
+		}
+	}
+
+	state := make([]hookState, len(matches))
+	var abortErr error
+	stopped := func() bool {
+		return abortErr != nil || goCtx.Err() != nil
+	}
+	fail := func(i int, s hookState, err error) {
+		name := matches[i].Name
+		switch policy := failurePolicy(matches[i].Hook, ctx.Phase); {
+		case policy == OnFailureAbort || goCtx.Err() != nil:
+			state[i] = s
+			if abortErr == nil {
+				abortErr = err
+			}
+			onError(name, err, true)
+		case policy == OnFailureIgnore && s == hookFailed:
+			state[i] = hookDone
+			l.Debug("hook failed (ignored)", "name", name, "error", err)
+		case policy == OnFailureIgnore:
+			state[i] = s
+			l.Debug("hook skipped (ignored)", "name", name, "error", err)
+		default:
+			state[i] = s
+			onError(name, err, false)
+		}
+	}
+	finish := func(i int, err error) {
+		if err != nil {
+			fail(i, hookFailed, err)
+			return
+		}
+		state[i] = hookDone
+		l.Debug("hook completed", "name", matches[i].Name)
+	}
+
+	type result struct {
+		i   int
+		err error
+	}
+	results := make(chan result)
+	running := 0
+
+	for {
+		progress := false
+	scan:
+		for i, m := range matches {
+			if stopped() {
+				break
+			}
+			if state[i] != hookPending {
+				continue
+			}
+
+			ready := true
+			for _, j := range deps[i] {
+				switch state[j] {
+				case hookFailed, hookSkipped:
+					fail(i, hookSkipped, fmt.Errorf("skipped because hook %q didn't succeed", matches[j].Name))
+					progress = true
+					continue scan
+				case hookPending, hookRunning:
+					ready = false
+				}
+			}
+			if !ready {
+				continue
+			}
+
+			if !m.Hook.Parallel && running > 0 {
+				break // wait for the parallel hooks to finish first
+			}
+			cmd, run := prepareHook(goCtx, m.Name, m.Hook, ctx)
+			progress = true
+			if !run {
+				finish(i, nil)
+				continue
+			}
+			if !m.Hook.Parallel {
+				finish(i, execHook(goCtx, m.Name, m.Hook, cmd, ctx.Phase, workDir, ""))
+				break // rescan: hooks waiting for this one may come first
+			}
+			state[i] = hookRunning
+			running++
+			go func() {
+				results <- result{i, execHook(goCtx, m.Name, m.Hook, cmd, ctx.Phase, workDir, "["+m.Name+"] ")}
+			}()
+		}
+
+		if running > 0 {
+			r := <-results
+			running--
+			finish(r.i, r.err)
+			continue
+		}
+		if !progress {
+			break
+		}
+	}
+
+	if !stopped() {
+		for i := range matches {
+			if state[i] == hookPending {
+				fail(i, hookSkipped, errDependencyCycle)
+			}
+		}
+	}
+	if abortErr == nil && goCtx.Err() != nil && slices.Contains(state, hookPending) {
+		return goCtx.Err()
+	}
+	return abortErr
+}
+
+// outputMu serializes the lines written by hooks running in parallel.
+var outputMu sync.Mutex
+
+// prefixWriter writes complete lines to w, each prefixed with prefix.
+// Call Flush after the last write to emit an unterminated last line.
+type prefixWriter struct {
+	w      io.Writer
+	prefix string
+	buf    []byte
+}
+
+func newPrefixWriter(w io.Writer, prefix string) *prefixWriter {
+	return &prefixWriter{w: w, prefix: prefix}
+}
+
+func (p *prefixWriter) Write(b []byte) (int, error) {
+	p.buf = append(p.buf, b...)
+	for {
+		i := bytes.IndexByte(p.buf, '\n')
+		if i < 0 {
+			break
+		}
+		if err := p.writeLine(p.buf[:i+1]); err != nil {
+			return 0, err
+		}
+		p.buf = p.buf[i+1:]
+	}
+	return len(b), nil
+}
+
+// Flush writes a pending unterminated line.
+func (p *prefixWriter) Flush() error {
+	if len(p.buf) == 0 {
+		return nil
+	}
+	line := append(p.buf, '\n')
+	p.buf = nil
+	return p.writeLine(line)
+}
+
+func (p *prefixWriter) writeLine(line []byte) error {
+	outputMu.Lock()
+	defer outputMu.Unlock()
+	_, err := p.w.Write(append([]byte(p.prefix), line...))
+	return err
+}
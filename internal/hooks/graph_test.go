@@ -0,0 +1,221 @@
+package hooks
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/raphi011/wt/internal/config"
+)
+
+// graphMatches builds matches in the given order from name/hook pairs.
+func graphMatches(hooks map[string]config.Hook, order ...string) []HookMatch {
+	matches := make([]HookMatch, len(order))
+	for i, name := range order {
+		hook := hooks[name]
+		matches[i] = HookMatch{Hook: &hook, Name: name}
+	}
+	return matches
+}
+
+// readOrder returns the lines hooks appended to the file "order" in dir.
+func readOrder(t *testing.T, dir string) []string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, "order"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Fields(string(data))
+}
+
+func TestRunGraph_DependencyOrder(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	dir := t.TempDir()
+	matches := graphMatches(map[string]config.Hook{
+		"a-editor":  {Command: "echo editor >> order", After: []string{"b-build"}},
+		"b-build":   {Command: "echo build >> order", After: []string{"c-install", "not-matched"}},
+		"c-install": {Command: "echo install >> order"},
+		"d-notify":  {Command: "echo notify >> order"},
+	}, "a-editor", "b-build", "c-install", "d-notify")
+
+	err := runGraph(logCtx(&buf), matches, Context{}, dir, func(name string, err error, abort bool) {
+		t.Errorf("hook %q failed: %v", name, err)
+	})
+	if err != nil {
+		t.Fatalf("runGraph() error = %v", err)
+	}
+
+	// Hooks without dependencies keep the order of matches
+	want := []string{"install", "build", "editor", "notify"}
+	if got := readOrder(t, dir); !reflect.DeepEqual(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+}
+
+func TestRunGraph_ParallelHooksRunConcurrently(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	dir := t.TempDir()
+	// Each hook waits for the other's marker file, so they only both
+	// succeed if they run at the same time
+	wait := func(self, other string) string {
+		return "touch " + self + "; for i in $(seq 1 500); do [ -f " + other + " ] && exit 0; sleep 0.01; done; exit 1"
+	}
+	matches := graphMatches(map[string]config.Hook{
+		"deps":   {Command: wait("deps", "lint"), Parallel: true},
+		"lint":   {Command: wait("lint", "deps"), Parallel: true},
+		"editor": {Command: "echo editor >> order", After: []string{"deps", "lint"}},
+	}, "deps", "editor", "lint")
+
+	var failed []string
+	err := runGraph(logCtx(&buf), matches, Context{}, dir, func(name string, err error, abort bool) {
+		failed = append(failed, name)
+	})
+	if err != nil || failed != nil {
+		t.Fatalf("runGraph() error = %v, failed = %v", err, failed)
+	}
+	if got := readOrder(t, dir); !reflect.DeepEqual(got, []string{"editor"}) {
+		t.Errorf("order = %v, want [editor]", got)
+	}
+}
+
+func TestRunGraph_SkipsDependentsOfFailedHook(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	dir := t.TempDir()
+	matches := graphMatches(map[string]config.Hook{
+		"install": {Command: "exit 3"},
+		"build":   {Command: "echo build >> order", After: []string{"install"}},
+		"test":    {Command: "echo test >> order", After: []string{"build"}},
+		"notify":  {Command: "echo notify >> order"},
+	}, "build", "install", "notify", "test")
+
+	errs := make(map[string]string)
+	err := runGraph(logCtx(&buf), matches, Context{Phase: PhaseAfter}, dir, func(name string, err error, abort bool) {
+		errs[name] = err.Error()
+	})
+	if err != nil {
+		t.Errorf("runGraph() error = %v, want nil for warn failures", err)
+	}
+
+	want := map[string]string{
+		"install": "command failed (exit 3): exit 3",
+		"build":   `skipped because hook "install" didn't succeed`,
+		"test":    `skipped because hook "build" didn't succeed`,
+	}
+	if !reflect.DeepEqual(errs, want) {
+		t.Errorf("reported errors = %v, want %v", errs, want)
+	}
+	if got := readOrder(t, dir); !reflect.DeepEqual(got, []string{"notify"}) {
+		t.Errorf("order = %v, want [notify]", got)
+	}
+}
+
+func TestRunGraph_AbortStopsRemainingHooks(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	dir := t.TempDir()
+	matches := graphMatches(map[string]config.Hook{
+		"guard": {Command: "exit 1", OnFailure: OnFailureAbort},
+		"later": {Command: "echo later >> order"},
+	}, "guard", "later")
+
+	var failed []string
+	err := runGraph(logCtx(&buf), matches, Context{Phase: PhaseAfter}, dir, func(name string, err error, abort bool) {
+		if !abort {
+			t.Errorf("hook %q: abort = false, want true", name)
+		}
+		failed = append(failed, name)
+	})
+	if err == nil {
+		t.Fatal("runGraph() error = nil, want guard failure")
+	}
+	if !reflect.DeepEqual(failed, []string{"guard"}) {
+		t.Errorf("failed = %v, want [guard]", failed)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "order")); !os.IsNotExist(err) {
+		t.Error("hook after the failure should not have run")
+	}
+}
+
+func TestRunGraph_Cycle(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	matches := graphMatches(map[string]config.Hook{
+		"a": {Command: "true", After: []string{"b"}},
+		"b": {Command: "true", After: []string{"a"}},
+		"c": {Command: "true"},
+	}, "a", "b", "c")
+
+	var failed []string
+	err := runGraph(logCtx(&buf), matches, Context{Phase: PhaseBefore}, t.TempDir(), func(name string, err error, abort bool) {
+		failed = append(failed, name)
+	})
+	if !errors.Is(err, errDependencyCycle) {
+		t.Errorf("runGraph() error = %v, want dependency cycle", err)
+	}
+	if !reflect.DeepEqual(failed, []string{"a", "b"}) {
+		t.Errorf("failed = %v, want [a b]", failed)
+	}
+}
+
+func TestRunGraph_FailurePolicies(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	dir := t.TempDir()
+	matches := graphMatches(map[string]config.Hook{
+		"cache":   {Command: "exit 2", OnFailure: OnFailureIgnore},
+		"install": {Command: "echo install >> order", After: []string{"cache"}},
+		"lint":    {Command: "exit 1", OnFailure: OnFailureWarn},
+		"check":   {Command: "echo check >> order"},
+	}, "cache", "install", "lint", "check")
+
+	var warned []string
+	err := runGraph(logCtx(&buf), matches, Context{Phase: PhaseBefore}, dir, func(name string, err error, abort bool) {
+		if abort {
+			t.Errorf("hook %q: abort = true, want false", name)
+		}
+		warned = append(warned, name)
+	})
+	if err != nil {
+		t.Fatalf("runGraph() error = %v, want nil", err)
+	}
+	// The ignored failure counts as success, so install still runs
+	if !reflect.DeepEqual(warned, []string{"lint"}) {
+		t.Errorf("warned = %v, want [lint]", warned)
+	}
+	if got := readOrder(t, dir); !reflect.DeepEqual(got, []string{"install", "check"}) {
+		t.Errorf("order = %v, want [install check]", got)
+	}
+}
+
+func TestPrefixWriter(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	w := newPrefixWriter(&buf, "[npm] ")
+	for _, chunk := range []string{"added 3 ", "packages\nfound 0 vuln", "erabilities\n", "done"} {
+		if _, err := w.Write([]byte(chunk)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "[npm] added 3 packages\n[npm] found 0 vulnerabilities\n[npm] done\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
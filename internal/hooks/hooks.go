@@ -1,15 +1,20 @@
 package hooks
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"os"
 	"os/exec"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/mattn/go-isatty"
 	"github.com/raphi011/wt/internal/config"
@@ -23,7 +28,11 @@ type CommandType string
 const (
 	CommandCheckout CommandType = "checkout"
 	CommandPrune    CommandType = "prune"
-	CommandMerge    CommandType = "merge"
+	CommandPR       CommandType = "pr"
+	CommandRepo     CommandType = "repo"
+	CommandNote     CommandType = "note"
+	CommandLabel    CommandType = "label"
+	CommandCd       CommandType = "cd"
 	CommandRun      CommandType = "run"
 )
 
@@ -36,11 +45,17 @@ const (
 	PhaseAfter  PhaseType = "after"
 )
 
-// Action constants for checkout subtypes and manual invocation.
+// Action constants for trigger subtypes and manual invocation.
 const (
-	ActionCreate = "create"
-	ActionOpen   = "open"
-	ActionPR     = "pr"
+	ActionCreate = "create" // checkout, pr
+	ActionOpen   = "open"   // checkout
+	ActionPR     = "pr"     // checkout
+	ActionMerge  = "merge"  // pr
+	ActionAdd    = "add"    // repo, label
+	ActionClone  = "clone"  // repo
+	ActionSet    = "set"    // note
+	ActionClear  = "clear"  // note, label
+	ActionRemove = "remove" // label
 	ActionManual = "manual"
 )
 
@@ -50,12 +65,15 @@ type Context struct {
 	RepoDir     string            // absolute main repo path
 	Branch      string            // branch name
 	Repo        string            // registered repo name (as shown in wt repo list)
-	Trigger     string            // command that triggered the hook (checkout, prune, merge, run)
-	Action      string            // checkout subtype: create, open, pr, manual (for wt hook)
+	Trigger     string            // command that triggered the hook (checkout, prune, pr, repo, note, label, cd, run)
+	Action      string            // trigger subtype, e.g. create, open, pr, merge; manual (for wt hook)
 	Phase       PhaseType         // PhaseBefore or PhaseAfter
 	ConfigDir   string            // absolute path to ~/.wt/ config directory
 	PRNumber    *int              // PR/MR number (nil for non-PR checkouts)
 	PRRepo      string            // forge repo path, e.g. owner/repo (empty for non-PR checkouts)
+	PRURL       string            // PR/MR web URL (pr hooks)
+	Note        string            // new branch note (note hooks, empty when cleared)
+	Label       string            // label added or removed (label hooks)
 	Env         map[string]string // custom variables from --arg key=value flags
 	DryRun      bool              // if true, print command instead of executing
 }
@@ -71,7 +89,7 @@ type HookMatch struct {
 // kept as a separate type to avoid an import cycle between config and hooks.
 type HookSelector struct {
 	Command CommandType
-	Action  string    // checkout subtype: "create", "open", "pr"; empty for prune/merge
+	Action  string    // trigger subtype, e.g. "create", "open", "pr"; empty for prune and cd
 	Phase   PhaseType // PhaseBefore or PhaseAfter
 }
 
@@ -141,8 +159,27 @@ func hookMatchesCommand(hook config.Hook, cmdType CommandType, subtype, phase st
 	return false
 }
 
+// Failure policies of a hook (config.Hook.OnFailure).
+const (
+	OnFailureWarn   = "warn"   // log a warning and continue
+	OnFailureAbort  = "abort"  // stop running hooks and fail the operation
+	OnFailureIgnore = "ignore" // continue as if the hook succeeded
+)
+
+// failurePolicy returns the hook's on_failure policy. Defaults to abort for
+// before-phase hooks and warn for after-phase hooks.
+func failurePolicy(hook *config.Hook, phase PhaseType) string {
+	if hook.OnFailure != "" {
+		return hook.OnFailure
+	}
+	if phase == PhaseBefore {
+		return OnFailureAbort
+	}
+	return OnFailureWarn
+}
+
 // RunAllNonFatal runs all matched hooks, logging failures as warnings instead of returning errors.
-// Hooks run in alphabetical order by name (determined by SelectHooks).
+// Hooks run in dependency order (see runGraph), otherwise in the order of matches.
 // Prints "No hooks matched" if matches is empty.
 func RunAllNonFatal(goCtx context.Context, matches []HookMatch, ctx Context, workDir string) {
 	l := log.FromContext(goCtx)
@@ -152,52 +189,153 @@ func RunAllNonFatal(goCtx context.Context, matches []HookMatch, ctx Context, wor
 		return
 	}
 
-	for _, match := range matches {
-		if err := runHook(goCtx, match.Name, match.Hook, ctx, workDir); err != nil {
-			l.Printf("Warning: hook %q failed: %v\n", match.Name, err)
-		}
-	}
+	runGraph(goCtx, matches, ctx, workDir, func(name string, err error, abort bool) {
+		l.Printf("Warning: hook %q failed: %v\n", name, err)
+	})
 }
 
 // RunForEach runs all matched hooks for a single item (e.g., one worktree in a batch).
-// Hooks run in alphabetical order by name (determined by SelectHooks).
+// Hooks run in dependency order (see runGraph), otherwise in the order of matches.
 // Logs failures as warnings with branch context. Does NOT print "no hooks matched".
-func RunForEach(goCtx context.Context, matches []HookMatch, ctx Context, workDir string) {
+// Returns an error only if a hook with on_failure = "abort" failed.
+func RunForEach(goCtx context.Context, matches []HookMatch, ctx Context, workDir string) error {
 	l := log.FromContext(goCtx)
 
-	for _, match := range matches {
-		if err := runHook(goCtx, match.Name, match.Hook, ctx, workDir); err != nil {
-			l.Printf("Warning: hook %q failed for %s: %v\n", match.Name, ctx.Branch, err)
+	return runGraph(goCtx, matches, ctx, workDir, func(name string, err error, abort bool) {
+		if abort {
+			l.Printf("Hook %q failed for %s — aborting\n", name, ctx.Branch)
+			return
 		}
-	}
+		l.Printf("Warning: hook %q failed for %s: %v\n", name, ctx.Branch, err)
+	})
 }
 
-// RunBeforeHooks runs all matched hooks for a before phase, aborting on first failure.
+// RunBeforeHooks runs all matched hooks for a before phase, aborting on the
+// first failure of a hook whose on_failure isn't "warn" or "ignore".
+// Parallel hooks that are already running are waited for before returning.
 func RunBeforeHooks(goCtx context.Context, matches []HookMatch, ctx Context, workDir string) error {
 	l := log.FromContext(goCtx)
-	for _, match := range matches {
-		if err := runHook(goCtx, match.Name, match.Hook, ctx, workDir); err != nil {
-			l.Printf("Hook %q failed — aborting operation for %s\n", match.Name, ctx.Branch)
-			return err
+	ctx.Phase = PhaseBefore
+	return runGraph(goCtx, matches, ctx, workDir, func(name string, err error, abort bool) {
+		if abort {
+			l.Printf("Hook %q failed — aborting operation for %s\n", name, ctx.Branch)
+			return
 		}
-	}
-	return nil
+		l.Printf("Warning: hook %q failed for %s: %v\n", name, ctx.Branch, err)
+	})
 }
 
 // RunSingle runs a single hook by name with the given context.
-// Used by `wt hook` to execute a specific hook manually.
+// Used by `wt hook` to execute a specific hook manually. The hook's timeout
+// and retries apply; its on_failure doesn't, failures are always returned.
 func RunSingle(goCtx context.Context, name string, hook *config.Hook, ctx Context) error {
 	return runHook(goCtx, name, hook, ctx, ctx.WorktreeDir)
 }
 
 // runHook executes a single hook with variable substitution.
 func runHook(goCtx context.Context, name string, hook *config.Hook, ctx Context, workDir string) error {
+	cmd, run := prepareHook(goCtx, name, hook, ctx)
+	if !run {
+		return nil
+	}
+	if err := execHook(goCtx, name, hook, cmd, ctx.Phase, workDir, ""); err != nil {
+		return err
+	}
+	log.FromContext(goCtx).Debug("hook completed", "name", name)
+	return nil
+}
+
+// hookCommand is a hook's command after placeholder substitution.
+type hookCommand struct {
+	line  string   // shell command line (command hooks)
+	argv  []string // program and arguments run without a shell (argv and script hooks)
+	env   []string // WT_ variables added to the environment
+	input []byte   // stdin of script hooks (see scriptInput), instead of the terminal
+}
+
+// scriptInput is the hook context passed to hook scripts as JSON on stdin.
+type scriptInput struct {
+	Hook        string            `json:"hook"`
+	WorktreeDir string            `json:"worktree_dir"`
+	RepoDir     string            `json:"repo_dir"`
+	Branch      string            `json:"branch"`
+	Repo        string            `json:"repo"`
+	Trigger     string            `json:"trigger"`
+	Action      string            `json:"action"`
+	Phase       PhaseType         `json:"phase"`
+	ConfigDir   string            `json:"config_dir"`
+	PRNumber    *int              `json:"pr_number"`
+	PRRepo      string            `json:"pr_repo"`
+	PRURL       string            `json:"pr_url"`
+	Note        string            `json:"note"`
+	Label       string            `json:"label"`
+	Args        map[string]string `json:"args"`
+}
+
+// String returns the command for messages: the shell command line, or the
+// arguments, quoted where needed.
+func (c hookCommand) String() string {
+	if c.argv == nil {
+		return c.line
+	}
+	words := make([]string, len(c.argv))
+	for i, arg := range c.argv {
+		words[i] = arg
+		if arg == "" || strings.ContainsFunc(arg, func(r rune) bool {
+			return !strings.ContainsRune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_-./:=@%+,", r)
+		}) {
+			words[i] = shellQuote(arg)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// newScriptInput returns the JSON passed on stdin to the hook script name.
+func newScriptInput(name string, ctx Context) []byte {
+	args := ctx.Env
+	if args == nil {
+		args = map[string]string{}
+	}
+	// Can't fail: the input only has strings, ints and a string map
+	data, _ := json.Marshal(scriptInput{
+		Hook:        name,
+		WorktreeDir: ctx.WorktreeDir,
+		RepoDir:     ctx.RepoDir,
+		Branch:      ctx.Branch,
+		Repo:        ctx.Repo,
+		Trigger:     ctx.Trigger,
+		Action:      ctx.Action,
+		Phase:       ctx.Phase,
+		ConfigDir:   ctx.ConfigDir,
+		PRNumber:    ctx.PRNumber,
+		PRRepo:      ctx.PRRepo,
+		PRURL:       ctx.PRURL,
+		Note:        ctx.Note,
+		Label:       ctx.Label,
+		Args:        args,
+	})
+	return data
+}
+
+// prepareHook substitutes the hook's placeholders and announces it. Returns
+// false if the hook must not be executed (dry run).
+func prepareHook(goCtx context.Context, name string, hook *config.Hook, ctx Context) (hookCommand, bool) {
 	l := log.FromContext(goCtx)
-	cmd := SubstitutePlaceholders(hook.Command, ctx)
+	cmd := hookCommand{env: hookEnv(ctx)}
+	if hook.Script != "" {
+		cmd.argv, cmd.input = []string{hook.Script}, newScriptInput(name, ctx)
+	} else if len(hook.Argv) > 0 {
+		cmd.argv = make([]string, len(hook.Argv))
+		for i, arg := range hook.Argv {
+			cmd.argv[i] = SubstitutePlaceholders(arg, ctx)
+		}
+	} else {
+		cmd.line = SubstitutePlaceholders(hook.Command, ctx)
+	}
 
 	if ctx.DryRun {
 		l.Printf("[dry-run] %s: %s\n", name, cmd)
-		return nil
+		return cmd, false
 	}
 
 	desc := hook.Description
@@ -205,25 +343,167 @@ func runHook(goCtx context.Context, name string, hook *config.Hook, ctx Context,
 		desc = name
 	}
 	l.Printf("%s\n", styles.PrimaryStyle.Render(fmt.Sprintf("Running %s...", desc)))
+	return cmd, true
+}
 
-	shell, args := shellCommand(cmd)
-	shellCmd := exec.Command(shell, args...)
-	shellCmd.Dir = workDir
-	shellCmd.Stdin = os.Stdin
-	shellCmd.Stdout = os.Stdout
-	shellCmd.Stderr = os.Stderr
-
-	if err := shellCmd.Run(); err != nil {
-		exitCode := 1
-		var exitErr *exec.ExitError
-		if errors.As(err, &exitErr) {
-			exitCode = exitErr.ExitCode()
+// HookError describes a failed hook command. Callers can get it from the
+// errors returned by the Run functions with errors.As.
+type HookError struct {
+	Command     string
+	ExitCode    int           // -1 if the command was killed or couldn't start
+	Duration    time.Duration // total run time of all attempts
+	Attempts    int
+	Timeout     time.Duration // set if the last attempt timed out
+	Interrupted bool          // the run was canceled, e.g. by Ctrl-C
+	Err         error         // set if the command couldn't be started
+}
+
+func (e *HookError) Error() string {
+	var msg string
+	switch {
+	case e.Interrupted:
+		msg = "command interrupted: " + e.Command
+	case e.Timeout > 0:
+		msg = fmt.Sprintf("command timed out after %s: %s", e.Timeout, e.Command)
+	case e.Err != nil:
+		msg = fmt.Sprintf("command failed (%v): %s", e.Err, e.Command)
+	default:
+		msg = fmt.Sprintf("command failed (exit %d): %s", e.ExitCode, e.Command)
+	}
+	if e.Attempts > 1 {
+		msg += fmt.Sprintf(" (%d attempts)", e.Attempts)
+	}
+	return msg
+}
+
+func (e *HookError) Unwrap() error {
+	return e.Err
+}
+
+// killWaitDelay bounds how long a killed hook may keep its output open.
+const killWaitDelay = 5 * time.Second
+
+type stdoutKey struct{}
+
+// WithStdout returns a context whose hooks write their standard output to w
+// instead of os.Stdout, for commands whose output is read by the shell (wt cd).
+func WithStdout(ctx context.Context, w io.Writer) context.Context {
+	return context.WithValue(ctx, stdoutKey{}, w)
+}
+
+// stdoutFromContext returns the hook output set with WithStdout, or os.Stdout.
+func stdoutFromContext(ctx context.Context) io.Writer {
+	if w, ok := ctx.Value(stdoutKey{}).(io.Writer); ok {
+		return w
+	}
+	return os.Stdout
+}
+
+// execHook runs a prepared hook command in workDir and records the result.
+// Failed attempts are retried up to hook.Retries times. An attempt is
+// killed, including its child processes, when it exceeds the hook's timeout
+// or goCtx is canceled (wt cancels it on Ctrl-C); interrupted hooks aren't
+// retried.
+//
+// With an empty prefix the command is attached to the terminal; otherwise
+// it gets no stdin and each line of its output is prefixed (for hooks
+// running in parallel). Scripts get their input as stdin instead.
+// Safe to call concurrently.
+func execHook(goCtx context.Context, name string, hook *config.Hook, cmd hookCommand, phase PhaseType, workDir, prefix string) error {
+	var (
+		stdin          io.Reader
+		stdout, stderr io.Writer = stdoutFromContext(goCtx), os.Stderr
+	)
+	if prefix == "" {
+		if cmd.input == nil {
+			stdin = os.Stdin
 		}
-		return fmt.Errorf("command failed (exit %d): %s", exitCode, cmd)
+	} else {
+		pout, perr := newPrefixWriter(stdout, prefix), newPrefixWriter(os.Stderr, prefix)
+		defer pout.Flush()
+		defer perr.Flush()
+		stdout, stderr = pout, perr
 	}
+	terminal := stdin != nil && (isatty.IsTerminal(os.Stdin.Fd()) || isatty.IsCygwinTerminal(os.Stdin.Fd()))
 
-	l.Debug("hook completed", "name", name)
-	return nil
+	start := time.Now()
+	var herr *HookError
+	for attempt := 1; ; attempt++ {
+		if cmd.input != nil {
+			stdin = bytes.NewReader(cmd.input)
+		}
+		herr = runAttempt(goCtx, hook.TimeoutDuration(), cmd, workDir, terminal, stdin, stdout, stderr)
+		if herr == nil {
+			break
+		}
+		herr.Attempts = attempt
+		if attempt > hook.Retries || herr.Interrupted {
+			break
+		}
+		// Parallel hooks must not use the logger, it isn't synchronized
+		if prefix == "" {
+			log.FromContext(goCtx).Printf("Hook %q failed, retrying (%d/%d): %v\n", name, attempt, hook.Retries, herr)
+		} else {
+			fmt.Fprintf(stderr, "failed, retrying (%d/%d): %v\n", attempt, hook.Retries, herr)
+		}
+	}
+
+	res := Result{Name: name, Phase: phase, Duration: time.Since(start)}
+	if herr == nil {
+		recorderFromContext(goCtx).record(res)
+		return nil
+	}
+	herr.Duration = res.Duration
+	res.Err, res.ExitCode = herr, herr.ExitCode
+	recorderFromContext(goCtx).record(res)
+	return herr
+}
+
+// runAttempt runs cmd once, killing it after timeout (0 = no limit) or when
+// goCtx is done. Returns nil on success.
+func runAttempt(goCtx context.Context, timeout time.Duration, cmd hookCommand, workDir string, terminal bool, stdin io.Reader, stdout, stderr io.Writer) *HookError {
+	runCtx := goCtx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(goCtx, timeout)
+		defer cancel()
+	}
+
+	var shellCmd *exec.Cmd
+	if cmd.argv != nil {
+		shellCmd = exec.CommandContext(runCtx, cmd.argv[0], cmd.argv[1:]...)
+	} else {
+		shell, args := shellCommand(cmd.line)
+		shellCmd = exec.CommandContext(runCtx, shell, args...)
+	}
+	shellCmd.Dir = workDir
+	shellCmd.Env = append(inheritedEnv(), cmd.env...)
+	shellCmd.Stdin = stdin
+	shellCmd.Stdout = stdout
+	shellCmd.Stderr = stderr
+	shellCmd.WaitDelay = killWaitDelay
+	done := killProcessGroup(shellCmd, terminal)
+
+	err := shellCmd.Run()
+	interrupted := done()
+	if err == nil {
+		return nil
+	}
+
+	herr := &HookError{Command: cmd.String(), ExitCode: -1}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		herr.ExitCode = exitErr.ExitCode()
+	}
+	switch {
+	case goCtx.Err() != nil || interrupted:
+		herr.Interrupted = true
+	case errors.Is(runCtx.Err(), context.DeadlineExceeded):
+		herr.Timeout = timeout
+	case exitErr == nil:
+		herr.Err = err
+	}
+	return herr
 }
 
 // ReadStdinIfPiped reads all content from stdin if it's piped (not a TTY).
@@ -302,22 +582,10 @@ func ParseEnvWithCachedStdin(envSlice []string, stdinContent string) (map[string
 	return result, nil
 }
 
-// envPlaceholderRegex matches {key}, {key:-default}, and {key:+text} patterns for env variables.
-// This is used after static replacements to expand custom env placeholders.
-// Supported formats:
-//   - {key}           - value from --arg key=value
-//   - {key:-default}  - value with default if key not set
-//   - {key:+text}     - conditional: expands to text if key is set and non-empty
-var envPlaceholderRegex = regexp.MustCompile(`\{([a-zA-Z_][a-zA-Z0-9_]*)(?::([-+])([^}]*))?\}`)
+// placeholderRegex matches {name}, {name:q}, {key:-default} and {key:+text}.
+// Static placeholder names may contain hyphens, --arg keys may not.
+var placeholderRegex = regexp.MustCompile(`\{([a-zA-Z_][a-zA-Z0-9_-]*)(?::([-+])([^}]*)|:(q))?\}`)
 
-// SubstitutePlaceholders replaces {placeholder} with values from Context.
-//
-// Static placeholders: {worktree-dir}, {repo-dir}, {branch}, {repo}, {trigger}, {action},
-// {phase}, {config-dir}, {pr-number}, {pr-repo}
-// Env placeholders (from Context.Env via --arg key=value or --arg key):
-//   - {key}           - value from --arg key=value
-//   - {key:-default}  - value with default if key not set
-//   - {key:+text}     - expands to text if key is set and non-empty, otherwise empty
 func formatPRNumber(n *int) string {
 	if n == nil {
 		return ""
@@ -325,35 +593,59 @@ func formatPRNumber(n *int) string {
 	return fmt.Sprintf("%d", *n)
 }
 
+// staticPlaceholders returns the values of the static placeholders by name
+// (without braces).
+func staticPlaceholders(ctx Context) map[string]string {
+	return map[string]string{
+		"worktree-dir": ctx.WorktreeDir,
+		"repo-dir":     ctx.RepoDir,
+		"branch":       ctx.Branch,
+		"repo":         ctx.Repo,
+		"trigger":      ctx.Trigger,
+		"action":       ctx.Action,
+		"phase":        string(ctx.Phase),
+		"config-dir":   ctx.ConfigDir,
+		"pr-number":    formatPRNumber(ctx.PRNumber),
+		"pr-repo":      ctx.PRRepo,
+		"pr-url":       ctx.PRURL,
+		"note":         ctx.Note,
+		"label":        ctx.Label,
+	}
+}
+
+// SubstitutePlaceholders replaces {placeholder} with values from Context.
+// Values are inserted as-is; append :q to a placeholder to insert its value
+// quoted for a POSIX shell instead, e.g. {branch:q} or {prompt:q}.
+//
+// Static placeholders: {worktree-dir}, {repo-dir}, {branch}, {repo}, {trigger}, {action},
+// {phase}, {config-dir}, {pr-number}, {pr-repo}, {pr-url}, {note}, {label}
+// Env placeholders (from Context.Env via --arg key=value or --arg key):
+//   - {key}           - value from --arg key=value
+//   - {key:-default}  - value with default if key not set
+//   - {key:+text}     - expands to text if key is set and non-empty, otherwise empty
 func SubstitutePlaceholders(command string, ctx Context) string {
-	// First, handle static replacements
-	replacements := map[string]string{
-		"{worktree-dir}": ctx.WorktreeDir,
-		"{repo-dir}":     ctx.RepoDir,
-		"{branch}":       ctx.Branch,
-		"{repo}":         ctx.Repo,
-		"{trigger}":      ctx.Trigger,
-		"{action}":       ctx.Action,
-		"{phase}":        string(ctx.Phase),
-		"{config-dir}":   ctx.ConfigDir,
-		"{pr-number}":    formatPRNumber(ctx.PRNumber),
-		"{pr-repo}":      ctx.PRRepo,
-	}
-
-	result := command
-	for placeholder, value := range replacements {
-		result = strings.ReplaceAll(result, placeholder, value)
-	}
-
-	// Then, handle env placeholders: {key}, {key:-default}, {key:+text}
-	result = envPlaceholderRegex.ReplaceAllStringFunc(result, func(match string) string {
-		submatch := envPlaceholderRegex.FindStringSubmatch(match)
+	static := staticPlaceholders(ctx)
+
+	// Substitute in a single pass, so values are never expanded again
+	return placeholderRegex.ReplaceAllStringFunc(command, func(match string) string {
+		submatch := placeholderRegex.FindStringSubmatch(match)
 		if submatch == nil {
 			return match
 		}
 		key := submatch[1]
 		operator := submatch[2] // "-", "+", or "" (no operator)
 		operand := submatch[3]  // text after the operator
+		quote := submatch[4] != ""
+
+		if val, ok := static[key]; ok && operator == "" {
+			if quote {
+				return shellQuote(val)
+			}
+			return val
+		}
+		if strings.Contains(key, "-") {
+			return match // not a static placeholder nor a valid --arg key
+		}
 
 		// Look up value in env map
 		val, isSet := "", false
@@ -376,12 +668,54 @@ func SubstitutePlaceholders(command string, ctx Context) string {
 			return operand
 		default:
 			// {key} - if key is set, use value; otherwise empty string
-			if isSet {
-				return val
+			if quote {
+				return shellQuote(val)
 			}
-			return ""
+			return val
 		}
 	})
+}
+
+// shellQuote quotes s as a single word for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// hookEnv returns the placeholder values as environment variables:
+// WT_<NAME> for static placeholders (e.g. WT_WORKTREE_DIR for {worktree-dir})
+// and WT_ARG_<KEY> for --arg values. Unlike placeholders, they are safe to
+// use with any value.
+func hookEnv(ctx Context) []string {
+	static := staticPlaceholders(ctx)
+	env := make([]string, 0, len(static)+len(ctx.Env))
+	for _, name := range slices.Sorted(maps.Keys(static)) {
+		env = append(env, "WT_"+envName(name)+"="+static[name])
+	}
+	for _, key := range slices.Sorted(maps.Keys(ctx.Env)) {
+		env = append(env, "WT_ARG_"+envName(key)+"="+ctx.Env[key])
+	}
+	return env
+}
 
-	return result
+// envName turns a placeholder or --arg name into an environment variable
+// name: upper case, with characters other than letters, digits and
+// underscores replaced by underscores.
+func envName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// inheritedEnv returns wt's environment without WT_ARG_ variables, which
+// would otherwise leak from a hook running wt into that wt's hooks.
+func inheritedEnv() []string {
+	return slices.DeleteFunc(os.Environ(), func(kv string) bool {
+		return strings.HasPrefix(kv, "WT_ARG_")
+	})
 }
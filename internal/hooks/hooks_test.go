@@ -3,8 +3,16 @@ package hooks
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/raphi011/wt/internal/config"
 	"github.com/raphi011/wt/internal/log"
@@ -440,7 +448,7 @@ func TestSelectHooks_OnAll(t *testing.T) {
 	}
 
 	// "all" should match all command types
-	for _, cmdType := range []CommandType{CommandCheckout, CommandPrune, CommandMerge} {
+	for _, cmdType := range []CommandType{CommandCheckout, CommandPrune, CommandPR} {
 		matches, err := SelectHooks(hooksConfig, nil, false, HookSelector{Command: cmdType, Phase: PhaseAfter})
 		if err != nil {
 			t.Errorf("unexpected error for %s: %v", cmdType, err)
@@ -1233,3 +1241,289 @@ func TestFindMatchingHooks_AlphabeticalOrder(t *testing.T) {
 		}
 	}
 }
+
+func TestRunSingle_Retries(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	dir := t.TempDir()
+	// Fails on the first two attempts
+	hook := &config.Hook{
+		Command: "echo x >> attempts; [ $(wc -l < attempts) -ge 3 ]",
+		Retries: 2,
+	}
+
+	if err := RunSingle(logCtx(&buf), "flaky", hook, Context{WorktreeDir: dir}); err != nil {
+		t.Fatalf("RunSingle() = %v, want nil", err)
+	}
+	if !strings.Contains(buf.String(), `Hook "flaky" failed, retrying (2/2)`) {
+		t.Errorf("output = %q, want retry message", buf.String())
+	}
+
+	err := RunSingle(logCtx(&buf), "flaky", &config.Hook{Command: "exit 4", Retries: 1}, Context{WorktreeDir: dir})
+	var herr *HookError
+	if !errors.As(err, &herr) {
+		t.Fatalf("RunSingle() = %v, want *HookError", err)
+	}
+	if herr.ExitCode != 4 || herr.Attempts != 2 {
+		t.Errorf("ExitCode = %d, Attempts = %d, want 4 and 2", herr.ExitCode, herr.Attempts)
+	}
+	if want := "command failed (exit 4): exit 4 (2 attempts)"; herr.Error() != want {
+		t.Errorf("Error() = %q, want %q", herr.Error(), want)
+	}
+}
+
+func TestRunSingle_TimeoutKillsChildProcesses(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("process groups are not killed on Windows")
+	}
+
+	var buf bytes.Buffer
+	dir := t.TempDir()
+	// The background child would create "leaked" if it outlived the hook
+	hook := &config.Hook{
+		Command: "(sleep 1; touch leaked) & sleep 5",
+		Timeout: "100ms",
+	}
+
+	start := time.Now()
+	err := RunSingle(logCtx(&buf), "slow", hook, Context{WorktreeDir: dir})
+	var herr *HookError
+	if !errors.As(err, &herr) || herr.Timeout != 100*time.Millisecond {
+		t.Fatalf("RunSingle() = %v, want timeout error", err)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("RunSingle() took %v, want the hook killed after its timeout", elapsed)
+	}
+	if !strings.Contains(err.Error(), "command timed out after 100ms") {
+		t.Errorf("error = %q, want timeout message", err.Error())
+	}
+
+	time.Sleep(1500 * time.Millisecond)
+	if _, err := os.Stat(filepath.Join(dir, "leaked")); !os.IsNotExist(err) {
+		t.Error("child process of the hook outlived it")
+	}
+}
+
+func TestRunSingle_Interrupted(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	ctx, cancel := context.WithCancel(logCtx(&buf))
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	// Interrupted hooks are not retried
+	hook := &config.Hook{Command: "sleep 5", Retries: 3}
+	err := RunSingle(ctx, "slow", hook, Context{WorktreeDir: t.TempDir()})
+	var herr *HookError
+	if !errors.As(err, &herr) || !herr.Interrupted || herr.Attempts != 1 {
+		t.Fatalf("RunSingle() = %v, want interrupted error after 1 attempt", err)
+	}
+}
+
+func TestRunBeforeHooks_WarnPolicyDoesNotAbort(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	hook := config.Hook{Command: "exit 1", OnFailure: OnFailureWarn}
+	matches := []HookMatch{{Hook: &hook, Name: "lint"}}
+	hookCtx := Context{WorktreeDir: t.TempDir(), Branch: "main"}
+
+	if err := RunBeforeHooks(logCtx(&buf), matches, hookCtx, hookCtx.WorktreeDir); err != nil {
+		t.Errorf("RunBeforeHooks() = %v, want nil", err)
+	}
+	if !strings.Contains(buf.String(), `Warning: hook "lint" failed for main`) {
+		t.Errorf("output = %q, want warning", buf.String())
+	}
+}
+
+func TestRunForEach_AbortPolicyReturnsError(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	hook := config.Hook{Command: "exit 1", OnFailure: OnFailureAbort}
+	matches := []HookMatch{{Hook: &hook, Name: "deploy"}}
+	hookCtx := Context{WorktreeDir: t.TempDir(), Branch: "main", Phase: PhaseAfter}
+
+	if err := RunForEach(logCtx(&buf), matches, hookCtx, hookCtx.WorktreeDir); err == nil {
+		t.Error("RunForEach() = nil, want error")
+	}
+	if !strings.Contains(buf.String(), `Hook "deploy" failed for main — aborting`) {
+		t.Errorf("output = %q, want abort message", buf.String())
+	}
+}
+
+func TestSubstitutePlaceholders_Quoted(t *testing.T) {
+	t.Parallel()
+
+	ctx := Context{
+		WorktreeDir: "/home/user/it's here",
+		Branch:      "feature/x",
+		Env:         map[string]string{"prompt": "fix `rm -rf ~`; it's $HOME", "raw": "{branch}"},
+	}
+
+	tests := []struct {
+		command  string
+		expected string
+	}{
+		{"cd {worktree-dir:q}", `cd '/home/user/it'\''s here'`},
+		{"echo {branch:q}", `echo 'feature/x'`},
+		{"claude -p {prompt:q}", `claude -p 'fix ` + "`rm -rf ~`" + `; it'\''s $HOME'`},
+		{"echo {missing:q}", `echo ''`},
+		{"echo {pr-number:q}", `echo ''`},
+		// Values are not expanded again
+		{"echo {raw}", "echo {branch}"},
+		// Unknown placeholders with hyphens are kept
+		{"echo {not-a-key:q}", "echo {not-a-key:q}"},
+	}
+
+	for _, tt := range tests {
+		if got := SubstitutePlaceholders(tt.command, ctx); got != tt.expected {
+			t.Errorf("SubstitutePlaceholders(%q) = %q, want %q", tt.command, got, tt.expected)
+		}
+	}
+}
+
+func TestHookEnv(t *testing.T) {
+	t.Parallel()
+
+	ctx := Context{
+		WorktreeDir: "/wt/repo-feature",
+		Branch:      "feature",
+		PRNumber:    new(7),
+		Phase:       PhaseAfter,
+		Env:         map[string]string{"prompt": "it's", "dry-run": "true"},
+	}
+
+	env := hookEnv(ctx)
+	for _, want := range []string{
+		"WT_WORKTREE_DIR=/wt/repo-feature",
+		"WT_BRANCH=feature",
+		"WT_PR_NUMBER=7",
+		"WT_PR_REPO=",
+		"WT_PHASE=after",
+		"WT_ARG_PROMPT=it's",
+		"WT_ARG_DRY_RUN=true",
+	} {
+		if !slices.Contains(env, want) {
+			t.Errorf("hookEnv() = %v, missing %q", env, want)
+		}
+	}
+}
+
+func TestRunSingle_EnvAndArgv(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("WT_ARG_LEAKED", "outer")
+
+	ctx := Context{
+		WorktreeDir: dir,
+		Branch:      `it's "quoted"; touch injected`,
+		Env:         map[string]string{"msg": "$(touch injected)"},
+	}
+
+	// The environment variables are set for shell hooks
+	var buf bytes.Buffer
+	hook := &config.Hook{Command: `printf '%s|%s|%s' "$WT_BRANCH" "$WT_ARG_MSG" "$WT_ARG_LEAKED" > env.txt`}
+	if err := RunSingle(logCtx(&buf), "env", hook, ctx); err != nil {
+		t.Fatalf("RunSingle(env) = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "env.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := ctx.Branch + "|$(touch injected)|"; string(data) != want {
+		t.Errorf("env.txt = %q, want %q", data, want)
+	}
+
+	// argv hooks pass each substituted element as one argument
+	hook = &config.Hook{Argv: []string{"sh", "-c", `printf '%s|%s' "$1" "$2" > argv.txt`, "sh", "{branch}", "{msg}"}}
+	if err := RunSingle(logCtx(&buf), "argv", hook, ctx); err != nil {
+		t.Fatalf("RunSingle(argv) = %v", err)
+	}
+	data, err = os.ReadFile(filepath.Join(dir, "argv.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := ctx.Branch + "|$(touch injected)"; string(data) != want {
+		t.Errorf("argv.txt = %q, want %q", data, want)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "injected")); !os.IsNotExist(err) {
+		t.Error("a value was executed as shell code")
+	}
+}
+
+func TestRunSingle_ArgvDryRun(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	hook := &config.Hook{Argv: []string{"claude", "-p", "{prompt}"}}
+	ctx := Context{DryRun: true, Env: map[string]string{"prompt": "fix it"}}
+
+	if err := RunSingle(logCtx(&buf), "claude", hook, ctx); err != nil {
+		t.Fatalf("RunSingle() = %v", err)
+	}
+	if want := "[dry-run] claude: claude -p 'fix it'\n"; buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestRunSingle_Script(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script")
+	}
+
+	dir := t.TempDir()
+	script := filepath.Join(dir, "setup.sh")
+	content := "#!/bin/sh\ncat > input.json\nprintf '%s|%s' \"$WT_BRANCH\" \"$WT_ARG_ENV\" > env.txt\n"
+	if err := os.WriteFile(script, []byte(content), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	hook := &config.Hook{Script: script, On: []string{"checkout"}}
+	ctx := Context{
+		WorktreeDir: dir,
+		Branch:      "feature",
+		Trigger:     "checkout",
+		Phase:       PhaseAfter,
+		Env:         map[string]string{"env": "dev"},
+	}
+	if err := RunSingle(logCtx(&buf), "checkout/setup.sh", hook, ctx); err != nil {
+		t.Fatalf("RunSingle() = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "env.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "feature|dev" {
+		t.Errorf("env.txt = %q, want %q", data, "feature|dev")
+	}
+
+	data, err = os.ReadFile(filepath.Join(dir, "input.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var input map[string]any
+	if err := json.Unmarshal(data, &input); err != nil {
+		t.Fatalf("stdin is not JSON: %v (%q)", err, data)
+	}
+	for key, want := range map[string]any{
+		"hook":         "checkout/setup.sh",
+		"worktree_dir": dir,
+		"branch":       "feature",
+		"trigger":      "checkout",
+		"phase":        "after",
+		"pr_number":    nil,
+		"args":         map[string]any{"env": "dev"},
+	} {
+		if got := input[key]; !reflect.DeepEqual(got, want) {
+			t.Errorf("input[%q] = %v, want %v", key, got, want)
+		}
+	}
+}
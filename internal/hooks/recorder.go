@@ -0,0 +1,55 @@
+package hooks
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Result is the outcome of a single hook run.
+type Result struct {
+	Name     string
+	Phase    PhaseType
+	Err      error
+	ExitCode int // exit code of the last attempt (-1 if killed)
+	Duration time.Duration
+}
+
+// Recorder collects the results of hooks run with a context carrying it
+// (see WithRecorder). Used to journal which hooks ran for an operation.
+type Recorder struct {
+	mu      sync.Mutex
+	results []Result
+}
+
+type recorderKey struct{}
+
+// WithRecorder returns a context whose hook runs are recorded in r.
+func WithRecorder(ctx context.Context, r *Recorder) context.Context {
+	return context.WithValue(ctx, recorderKey{}, r)
+}
+
+// recorderFromContext returns the recorder in ctx, or nil.
+func recorderFromContext(ctx context.Context) *Recorder {
+	r, _ := ctx.Value(recorderKey{}).(*Recorder)
+	return r
+}
+
+// record appends a result. Safe to call on a nil recorder.
+func (r *Recorder) record(res Result) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.results = append(r.results, res)
+}
+
+// Take returns the recorded results and clears the recorder.
+func (r *Recorder) Take() []Result {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	results := r.results
+	r.results = nil
+	return results
+}
@@ -0,0 +1,109 @@
+package hooks
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/raphi011/wt/internal/config"
+)
+
+// terminalHookDirEnv is set for the helper process started by
+// TestRunSingle_TerminalTimeoutKillsChildProcesses.
+const terminalHookDirEnv = "WT_TEST_TERMINAL_HOOK_DIR"
+
+func TestRunSingle_TerminalTimeoutKillsChildProcesses(t *testing.T) {
+	t.Parallel()
+
+	ptm, pts := openPTY(t)
+	dir := t.TempDir()
+
+	// The helper runs the hook with the pty as its controlling terminal
+	cmd := exec.Command(os.Args[0], "-test.run=^TestTerminalHookHelper$")
+	cmd.Env = append(os.Environ(), terminalHookDirEnv+"="+dir)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = pts, pts, pts
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true, Setctty: true, Ctty: 0}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	pts.Close()
+
+	var out bytes.Buffer
+	copied := make(chan struct{})
+	go func() {
+		io.Copy(&out, ptm) // returns EIO once the helper has exited
+		close(copied)
+	}()
+	err := cmd.Wait()
+	ptm.Close()
+	<-copied
+	if err != nil {
+		t.Fatalf("helper failed: %v\n%s", err, out.String())
+	}
+}
+
+// TestTerminalHookHelper is run by TestRunSingle_TerminalTimeoutKillsChildProcesses
+// in a session of its own, with a pty as stdin. It checks for leaked child
+// processes itself: when it exits, the foreground group gets SIGHUP.
+func TestTerminalHookHelper(t *testing.T) {
+	dir := os.Getenv(terminalHookDirEnv)
+	if dir == "" {
+		t.Skip("helper process only")
+	}
+
+	var buf bytes.Buffer
+	// The background child would create "leaked" if it outlived the hook
+	hook := &config.Hook{
+		Command: "(sleep 1; touch leaked) & sleep 5",
+		Timeout: "100ms",
+	}
+	err := RunSingle(logCtx(&buf), "slow", hook, Context{WorktreeDir: dir})
+	var herr *HookError
+	if !errors.As(err, &herr) || herr.Timeout != 100*time.Millisecond {
+		t.Fatalf("RunSingle() = %v, want timeout error", err)
+	}
+
+	tty := int(os.Stdin.Fd())
+	if pgrp, err := unix.IoctlGetInt(tty, unix.TIOCGPGRP); err != nil || pgrp != syscall.Getpgrp() {
+		t.Errorf("foreground group = %d (%v), want %d after the hook", pgrp, err, syscall.Getpgrp())
+	}
+
+	time.Sleep(1500 * time.Millisecond)
+	if _, err := os.Stat(filepath.Join(dir, "leaked")); !os.IsNotExist(err) {
+		t.Error("child process of the terminal hook outlived it")
+	}
+}
+
+// openPTY opens a pseudo terminal and returns its master and slave.
+func openPTY(t *testing.T) (ptm, pts *os.File) {
+	t.Helper()
+
+	ptm, err := os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		t.Skipf("no pty: %v", err)
+	}
+	t.Cleanup(func() { ptm.Close() })
+
+	if err := unix.IoctlSetPointerInt(int(ptm.Fd()), unix.TIOCSPTLCK, 0); err != nil {
+		t.Fatalf("unlock pty: %v", err)
+	}
+	n, err := unix.IoctlGetInt(int(ptm.Fd()), unix.TIOCGPTN)
+	if err != nil {
+		t.Fatalf("get pty number: %v", err)
+	}
+	pts, err = os.OpenFile(fmt.Sprintf("/dev/pts/%d", n), os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		t.Fatalf("open pty slave: %v", err)
+	}
+	t.Cleanup(func() { pts.Close() })
+	return ptm, pts
+}
@@ -2,7 +2,74 @@
 
 package hooks
 
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
 // shellCommand returns the shell and arguments for running a command string.
 func shellCommand(command string) (string, []string) {
 	return "sh", []string{"-c", command}
 }
+
+// killProcessGroup starts cmd in its own process group and makes canceling
+// its context kill the whole group, so child processes of the shell don't
+// outlive it.
+//
+// A hook attached to the terminal gets the terminal's foreground while it
+// runs, as only the foreground group may read from it. Ctrl-C then reaches
+// the hook only. The returned function must be called after cmd has exited:
+// it takes the foreground back and, if the hook was killed by Ctrl-C, passes
+// the interrupt on to wt and reports it.
+func killProcessGroup(cmd *exec.Cmd, terminal bool) (done func() (interrupted bool)) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		err := syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+		if errors.Is(err, syscall.ESRCH) {
+			return os.ErrProcessDone
+		}
+		return err
+	}
+
+	tty := int(os.Stdin.Fd())
+	if !terminal || !isForeground(tty) {
+		return func() bool { return false }
+	}
+	// Stdin of the hook is the terminal, so it's fd 0 in the child
+	cmd.SysProcAttr.Foreground = true
+	cmd.SysProcAttr.Ctty = 0
+
+	return func() bool {
+		takeForeground(tty)
+		if cmd.ProcessState == nil {
+			return false
+		}
+		status, ok := cmd.ProcessState.Sys().(syscall.WaitStatus)
+		if !ok || !status.Signaled() || status.Signal() != syscall.SIGINT {
+			return false
+		}
+		_ = syscall.Kill(os.Getpid(), syscall.SIGINT)
+		return true
+	}
+}
+
+// isForeground reports whether wt's process group is the foreground group
+// of the terminal tty. A wt running in the background must not take it.
+func isForeground(tty int) bool {
+	pgrp, err := unix.IoctlGetInt(tty, unix.TIOCGPGRP)
+	return err == nil && pgrp == syscall.Getpgrp()
+}
+
+// takeForeground makes wt's process group the foreground group of the
+// terminal tty again. SIGTTOU is ignored meanwhile, as a background group
+// changing the foreground would be stopped by it.
+func takeForeground(tty int) {
+	signal.Ignore(syscall.SIGTTOU)
+	defer signal.Reset(syscall.SIGTTOU)
+	_ = unix.IoctlSetPointerInt(tty, unix.TIOCSPGRP, syscall.Getpgrp())
+}
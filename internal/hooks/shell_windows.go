@@ -2,7 +2,15 @@
 
 package hooks
 
+import "os/exec"
+
 // shellCommand returns the shell and arguments for running a command string.
 func shellCommand(command string) (string, []string) {
 	return "cmd", []string{"/c", command}
 }
+
+// killProcessGroup is a no-op on Windows: canceling the context of cmd
+// kills the shell process only.
+func killProcessGroup(cmd *exec.Cmd, terminal bool) (done func() (interrupted bool)) {
+	return func() bool { return false }
+}
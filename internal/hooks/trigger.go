@@ -10,3 +10,8 @@ type ParsedTrigger = hooktrigger.ParsedTrigger
 func ParseTrigger(s string) (ParsedTrigger, error) {
 	return hooktrigger.ParseTrigger(s)
 }
+
+// AllPlaceholders returns the names of all static placeholders, sorted.
+func AllPlaceholders() []string {
+	return hooktrigger.AllPlaceholders()
+}
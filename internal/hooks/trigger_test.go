@@ -1,6 +1,8 @@
 package hooks
 
 import (
+	"maps"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -26,15 +28,26 @@ func TestParseTrigger(t *testing.T) {
 		{name: "after:checkout:create", input: "after:checkout:create", wantPhase: "after", wantType: "checkout", wantSub: "create"},
 		{name: "prune", input: "prune", wantPhase: "after", wantType: "prune", wantSub: ""},
 		{name: "before:prune", input: "before:prune", wantPhase: "before", wantType: "prune", wantSub: ""},
-		{name: "merge", input: "merge", wantPhase: "after", wantType: "merge", wantSub: ""},
-		{name: "before:merge", input: "before:merge", wantPhase: "before", wantType: "merge", wantSub: ""},
+		{name: "merge is pr:merge", input: "merge", wantPhase: "after", wantType: "pr", wantSub: "merge"},
+		{name: "before:merge", input: "before:merge", wantPhase: "before", wantType: "pr", wantSub: "merge"},
+		{name: "pr", input: "pr", wantPhase: "after", wantType: "pr", wantSub: ""},
+		{name: "after:pr:create", input: "after:pr:create", wantPhase: "after", wantType: "pr", wantSub: "create"},
+		{name: "before:pr:merge", input: "before:pr:merge", wantPhase: "before", wantType: "pr", wantSub: "merge"},
+		{name: "after:repo:clone", input: "after:repo:clone", wantPhase: "after", wantType: "repo", wantSub: "clone"},
+		{name: "repo:add", input: "repo:add", wantPhase: "after", wantType: "repo", wantSub: "add"},
+		{name: "after:note", input: "after:note", wantPhase: "after", wantType: "note", wantSub: ""},
+		{name: "before:note:clear", input: "before:note:clear", wantPhase: "before", wantType: "note", wantSub: "clear"},
+		{name: "label:remove", input: "label:remove", wantPhase: "after", wantType: "label", wantSub: "remove"},
+		{name: "cd", input: "cd", wantPhase: "after", wantType: "cd", wantSub: ""},
+		{name: "before:cd", input: "before:cd", wantPhase: "before", wantType: "cd", wantSub: ""},
 		{name: "all", input: "all", wantPhase: "after", wantType: "all", wantSub: ""},
 		{name: "before:all", input: "before:all", wantPhase: "before", wantType: "all", wantSub: ""},
 		{name: "after:all", input: "after:all", wantPhase: "after", wantType: "all", wantSub: ""},
 
 		// Invalid inputs
-		{name: "cd is removed", input: "cd", wantErr: true, errContain: "no longer a valid trigger"},
-		{name: "pr is not a trigger", input: "pr", wantErr: true, errContain: "not a valid trigger"},
+		{name: "unknown trigger", input: "push", wantErr: true, errContain: "not a valid trigger"},
+		{name: "unknown pr subtype", input: "pr:close", wantErr: true, errContain: "unknown subtype"},
+		{name: "cd no subtypes", input: "cd:back", wantErr: true, errContain: "does not support subtypes"},
 		{name: "unknown subtype", input: "checkout:foo", wantErr: true, errContain: "unknown subtype"},
 		{name: "prune no subtypes", input: "prune:create", wantErr: true, errContain: "does not support subtypes"},
 		{name: "merge no subtypes", input: "merge:pr", wantErr: true, errContain: "does not support subtypes"},
@@ -44,7 +57,6 @@ func TestParseTrigger(t *testing.T) {
 		{name: "trailing colon", input: "before:checkout:", wantErr: true, errContain: "empty subtype"},
 		{name: "too many segments", input: "a:b:c:d", wantErr: true, errContain: "too many segments"},
 		{name: "all no subtypes", input: "all:pr", wantErr: true, errContain: "does not support subtypes"},
-		{name: "before:cd", input: "before:cd", wantErr: true, errContain: "no longer a valid trigger"},
 	}
 
 	for _, tt := range tests {
@@ -93,10 +105,14 @@ func TestParsedTrigger_Matches(t *testing.T) {
 		{name: "before:checkout:pr no match create", on: "before:checkout:pr", trigger: "checkout", subtype: "create", want: false},
 		{name: "all matches checkout:pr", on: "all", trigger: "checkout", subtype: "pr", want: true},
 		{name: "all matches prune", on: "all", trigger: "prune", subtype: "", want: true},
-		{name: "all matches merge", on: "all", trigger: "merge", subtype: "", want: true},
+		{name: "all matches pr:merge", on: "all", trigger: "pr", subtype: "merge", want: true},
 		{name: "before:all matches prune", on: "before:all", trigger: "prune", subtype: "", want: true},
 		{name: "prune no match checkout", on: "prune", trigger: "checkout", subtype: "create", want: false},
 		{name: "merge no match prune", on: "merge", trigger: "prune", subtype: "", want: false},
+		{name: "merge matches pr:merge", on: "merge", trigger: "pr", subtype: "merge", want: true},
+		{name: "merge no match pr:create", on: "merge", trigger: "pr", subtype: "create", want: false},
+		{name: "pr matches create", on: "pr", trigger: "pr", subtype: "create", want: true},
+		{name: "pr no match checkout:pr", on: "pr", trigger: "checkout", subtype: "pr", want: false},
 		{name: "checkout no match prune", on: "checkout", trigger: "prune", subtype: "", want: false},
 	}
 
@@ -113,3 +129,49 @@ func TestParsedTrigger_Matches(t *testing.T) {
 		})
 	}
 }
+
+func TestParsedTrigger_Placeholders(t *testing.T) {
+	tests := []struct {
+		on     string
+		has    []string
+		hasNot []string
+	}{
+		{on: "checkout", has: []string{"worktree-dir", "branch", "pr-number"}, hasNot: []string{"note", "pr-url"}},
+		{on: "checkout:create", has: []string{"worktree-dir", "branch"}, hasNot: []string{"pr-number", "pr-repo"}},
+		{on: "checkout:pr", has: []string{"pr-number", "pr-repo"}, hasNot: []string{"pr-url"}},
+		{on: "before:pr:merge", has: []string{"worktree-dir", "pr-number", "pr-url"}, hasNot: []string{"label"}},
+		{on: "repo:clone", has: []string{"repo", "repo-dir", "config-dir"}, hasNot: []string{"worktree-dir", "branch"}},
+		{on: "note", has: []string{"branch", "note"}, hasNot: []string{"worktree-dir"}},
+		{on: "label:add", has: []string{"label", "repo"}, hasNot: []string{"branch"}},
+		{on: "cd", has: []string{"worktree-dir", "branch"}, hasNot: []string{"pr-number"}},
+		{on: "all", has: AllPlaceholders()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.on, func(t *testing.T) {
+			parsed, err := ParseTrigger(tt.on)
+			if err != nil {
+				t.Fatalf("ParseTrigger(%q) error: %v", tt.on, err)
+			}
+			got := parsed.Placeholders()
+			for _, name := range tt.has {
+				if !slices.Contains(got, name) {
+					t.Errorf("Placeholders() = %v, want {%s}", got, name)
+				}
+			}
+			for _, name := range tt.hasNot {
+				if slices.Contains(got, name) {
+					t.Errorf("Placeholders() = %v, don't want {%s}", got, name)
+				}
+			}
+		})
+	}
+}
+
+func TestAllPlaceholders(t *testing.T) {
+	// Every static placeholder must be known to trigger validation
+	want := slices.Sorted(maps.Keys(staticPlaceholders(Context{})))
+	if got := AllPlaceholders(); !slices.Equal(got, want) {
+		t.Errorf("AllPlaceholders() = %v, want %v", got, want)
+	}
+}
@@ -10,24 +10,39 @@ import (
 // See also hooks.HookSelector which mirrors this for runtime hook selection.
 type ParsedTrigger struct {
 	Phase   string // "before" or "after"
-	Trigger string // "checkout", "prune", "merge", or "all"
-	Subtype string // checkout only: "create", "open", "pr", or "" (match all)
+	Trigger string // "checkout", "prune", "pr", "repo", "note", "label", "cd", or "all"
+	Subtype string // one of validSubtypes[Trigger], or "" (match all)
 }
 
 var (
 	validTimings  = []string{"before", "after"}
-	validTriggers = []string{"checkout", "prune", "merge", "all"}
+	validTriggers = []string{"checkout", "prune", "merge", "pr", "repo", "note", "label", "cd", "all"}
 	validSubtypes = map[string][]string{
 		"checkout": {"create", "open", "pr"},
+		"pr":       {"create", "merge"},
+		"repo":     {"add", "clone"},
+		"note":     {"set", "clear"},
+		"label":    {"add", "remove", "clear"},
 	}
-	removedTriggers = map[string]string{
-		"cd": `"cd" is no longer a valid trigger, use "wt hook <name>" instead`,
+	// aliasTriggers maps triggers kept for compatibility to trigger:subtype.
+	aliasTriggers = map[string][2]string{
+		"merge": {"pr", "merge"},
 	}
 )
 
-func isRemovedTrigger(s string) bool {
-	_, ok := removedTriggers[s]
-	return ok
+// Placeholders common to all triggers.
+var commonPlaceholders = []string{"repo", "repo-dir", "trigger", "action", "phase", "config-dir"}
+
+// triggerPlaceholders lists the placeholders that have a value for a trigger
+// (all its subtypes) or a trigger:subtype, besides commonPlaceholders.
+var triggerPlaceholders = map[string][]string{
+	"checkout":    {"worktree-dir", "branch"},
+	"checkout:pr": {"pr-number", "pr-repo"},
+	"prune":       {"worktree-dir", "branch"},
+	"pr":          {"worktree-dir", "branch", "pr-number", "pr-repo", "pr-url"},
+	"note":        {"branch", "note"},
+	"label":       {"label"},
+	"cd":          {"worktree-dir", "branch"},
 }
 
 // ParseTrigger parses an "on" value string into a ParsedTrigger.
@@ -60,7 +75,7 @@ func ParseTrigger(s string) (ParsedTrigger, error) {
 		if slices.Contains(validTimings, parts[0]) {
 			phase = parts[0]
 			trigger = parts[1]
-		} else if slices.Contains(validTriggers, parts[0]) || isRemovedTrigger(parts[0]) {
+		} else if slices.Contains(validTriggers, parts[0]) {
 			phase = "after"
 			trigger = parts[0]
 			subtype = parts[1]
@@ -77,10 +92,6 @@ func ParseTrigger(s string) (ParsedTrigger, error) {
 		subtype = parts[2]
 	}
 
-	if msg, ok := removedTriggers[trigger]; ok {
-		return ParsedTrigger{}, fmt.Errorf("%s", msg)
-	}
-
 	if !slices.Contains(validTriggers, trigger) {
 		return ParsedTrigger{}, fmt.Errorf("%q is not a valid trigger (valid: %s)", trigger, strings.Join(validTriggers, ", "))
 	}
@@ -95,6 +106,10 @@ func ParseTrigger(s string) (ParsedTrigger, error) {
 		}
 	}
 
+	if alias, ok := aliasTriggers[trigger]; ok {
+		trigger, subtype = alias[0], alias[1]
+	}
+
 	return ParsedTrigger{
 		Phase:   phase,
 		Trigger: trigger,
@@ -115,3 +130,39 @@ func (p ParsedTrigger) Matches(trigger, subtype string) bool {
 	}
 	return p.Subtype == subtype
 }
+
+// String returns the trigger as trigger[:subtype], without the phase.
+func (p ParsedTrigger) String() string {
+	if p.Subtype == "" {
+		return p.Trigger
+	}
+	return p.Trigger + ":" + p.Subtype
+}
+
+// Placeholders returns the names of the static placeholders (without braces)
+// that have a value for the trigger: those of any of its subtypes if it has
+// none, all placeholders for "all".
+func (p ParsedTrigger) Placeholders() []string {
+	if p.Trigger == "all" {
+		return AllPlaceholders()
+	}
+	names := slices.Clone(commonPlaceholders)
+	names = append(names, triggerPlaceholders[p.Trigger]...)
+	if p.Subtype != "" {
+		return append(names, triggerPlaceholders[p.String()]...)
+	}
+	for _, subtype := range validSubtypes[p.Trigger] {
+		names = append(names, triggerPlaceholders[p.Trigger+":"+subtype]...)
+	}
+	return names
+}
+
+// AllPlaceholders returns the names of all static placeholders, sorted.
+func AllPlaceholders() []string {
+	names := slices.Clone(commonPlaceholders)
+	for _, list := range triggerPlaceholders {
+		names = append(names, list...)
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
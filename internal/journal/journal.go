@@ -0,0 +1,161 @@
+// Package journal keeps an append-only log of mutating wt operations.
+//
+// Every checkout, prune, PR merge, repo, label and note change appends one
+// JSON line to ~/.wt/journal.jsonl recording what was done (command, repo,
+// branch, path, commit), the outcome of hooks that ran, and how long it took.
+// `wt log` reads and filters the journal.
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// HookResult is the outcome of a single hook run during an operation.
+type HookResult struct {
+	Name       string `json:"name"`
+	Phase      string `json:"phase"` // "before" or "after"
+	Error      string `json:"error,omitempty"`
+	ExitCode   int    `json:"exit_code,omitempty"` // -1 if killed, e.g. on timeout
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// Entry is a single journaled operation.
+type Entry struct {
+	Time       time.Time    `json:"time"`
+	Command    string       `json:"command"` // e.g. "checkout", "prune", "pr merge", "label add"
+	Repo       string       `json:"repo,omitempty"`
+	RepoPath   string       `json:"repo_path,omitempty"`
+	Branch     string       `json:"branch,omitempty"`
+	Path       string       `json:"path,omitempty"` // worktree path
+	SHA        string       `json:"sha,omitempty"`  // HEAD commit of the worktree or branch
+	Detail     string       `json:"detail,omitempty"`
+	Hooks      []HookResult `json:"hooks,omitempty"`
+	Error      string       `json:"error,omitempty"`
+	DurationMS int64        `json:"duration_ms"`
+}
+
+// Failed reports whether the operation returned an error.
+func (e Entry) Failed() bool {
+	return e.Error != ""
+}
+
+// FailedHooks returns the number of hooks that failed.
+func (e Entry) FailedHooks() int {
+	n := 0
+	for _, h := range e.Hooks {
+		if h.Error != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// Append writes entries to the journal at path, one JSON object per line.
+// The file and its directory are created if needed.
+func Append(path string, entries ...Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	var buf []byte
+	for _, e := range entries {
+		line, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		buf = append(buf, line...)
+		buf = append(buf, '\n')
+	}
+
+	// A single O_APPEND write keeps lines intact across concurrent writers
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(buf); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Read returns all entries of the journal at path, oldest first.
+// Returns nil if the file doesn't exist. Malformed lines (e.g. a partial
+// write) are skipped.
+func Read(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(line, &e); err != nil {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Filter selects journal entries. Zero fields match everything.
+type Filter struct {
+	Repo    string    // repo name
+	Branch  string    // branch name
+	Command string    // command, or its first word (e.g. "pr" matches "pr merge")
+	Since   time.Time // inclusive
+	Until   time.Time // exclusive
+}
+
+// Match reports whether e passes the filter.
+func (f Filter) Match(e Entry) bool {
+	if f.Repo != "" && e.Repo != f.Repo {
+		return false
+	}
+	if f.Branch != "" && e.Branch != f.Branch {
+		return false
+	}
+	if f.Command != "" && e.Command != f.Command && !strings.HasPrefix(e.Command, f.Command+" ") {
+		return false
+	}
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !e.Time.Before(f.Until) {
+		return false
+	}
+	return true
+}
+
+// Apply returns the entries matching the filter, keeping their order.
+func (f Filter) Apply(entries []Entry) []Entry {
+	var matched []Entry
+	for _, e := range entries {
+		if f.Match(e) {
+			matched = append(matched, e)
+		}
+	}
+	return matched
+}
@@ -0,0 +1,99 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAppendRead(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "sub", "journal.jsonl")
+
+	entries, err := Read(path)
+	if err != nil || entries != nil {
+		t.Fatalf("Read() on missing file = %v, %v; want nil, nil", entries, err)
+	}
+
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := Append(path, Entry{Time: at, Command: "checkout", Repo: "app", Branch: "feature"}); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	if err := Append(path,
+		Entry{Time: at.Add(time.Minute), Command: "prune", Repo: "app", Branch: "feature",
+			Hooks: []HookResult{{Name: "cleanup", Phase: "after", Error: "exit 1"}}},
+		Entry{Time: at.Add(2 * time.Minute), Command: "label add", Repo: "api", Detail: "backend"},
+	); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+
+	entries, err = Read(path)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Read() returned %d entries, want 3", len(entries))
+	}
+	if entries[0].Command != "checkout" || !entries[0].Time.Equal(at) {
+		t.Errorf("first entry = %+v", entries[0])
+	}
+	if entries[1].FailedHooks() != 1 {
+		t.Errorf("FailedHooks() = %d, want 1", entries[1].FailedHooks())
+	}
+}
+
+func TestRead_SkipsMalformedLines(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	content := `{"time":"2026-01-02T03:04:05Z","command":"checkout"}
+{"time":"2026-01-02T03:
+{"time":"2026-01-02T03:05:05Z","command":"prune"}
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := Read(path)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(entries) != 2 || entries[1].Command != "prune" {
+		t.Errorf("Read() = %+v, want checkout and prune", entries)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	e := Entry{Time: at, Command: "pr merge", Repo: "app", Branch: "feature"}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   bool
+	}{
+		{"empty filter", Filter{}, true},
+		{"repo match", Filter{Repo: "app"}, true},
+		{"repo mismatch", Filter{Repo: "api"}, false},
+		{"branch mismatch", Filter{Branch: "main"}, false},
+		{"exact command", Filter{Command: "pr merge"}, true},
+		{"command group", Filter{Command: "pr"}, true},
+		{"command prefix is not a word", Filter{Command: "p"}, false},
+		{"since inclusive", Filter{Since: at}, true},
+		{"since after", Filter{Since: at.Add(time.Second)}, false},
+		{"until exclusive", Filter{Until: at}, false},
+		{"until after", Filter{Until: at.Add(time.Second)}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.filter.Match(e); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+// Package migrate upgrades the JSON state files in ~/.wt/ (repos.json,
+// prs.json, history.json) between schema versions.
+//
+// Every file carries a top-level "version" field; files written before
+// versioning existed are version 0. Packages owning a file register one
+// [Migration] per format change (from N to N+1) in an init function, and
+// call [Upgrade] before loading. Upgrading writes a backup of the original
+// file next to it before replacing it.
+package migrate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/raphi011/wt/internal/fs"
+)
+
+// ErrNewerVersion is returned for files written by a newer wt release.
+var ErrNewerVersion = errors.New("file was written by a newer version of wt")
+
+// Migration upgrades one kind of state file from version From to From+1.
+type Migration struct {
+	Kind        string // file kind, e.g. "registry"
+	From        int    // version the migration upgrades from
+	Description string // shown by `wt doctor`
+
+	// Up rewrites the decoded JSON document in place. The "version"
+	// field is set by Upgrade and need not be touched.
+	Up func(doc map[string]any) error
+}
+
+var (
+	mu         sync.RWMutex
+	migrations = map[string][]Migration{}
+)
+
+// Register adds a migration. Migrations for a kind must be registered in
+// order without gaps, starting at version 0; Register panics otherwise,
+// since that is a programming error caught by any test.
+func Register(m Migration) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if m.Up == nil {
+		panic(fmt.Sprintf("migrate: %s migration from v%d has no Up func", m.Kind, m.From))
+	}
+	if want := len(migrations[m.Kind]); m.From != want {
+		panic(fmt.Sprintf("migrate: %s migration from v%d registered, want v%d", m.Kind, m.From, want))
+	}
+	migrations[m.Kind] = append(migrations[m.Kind], m)
+}
+
+// Latest returns the current schema version of kind, i.e. the number of
+// migrations registered for it.
+func Latest(kind string) int {
+	mu.RLock()
+	defer mu.RUnlock()
+	return len(migrations[kind])
+}
+
+// Pending returns the migrations needed to bring kind from version up to
+// the latest version.
+func Pending(kind string, version int) []Migration {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	all := migrations[kind]
+	if version < 0 || version >= len(all) {
+		return nil
+	}
+	return append([]Migration(nil), all[version:]...)
+}
+
+// Kinds returns all kinds with registered migrations, sorted.
+func Kinds() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	kinds := make([]string, 0, len(migrations))
+	for kind := range migrations {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// Result describes the schema state of a file before and after Upgrade.
+type Result struct {
+	Path    string
+	Kind    string
+	From    int    // version found on disk
+	To      int    // version after upgrading (== From if nothing was done)
+	Backup  string // path of the backup, empty if nothing was written
+	Missing bool   // file doesn't exist
+}
+
+// NeedsMigration reports whether the file is older than the latest version.
+func (r Result) NeedsMigration() bool {
+	return !r.Missing && r.From < Latest(r.Kind)
+}
+
+// Inspect reports the version of the file at path without modifying it.
+// Returns an error wrapping ErrNewerVersion if the file is newer than the
+// latest registered version of kind.
+func Inspect(path, kind string) (Result, error) {
+	res := Result{Path: path, Kind: kind}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			res.Missing = true
+			return res, nil
+		}
+		return res, err
+	}
+
+	version, err := readVersion(data)
+	if err != nil {
+		return res, fmt.Errorf("%s: %w", path, err)
+	}
+	res.From, res.To = version, version
+
+	if latest := Latest(kind); version > latest {
+		return res, fmt.Errorf("%s has schema version %d, this wt supports up to %d: %w", path, version, latest, ErrNewerVersion)
+	}
+	return res, nil
+}
+
+// Upgrade migrates the file at path to the latest version of kind.
+// The original contents are copied to a "<path>.v<N>-<timestamp>.bak"
+// backup first. Missing and up-to-date files are left alone.
+func Upgrade(path, kind string) (Result, error) {
+	res, err := Inspect(path, kind)
+	if err != nil || !res.NeedsMigration() {
+		return res, err
+	}
+
+	// Re-read under the lock: another process may have upgraded meanwhile
+	err = fs.WithLock(path, func() error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		var doc map[string]any
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if doc == nil {
+			doc = map[string]any{}
+		}
+
+		version, err := readVersion(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		res.From, res.To = version, version
+
+		pending := Pending(kind, version)
+		if len(pending) == 0 {
+			return nil
+		}
+
+		for _, m := range pending {
+			if err := m.Up(doc); err != nil {
+				return fmt.Errorf("migrate %s from v%d: %w", path, m.From, err)
+			}
+		}
+		res.To = version + len(pending)
+		doc["version"] = res.To
+
+		backup := fmt.Sprintf("%s.v%d-%s.bak", path, version, time.Now().Format("20060102-150405"))
+		if err := os.WriteFile(backup, data, 0o600); err != nil {
+			return fmt.Errorf("write backup: %w", err)
+		}
+		res.Backup = backup
+
+		return fs.SaveJSON(path, doc)
+	})
+	return res, err
+}
+
+// readVersion extracts the top-level "version" field; 0 if absent.
+func readVersion(data []byte) (int, error) {
+	var v struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return 0, err
+	}
+	return v.Version, nil
+}
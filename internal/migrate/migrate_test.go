@@ -0,0 +1,136 @@
+package migrate
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testKind is registered once for all tests in this file.
+const testKind = "migrate-test"
+
+func init() {
+	// v0 → v1: rename "name" to "title"
+	Register(Migration{Kind: testKind, From: 0, Description: "rename name", Up: func(doc map[string]any) error {
+		doc["title"] = doc["name"]
+		delete(doc, "name")
+		return nil
+	}})
+	// v1 → v2: wrap title in a list
+	Register(Migration{Kind: testKind, From: 1, Description: "list titles", Up: func(doc map[string]any) error {
+		doc["titles"] = []any{doc["title"]}
+		delete(doc, "title")
+		return nil
+	}})
+}
+
+func TestUpgrade_RunsPendingMigrations(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "data.json")
+	original := `{"name":"foo","keep":true}`
+	if err := os.WriteFile(path, []byte(original), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := Upgrade(path, testKind)
+	if err != nil {
+		t.Fatalf("Upgrade failed: %v", err)
+	}
+	if res.From != 0 || res.To != 2 {
+		t.Errorf("Upgrade = v%d → v%d, want v0 → v2", res.From, res.To)
+	}
+
+	var doc map[string]any
+	data, _ := os.ReadFile(path)
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc["version"] != float64(2) || doc["keep"] != true {
+		t.Errorf("upgraded doc = %v, want version 2 with unrelated fields kept", doc)
+	}
+	if titles, _ := doc["titles"].([]any); len(titles) != 1 || titles[0] != "foo" {
+		t.Errorf("titles = %v, want [foo]", doc["titles"])
+	}
+
+	backup, err := os.ReadFile(res.Backup)
+	if err != nil {
+		t.Fatalf("backup not written: %v", err)
+	}
+	if string(backup) != original {
+		t.Errorf("backup = %s, want %s", backup, original)
+	}
+
+	// Second run is a no-op
+	res, err = Upgrade(path, testKind)
+	if err != nil {
+		t.Fatalf("second Upgrade failed: %v", err)
+	}
+	if res.From != 2 || res.To != 2 || res.Backup != "" {
+		t.Errorf("second Upgrade = %+v, want no-op at v2", res)
+	}
+}
+
+func TestUpgrade_PartiallyMigrated(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "data.json")
+	if err := os.WriteFile(path, []byte(`{"version":1,"title":"bar"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := Upgrade(path, testKind)
+	if err != nil {
+		t.Fatalf("Upgrade failed: %v", err)
+	}
+	if res.From != 1 || res.To != 2 {
+		t.Errorf("Upgrade = v%d → v%d, want v1 → v2", res.From, res.To)
+	}
+}
+
+func TestUpgrade_NewerVersion(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "data.json")
+	contents := `{"version":9}`
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Upgrade(path, testKind); !errors.Is(err, ErrNewerVersion) {
+		t.Fatalf("Upgrade error = %v, want ErrNewerVersion", err)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != contents {
+		t.Errorf("file was modified: %s", data)
+	}
+}
+
+func TestUpgrade_MissingFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "missing.json")
+	res, err := Upgrade(path, testKind)
+	if err != nil {
+		t.Fatalf("Upgrade failed: %v", err)
+	}
+	if !res.Missing || res.NeedsMigration() {
+		t.Errorf("Upgrade = %+v, want missing and nothing to do", res)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Upgrade should not create missing files")
+	}
+}
+
+func TestRegister_Gap(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Register should panic on a version gap")
+		}
+	}()
+	Register(Migration{Kind: testKind, From: 5, Up: func(map[string]any) error { return nil }})
+}
@@ -0,0 +1,30 @@
+package prcache
+
+import (
+	"github.com/raphi011/wt/internal/migrate"
+)
+
+// SchemaKind identifies prs.json in the migration registry (see wt doctor).
+const SchemaKind = "prcache"
+
+func init() {
+	// v0 → v1: files written before versioning. The format is unchanged;
+	// a null PR map is normalized to an empty one.
+	migrate.Register(migrate.Migration{
+		Kind:        SchemaKind,
+		From:        0,
+		Description: "add schema version",
+		Up: func(doc map[string]any) error {
+			if doc["prs"] == nil {
+				doc["prs"] = map[string]any{}
+			}
+			return nil
+		},
+	})
+}
+
+// Migrate upgrades the PR cache at path to the current schema version,
+// keeping a backup of the old file.
+func Migrate(path string) (migrate.Result, error) {
+	return migrate.Upgrade(path, SchemaKind)
+}
@@ -1,14 +1,19 @@
 // Package prcache provides PR status caching stored in ~/.wt/prs.json.
 // PRs are stored independently of worktree entries, keyed by repoPath:branch,
 // allowing PR info to be cached before worktrees are created.
+// Entries carry their fetch time; [TTL] decides when they need refreshing.
 package prcache
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/raphi011/wt/internal/forge"
 	"github.com/raphi011/wt/internal/fs"
+	"github.com/raphi011/wt/internal/migrate"
 )
 
 // CacheKey returns the cache key for a worktree, namespaced by repo path.
@@ -17,10 +22,64 @@ func CacheKey(repoPath, branch string) string {
 	return repoPath + ":" + branch
 }
 
+// SplitKey splits a cache key into repo path and branch. Git branch names
+// cannot contain ':', so the last colon separates the two.
+func SplitKey(key string) (repoPath, branch string) {
+	i := strings.LastIndex(key, ":")
+	if i < 0 {
+		return key, ""
+	}
+	return key[:i], key[i+1:]
+}
+
+// TTL controls when cached entries are considered stale.
+// A zero duration disables expiry for that state. Merged entries never expire.
+type TTL struct {
+	Open   time.Duration // open/draft PRs and branches without a PR
+	Closed time.Duration // closed (unmerged) PRs
+}
+
+// Enabled reports whether any expiry is configured.
+func (t TTL) Enabled() bool {
+	return t.Open > 0 || t.Closed > 0
+}
+
+// IsStale reports whether pr should be refreshed at now.
+// Missing or never-fetched entries are always stale; merged entries never are.
+func (t TTL) IsStale(pr *forge.PRInfo, now time.Time) bool {
+	if pr == nil || !pr.Fetched {
+		return true
+	}
+
+	var ttl time.Duration
+	switch pr.State {
+	case forge.PRStateMerged:
+		return false
+	case forge.PRStateClosed:
+		ttl = t.Closed
+	default:
+		ttl = t.Open
+	}
+	if ttl <= 0 {
+		return false
+	}
+	return now.Sub(pr.CachedAt) > ttl
+}
+
 // Cache stores PR info keyed by repoPath:branch
 type Cache struct {
-	PRs   map[string]*forge.PRInfo `json:"prs"`
-	dirty bool
+	Version int                      `json:"version"` // schema version, see Migrate
+	PRs     map[string]*forge.PRInfo `json:"prs"`
+	dirty   bool
+	path    string // file the cache was loaded from; empty = Path()
+
+	// Changes since the cache was loaded. A loaded cache only writes these
+	// back on save, on top of the current file, so concurrent wt processes
+	// don't overwrite each other's entries.
+	loaded  bool
+	reset   bool
+	changed map[string]bool
+	deleted map[string]bool
 }
 
 // New returns an empty, initialized cache.
@@ -34,18 +93,28 @@ func Path() string {
 	return filepath.Join(home, ".wt", "prs.json")
 }
 
-// LoadFrom loads the PR cache from the given path. Returns an empty cache if
-// the file is missing or corrupted.
+// LoadFrom loads the PR cache from the given path, migrating files with an
+// older schema version first. Returns an empty cache if the file is missing,
+// corrupted or was written by a newer wt.
 func LoadFrom(path string) *Cache {
 	var cache Cache
-	if err := fs.LoadJSON(path, &cache); err != nil {
-		return New()
+	_, err := migrate.Upgrade(path, SchemaKind)
+	if err == nil {
+		err = fs.LoadJSON(path, &cache)
+	}
+	if err != nil {
+		c := New()
+		c.path = path
+		c.loaded = true
+		return c
 	}
 
 	// Initialize nil map
 	if cache.PRs == nil {
 		cache.PRs = make(map[string]*forge.PRInfo)
 	}
+	cache.path = path
+	cache.loaded = true
 
 	return &cache
 }
@@ -57,12 +126,57 @@ func Load() *Cache {
 }
 
 // SaveTo saves the PR cache to the given path atomically.
+// The file is locked and re-read first: for a loaded cache only the entries
+// set, deleted or reset since loading are applied to its current contents;
+// a cache created with New (or a literal) overlays all of its entries.
+// On success c.PRs holds the merged result.
 func (c *Cache) SaveTo(path string) error {
-	return fs.SaveJSON(path, c)
+	return fs.WithLock(path, func() error {
+		merged, err := c.mergeInto(path)
+		if err != nil {
+			return err
+		}
+		if err := fs.SaveJSON(path, &Cache{Version: migrate.Latest(SchemaKind), PRs: merged}); err != nil {
+			return err
+		}
+		c.PRs = merged
+		c.loaded = true
+		c.reset = false
+		c.changed = nil
+		c.deleted = nil
+		return nil
+	})
 }
 
-// Save saves the PR cache to disk atomically
+// mergeInto applies the cache's changes to the current contents of path.
+// Refuses to touch files written by a newer wt.
+func (c *Cache) mergeInto(path string) (map[string]*forge.PRInfo, error) {
+	var disk Cache
+	loadErr := fs.LoadJSON(path, &disk)
+	if latest := migrate.Latest(SchemaKind); loadErr == nil && disk.Version > latest {
+		return nil, fmt.Errorf("%s has schema version %d, this wt supports up to %d: %w", path, disk.Version, latest, migrate.ErrNewerVersion)
+	}
+	if c.reset || loadErr != nil || disk.PRs == nil {
+		disk.PRs = make(map[string]*forge.PRInfo)
+	}
+	for key := range c.deleted {
+		delete(disk.PRs, key)
+	}
+	for key, pr := range c.PRs {
+		if c.loaded && !c.reset && !c.changed[key] {
+			continue
+		}
+		disk.PRs[key] = pr
+	}
+	return disk.PRs, nil
+}
+
+// Save saves the PR cache atomically to the file it was loaded from
+// (or Path() for caches created with New).
 func (c *Cache) Save() error {
+	if c.path != "" {
+		return c.SaveTo(c.path)
+	}
 	return c.SaveTo(Path())
 }
 
@@ -70,6 +184,7 @@ func (c *Cache) Save() error {
 func (c *Cache) Set(key string, pr *forge.PRInfo) {
 	c.PRs[key] = pr
 	c.dirty = true
+	c.markChanged(key)
 }
 
 // Get returns PR info for a cache key, or nil if not found
@@ -81,12 +196,51 @@ func (c *Cache) Get(key string) *forge.PRInfo {
 func (c *Cache) Delete(key string) {
 	delete(c.PRs, key)
 	c.dirty = true
+	c.markDeleted(key)
+}
+
+// RemoveIf deletes all entries for which remove returns true and returns
+// the number of removed entries.
+func (c *Cache) RemoveIf(remove func(repoPath, branch string) bool) int {
+	removed := 0
+	for key := range c.PRs {
+		if remove(SplitKey(key)) {
+			delete(c.PRs, key)
+			c.markDeleted(key)
+			removed++
+		}
+	}
+	if removed > 0 {
+		c.dirty = true
+	}
+	return removed
 }
 
 // Reset clears all cached data
 func (c *Cache) Reset() {
 	c.PRs = make(map[string]*forge.PRInfo)
 	c.dirty = true
+	c.reset = true
+	c.changed = nil
+	c.deleted = nil
+}
+
+// markChanged records that key was set since the cache was loaded.
+func (c *Cache) markChanged(key string) {
+	if c.changed == nil {
+		c.changed = make(map[string]bool)
+	}
+	c.changed[key] = true
+	delete(c.deleted, key)
+}
+
+// markDeleted records that key was removed since the cache was loaded.
+func (c *Cache) markDeleted(key string) {
+	if c.deleted == nil {
+		c.deleted = make(map[string]bool)
+	}
+	c.deleted[key] = true
+	delete(c.changed, key)
 }
 
 // SaveIfDirty saves the cache to disk only if it has been modified.
@@ -3,6 +3,7 @@ package prcache
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 	"time"
 
@@ -234,6 +235,58 @@ func TestLoadSave(t *testing.T) {
 	}
 }
 
+func TestSaveMergesConcurrentChanges(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "prs.json")
+
+	seed := New()
+	seed.Set("/repo:main", &forge.PRInfo{Number: 1, Fetched: true})
+	seed.Set("/repo:old", &forge.PRInfo{Number: 2, Fetched: true})
+	if err := seed.SaveTo(path); err != nil {
+		t.Fatalf("SaveTo failed: %v", err)
+	}
+
+	// Two processes load the same file and change different entries
+	a := LoadFrom(path)
+	b := LoadFrom(path)
+
+	a.Set("/repo:feature", &forge.PRInfo{Number: 3, Fetched: true})
+	a.Delete("/repo:old")
+	if err := a.Save(); err != nil {
+		t.Fatalf("a.Save failed: %v", err)
+	}
+
+	b.Set("/repo:main", &forge.PRInfo{Number: 10, Fetched: true})
+	if err := b.Save(); err != nil {
+		t.Fatalf("b.Save failed: %v", err)
+	}
+
+	loaded := LoadFrom(path)
+	if got := loaded.Get("/repo:feature"); got == nil || got.Number != 3 {
+		t.Errorf("/repo:feature = %+v, want #3 from first writer", got)
+	}
+	if got := loaded.Get("/repo:main"); got == nil || got.Number != 10 {
+		t.Errorf("/repo:main = %+v, want #10 from second writer", got)
+	}
+	if loaded.Get("/repo:old") != nil {
+		t.Error("/repo:old should stay deleted")
+	}
+	if len(b.PRs) != 2 {
+		t.Errorf("b.PRs has %d entries after save, want merged 2", len(b.PRs))
+	}
+
+	// Reset discards everything, including entries saved by others
+	b.Reset()
+	b.Set("/repo:only", &forge.PRInfo{Number: 4, Fetched: true})
+	if err := b.Save(); err != nil {
+		t.Fatalf("b.Save after Reset failed: %v", err)
+	}
+	if loaded := LoadFrom(path); len(loaded.PRs) != 1 || loaded.Get("/repo:only") == nil {
+		t.Errorf("after Reset, PRs = %v, want only /repo:only", loaded.PRs)
+	}
+}
+
 func TestDirtyFlag(t *testing.T) {
 	t.Parallel()
 
@@ -356,11 +409,18 @@ func TestLoadFrom(t *testing.T) {
 		now := time.Now().Truncate(time.Second)
 		original := New()
 		original.Set("/repo:feat", &forge.PRInfo{
-			Number:   99,
-			State:    "MERGED",
-			Author:   "alice",
-			CachedAt: now,
-			Fetched:  true,
+			Number:          99,
+			State:           "MERGED",
+			Author:          "alice",
+			CachedAt:        now,
+			Fetched:         true,
+			Title:           "Add feature",
+			BaseBranch:      "main",
+			Checks:          forge.CheckStatePassing,
+			Mergeable:       forge.MergeStateMergeable,
+			ReviewRequested: []string{"bob"},
+			Labels:          []string{"bug", "ui"},
+			UpdatedAt:       now.Add(-time.Hour),
 		})
 
 		if err := original.SaveTo(path); err != nil {
@@ -384,6 +444,15 @@ func TestLoadFrom(t *testing.T) {
 		if !pr.CachedAt.Equal(now) {
 			t.Errorf("CachedAt = %v, want %v", pr.CachedAt, now)
 		}
+		if pr.Title != "Add feature" || pr.BaseBranch != "main" || pr.Checks != forge.CheckStatePassing || pr.Mergeable != forge.MergeStateMergeable {
+			t.Errorf("metadata not persisted: %+v", pr)
+		}
+		if !slices.Equal(pr.ReviewRequested, []string{"bob"}) || !slices.Equal(pr.Labels, []string{"bug", "ui"}) {
+			t.Errorf("ReviewRequested/Labels = %v/%v, want [bob]/[bug ui]", pr.ReviewRequested, pr.Labels)
+		}
+		if !pr.UpdatedAt.Equal(now.Add(-time.Hour)) {
+			t.Errorf("UpdatedAt = %v, want %v", pr.UpdatedAt, now.Add(-time.Hour))
+		}
 	})
 }
 
@@ -416,3 +485,98 @@ func TestSaveIfDirtyWhenDirty(t *testing.T) {
 		t.Error("saved cache should contain /repo:main entry")
 	}
 }
+
+func TestSplitKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		key        string
+		wantRepo   string
+		wantBranch string
+	}{
+		{"/path/to/repo:feature", "/path/to/repo", "feature"},
+		{"/repo:feature/sub/deep", "/repo", "feature/sub/deep"},
+		{"C:/repo:main", "C:/repo", "main"},
+		{"no-colon", "no-colon", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			t.Parallel()
+			repo, branch := SplitKey(tt.key)
+			if repo != tt.wantRepo || branch != tt.wantBranch {
+				t.Errorf("SplitKey(%q) = (%q, %q), want (%q, %q)", tt.key, repo, branch, tt.wantRepo, tt.wantBranch)
+			}
+		})
+	}
+}
+
+func TestTTLIsStale(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	ttl := TTL{Open: 15 * time.Minute, Closed: 24 * time.Hour}
+
+	tests := []struct {
+		name string
+		ttl  TTL
+		pr   *forge.PRInfo
+		want bool
+	}{
+		{"missing entry", ttl, nil, true},
+		{"not fetched", ttl, &forge.PRInfo{}, true},
+		{"fresh open", ttl, &forge.PRInfo{State: forge.PRStateOpen, Fetched: true, CachedAt: now.Add(-time.Minute)}, false},
+		{"expired open", ttl, &forge.PRInfo{State: forge.PRStateOpen, Fetched: true, CachedAt: now.Add(-time.Hour)}, true},
+		{"expired no PR", ttl, &forge.PRInfo{Fetched: true, CachedAt: now.Add(-time.Hour)}, true},
+		{"closed uses closed ttl", ttl, &forge.PRInfo{State: forge.PRStateClosed, Fetched: true, CachedAt: now.Add(-time.Hour)}, false},
+		{"expired closed", ttl, &forge.PRInfo{State: forge.PRStateClosed, Fetched: true, CachedAt: now.Add(-48 * time.Hour)}, true},
+		{"merged is sticky", ttl, &forge.PRInfo{State: forge.PRStateMerged, Fetched: true, CachedAt: now.Add(-365 * 24 * time.Hour)}, false},
+		{"zero ttl never expires", TTL{}, &forge.PRInfo{State: forge.PRStateOpen, Fetched: true, CachedAt: now.Add(-time.Hour)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.ttl.IsStale(tt.pr, now); got != tt.want {
+				t.Errorf("IsStale() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if (TTL{}).Enabled() {
+		t.Error("zero TTL should not be enabled")
+	}
+	if !(TTL{Closed: time.Hour}).Enabled() {
+		t.Error("TTL with closed duration should be enabled")
+	}
+}
+
+func TestRemoveIf(t *testing.T) {
+	t.Parallel()
+
+	c := New()
+	c.PRs["/repo/a:main"] = &forge.PRInfo{Number: 1}
+	c.PRs["/repo/a:gone"] = &forge.PRInfo{Number: 2}
+	c.PRs["/repo/b:main"] = &forge.PRInfo{Number: 3}
+
+	removed := c.RemoveIf(func(repoPath, branch string) bool {
+		return repoPath == "/repo/b" || branch == "gone"
+	})
+	if removed != 2 {
+		t.Errorf("RemoveIf() = %d, want 2", removed)
+	}
+	if c.Get("/repo/a:main") == nil {
+		t.Error("/repo/a:main should be kept")
+	}
+	if len(c.PRs) != 1 {
+		t.Errorf("expected 1 remaining entry, got %d", len(c.PRs))
+	}
+	if !c.dirty {
+		t.Error("expected dirty=true after removing entries")
+	}
+
+	c.dirty = false
+	if c.RemoveIf(func(string, string) bool { return false }) != 0 || c.dirty {
+		t.Error("removing nothing should not mark the cache dirty")
+	}
+}
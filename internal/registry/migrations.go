@@ -0,0 +1,37 @@
+package registry
+
+import (
+	"github.com/raphi011/wt/internal/migrate"
+)
+
+// SchemaKind identifies repos.json in the migration registry (see wt doctor).
+const SchemaKind = "registry"
+
+func init() {
+	// v0 → v1: files written before versioning. The format is unchanged;
+	// a null repo list is normalized to an empty one.
+	migrate.Register(migrate.Migration{
+		Kind:        SchemaKind,
+		From:        0,
+		Description: "add schema version",
+		Up: func(doc map[string]any) error {
+			if doc["repos"] == nil {
+				doc["repos"] = []any{}
+			}
+			return nil
+		},
+	})
+}
+
+// Migrate upgrades the registry at path (or ~/.wt/repos.json if empty)
+// to the current schema version, keeping a backup of the old file.
+func Migrate(path string) (migrate.Result, error) {
+	if path == "" {
+		var err error
+		path, err = registryPath()
+		if err != nil {
+			return migrate.Result{}, err
+		}
+	}
+	return migrate.Upgrade(path, SchemaKind)
+}
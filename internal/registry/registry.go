@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/raphi011/wt/internal/fs"
+	"github.com/raphi011/wt/internal/migrate"
 )
 
 // Repo represents a registered git repository
@@ -21,7 +22,8 @@ type Repo struct {
 
 // Registry holds all registered repos
 type Registry struct {
-	Repos []Repo `json:"repos"`
+	Version int    `json:"version"` // schema version, see Migrate
+	Repos   []Repo `json:"repos"`
 }
 
 // registryPath returns the path to ~/.wt/repos.json
@@ -35,6 +37,7 @@ func registryPath() (string, error) {
 
 // Load reads the registry from the specified path, or ~/.wt/repos.json if empty.
 // Returns empty registry if file doesn't exist (auto-creates ~/.wt/).
+// Files with an older schema version are migrated first.
 func Load(path string) (*Registry, error) {
 	if path == "" {
 		var err error
@@ -44,6 +47,10 @@ func Load(path string) (*Registry, error) {
 		}
 	}
 
+	if _, err := migrate.Upgrade(path, SchemaKind); err != nil {
+		return nil, fmt.Errorf("load registry: %w", err)
+	}
+
 	var reg Registry
 	if err := fs.LoadJSON(path, &reg); err != nil {
 		if os.IsNotExist(err) {
@@ -65,13 +72,48 @@ func (r *Registry) Save(path string) error {
 		}
 	}
 
-	if err := fs.SaveJSON(path, r); err != nil {
+	r.Version = migrate.Latest(SchemaKind)
+	if err := fs.WithLock(path, func() error { return fs.SaveJSON(path, r) }); err != nil {
 		return fmt.Errorf("save registry: %w", err)
 	}
 
 	return nil
 }
 
+// Transact performs a locked read-modify-write of the registry at path
+// (or ~/.wt/repos.json if empty): it reloads the registry under the lock,
+// applies fn and saves the result. Use it instead of Load+Save when other
+// wt processes may modify the registry concurrently. Nothing is written
+// if fn returns an error.
+func Transact(path string, fn func(*Registry) error) error {
+	if path == "" {
+		var err error
+		path, err = registryPath()
+		if err != nil {
+			return err
+		}
+	}
+
+	if _, err := migrate.Upgrade(path, SchemaKind); err != nil {
+		return fmt.Errorf("update registry: %w", err)
+	}
+
+	reg := &Registry{Repos: []Repo{}}
+	var fnErr error
+	err := fs.UpdateJSON(path, reg, func() error {
+		fnErr = fn(reg)
+		reg.Version = migrate.Latest(SchemaKind)
+		return fnErr
+	})
+	if fnErr != nil {
+		return fnErr
+	}
+	if err != nil {
+		return fmt.Errorf("update registry: %w", err)
+	}
+	return nil
+}
+
 // Add registers a new repo. Returns error if path already registered.
 func (r *Registry) Add(repo Repo) error {
 	// Normalize path: absolute + symlink resolution so the registry
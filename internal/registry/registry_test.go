@@ -1,8 +1,10 @@
 package registry
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 )
 
@@ -353,6 +355,90 @@ func TestRegistrySaveLoad(t *testing.T) {
 	}
 }
 
+func TestTransact_ConcurrentAdds(t *testing.T) {
+	t.Parallel()
+
+	regPath := filepath.Join(t.TempDir(), "repos.json")
+
+	// Simulates many wt processes registering repos at the same time
+	const workers = 20
+	var wg sync.WaitGroup
+	for i := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := Transact(regPath, func(r *Registry) error {
+				return r.Add(Repo{Name: fmt.Sprintf("repo%d", i), Path: fmt.Sprintf("/tmp/repo%d", i)})
+			})
+			if err != nil {
+				t.Errorf("Transact failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	loaded, err := Load(regPath)
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if len(loaded.Repos) != workers {
+		t.Errorf("expected %d repos, got %d (lost updates)", workers, len(loaded.Repos))
+	}
+}
+
+func TestTransact_ErrorLeavesFileUnchanged(t *testing.T) {
+	t.Parallel()
+
+	regPath := filepath.Join(t.TempDir(), "repos.json")
+	reg := &Registry{Repos: []Repo{{Name: "foo", Path: "/tmp/foo"}}}
+	if err := reg.Save(regPath); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	err := Transact(regPath, func(r *Registry) error {
+		if err := r.Add(Repo{Name: "bar", Path: "/tmp/bar"}); err != nil {
+			return err
+		}
+		return r.Add(Repo{Name: "foo", Path: "/tmp/other"})
+	})
+	if err == nil {
+		t.Fatal("expected duplicate name error")
+	}
+
+	loaded, err := Load(regPath)
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if len(loaded.Repos) != 1 {
+		t.Errorf("expected 1 repo after failed transaction, got %d", len(loaded.Repos))
+	}
+}
+
+func TestLoad_MigratesUnversioned(t *testing.T) {
+	t.Parallel()
+
+	regPath := filepath.Join(t.TempDir(), "repos.json")
+	v0 := `{"repos":[{"path":"/tmp/foo","name":"foo","worktree_format":"{branch}","labels":["backend"]}]}`
+	if err := os.WriteFile(regPath, []byte(v0), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	reg, err := Load(regPath)
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if reg.Version != 1 {
+		t.Errorf("Version = %d, want 1", reg.Version)
+	}
+	repo, err := reg.FindByName("foo")
+	if err != nil {
+		t.Fatalf("FindByName failed: %v", err)
+	}
+	if repo.WorktreeFormat != "{branch}" || !repo.HasLabel("backend") {
+		t.Errorf("migration lost repo settings: %+v", repo)
+	}
+}
+
 func TestAllLabels(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,350 @@
+// Package trash keeps removed worktrees and repositories recoverable.
+//
+// Instead of deleting a directory, it is moved into <trash>/<timestamp>/files
+// next to a manifest.json that records where it came from and how to restore
+// it (repo, branch, HEAD commit, note, whether the branch was deleted).
+// The HEAD commit of a trashed worktree is pinned by refs/wt-trash/<id> in
+// its repo, so unpushed commits survive garbage collection after the branch
+// is deleted. The trash directory is ~/.wt/trash by default.
+package trash
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/raphi011/wt/internal/fs"
+	"github.com/raphi011/wt/internal/git"
+	"github.com/raphi011/wt/internal/registry"
+)
+
+const (
+	manifestFile = "manifest.json"
+	filesDir     = "files"
+	indexFile    = "index"
+
+	// idFormat is the timestamp layout used for entry directory names.
+	idFormat = "20060102-150405"
+
+	// refPrefix is the namespace of the refs pinning trashed commits.
+	refPrefix = "refs/wt-trash/"
+)
+
+// Kind is the type of a trashed item.
+type Kind string
+
+// Kind constants
+const (
+	KindWorktree Kind = "worktree" // a pruned worktree
+	KindRepo     Kind = "repo"     // a repository removed with `wt repo remove -D`
+)
+
+// Manifest describes a trashed directory and how to restore it.
+type Manifest struct {
+	Kind          Kind           `json:"kind"`
+	Command       string         `json:"command"` // command that trashed it, e.g. "prune"
+	TrashedAt     time.Time      `json:"trashed_at"`
+	Path          string         `json:"path"` // original location
+	RepoPath      string         `json:"repo_path"`
+	RepoName      string         `json:"repo_name"`
+	Branch        string         `json:"branch,omitempty"`   // empty for detached worktrees
+	HeadSHA       string         `json:"head_sha,omitempty"` // HEAD commit at removal, pinned by Ref
+	Note          string         `json:"note,omitempty"`     // branch.<name>.description
+	BranchDeleted bool           `json:"branch_deleted"`
+	Repo          *registry.Repo `json:"repo,omitempty"` // registry entry of a removed repo
+}
+
+// Entry is a single item in the trash.
+type Entry struct {
+	Manifest
+	ID  string `json:"id"`  // directory name (timestamp)
+	Dir string `json:"dir"` // absolute entry directory
+}
+
+// FilesPath returns the directory holding the trashed files.
+func (e *Entry) FilesPath() string {
+	return filepath.Join(e.Dir, filesDir)
+}
+
+// IndexPath returns the path of the saved git index, if any.
+func (e *Entry) IndexPath() string {
+	return filepath.Join(e.Dir, indexFile)
+}
+
+// HasIndex reports whether a git index was saved with the entry.
+func (e *Entry) HasIndex() bool {
+	_, err := os.Stat(e.IndexPath())
+	return err == nil
+}
+
+// Ref returns the ref that pins the HEAD commit of a trashed worktree in
+// its repo, or "" if the entry has no commit to pin.
+func (e *Entry) Ref() string {
+	if e.Kind != KindWorktree || e.HeadSHA == "" {
+		return ""
+	}
+	return refPrefix + e.ID
+}
+
+// Save rewrites the entry's manifest.
+func (e *Entry) Save() error {
+	return fs.SaveJSON(filepath.Join(e.Dir, manifestFile), e.Manifest)
+}
+
+// SaveIndex copies the git index at src into the entry, so staged changes
+// survive a restore. A missing src is not an error.
+func (e *Entry) SaveIndex(src string) error {
+	if _, err := os.Lstat(src); os.IsNotExist(err) {
+		return nil
+	}
+	return copyFile(src, e.IndexPath(), 0o644)
+}
+
+// RestoreIndex copies the saved git index to dst.
+func (e *Entry) RestoreIndex(dst string) error {
+	return copyFile(e.IndexPath(), dst, 0o644)
+}
+
+// Delete permanently removes the entry and the ref pinning its commit.
+// The ref is left alone if the repo no longer exists.
+func (e *Entry) Delete(ctx context.Context) error {
+	if err := os.RemoveAll(e.Dir); err != nil {
+		return err
+	}
+	ref := e.Ref()
+	if ref == "" {
+		return nil
+	}
+	if _, err := os.Stat(e.RepoPath); err != nil {
+		return nil
+	}
+	return git.DeleteRef(ctx, e.RepoPath, ref)
+}
+
+// RestoreFiles moves the trashed files to dst. If dst does not exist, the
+// whole directory is moved; otherwise each top-level entry is moved into dst,
+// except names in skip. Fails if an entry already exists in dst.
+func (e *Entry) RestoreFiles(dst string, skip ...string) error {
+	src := e.FilesPath()
+	if _, err := os.Lstat(dst); os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+			return err
+		}
+		return moveDir(src, dst)
+	}
+
+	children, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	skipped := make(map[string]bool, len(skip))
+	for _, name := range skip {
+		skipped[name] = true
+	}
+	for _, c := range children {
+		if skipped[c.Name()] {
+			continue
+		}
+		target := filepath.Join(dst, c.Name())
+		if _, err := os.Lstat(target); err == nil {
+			return fmt.Errorf("cannot restore %s: already exists", target)
+		}
+		if err := moveDir(filepath.Join(src, c.Name()), target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Put moves the directory at m.Path into a new entry under trashDir and
+// writes its manifest. The HEAD commit of a worktree is pinned (see
+// [Entry.Ref]) before anything is moved. TrashedAt is set to the current
+// time if zero.
+func Put(ctx context.Context, trashDir string, m Manifest) (*Entry, error) {
+	if m.TrashedAt.IsZero() {
+		m.TrashedAt = time.Now()
+	}
+
+	if err := os.MkdirAll(trashDir, 0o755); err != nil {
+		return nil, fmt.Errorf("create trash directory: %w", err)
+	}
+
+	// Claim a unique directory: timestamp, with a numeric suffix on collision
+	base := m.TrashedAt.Format(idFormat)
+	id := base
+	for i := 2; ; i++ {
+		err := os.Mkdir(filepath.Join(trashDir, id), 0o755)
+		if err == nil {
+			break
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("create trash entry: %w", err)
+		}
+		id = base + "-" + strconv.Itoa(i)
+	}
+
+	e := &Entry{Manifest: m, ID: id, Dir: filepath.Join(trashDir, id)}
+	if err := e.Save(); err != nil {
+		os.RemoveAll(e.Dir)
+		return nil, fmt.Errorf("write trash manifest: %w", err)
+	}
+	if ref := e.Ref(); ref != "" {
+		if err := git.UpdateRef(ctx, m.RepoPath, ref, m.HeadSHA); err != nil {
+			os.RemoveAll(e.Dir)
+			return nil, fmt.Errorf("pin commit of %s: %w", m.Path, err)
+		}
+	}
+	if err := moveDir(m.Path, e.FilesPath()); err != nil {
+		e.Delete(ctx)
+		return nil, fmt.Errorf("move %s to trash: %w", m.Path, err)
+	}
+	return e, nil
+}
+
+// List returns all entries in trashDir, newest first.
+// Directories without a readable manifest are skipped.
+func List(trashDir string) ([]Entry, error) {
+	dirs, err := os.ReadDir(trashDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		e, err := load(trashDir, d.Name())
+		if err != nil {
+			continue
+		}
+		entries = append(entries, *e)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].TrashedAt.Equal(entries[j].TrashedAt) {
+			return entries[i].TrashedAt.After(entries[j].TrashedAt)
+		}
+		return entries[i].ID > entries[j].ID
+	})
+	return entries, nil
+}
+
+// Get returns the entry with the given ID.
+func Get(trashDir, id string) (*Entry, error) {
+	if id == "" || filepath.Base(id) != id {
+		return nil, fmt.Errorf("invalid trash entry %q", id)
+	}
+	e, err := load(trashDir, id)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("trash entry %q not found", id)
+		}
+		return nil, err
+	}
+	return e, nil
+}
+
+// Empty permanently deletes entries trashed more than olderThan before now
+// (all entries if olderThan is 0), including the refs pinning their commits.
+// Returns the number of deleted entries.
+func Empty(ctx context.Context, trashDir string, olderThan time.Duration, now time.Time) (int, error) {
+	entries, err := List(trashDir)
+	if err != nil {
+		return 0, err
+	}
+	deleted := 0
+	for _, e := range entries {
+		if olderThan > 0 && now.Sub(e.TrashedAt) < olderThan {
+			continue
+		}
+		if err := e.Delete(ctx); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// load reads the manifest of entry id.
+func load(trashDir, id string) (*Entry, error) {
+	e := &Entry{ID: id, Dir: filepath.Join(trashDir, id)}
+	if err := fs.LoadJSON(filepath.Join(e.Dir, manifestFile), &e.Manifest); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// moveDir renames src to dst, falling back to copy and delete when they are
+// on different filesystems.
+func moveDir(src, dst string) error {
+	err := os.Rename(src, dst)
+	if err == nil || !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+	if err := copyTree(src, dst); err != nil {
+		os.RemoveAll(dst)
+		return err
+	}
+	return os.RemoveAll(src)
+}
+
+// copyTree recursively copies src to dst, preserving file modes and symlinks.
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		default:
+			return copyFile(path, target, info.Mode().Perm())
+		}
+	})
+}
+
+// copyFile copies a regular file.
+func copyFile(src, dst string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,172 @@
+package trash
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeTree creates dir with a file, a nested file and a .git file.
+func writeTree(t *testing.T, dir string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{
+		"a.txt":     "a\n",
+		"sub/b.txt": "b\n",
+		".git":      "gitdir: /nowhere\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestPutListGet(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	trashDir := filepath.Join(tmp, "trash")
+	wtPath := filepath.Join(tmp, "repo-feature")
+	writeTree(t, wtPath)
+
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.Local)
+	e, err := Put(context.Background(), trashDir, Manifest{Kind: KindWorktree, Path: wtPath, Branch: "feature", TrashedAt: at})
+	if err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if e.ID != "20260102-030405" {
+		t.Errorf("ID = %q, want timestamp", e.ID)
+	}
+	if _, err := os.Stat(wtPath); !os.IsNotExist(err) {
+		t.Error("original path should be gone after Put")
+	}
+	if _, err := os.Stat(filepath.Join(e.FilesPath(), "sub", "b.txt")); err != nil {
+		t.Errorf("trashed files missing: %v", err)
+	}
+
+	// Same timestamp gets a suffix
+	other := filepath.Join(tmp, "repo-other")
+	writeTree(t, other)
+	e2, err := Put(context.Background(), trashDir, Manifest{Kind: KindWorktree, Path: other, Branch: "other", TrashedAt: at})
+	if err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if e2.ID != "20260102-030405-2" {
+		t.Errorf("colliding ID = %q, want suffix -2", e2.ID)
+	}
+
+	entries, err := List(trashDir)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(entries) != 2 || entries[0].ID != e2.ID {
+		t.Fatalf("List() = %+v, want newest (%s) first", entries, e2.ID)
+	}
+
+	got, err := Get(trashDir, e.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Branch != "feature" || got.Path != wtPath {
+		t.Errorf("Get() manifest = %+v", got.Manifest)
+	}
+
+	if _, err := Get(trashDir, "../escape"); err == nil {
+		t.Error("Get() should reject path traversal")
+	}
+	if _, err := Get(trashDir, "missing"); err == nil {
+		t.Error("Get() should fail for unknown entries")
+	}
+}
+
+func TestList_NoTrashDir(t *testing.T) {
+	t.Parallel()
+
+	entries, err := List(filepath.Join(t.TempDir(), "missing"))
+	if err != nil || entries != nil {
+		t.Errorf("List() = %v, %v; want nil, nil", entries, err)
+	}
+}
+
+func TestRestoreFiles(t *testing.T) {
+	t.Parallel()
+
+	t.Run("missing destination", func(t *testing.T) {
+		t.Parallel()
+		tmp := t.TempDir()
+		src := filepath.Join(tmp, "wt")
+		writeTree(t, src)
+		e, err := Put(context.Background(), filepath.Join(tmp, "trash"), Manifest{Path: src})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := e.RestoreFiles(src); err != nil {
+			t.Fatalf("RestoreFiles() error = %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(src, ".git")); err != nil {
+			t.Errorf("restored tree should be complete: %v", err)
+		}
+	})
+
+	t.Run("existing destination skips names", func(t *testing.T) {
+		t.Parallel()
+		tmp := t.TempDir()
+		src := filepath.Join(tmp, "wt")
+		writeTree(t, src)
+		e, err := Put(context.Background(), filepath.Join(tmp, "trash"), Manifest{Path: src})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Destination re-created with a fresh .git file
+		if err := os.MkdirAll(src, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(src, ".git"), []byte("gitdir: /new\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := e.RestoreFiles(src, ".git"); err != nil {
+			t.Fatalf("RestoreFiles() error = %v", err)
+		}
+		data, _ := os.ReadFile(filepath.Join(src, ".git"))
+		if string(data) != "gitdir: /new\n" {
+			t.Errorf(".git should not be overwritten, got %q", data)
+		}
+		if _, err := os.Stat(filepath.Join(src, "sub", "b.txt")); err != nil {
+			t.Errorf("nested file should be restored: %v", err)
+		}
+	})
+}
+
+func TestEmpty(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	trashDir := filepath.Join(tmp, "trash")
+	now := time.Now()
+
+	for i, age := range []time.Duration{time.Hour, 48 * time.Hour} {
+		dir := filepath.Join(tmp, "wt", string(rune('a'+i)))
+		writeTree(t, dir)
+		if _, err := Put(context.Background(), trashDir, Manifest{Path: dir, TrashedAt: now.Add(-age)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	n, err := Empty(context.Background(), trashDir, 24*time.Hour, now)
+	if err != nil || n != 1 {
+		t.Fatalf("Empty(24h) = %d, %v; want 1", n, err)
+	}
+	n, err = Empty(context.Background(), trashDir, 0, now)
+	if err != nil || n != 1 {
+		t.Fatalf("Empty(0) = %d, %v; want 1", n, err)
+	}
+	if entries, _ := List(trashDir); len(entries) != 0 {
+		t.Errorf("trash should be empty, got %d entries", len(entries))
+	}
+}
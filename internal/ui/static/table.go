@@ -6,18 +6,21 @@
 package static
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
 	"charm.land/lipgloss/v2"
 	"charm.land/lipgloss/v2/table"
 
+	"github.com/raphi011/wt/internal/forge"
 	"github.com/raphi011/wt/internal/git"
 	"github.com/raphi011/wt/internal/ui/styles"
 )
 
 // WorktreeTableHeaders are the column headers for worktree tables used by list and prune.
-var WorktreeTableHeaders = []string{"REPO", "BRANCH", "COMMIT", "AGE", "PR", "NOTE"}
+var WorktreeTableHeaders = []string{"REPO", "BRANCH", "COMMIT", "AGE", "PR", "CHECKS", "NOTE"}
 
 // WorktreeTableRow formats a git.Worktree as a table row matching WorktreeTableHeaders.
 // staleDays controls stale highlighting: if > 0 and the commit is older than staleDays,
@@ -35,7 +38,112 @@ func WorktreeTableRow(wt git.Worktree, staleDays int) []string {
 		age = styles.WarningStyle.Render(age)
 	}
 
-	return []string{wt.RepoName, wt.Branch, commit, age, pr, wt.Note}
+	return []string{wt.RepoName, wt.Branch, commit, age, pr, styles.FormatChecks(wt.PRChecks), wt.Note}
+}
+
+// PruneTableHeaders are the column headers for the prune results table.
+// Same as WorktreeTableHeaders with REASON in place of CHECKS (CI status
+// doesn't matter for worktrees being removed).
+var PruneTableHeaders = []string{"REPO", "BRANCH", "COMMIT", "AGE", "PR", "REASON", "NOTE"}
+
+// PruneTableRow formats a git.Worktree as a table row matching PruneTableHeaders.
+// reason explains why the worktree is pruned (e.g. "pr", "squash", "stale").
+func PruneTableRow(wt git.Worktree, staleDays int, reason string) []string {
+	row := WorktreeTableRow(wt, staleDays)
+	return append(row[:5:5], reason, row[6])
+}
+
+// PRListTableHeaders are the column headers for the open PRs table of pr list.
+var PRListTableHeaders = []string{"REPO", "PR", "TITLE", "AUTHOR", "BRANCH", "LABELS", "WORKTREE"}
+
+// prListTitleWidth is the width at which PR titles are truncated.
+const prListTitleWidth = 60
+
+// PRListTableRow formats an open PR of repoName as a table row matching
+// PRListTableHeaders. hasWorktree marks PRs whose branch is checked out.
+func PRListTableRow(repoName string, pr forge.OpenPR, hasWorktree bool) []string {
+	title := pr.Title
+	if runes := []rune(title); len(runes) > prListTitleWidth {
+		title = string(runes[:prListTitleWidth-1]) + "…"
+	}
+	if pr.IsDraft {
+		title += styles.MutedStyle.Render(" (draft)")
+	}
+
+	worktree := ""
+	if hasWorktree {
+		worktree = "✓"
+	}
+
+	return []string{
+		repoName,
+		styles.FormatPRRef(pr.Number, forge.PRStateOpen, pr.IsDraft, pr.URL),
+		title,
+		pr.Author,
+		pr.Branch,
+		strings.Join(pr.Labels, ", "),
+		worktree,
+	}
+}
+
+// StatusTableHeaders are the column headers for the status dashboard.
+var StatusTableHeaders = []string{"REPO", "BRANCH", "STATE", "CHANGES", "UPSTREAM", "DEFAULT", "STASH", "PR"}
+
+// StatusTableRow formats a git.WorktreeStatus as a table row matching StatusTableHeaders.
+// CHANGES lists staged (+), unstaged (~), untracked (?) and conflicted (!) counts.
+// UPSTREAM and DEFAULT show commits ahead (↑) and behind (↓) the upstream
+// branch and origin/<default>.
+func StatusTableRow(st git.WorktreeStatus) []string {
+	state := "clean"
+	switch {
+	case st.Operation != "":
+		state = styles.ErrorStyle.Render(st.Operation)
+	case st.Dirty:
+		state = styles.WarningStyle.Render("dirty")
+	}
+
+	var changes []string
+	for _, c := range []struct {
+		prefix string
+		n      int
+	}{{"+", st.Staged}, {"~", st.Unstaged}, {"?", st.Untracked}, {"!", st.Conflicted}} {
+		if c.n > 0 {
+			changes = append(changes, fmt.Sprintf("%s%d", c.prefix, c.n))
+		}
+	}
+
+	upstream := "-"
+	if st.Upstream != "" {
+		upstream = formatAheadBehind(st.Ahead, st.Behind)
+	}
+	defaultBranch := "-"
+	if st.DefaultBranch != "" {
+		defaultBranch = formatAheadBehind(st.DefaultAhead, st.DefaultBehind)
+	}
+
+	stash := ""
+	if st.Stashes > 0 {
+		stash = strconv.Itoa(st.Stashes)
+	}
+
+	pr := styles.FormatPRRef(st.PRNumber, st.PRState, st.PRDraft, st.PRURL)
+
+	return []string{st.RepoName, st.Branch, state, strings.Join(changes, " "), upstream, defaultBranch, stash, pr}
+}
+
+// formatAheadBehind formats ahead/behind counts as "↑2 ↓1", or "=" when in sync.
+func formatAheadBehind(ahead, behind int) string {
+	var parts []string
+	if ahead > 0 {
+		parts = append(parts, fmt.Sprintf("↑%d", ahead))
+	}
+	if behind > 0 {
+		parts = append(parts, fmt.Sprintf("↓%d", behind))
+	}
+	if len(parts) == 0 {
+		return "="
+	}
+	return strings.Join(parts, " ")
 }
 
 // RenderTable creates a formatted table with proper column alignment.
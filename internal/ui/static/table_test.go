@@ -21,13 +21,14 @@ func TestWorktreeTableRow(t *testing.T) {
 		PRNumber:   99,
 		PRState:    forge.PRStateOpen,
 		PRURL:      "https://github.com/org/repo/pull/99",
+		PRChecks:   forge.CheckStateFailing,
 	}
 
 	row := WorktreeTableRow(wt, 0)
 
-	// Must have exactly 6 columns matching headers: REPO, BRANCH, COMMIT, AGE, PR, NOTE
-	if len(row) != 6 {
-		t.Fatalf("expected 6 columns, got %d", len(row))
+	// Must have exactly 7 columns matching headers: REPO, BRANCH, COMMIT, AGE, PR, CHECKS, NOTE
+	if len(row) != 7 {
+		t.Fatalf("expected 7 columns, got %d", len(row))
 	}
 
 	if row[0] != "my-repo" {
@@ -46,8 +47,11 @@ func TestWorktreeTableRow(t *testing.T) {
 	if row[4] == "" {
 		t.Error("column 4 (PR) should not be empty for PRNumber > 0")
 	}
-	if row[5] != "wip" {
-		t.Errorf("column 5 (NOTE) = %q, want %q", row[5], "wip")
+	if !strings.Contains(row[5], "Failing") {
+		t.Errorf("column 5 (CHECKS) = %q, want failing checks", row[5])
+	}
+	if row[6] != "wip" {
+		t.Errorf("column 6 (NOTE) = %q, want %q", row[6], "wip")
 	}
 }
 
@@ -169,3 +173,105 @@ func TestRenderTableMultipleRows(t *testing.T) {
 		}
 	}
 }
+
+func TestPruneTableRow(t *testing.T) {
+	t.Parallel()
+
+	wt := git.Worktree{
+		RepoName:    "my-repo",
+		Branch:      "feature-x",
+		CommitHash:  "abc1234def5678",
+		CommitAge:   "3 hours ago",
+		Note:        "wip",
+		MergeReason: git.MergeReasonSquash,
+	}
+
+	row := PruneTableRow(wt, 0, string(wt.MergeReason))
+
+	if len(row) != len(PruneTableHeaders) {
+		t.Fatalf("expected %d columns, got %d", len(PruneTableHeaders), len(row))
+	}
+	if row[5] != "squash" {
+		t.Errorf("column 5 (REASON) = %q, want %q", row[5], "squash")
+	}
+	if row[6] != "wip" {
+		t.Errorf("column 6 (NOTE) = %q, want %q", row[6], "wip")
+	}
+}
+
+func TestPRListTableRow(t *testing.T) {
+	t.Parallel()
+
+	pr := forge.OpenPR{
+		Number:  12,
+		Title:   strings.Repeat("x", 70),
+		Author:  "alice",
+		Branch:  "feature-x",
+		IsDraft: true,
+		Labels:  []string{"bug", "p1"},
+	}
+
+	row := PRListTableRow("my-repo", pr, true)
+	if len(row) != len(PRListTableHeaders) {
+		t.Fatalf("expected %d columns, got %d", len(PRListTableHeaders), len(row))
+	}
+	if row[0] != "my-repo" || row[3] != "alice" || row[4] != "feature-x" {
+		t.Errorf("unexpected row %q", row)
+	}
+	if !strings.Contains(row[1], "#12") {
+		t.Errorf("column 1 (PR) = %q, want #12", row[1])
+	}
+	if !strings.HasPrefix(row[2], strings.Repeat("x", 59)+"…") || !strings.Contains(row[2], "(draft)") {
+		t.Errorf("column 2 (TITLE) = %q, want truncated draft title", row[2])
+	}
+	if row[5] != "bug, p1" {
+		t.Errorf("column 5 (LABELS) = %q, want %q", row[5], "bug, p1")
+	}
+	if row[6] == "" {
+		t.Error("column 6 (WORKTREE) should mark checked out PRs")
+	}
+
+	if row := PRListTableRow("my-repo", forge.OpenPR{Number: 1, Title: "Fix"}, false); row[2] != "Fix" || row[6] != "" {
+		t.Errorf("unexpected row %q", row)
+	}
+}
+
+func TestStatusTableRow(t *testing.T) {
+	t.Parallel()
+
+	st := git.WorktreeStatus{
+		Worktree:      git.Worktree{RepoName: "my-repo", Branch: "feature-x"},
+		Staged:        2,
+		Untracked:     1,
+		Upstream:      "origin/feature-x",
+		Ahead:         3,
+		DefaultBranch: "main",
+		DefaultAhead:  3,
+		DefaultBehind: 5,
+		Stashes:       1,
+	}
+
+	row := StatusTableRow(st)
+
+	if len(row) != len(StatusTableHeaders) {
+		t.Fatalf("expected %d columns, got %d", len(StatusTableHeaders), len(row))
+	}
+	if row[3] != "+2 ?1" {
+		t.Errorf("column 3 (CHANGES) = %q, want %q", row[3], "+2 ?1")
+	}
+	if row[4] != "↑3" {
+		t.Errorf("column 4 (UPSTREAM) = %q, want %q", row[4], "↑3")
+	}
+	if row[5] != "↑3 ↓5" {
+		t.Errorf("column 5 (DEFAULT) = %q, want %q", row[5], "↑3 ↓5")
+	}
+	if row[6] != "1" {
+		t.Errorf("column 6 (STASH) = %q, want %q", row[6], "1")
+	}
+
+	// No upstream and in sync with default
+	row = StatusTableRow(git.WorktreeStatus{DefaultBranch: "main"})
+	if row[2] != "clean" || row[4] != "-" || row[5] != "=" {
+		t.Errorf("clean row = %q, want STATE=clean UPSTREAM=- DEFAULT==", row)
+	}
+}
@@ -14,6 +14,10 @@ type Symbols struct {
 	PROpen   string
 	PRClosed string
 	PRDraft  string
+
+	ChecksPassing string
+	ChecksFailing string
+	ChecksPending string
 }
 
 // Default symbols (ASCII-safe)
@@ -22,6 +26,10 @@ var defaultSymbols = Symbols{
 	PROpen:   "○",
 	PRClosed: "✕",
 	PRDraft:  "◌",
+
+	ChecksPassing: "✓",
+	ChecksFailing: "✗",
+	ChecksPending: "◷",
 }
 
 // Nerd font symbols
@@ -30,6 +38,10 @@ var nerdfontSymbols = Symbols{
 	PROpen:   "\uea64", // nf-oct-git_pull_request
 	PRClosed: "\uebda", // nf-oct-git_pull_request_closed
 	PRDraft:  "\uebdb", // nf-oct-git_pull_request_draft
+
+	ChecksPassing: "\uf42e", // nf-oct-check
+	ChecksFailing: "\uf467", // nf-oct-x
+	ChecksPending: "\uf43a", // nf-oct-clock
 }
 
 // useNerdfont tracks whether nerd font symbols are enabled
@@ -137,6 +149,50 @@ func FormatStaleReason(commitAge string) string {
 	return "⏳ Stale (" + commitAge + ")"
 }
 
+// FormatChecksState returns a formatted string with symbol and CI check state.
+// state should be forge.CheckStatePassing, forge.CheckStateFailing,
+// forge.CheckStatePending, or empty (unknown).
+func FormatChecksState(state string) string {
+	switch state {
+	case forge.CheckStatePassing:
+		return currentSymbols.ChecksPassing + " Passing"
+	case forge.CheckStateFailing:
+		return currentSymbols.ChecksFailing + " Failing"
+	case forge.CheckStatePending:
+		return currentSymbols.ChecksPending + " Pending"
+	default:
+		return ""
+	}
+}
+
+// FormatChecks returns FormatChecksState colored by outcome, for table cells.
+func FormatChecks(state string) string {
+	text := FormatChecksState(state)
+	switch state {
+	case forge.CheckStatePassing:
+		return SuccessStyle.Render(text)
+	case forge.CheckStateFailing:
+		return ErrorStyle.Render(text)
+	case forge.CheckStatePending:
+		return WarningStyle.Render(text)
+	default:
+		return text
+	}
+}
+
+// FormatMergeReason returns a formatted merge reason string with symbol,
+// e.g. "● Merged (squash)". reason is a git.MergeReason value; empty returns "".
+func FormatMergeReason(reason string) string {
+	switch reason {
+	case "":
+		return ""
+	case "upstream-gone":
+		return currentSymbols.PRMerged + " Upstream gone"
+	default:
+		return currentSymbols.PRMerged + " Merged (" + reason + ")"
+	}
+}
+
 // PRStateSymbol returns just the symbol for a PR state
 func PRStateSymbol(state string, isDraft bool) string {
 	switch state {
@@ -215,6 +215,30 @@ func TestFormatStaleReason(t *testing.T) {
 	}
 }
 
+func TestFormatMergeReason(t *testing.T) {
+	SetNerdfont(false)
+
+	tests := []struct {
+		reason   string
+		expected string
+	}{
+		{"pr", "● Merged (pr)"},
+		{"ancestor", "● Merged (ancestor)"},
+		{"squash", "● Merged (squash)"},
+		{"upstream-gone", "● Upstream gone"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.reason, func(t *testing.T) {
+			got := FormatMergeReason(tt.reason)
+			if got != tt.expected {
+				t.Errorf("FormatMergeReason(%q) = %q, want %q", tt.reason, got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestCurrentSymbols(t *testing.T) {
 	SetNerdfont(false)
 	symbols := CurrentSymbols()
@@ -232,3 +256,28 @@ func TestCurrentSymbols(t *testing.T) {
 
 	SetNerdfont(false)
 }
+
+func TestFormatChecks(t *testing.T) {
+	SetNerdfont(false)
+
+	tests := []struct {
+		state    string
+		expected string
+	}{
+		{forge.CheckStatePassing, "✓ Passing"},
+		{forge.CheckStateFailing, "✗ Failing"},
+		{forge.CheckStatePending, "◷ Pending"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.state, func(t *testing.T) {
+			if got := FormatChecksState(tt.state); got != tt.expected {
+				t.Errorf("FormatChecksState(%q) = %q, want %q", tt.state, got, tt.expected)
+			}
+			if got := ansi.Strip(FormatChecks(tt.state)); got != tt.expected {
+				t.Errorf("FormatChecks(%q) = %q, want %q", tt.state, got, tt.expected)
+			}
+		})
+	}
+}
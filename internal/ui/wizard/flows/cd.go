@@ -26,6 +26,7 @@ type CdWorktreeInfo struct {
 	Branch     string
 	Path       string
 	LastAccess time.Time
+	PRStatus   string // PR state display string from cache (e.g. "#42 ○ Open"), empty if unknown
 }
 
 // CdWizardParams contains parameters for the cd interactive list.
@@ -105,8 +106,9 @@ func CdInteractive(params CdWizardParams) (CdOptions, error) {
 
 	for i, wt := range params.Worktrees {
 		options[i] = framework.Option{
-			Label: fmt.Sprintf("%s:%s", wt.RepoName, wt.Branch),
-			Value: i,
+			Label:       fmt.Sprintf("%s:%s", wt.RepoName, wt.Branch),
+			Value:       i,
+			Description: wt.PRStatus,
 		}
 	}
 
@@ -112,6 +112,8 @@ func extractCommand(testName string) string {
 		"NoteGet":       "wt note",
 		"NoteClear":     "wt note",
 		"Prune":         "wt prune",
+		"Stack":         "wt stack",
+		"StackRebase":   "wt stack",
 		"Repo":          "wt repo",
 		"Forge":         "forge",
 		"GitHub":        "forge",